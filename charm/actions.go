@@ -4,11 +4,15 @@
 package charm
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/binary132/gojsonschema"
 	"launchpad.net/goyaml"
@@ -26,9 +30,252 @@ type Actions struct {
 // ActionSpec is a definition of the parameters and traits of an Action.
 // The Params map is expected to conform to JSON-Schema Draft 4 as defined at
 // http://json-schema.org/draft-04/schema# (see http://json-schema.org/latest/json-schema-core.html)
+// Timeout, if non-zero, is how long the action may run before the unit
+// stops it; it is written in actions.yaml as a duration such as "5m".
 type ActionSpec struct {
 	Description string
 	Params      map[string]interface{}
+	Timeout     time.Duration `yaml:"-"`
+}
+
+// ParamError describes a single action parameter that failed validation.
+type ParamError struct {
+	// Param holds the path to the offending parameter, with nested
+	// parameters separated by dots; it is empty for failures of the
+	// parameters as a whole, such as a missing required parameter.
+	Param   string
+	Message string
+}
+
+func (e ParamError) Error() string {
+	if e.Param == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Param, e.Message)
+}
+
+// ParamsError is returned by ValidateParams when one or more action
+// parameters fail validation.
+type ParamsError struct {
+	Errors []ParamError
+}
+
+func (e *ParamsError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid action parameters: " + strings.Join(messages, "; ")
+}
+
+// ValidateParams checks params against the parameter schema of the
+// action, and returns a copy of params with the declared defaults of
+// any missing parameters filled in. The schema is checked with
+// gojsonschema as a JSON-Schema Draft 4 object schema whose properties
+// are the declared parameters; for compatibility, a parameter may also
+// be marked as required with "required: true" or "optional: false".
+// Parameters not declared by the schema are passed through unchecked.
+// If validation fails, the error is a *ParamsError listing every
+// offending parameter.
+func (spec ActionSpec) ValidateParams(params map[string]interface{}) (map[string]interface{}, error) {
+	result := applyDefaults(spec.Params, params)
+	schema, err := gojsonschema.NewJsonSchemaDocument(objectSchema(spec.Params, nil))
+	if err != nil {
+		return nil, fmt.Errorf("invalid params schema: %v", err)
+	}
+	doc, err := jsonValue(result)
+	if err != nil {
+		return nil, err
+	}
+	validation := schema.Validate(doc)
+	if validation.Valid() {
+		return result, nil
+	}
+	var errs []ParamError
+	for _, e := range validation.Errors() {
+		errs = append(errs, ParamError{paramPath(e.Context.String()), e.Description})
+	}
+	sort.Sort(paramErrors(errs))
+	return nil, &ParamsError{errs}
+}
+
+// applyDefaults returns a copy of values with the defaults declared by
+// the given property schemas filled in, recursing into the properties
+// of object parameters and the items of array parameters.
+func applyDefaults(properties map[string]interface{}, values map[string]interface{}) map[string]interface{} {
+	var result map[string]interface{}
+	if values != nil {
+		result = make(map[string]interface{})
+		for name, value := range values {
+			result[name] = value
+		}
+	}
+	for name, property := range properties {
+		schema, _ := property.(map[string]interface{})
+		value, ok := values[name]
+		if !ok {
+			if def, ok := schema["default"]; ok {
+				if result == nil {
+					result = make(map[string]interface{})
+				}
+				result[name] = def
+			}
+			continue
+		}
+		result[name] = valueDefaults(schema, value)
+	}
+	return result
+}
+
+// valueDefaults returns value with the defaults declared by schema
+// filled in.
+func valueDefaults(schema map[string]interface{}, value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			return applyDefaults(properties, typedValue)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			result := make([]interface{}, len(typedValue))
+			for i, item := range typedValue {
+				result[i] = valueDefaults(items, item)
+			}
+			return result
+		}
+	}
+	return value
+}
+
+// objectSchema returns a Draft 4 schema for an object with the given
+// properties. Properties marked as required in the Draft 3 style, with
+// "required: true" or "optional: false", are added to the required list.
+// Keys starting with "$", such as "$schema", are schema keywords rather
+// than properties.
+func objectSchema(properties map[string]interface{}, required []string) map[string]interface{} {
+	keywords := make(map[string]interface{})
+	draft4Properties := make(map[string]interface{})
+	for name, property := range properties {
+		if strings.HasPrefix(name, "$") {
+			keywords[name] = property
+			continue
+		}
+		schema, ok := property.(map[string]interface{})
+		if !ok {
+			draft4Properties[name] = property
+			continue
+		}
+		if isRequired(schema) {
+			required = append(required, name)
+		}
+		draft4Properties[name] = propertySchema(schema)
+	}
+	result := map[string]interface{}{
+		"type":       "object",
+		"properties": draft4Properties,
+	}
+	for key, value := range keywords {
+		result[key] = value
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		list := make([]interface{}, len(required))
+		for i, name := range required {
+			list[i] = name
+		}
+		result["required"] = list
+	}
+	return result
+}
+
+// propertySchema returns a Draft 4 version of the given property schema.
+func propertySchema(schema map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range schema {
+		switch key {
+		case "required":
+			if _, ok := value.(bool); ok {
+				continue
+			}
+		case "optional":
+			continue
+		}
+		result[key] = value
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		objSchema := objectSchema(properties, stringList(schema["required"]))
+		for key, value := range objSchema {
+			result[key] = value
+		}
+		if _, ok := objSchema["required"]; !ok {
+			delete(result, "required")
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		result["items"] = propertySchema(items)
+	}
+	return result
+}
+
+// isRequired reports whether a property schema marks the property as
+// required in the Draft 3 style.
+func isRequired(schema map[string]interface{}) bool {
+	if r, ok := schema["required"].(bool); ok && r {
+		return true
+	}
+	if o, ok := schema["optional"].(bool); ok && !o {
+		return true
+	}
+	return false
+}
+
+// stringList returns the strings held in v, if it is a list.
+func stringList(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// jsonValue returns value as it would be seen after a round trip
+// through JSON, so that numbers are validated consistently whatever
+// their Go type.
+func jsonValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal action parameters: %v", err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal action parameters: %v", err)
+	}
+	return result, nil
+}
+
+// paramPath converts a gojsonschema context such as "(root).options.level"
+// into a parameter path such as "options.level".
+func paramPath(context string) string {
+	path := strings.TrimPrefix(context, "(root)")
+	return strings.TrimPrefix(path, ".")
+}
+
+// paramErrors sorts parameter errors by parameter, then by message.
+type paramErrors []ParamError
+
+func (e paramErrors) Len() int      { return len(e) }
+func (e paramErrors) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e paramErrors) Less(i, j int) bool {
+	if e[i].Param != e[j].Param {
+		return e[i].Param < e[j].Param
+	}
+	return e[i].Message < e[j].Message
 }
 
 func NewActions() *Actions {
@@ -45,8 +292,18 @@ func ReadActionsYaml(r io.Reader) (*Actions, error) {
 	if err := goyaml.Unmarshal(data, &unmarshaledActions); err != nil {
 		return nil, err
 	}
+	// Timeouts are written as duration strings, which goyaml cannot
+	// unmarshal into a time.Duration, so they are read separately.
+	var timeouts struct {
+		ActionSpecs map[string]struct {
+			Timeout string
+		} `yaml:"actions"`
+	}
+	if err := goyaml.Unmarshal(data, &timeouts); err != nil {
+		return nil, err
+	}
 
-	for name, actionSpec := range unmarshaledActions.ActionSpecs {
+	for name := range unmarshaledActions.ActionSpecs {
 		if valid := actionNameRule.MatchString(name); !valid {
 			return nil, fmt.Errorf("bad action name %s", name)
 		}
@@ -70,11 +327,18 @@ func ReadActionsYaml(r io.Reader) (*Actions, error) {
 		// Now substitute the cleansed map into the original.
 		var swap = unmarshaledActions.ActionSpecs[name]
 		swap.Params = cleansedParams
+		if timeout := timeouts.ActionSpecs[name].Timeout; timeout != "" {
+			d, err := time.ParseDuration(timeout)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("bad timeout %q for action %s", timeout, name)
+			}
+			swap.Timeout = d
+		}
 		unmarshaledActions.ActionSpecs[name] = swap
 
 		// Make sure the new Params doc conforms to JSON-Schema
 		// Draft 4 (http://json-schema.org/latest/json-schema-core.html)
-		_, err = gojsonschema.NewJsonSchemaDocument(actionSpec.Params)
+		_, err = gojsonschema.NewJsonSchemaDocument(objectSchema(swap.Params, nil))
 		if err != nil {
 			return nil, fmt.Errorf("invalid params schema for action schema %s: %v", name, err)
 		}
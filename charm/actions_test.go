@@ -5,6 +5,7 @@ package charm
 
 import (
 	"bytes"
+	"time"
 
 	gc "launchpad.net/gocheck"
 )
@@ -273,6 +274,20 @@ actions:
 			"snapshot": ActionSpec{
 				Description: "Take a snapshot of the database.",
 				Params:      map[string]interface{}{}}}},
+	}, {
+		description: "A schema with a timeout.",
+		yaml: `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      timeout: 5m30s
+`,
+
+		expectedActions: &Actions{map[string]ActionSpec{
+			"snapshot": ActionSpec{
+				Description: "Take a snapshot of the database.",
+				Params:      map[string]interface{}{},
+				Timeout:     5*time.Minute + 30*time.Second}}},
 	}}
 
 	// Beginning of testing loop
@@ -397,6 +412,26 @@ actions:
 `,
 
 		expectedError: "bad param name Outfile",
+	}, {
+		description: "Malformed Actions: unparseable timeout.",
+		yaml: `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      timeout: soon
+`,
+
+		expectedError: `bad timeout "soon" for action snapshot`,
+	}, {
+		description: "Malformed Actions: negative timeout.",
+		yaml: `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      timeout: -1m
+`,
+
+		expectedError: `bad timeout "-1m" for action snapshot`,
 	}}
 
 	for i, test := range badActionsYamlTests {
@@ -407,3 +442,118 @@ actions:
 		c.Assert(err.Error(), gc.Equals, test.expectedError)
 	}
 }
+
+const validateParamsYaml = `
+actions:
+   snapshot:
+      description: Take a snapshot of the database.
+      params:
+         outfile:
+            type: string
+            default: foo.bz2
+            minLength: 1
+         quality:
+            type: integer
+            required: true
+         mode:
+            type: string
+            enum: [full, incremental]
+         ratio:
+            type: number
+         options:
+            type: object
+            required: [level]
+            properties:
+               level:
+                  type: integer
+               verbose:
+                  type: boolean
+                  default: false
+         hosts:
+            type: array
+            items:
+               type: string
+`
+
+func (s *ActionsSuite) TestValidateParams(c *gc.C) {
+	actions, err := ReadActionsYaml(bytes.NewReader([]byte(validateParamsYaml)))
+	c.Assert(err, gc.IsNil)
+	spec := actions.ActionSpecs["snapshot"]
+
+	var validateParamsTests = []struct {
+		description   string
+		params        map[string]interface{}
+		expected      map[string]interface{}
+		expectedError string
+	}{{
+		description: "Defaults are filled in.",
+		params:      map[string]interface{}{"quality": 5},
+		expected:    map[string]interface{}{"quality": 5, "outfile": "foo.bz2"},
+	}, {
+		description: "Given values override defaults.",
+		params:      map[string]interface{}{"quality": 5, "outfile": "out.tar"},
+		expected:    map[string]interface{}{"quality": 5, "outfile": "out.tar"},
+	}, {
+		description: "Integral floats are integers, and integers are numbers.",
+		params:      map[string]interface{}{"quality": 5.0, "ratio": 2},
+		expected:    map[string]interface{}{"quality": 5.0, "ratio": 2, "outfile": "foo.bz2"},
+	}, {
+		description: "Undeclared parameters pass through.",
+		params:      map[string]interface{}{"quality": 5, "extra": "x"},
+		expected:    map[string]interface{}{"quality": 5, "extra": "x", "outfile": "foo.bz2"},
+	}, {
+		description: "Enum values are accepted.",
+		params:      map[string]interface{}{"quality": 5, "mode": "full"},
+		expected:    map[string]interface{}{"quality": 5, "mode": "full", "outfile": "foo.bz2"},
+	}, {
+		description: "Nested objects and arrays are validated.",
+		params: map[string]interface{}{
+			"quality": 5,
+			"options": map[string]interface{}{"level": 3},
+			"hosts":   []interface{}{"a", "b"},
+		},
+		expected: map[string]interface{}{
+			"quality": 5,
+			"outfile": "foo.bz2",
+			"options": map[string]interface{}{"level": 3, "verbose": false},
+			"hosts":   []interface{}{"a", "b"},
+		},
+	}, {
+		description:   "Missing required parameter.",
+		params:        nil,
+		expectedError: "invalid action parameters: .*quality.*required.*",
+	}, {
+		description:   "Wrong type.",
+		params:        map[string]interface{}{"quality": 5.5, "outfile": 3},
+		expectedError: "invalid action parameters: outfile: .*string.*; quality: .*integer.*",
+	}, {
+		description:   "Value not in enum.",
+		params:        map[string]interface{}{"quality": 5, "mode": "partial"},
+		expectedError: `invalid action parameters: mode: .*full.*`,
+	}, {
+		description: "Nested failures report the parameter path.",
+		params: map[string]interface{}{
+			"quality": 5,
+			"options": map[string]interface{}{"verbose": "yes"},
+			"hosts":   []interface{}{"a", 1},
+		},
+		expectedError: `invalid action parameters: hosts.*1.*string.*; ` +
+			`options: .*level.*required.*; options.verbose: .*boolean.*`,
+	}, {
+		description:   "Other JSON-Schema keywords are honoured.",
+		params:        map[string]interface{}{"quality": 5, "outfile": ""},
+		expectedError: "invalid action parameters: outfile: .*",
+	}}
+
+	for i, test := range validateParamsTests {
+		c.Logf("test %d: %s", i, test.description)
+		result, err := spec.ValidateParams(test.params)
+		if test.expectedError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectedError)
+			c.Check(err, gc.FitsTypeOf, &ParamsError{})
+			continue
+		}
+		c.Check(err, gc.IsNil)
+		c.Check(result, gc.DeepEquals, test.expected)
+	}
+}
@@ -17,6 +17,17 @@ const (
 	UpgradeCharm  Kind = "upgrade-charm"
 	Stop          Kind = "stop"
 
+	// UpdateStatus runs periodically, giving the charm the chance to
+	// refresh the status it reports for the unit.
+	UpdateStatus Kind = "update-status"
+
+	// These hooks are associated with the leadership of the unit's
+	// service. LeaderElected runs on the unit that has become leader;
+	// LeaderSettingsChanged runs on the other units when the leader
+	// changes the settings it shares with them.
+	LeaderElected         Kind = "leader-elected"
+	LeaderSettingsChanged Kind = "leader-settings-changed"
+
 	// These hooks require an associated relation, and the name of the relation
 	// unit whose change triggered the hook. The hook file names that these
 	// kinds represent will be prefixed by the relation name; for example,
@@ -29,6 +40,11 @@ const (
 	// will be prefixed by the relation name, just like the other Relation* Kind
 	// values.
 	RelationBroken Kind = "relation-broken"
+
+	// ActionRequested is not a hook that can be implemented by a charm;
+	// it is used by the uniter to represent the execution of an action,
+	// the script for which is found in the charm's actions directory.
+	ActionRequested Kind = "action-requested"
 )
 
 var unitHooks = []Kind{
@@ -37,6 +53,9 @@ var unitHooks = []Kind{
 	ConfigChanged,
 	UpgradeCharm,
 	Stop,
+	UpdateStatus,
+	LeaderElected,
+	LeaderSettingsChanged,
 }
 
 // UnitHooks returns all known unit hook kinds.
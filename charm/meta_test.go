@@ -368,6 +368,9 @@ func (s *MetaSuite) TestMetaHooks(c *gc.C) {
 		"config-changed":                    true,
 		"upgrade-charm":                     true,
 		"stop":                              true,
+		"update-status":                     true,
+		"leader-elected":                    true,
+		"leader-settings-changed":           true,
 		"cache-relation-joined":             true,
 		"cache-relation-changed":            true,
 		"cache-relation-departed":           true,
@@ -0,0 +1,88 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+type ActionCommand struct {
+	*cmd.SuperCommand
+}
+
+const actionCommandDoc = `
+"juju action" is used to inspect actions queued with "juju do" and to
+retrieve their results.
+`
+
+const actionCommandPurpose = "inspect queued and completed actions"
+
+func NewActionCommand() cmd.Command {
+	actioncmd := &ActionCommand{
+		SuperCommand: cmd.NewSuperCommand(cmd.SuperCommandParams{
+			Name:        "action",
+			Doc:         actionCommandDoc,
+			UsagePrefix: "juju",
+			Purpose:     actionCommandPurpose,
+		}),
+	}
+	// Define each subcommand in a separate "action_FOO.go" source file
+	// (with tests in action_FOO_test.go) and wire in here.
+	actioncmd.Register(envcmd.Wrap(&ActionStatusCommand{}))
+	actioncmd.Register(envcmd.Wrap(&ActionCancelCommand{}))
+	actioncmd.Register(envcmd.Wrap(&ActionFetchCommand{}))
+	actioncmd.Register(envcmd.Wrap(&ActionProgressCommand{}))
+	return actioncmd
+}
+
+// actionAPI is the subset of the actions client used by the
+// "juju action" subcommands.
+type actionAPI interface {
+	List(tags ...string) ([]params.ActionListResult, error)
+	Result(id string) (*params.ActionResult, error)
+	ServiceResult(id string) (*params.ServiceActionResult, error)
+	Cancel(ids ...string) ([]params.ErrorResult, error)
+	Close() error
+}
+
+var getActionAPI = func(envName string) (actionAPI, error) {
+	return juju.NewActionsClient(envName)
+}
+
+// formatActionResult returns the printable form of an action result.
+func formatActionResult(result params.ActionResult) map[string]interface{} {
+	out := map[string]interface{}{
+		"id":     result.ActionId,
+		"unit":   receiverName(result.Receiver),
+		"action": result.Name,
+		"status": result.Status,
+	}
+	if len(result.Params) > 0 {
+		out["params"] = result.Params
+	}
+	if len(result.Results) > 0 {
+		out["results"] = result.Results
+	}
+	if result.Output != "" {
+		out["output"] = result.Output
+	}
+	if result.Message != "" {
+		out["message"] = result.Message
+	}
+	for key, t := range map[string]time.Time{
+		"enqueued":  result.Enqueued,
+		"started":   result.Started,
+		"completed": result.Completed,
+	} {
+		if !t.IsZero() {
+			out[key] = t.UTC().Format(time.RFC3339)
+		}
+	}
+	return out
+}
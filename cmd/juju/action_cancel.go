@@ -0,0 +1,74 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const actionCancelCommandDoc = `
+Cancel queued or running actions. An action that has not started yet is
+removed from the queue; an action that is running is stopped by its unit,
+along with any processes it started. Either way, the action is reported
+with a "cancelled" status. Cancelling a service action cancels it on
+every unit, and stops it being queued on units added to the service
+later.
+
+Examples:
+  juju action cancel u#mysql/0#a#0                  (Cancel one action)
+  juju action cancel u#mysql/0#a#0 u#mysql/1#a#0    (Cancel several actions)
+  juju action cancel s#mysql#a#0                    (Cancel a service action)
+`
+
+// ActionCancelCommand cancels queued or running actions.
+type ActionCancelCommand struct {
+	envcmd.EnvCommandBase
+	actionIds []string
+}
+
+func (c *ActionCancelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel",
+		Args:    "<action id> ...",
+		Purpose: "cancel queued or running actions",
+		Doc:     actionCancelCommandDoc,
+	}
+}
+
+func (c *ActionCancelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no action id specified")
+	}
+	c.actionIds = args
+	return nil
+}
+
+func (c *ActionCancelCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.Cancel(c.actionIds...)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(c.actionIds) {
+		return fmt.Errorf("expected %d results, got %d", len(c.actionIds), len(results))
+	}
+	var failed bool
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot cancel action %q: %v\n", c.actionIds[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionCancelCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockActionAPI
+}
+
+var _ = gc.Suite(&ActionCancelCommandSuite{})
+
+func (s *ActionCancelCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockActionAPI{}
+	s.PatchValue(&getActionAPI, func(envName string) (actionAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newActionCancelCommand() cmd.Command {
+	return envcmd.Wrap(&ActionCancelCommand{})
+}
+
+func (s *ActionCancelCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionCancelCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no action id specified")
+}
+
+func (s *ActionCancelCommandSuite) TestRun(c *gc.C) {
+	s.mockAPI.cancelResults = []params.ErrorResult{{}, {}}
+	context, err := testing.RunCommand(c, newActionCancelCommand(), "u#mysql/0#a#0", "u#mysql/1#a#0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.cancelled, gc.DeepEquals, []string{"u#mysql/0#a#0", "u#mysql/1#a#0"})
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "")
+}
+
+func (s *ActionCancelCommandSuite) TestRunError(c *gc.C) {
+	s.mockAPI.cancelResults = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: `action "u#mysql/1#a#9" not found`}},
+	}
+	context, err := testing.RunCommand(c, newActionCancelCommand(), "u#mysql/0#a#0", "u#mysql/1#a#9")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(context), gc.Equals,
+		`cannot cancel action "u#mysql/1#a#9": action "u#mysql/1#a#9" not found`+"\n")
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const actionFetchCommandDoc = `
+Show the outcome of a queued action. An action that has not run yet is
+reported with a "pending" status, and one that is still running with a
+"running" status; use --wait to block until it has finished.
+
+Examples:
+  juju action fetch u#mysql/0#a#0           (Show the outcome of the action)
+  juju action fetch u#mysql/0#a#0 --wait    (Wait for the action to finish first)
+`
+
+// actionFetchPollInterval is how often "juju action fetch --wait"
+// checks whether the action has finished.
+var actionFetchPollInterval = time.Second
+
+// ActionFetchCommand shows the outcome of a single action.
+type ActionFetchCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	actionId string
+	wait     bool
+}
+
+func (c *ActionFetchCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "fetch",
+		Args:    "<action id>",
+		Purpose: "show the outcome of an action",
+		Doc:     actionFetchCommandDoc,
+	}
+}
+
+func (c *ActionFetchCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.BoolVar(&c.wait, "wait", false, "wait for the action to finish")
+}
+
+func (c *ActionFetchCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no action id specified")
+	}
+	c.actionId, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// actionUnfinished reports whether an action with the given status has
+// yet to finish.
+func actionUnfinished(status string) bool {
+	return status == "pending" || status == "running"
+}
+
+func (c *ActionFetchCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	for {
+		result, err := client.Result(c.actionId)
+		if err != nil {
+			return err
+		}
+		if !c.wait || !actionUnfinished(result.Status) {
+			return c.out.Write(ctx, formatActionResult(*result))
+		}
+		time.Sleep(actionFetchPollInterval)
+	}
+}
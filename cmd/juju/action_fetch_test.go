@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionFetchCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockActionAPI
+}
+
+var _ = gc.Suite(&ActionFetchCommandSuite{})
+
+func (s *ActionFetchCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockActionAPI{}
+	s.PatchValue(&getActionAPI, func(envName string) (actionAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(&actionFetchPollInterval, time.Duration(0))
+}
+
+func newActionFetchCommand() cmd.Command {
+	return envcmd.Wrap(&ActionFetchCommand{})
+}
+
+var (
+	pendingResult = &params.ActionResult{
+		ActionId: "u#mysql/0#a#0",
+		Receiver: "unit-mysql-0",
+		Name:     "backup",
+		Status:   "pending",
+	}
+	runningResult = &params.ActionResult{
+		ActionId: "u#mysql/0#a#0",
+		Receiver: "unit-mysql-0",
+		Name:     "backup",
+		Status:   "running",
+		Enqueued: time.Date(2014, 7, 1, 10, 0, 0, 0, time.UTC),
+		Started:  time.Date(2014, 7, 1, 10, 1, 0, 0, time.UTC),
+	}
+	completeResult = &params.ActionResult{
+		ActionId:  "u#mysql/0#a#0",
+		Receiver:  "unit-mysql-0",
+		Name:      "backup",
+		Params:    map[string]interface{}{"outfile": "out.tar"},
+		Status:    "complete",
+		Results:   map[string]interface{}{"size": 10},
+		Output:    "done",
+		Message:   "backed up",
+		Enqueued:  time.Date(2014, 7, 1, 10, 0, 0, 0, time.UTC),
+		Completed: time.Date(2014, 7, 1, 10, 5, 0, 0, time.UTC),
+	}
+)
+
+func (s *ActionFetchCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionFetchCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no action id specified")
+	err = testing.InitCommand(&ActionFetchCommand{}, []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ActionFetchCommandSuite) TestRunPending(c *gc.C) {
+	s.mockAPI.results = []*params.ActionResult{pendingResult, completeResult}
+	context, err := testing.RunCommand(c, newActionFetchCommand(), "u#mysql/0#a#0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.Equals, 1)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"action: backup\n"+
+		"id: u#mysql/0#a#0\n"+
+		"status: pending\n"+
+		"unit: mysql/0\n")
+}
+
+func (s *ActionFetchCommandSuite) TestRunWait(c *gc.C) {
+	s.mockAPI.results = []*params.ActionResult{pendingResult, runningResult, runningResult, completeResult}
+	context, err := testing.RunCommand(c, newActionFetchCommand(), "u#mysql/0#a#0", "--wait")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.Equals, 3)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"action: backup\n"+
+		"completed: 2014-07-01T10:05:00Z\n"+
+		"enqueued: 2014-07-01T10:00:00Z\n"+
+		"id: u#mysql/0#a#0\n"+
+		"message: backed up\n"+
+		"output: done\n"+
+		"params:\n"+
+		"  outfile: out.tar\n"+
+		"results:\n"+
+		"  size: 10\n"+
+		"status: complete\n"+
+		"unit: mysql/0\n")
+}
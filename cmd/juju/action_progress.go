@@ -0,0 +1,92 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const actionProgressCommandDoc = `
+Show the progress of an action queued on a service, as printed by
+"juju do <service> <action>". The status of the action on each unit is
+reported, along with the number of units in each status.
+
+Example:
+  juju action progress s#mysql#a#0    (Show the progress of the service action)
+`
+
+// ActionProgressCommand shows the progress of a service action across
+// the units of the service.
+type ActionProgressCommand struct {
+	envcmd.EnvCommandBase
+	out      cmd.Output
+	actionId string
+}
+
+func (c *ActionProgressCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "progress",
+		Args:    "<service action id>",
+		Purpose: "show the progress of an action queued on a service",
+		Doc:     actionProgressCommandDoc,
+	}
+}
+
+func (c *ActionProgressCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ActionProgressCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no service action id specified")
+	}
+	c.actionId, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ActionProgressCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	result, err := client.ServiceResult(c.actionId)
+	if err != nil {
+		return err
+	}
+	service := result.Receiver
+	if _, name, err := names.ParseTag(result.Receiver, names.ServiceTagKind); err == nil {
+		service = name
+	}
+	summary := make(map[string]int)
+	units := make(map[string]interface{})
+	for _, unit := range result.Units {
+		summary[unit.Status]++
+		formatted := formatActionResult(unit)
+		delete(formatted, "unit")
+		delete(formatted, "action")
+		delete(formatted, "params")
+		units[receiverName(unit.Receiver)] = formatted
+	}
+	out := map[string]interface{}{
+		"id":      result.Id,
+		"service": service,
+		"action":  result.Name,
+		"summary": summary,
+		"units":   units,
+	}
+	if len(result.Params) > 0 {
+		out["params"] = result.Params
+	}
+	if result.IncludeFuture {
+		out["include-future"] = true
+	}
+	return c.out.Write(ctx, out)
+}
@@ -0,0 +1,87 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionProgressCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockActionAPI
+}
+
+var _ = gc.Suite(&ActionProgressCommandSuite{})
+
+func (s *ActionProgressCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockActionAPI{}
+	s.PatchValue(&getActionAPI, func(envName string) (actionAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newActionProgressCommand() cmd.Command {
+	return envcmd.Wrap(&ActionProgressCommand{})
+}
+
+func (s *ActionProgressCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionProgressCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no service action id specified")
+	err = testing.InitCommand(&ActionProgressCommand{}, []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *ActionProgressCommandSuite) TestRun(c *gc.C) {
+	s.mockAPI.serviceResult = &params.ServiceActionResult{
+		Id:            "s#mysql#a#0",
+		Receiver:      "service-mysql",
+		Name:          "flush-cache",
+		IncludeFuture: true,
+		Units: []params.ActionResult{{
+			ActionId: "u#mysql/0#a#0",
+			Receiver: "unit-mysql-0",
+			Name:     "flush-cache",
+			Status:   "complete",
+		}, {
+			ActionId: "u#mysql/1#a#0",
+			Receiver: "unit-mysql-1",
+			Name:     "flush-cache",
+			Status:   "complete",
+		}, {
+			ActionId: "u#mysql/2#a#0",
+			Receiver: "unit-mysql-2",
+			Name:     "flush-cache",
+			Status:   "fail",
+			Message:  "cache locked",
+		}},
+	}
+	context, err := testing.RunCommand(c, newActionProgressCommand(), "s#mysql#a#0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.serviceId, gc.Equals, "s#mysql#a#0")
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"action: flush-cache\n"+
+		"id: s#mysql#a#0\n"+
+		"include-future: true\n"+
+		"service: mysql\n"+
+		"summary:\n"+
+		"  complete: 2\n"+
+		"  fail: 1\n"+
+		"units:\n"+
+		"  mysql/0:\n"+
+		"    id: u#mysql/0#a#0\n"+
+		"    status: complete\n"+
+		"  mysql/1:\n"+
+		"    id: u#mysql/1#a#0\n"+
+		"    status: complete\n"+
+		"  mysql/2:\n"+
+		"    id: u#mysql/2#a#0\n"+
+		"    message: cache locked\n"+
+		"    status: fail\n")
+}
@@ -0,0 +1,97 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const actionStatusCommandDoc = `
+Show the pending and completed actions of the given units or services.
+
+Examples:
+  juju action status mysql/0          (Show the actions of unit mysql/0)
+  juju action status mysql wordpress  (Show the actions of every mysql and wordpress unit)
+`
+
+// ActionStatusCommand lists the actions of units or services.
+type ActionStatusCommand struct {
+	envcmd.EnvCommandBase
+	out  cmd.Output
+	tags []string
+}
+
+func (c *ActionStatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status",
+		Args:    "<unit | service> ...",
+		Purpose: "show the actions of units or services",
+		Doc:     actionStatusCommandDoc,
+	}
+}
+
+func (c *ActionStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ActionStatusCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no unit or service specified")
+	}
+	c.tags = make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case names.IsUnit(arg):
+			c.tags[i] = names.UnitTag(arg)
+		case names.IsService(arg):
+			c.tags[i] = names.ServiceTag(arg)
+		default:
+			return fmt.Errorf("invalid unit or service name %q", arg)
+		}
+	}
+	return nil
+}
+
+func (c *ActionStatusCommand) Run(ctx *cmd.Context) error {
+	client, err := getActionAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	results, err := client.List(c.tags...)
+	if err != nil {
+		return err
+	}
+	out := make(map[string]interface{})
+	for _, result := range results {
+		name := receiverName(result.Receiver)
+		if result.Error != nil {
+			out[name] = map[string]interface{}{"error": result.Error.Error()}
+			continue
+		}
+		var actions []map[string]interface{}
+		for _, action := range result.Pending {
+			actions = append(actions, map[string]interface{}{
+				"id":     action.Id,
+				"action": action.Name,
+				"status": "pending",
+			})
+		}
+		for _, completed := range result.Completed {
+			actions = append(actions, map[string]interface{}{
+				"id":     completed.ActionId,
+				"action": completed.Name,
+				"status": completed.Status,
+			})
+		}
+		out[name] = actions
+	}
+	return c.out.Write(ctx, out)
+}
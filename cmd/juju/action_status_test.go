@@ -0,0 +1,65 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type ActionStatusCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockActionAPI
+}
+
+var _ = gc.Suite(&ActionStatusCommandSuite{})
+
+func (s *ActionStatusCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockActionAPI{}
+	s.PatchValue(&getActionAPI, func(envName string) (actionAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newActionStatusCommand() cmd.Command {
+	return envcmd.Wrap(&ActionStatusCommand{})
+}
+
+func (s *ActionStatusCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ActionStatusCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no unit or service specified")
+	err = testing.InitCommand(&ActionStatusCommand{}, []string{"mysql/x"})
+	c.Assert(err, gc.ErrorMatches, `invalid unit or service name "mysql/x"`)
+}
+
+func (s *ActionStatusCommandSuite) TestRun(c *gc.C) {
+	s.mockAPI.list = []params.ActionListResult{{
+		Receiver: "unit-mysql-0",
+		Pending:  []params.Action{{Id: "u#mysql/0#a#1", Name: "backup"}},
+		Completed: []params.ActionResult{
+			{ActionId: "u#mysql/0#a#0", Name: "snapshot", Status: "complete"},
+		},
+	}, {
+		Receiver: "unit-wordpress-0",
+		Error:    &params.Error{Message: `unit "wordpress/0" not found`},
+	}}
+	context, err := testing.RunCommand(c, newActionStatusCommand(), "mysql/0", "wordpress/0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.tags, gc.DeepEquals, []string{"unit-mysql-0", "unit-wordpress-0"})
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"mysql/0:\n"+
+		"- action: backup\n"+
+		"  id: u#mysql/0#a#1\n"+
+		"  status: pending\n"+
+		"- action: snapshot\n"+
+		"  id: u#mysql/0#a#0\n"+
+		"  status: complete\n"+
+		"wordpress/0:\n"+
+		"  error: unit \"wordpress/0\" not found\n")
+}
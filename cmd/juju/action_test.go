@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ActionCommandSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ActionCommandSuite{})
+
+var expectedActionCommmandNames = []string{
+	"cancel",
+	"fetch",
+	"help",
+	"progress",
+	"status",
+}
+
+func (s *ActionCommandSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := coretesting.RunCommand(c, NewActionCommand(), "--help")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Matches,
+		"(?s)usage: action <command> .+"+
+			actionCommandPurpose+".+"+
+			actionCommandDoc+".+")
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(coretesting.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		namesFound = append(namesFound, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Assert(namesFound, gc.DeepEquals, expectedActionCommmandNames)
+}
+
+// mockActionAPI is shared by the "juju action" subcommand tests.
+type mockActionAPI struct {
+	tags          []string
+	list          []params.ActionListResult
+	results       []*params.ActionResult
+	calls         int
+	serviceResult *params.ServiceActionResult
+	serviceId     string
+	cancelled     []string
+	cancelResults []params.ErrorResult
+}
+
+func (m *mockActionAPI) Close() error {
+	return nil
+}
+
+func (m *mockActionAPI) List(tags ...string) ([]params.ActionListResult, error) {
+	m.tags = tags
+	return m.list, nil
+}
+
+func (m *mockActionAPI) Result(id string) (*params.ActionResult, error) {
+	result := m.results[m.calls]
+	if m.calls < len(m.results)-1 {
+		m.calls++
+	}
+	return result, nil
+}
+
+func (m *mockActionAPI) ServiceResult(id string) (*params.ServiceActionResult, error) {
+	m.serviceId = id
+	return m.serviceResult, nil
+}
+
+func (m *mockActionAPI) Cancel(ids ...string) ([]params.ErrorResult, error) {
+	m.cancelled = ids
+	return m.cancelResults, nil
+}
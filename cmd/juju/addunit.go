@@ -46,6 +46,7 @@ type AddUnitCommand struct {
 	envcmd.EnvCommandBase
 	UnitCommandBase
 	ServiceName string
+	DryRun      bool
 }
 
 const addUnitDoc = `
@@ -57,6 +58,9 @@ By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
 argument.
 
+With --dry-run, add-unit reports the changes it would make, as checked by
+the API server, without making them.
+
 Examples:
  juju add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
  juju add-unit mysql --to 23       (Add a mysql unit to machine 23)
@@ -76,6 +80,7 @@ func (c *AddUnitCommand) Info() *cmd.Info {
 func (c *AddUnitCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.UnitCommandBase.SetFlags(f)
 	f.IntVar(&c.NumUnits, "n", 1, "number of service units to add")
+	f.BoolVar(&c.DryRun, "dry-run", false, "report the changes that would be made, without making them")
 }
 
 func (c *AddUnitCommand) Init(args []string) error {
@@ -93,13 +98,18 @@ func (c *AddUnitCommand) Init(args []string) error {
 
 // Run connects to the environment specified on the command line
 // and calls AddServiceUnits for the given service.
-func (c *AddUnitCommand) Run(_ *cmd.Context) error {
+func (c *AddUnitCommand) Run(ctx *cmd.Context) error {
 	apiclient, err := juju.NewAPIClientFromName(c.EnvName)
 	if err != nil {
 		return err
 	}
 	defer apiclient.Close()
 
+	if c.DryRun {
+		changes, err := apiclient.AddServiceUnitsDryRun(c.ServiceName, c.NumUnits, c.ToMachineSpec)
+		return reportDryRun(ctx, changes, err)
+	}
+
 	_, err = apiclient.AddServiceUnits(c.ServiceName, c.NumUnits, c.ToMachineSpec)
 	return err
 }
@@ -131,3 +131,19 @@ func (s *AddUnitSuite) TestForceMachineNewContainer(c *gc.C) {
 	s.assertForceMachine(c, svc, 3, 1, machine.Id()+"/lxc/0")
 	s.assertForceMachine(c, svc, 3, 2, machine.Id())
 }
+
+func (s *AddUnitSuite) TestDryRun(c *gc.C) {
+	curl := s.setupService(c)
+	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AddUnitCommand{}), "some-service-name", "--to", "lxc:"+machine.Id(), "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"add lxc container on machine "+machine.Id()+"\n"+
+		"add 1 unit(s) of service some-service-name to the new container\n")
+	s.AssertService(c, "some-service-name", curl, 1, 0)
+	containers, err := machine.Containers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
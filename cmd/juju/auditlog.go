@@ -0,0 +1,178 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+const auditLogCommandDoc = `
+Show the calls made by users that may have changed the environment,
+oldest first. Calls that only read the environment, such as those made
+by "juju status", are not recorded. The values of passwords and other
+secrets are removed from the recorded arguments.
+
+The calls shown can be restricted to those made by a user, those made
+in a time range, and those that touched an entity: a service or unit
+name, a machine id, or a tag.
+Times are given as dates (2014-09-01), or as RFC3339 times
+(2014-09-01T12:00:00Z).
+
+Only users with the admin role may show the audit log.
+
+Examples:
+
+    juju audit-log --user bob
+    juju audit-log --after 2014-09-01 --before 2014-09-02
+    juju audit-log --entity wordpress/0
+`
+
+// AuditLogCommand shows the calls recorded in the audit log.
+type AuditLogCommand struct {
+	envcmd.EnvCommandBase
+	out    cmd.Output
+	user   string
+	after  string
+	before string
+	entity string
+	filter params.AuditLogFilter
+}
+
+func (c *AuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "show the changes made to the environment by users",
+		Doc:     auditLogCommandDoc,
+	}
+}
+
+func (c *AuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+	f.StringVar(&c.user, "user", "", "show only the calls made by this user")
+	f.StringVar(&c.after, "after", "", "show only the calls made at or after this time")
+	f.StringVar(&c.before, "before", "", "show only the calls made before this time")
+	f.StringVar(&c.entity, "entity", "", "show only the calls that touched this entity")
+}
+
+func (c *AuditLogCommand) Init(args []string) error {
+	if c.user != "" {
+		if !names.IsUser(c.user) {
+			return fmt.Errorf("invalid user name %q", c.user)
+		}
+		c.filter.User = names.UserTag(c.user)
+	}
+	var err error
+	if c.after != "" {
+		if c.filter.After, err = parseTime(c.after); err != nil {
+			return err
+		}
+	}
+	if c.before != "" {
+		if c.filter.Before, err = parseTime(c.before); err != nil {
+			return err
+		}
+	}
+	c.filter.Entity = c.entity
+	return cmd.CheckEmpty(args)
+}
+
+// parseTime parses a time given as a date or as an RFC3339 time.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a date or an RFC3339 time", value)
+}
+
+type auditLogAPI interface {
+	Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *AuditLogCommand) (auditLogAPI, error) {
+	return juju.NewAuditLogClient(c.EnvName)
+}
+
+func (c *AuditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	// The entries are returned a page at a time.
+	var entries []params.AuditLogEntry
+	filter := c.filter
+	for {
+		page, err := client.Entries(filter)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			break
+		}
+		entries = append(entries, page...)
+		filter.AfterId = page[len(page)-1].Id
+	}
+	out := make([]formattedAuditEntry, len(entries))
+	for i, entry := range entries {
+		out[i] = formatAuditEntry(entry)
+	}
+	return c.out.Write(ctx, out)
+}
+
+// formattedAuditEntry is the printable form of params.AuditLogEntry.
+type formattedAuditEntry struct {
+	Time  string `yaml:"time" json:"time"`
+	User  string `yaml:"user" json:"user"`
+	Call  string `yaml:"call" json:"call"`
+	Args  string `yaml:"args,omitempty" json:"args,omitempty"`
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func formatAuditEntry(entry params.AuditLogEntry) formattedAuditEntry {
+	user := entry.User
+	if _, name, err := names.ParseTag(user, names.UserTagKind); err == nil {
+		user = name
+	}
+	return formattedAuditEntry{
+		Time:  entry.Time.UTC().Format(time.RFC3339),
+		User:  user,
+		Call:  entry.Facade + "." + entry.Method,
+		Args:  entry.Args,
+		Error: entry.Error,
+	}
+}
+
+// formatAuditLogTabular returns a table of the audited calls, one per
+// line.
+func formatAuditLogTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]formattedAuditEntry)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	table := newStatusTable(false, "TIME", "USER", "CALL", "ARGS", "ERROR")
+	for _, entry := range entries {
+		table.addRow(entry.Time, entry.User, entry.Call, entry.Args, entry.Error)
+	}
+	var out bytes.Buffer
+	table.writeTo(&out)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockAuditLogAPI
+}
+
+var _ = gc.Suite(&AuditLogCommandSuite{})
+
+func (s *AuditLogCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockAuditLogAPI{
+		entries: []params.AuditLogEntry{{
+			Id:     "entry-0",
+			Time:   time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC),
+			User:   "user-admin",
+			Facade: "Client",
+			Method: "ServiceExpose",
+			Args:   `{"ServiceName":"wordpress"}`,
+		}, {
+			Id:     "entry-1",
+			Time:   time.Date(2014, 9, 1, 13, 0, 0, 0, time.UTC),
+			User:   "user-bob",
+			Facade: "Client",
+			Method: "ServiceExpose",
+			Args:   `{"ServiceName":"mysql"}`,
+			Error:  `service "mysql" not found`,
+		}},
+	}
+	s.PatchValue(&getAuditLogAPI, func(c *AuditLogCommand) (auditLogAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newAuditLogCommand() cmd.Command {
+	return envcmd.Wrap(&AuditLogCommand{})
+}
+
+func (s *AuditLogCommandSuite) TestAuditLog(c *gc.C) {
+	context, err := testing.RunCommand(c, newAuditLogCommand())
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.filters, gc.DeepEquals, []params.AuditLogFilter{{}, {AfterId: "entry-1"}})
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"TIME                  USER   CALL                  ARGS                         ERROR\n"+
+		"2014-09-01T12:00:00Z  admin  Client.ServiceExpose  {\"ServiceName\":\"wordpress\"}\n"+
+		"2014-09-01T13:00:00Z  bob    Client.ServiceExpose  {\"ServiceName\":\"mysql\"}      service \"mysql\" not found\n")
+}
+
+func (s *AuditLogCommandSuite) TestAuditLogPages(c *gc.C) {
+	s.mockAPI.pageSize = 1
+	context, err := testing.RunCommand(c, newAuditLogCommand(), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.filters, gc.DeepEquals, []params.AuditLogFilter{
+		{},
+		{AfterId: "entry-0"},
+		{AfterId: "entry-1"},
+	})
+	c.Assert(testing.Stdout(context), gc.Matches, "(?s)- time: 2014-09-01T12:00:00Z.*- time: 2014-09-01T13:00:00Z.*")
+}
+
+func (s *AuditLogCommandSuite) TestAuditLogJSON(c *gc.C) {
+	s.mockAPI.entries = s.mockAPI.entries[1:]
+	context, err := testing.RunCommand(c, newAuditLogCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `[{"time":"2014-09-01T13:00:00Z","user":"bob",`+
+		`"call":"Client.ServiceExpose","args":"{\"ServiceName\":\"mysql\"}",`+
+		`"error":"service \"mysql\" not found"}]`+"\n")
+}
+
+func (s *AuditLogCommandSuite) TestAuditLogFilter(c *gc.C) {
+	_, err := testing.RunCommand(c, newAuditLogCommand(),
+		"--user", "bob",
+		"--after", "2014-09-01",
+		"--before", "2014-09-01T13:30:00Z",
+		"--entity", "wordpress/0",
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.filters[0], gc.DeepEquals, params.AuditLogFilter{
+		User:   "user-bob",
+		After:  time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC),
+		Before: time.Date(2014, 9, 1, 13, 30, 0, 0, time.UTC),
+		Entity: "wordpress/0",
+	})
+}
+
+func (s *AuditLogCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--user", "not/valid"},
+		err:  `invalid user name "not/valid"`,
+	}, {
+		args: []string{"--after", "yesterday"},
+		err:  `invalid time "yesterday": expected a date or an RFC3339 time`,
+	}, {
+		args: []string{"--before", "2014-13-01"},
+		err:  `invalid time "2014-13-01": expected a date or an RFC3339 time`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&AuditLogCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AuditLogCommandSuite) TestError(c *gc.C) {
+	s.mockAPI.err = &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}
+	_, err := testing.RunCommand(c, newAuditLogCommand())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+// mockAuditLogAPI returns its entries a page at a time, recording the
+// filter given for each page.
+type mockAuditLogAPI struct {
+	entries  []params.AuditLogEntry
+	pageSize int
+	filters  []params.AuditLogFilter
+	err      error
+}
+
+func (m *mockAuditLogAPI) Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	m.filters = append(m.filters, filter)
+	if m.err != nil {
+		return nil, m.err
+	}
+	entries := m.entries
+	if filter.AfterId != "" {
+		for i, entry := range m.entries {
+			if entry.Id == filter.AfterId {
+				entries = m.entries[i+1:]
+			}
+		}
+	}
+	if m.pageSize > 0 && len(entries) > m.pageSize {
+		entries = entries[:m.pageSize]
+	}
+	return entries, nil
+}
+
+func (*mockAuditLogAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+type BackupsCommand struct {
+	*cmd.SuperCommand
+}
+
+const backupsCommandDoc = `
+"juju backups" is used to take backups of the state server, and to
+manage the backups kept by the environment.
+
+A backup holds a dump of the state database, taken while the database
+keeps running, along with the state server's agent configuration,
+certificates and logs. Backups are kept in the environment's own
+storage until they are removed.
+`
+
+const backupsCommandPurpose = "create and manage state server backups"
+
+func NewBackupsCommand() cmd.Command {
+	backupscmd := &BackupsCommand{
+		SuperCommand: cmd.NewSuperCommand(cmd.SuperCommandParams{
+			Name:        "backups",
+			Doc:         backupsCommandDoc,
+			UsagePrefix: "juju",
+			Purpose:     backupsCommandPurpose,
+		}),
+	}
+	// Define each subcommand in a separate "backups_FOO.go" source file
+	// (with tests in backups_FOO_test.go) and wire in here.
+	backupscmd.Register(envcmd.Wrap(&BackupsCreateCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsListCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsDownloadCommand{}))
+	backupscmd.Register(envcmd.Wrap(&BackupsRemoveCommand{}))
+	return backupscmd
+}
+
+// backupsAPI is the subset of the backups client used by the
+// "juju backups" subcommands.
+type backupsAPI interface {
+	Create(notes string) (*params.BackupsMetadataResult, error)
+	List() ([]params.BackupsMetadataResult, error)
+	Download(id string) (io.ReadCloser, error)
+	Remove(id string) error
+	Close() error
+}
+
+var getBackupsAPI = func(envName string) (backupsAPI, error) {
+	return juju.NewBackupsClient(envName)
+}
+
+// formatBackupMetadata returns the printable form of a backup's
+// metadata.
+func formatBackupMetadata(meta params.BackupsMetadataResult) map[string]interface{} {
+	out := map[string]interface{}{
+		"id":       meta.ID,
+		"size":     meta.Size,
+		"checksum": meta.Checksum,
+		"stored":   meta.Stored,
+		"version":  meta.Version.String(),
+	}
+	for key, value := range map[string]string{
+		"checksum-format": meta.ChecksumFormat,
+		"notes":           meta.Notes,
+		"environment":     meta.Environment,
+		"machine":         meta.Machine,
+		"hostname":        meta.Hostname,
+	} {
+		if value != "" {
+			out[key] = value
+		}
+	}
+	for key, t := range map[string]time.Time{
+		"started":  meta.Started,
+		"finished": meta.Finished,
+	} {
+		if !t.IsZero() {
+			out[key] = t.UTC().Format(time.RFC3339)
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const backupsCreateCommandDoc = `
+Take a backup of the state server and keep it in the environment's
+storage. The backup's metadata is shown once it has been stored; use
+"juju backups download" to fetch the archive itself.
+
+Examples:
+  juju backups create                           (Take a backup)
+  juju backups create --notes "before upgrade"  (Take a backup, with notes)
+`
+
+// BackupsCreateCommand takes a backup of the state server.
+type BackupsCreateCommand struct {
+	envcmd.EnvCommandBase
+	out   cmd.Output
+	notes string
+}
+
+func (c *BackupsCreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Purpose: "take a backup of the state server",
+		Doc:     backupsCreateCommandDoc,
+	}
+}
+
+func (c *BackupsCreateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.StringVar(&c.notes, "notes", "", "notes to record with the backup")
+}
+
+func (c *BackupsCreateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *BackupsCreateCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	meta, err := client.Create(c.notes)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatBackupMetadata(*meta))
+}
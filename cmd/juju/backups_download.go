@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const backupsDownloadCommandDoc = `
+Download the archive of a backup kept in the environment's storage. The
+archive is saved to juju-backup-<backup id>.tgz in the current directory
+unless another file is given.
+
+Examples:
+  juju backups download 20140801-120000.<env uuid>
+  juju backups download 20140801-120000.<env uuid> --filename backup.tgz
+`
+
+// BackupsDownloadCommand downloads the archive of a backup.
+type BackupsDownloadCommand struct {
+	envcmd.EnvCommandBase
+	id       string
+	filename string
+}
+
+func (c *BackupsDownloadCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "download",
+		Args:    "<backup id>",
+		Purpose: "download the archive of a backup",
+		Doc:     backupsDownloadCommandDoc,
+	}
+}
+
+func (c *BackupsDownloadCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.filename, "filename", "", "the file to save the archive to")
+}
+
+func (c *BackupsDownloadCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no backup id specified")
+	}
+	c.id = args[0]
+	if c.filename == "" {
+		c.filename = "juju-backup-" + c.id + ".tgz"
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *BackupsDownloadCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	archive, err := client.Download(c.id)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	filename := ctx.AbsPath(c.filename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("cannot create archive file: %v", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, archive); err != nil {
+		return fmt.Errorf("cannot download backup: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cannot write archive file: %v", err)
+	}
+	fmt.Fprintf(ctx.Stdout, "%s\n", filename)
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const backupsListCommandDoc = `
+List the backups kept in the environment's storage, oldest first.
+`
+
+// BackupsListCommand lists the backups kept by the environment.
+type BackupsListCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *BackupsListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list the backups of the state server",
+		Doc:     backupsListCommandDoc,
+	}
+}
+
+func (c *BackupsListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *BackupsListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *BackupsListCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	metas, err := client.List()
+	if err != nil {
+		return err
+	}
+	out := make([]map[string]interface{}, len(metas))
+	for i, meta := range metas {
+		out[i] = formatBackupMetadata(meta)
+	}
+	return c.out.Write(ctx, out)
+}
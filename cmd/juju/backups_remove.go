@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const backupsRemoveCommandDoc = `
+Remove backups, along with their archives, from the environment's
+storage.
+`
+
+// BackupsRemoveCommand removes backups.
+type BackupsRemoveCommand struct {
+	envcmd.EnvCommandBase
+	ids []string
+}
+
+func (c *BackupsRemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<backup id> ...",
+		Purpose: "remove backups",
+		Doc:     backupsRemoveCommandDoc,
+	}
+}
+
+func (c *BackupsRemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no backup id specified")
+	}
+	c.ids = args
+	return nil
+}
+
+func (c *BackupsRemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getBackupsAPI(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var failed bool
+	for _, id := range c.ids {
+		if err := client.Remove(id); err != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot remove backup %q: %v\n", id, err)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	gc "launchpad.net/gocheck"
+	"launchpad.net/goyaml"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type BackupsCommandSuite struct {
+	coretesting.FakeJujuHomeSuite
+	mockAPI *mockBackupsAPI
+}
+
+var _ = gc.Suite(&BackupsCommandSuite{})
+
+func (s *BackupsCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockBackupsAPI{}
+	s.PatchValue(&getBackupsAPI, func(envName string) (backupsAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+var expectedBackupsCommmandNames = []string{
+	"create",
+	"download",
+	"help",
+	"list",
+	"remove",
+}
+
+func (s *BackupsCommandSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "--help")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Matches,
+		"(?s)usage: backups <command> .+"+
+			backupsCommandPurpose+".+")
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(coretesting.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		namesFound = append(namesFound, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Assert(namesFound, gc.DeepEquals, expectedBackupsCommmandNames)
+}
+
+var sampleBackup = params.BackupsMetadataResult{
+	ID:             "20140801-120000.env-uuid",
+	Notes:          "before upgrade",
+	Started:        time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC),
+	Finished:       time.Date(2014, 8, 1, 12, 1, 0, 0, time.UTC),
+	Checksum:       "checksum",
+	ChecksumFormat: "SHA-1, base64 encoded",
+	Size:           42,
+	Stored:         true,
+	Environment:    "env-uuid",
+	Machine:        "0",
+	Hostname:       "juju-0",
+	Version:        version.MustParse("1.20.0"),
+}
+
+var sampleBackupOutput = map[string]interface{}{
+	"id":              "20140801-120000.env-uuid",
+	"notes":           "before upgrade",
+	"started":         "2014-08-01T12:00:00Z",
+	"finished":        "2014-08-01T12:01:00Z",
+	"checksum":        "checksum",
+	"checksum-format": "SHA-1, base64 encoded",
+	"size":            42,
+	"stored":          true,
+	"environment":     "env-uuid",
+	"machine":         "0",
+	"hostname":        "juju-0",
+	"version":         "1.20.0",
+}
+
+func (s *BackupsCommandSuite) TestCreate(c *gc.C) {
+	s.mockAPI.metas = []params.BackupsMetadataResult{sampleBackup}
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "create", "--notes", "before upgrade")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.notes, gc.Equals, "before upgrade")
+	var out map[string]interface{}
+	err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &out)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.DeepEquals, sampleBackupOutput)
+}
+
+func (s *BackupsCommandSuite) TestCreateRejectsArgs(c *gc.C) {
+	_, err := coretesting.RunCommand(c, NewBackupsCommand(), "create", "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *BackupsCommandSuite) TestList(c *gc.C) {
+	s.mockAPI.metas = []params.BackupsMetadataResult{{
+		ID:      "backup-0",
+		Size:    1,
+		Version: version.MustParse("1.20.0"),
+	}, {
+		ID:      "backup-1",
+		Size:    2,
+		Stored:  true,
+		Version: version.MustParse("1.20.1"),
+	}}
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "list")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"- checksum: \"\"\n"+
+		"  id: backup-0\n"+
+		"  size: 1\n"+
+		"  stored: false\n"+
+		"  version: 1.20.0\n"+
+		"- checksum: \"\"\n"+
+		"  id: backup-1\n"+
+		"  size: 2\n"+
+		"  stored: true\n"+
+		"  version: 1.20.1\n")
+}
+
+func (s *BackupsCommandSuite) TestDownload(c *gc.C) {
+	s.mockAPI.archive = "archive data"
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "download", "backup-0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.downloaded, gc.Equals, "backup-0")
+	filename := ctx.AbsPath("juju-backup-backup-0.tgz")
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, filename+"\n")
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "archive data")
+}
+
+func (s *BackupsCommandSuite) TestDownloadToFile(c *gc.C) {
+	s.mockAPI.archive = "archive data"
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "download", "backup-0", "--filename", "backup.tgz")
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(ctx.AbsPath("backup.tgz"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "archive data")
+}
+
+func (s *BackupsCommandSuite) TestDownloadRequiresId(c *gc.C) {
+	_, err := coretesting.RunCommand(c, NewBackupsCommand(), "download")
+	c.Assert(err, gc.ErrorMatches, "no backup id specified")
+}
+
+func (s *BackupsCommandSuite) TestRemove(c *gc.C) {
+	s.mockAPI.metas = []params.BackupsMetadataResult{{ID: "backup-0"}, {ID: "backup-1"}}
+	ctx, err := coretesting.RunCommand(c, NewBackupsCommand(), "remove", "backup-0", "backup-2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.mockAPI.removed, gc.DeepEquals, []string{"backup-0"})
+	c.Assert(coretesting.Stderr(ctx), gc.Equals,
+		`cannot remove backup "backup-2": backup "backup-2" not found`+"\n")
+}
+
+func (s *BackupsCommandSuite) TestRemoveRequiresId(c *gc.C) {
+	_, err := coretesting.RunCommand(c, NewBackupsCommand(), "remove")
+	c.Assert(err, gc.ErrorMatches, "no backup id specified")
+}
+
+// mockBackupsAPI is shared by the "juju backups" subcommand tests.
+type mockBackupsAPI struct {
+	notes      string
+	metas      []params.BackupsMetadataResult
+	archive    string
+	downloaded string
+	removed    []string
+}
+
+func (m *mockBackupsAPI) Close() error {
+	return nil
+}
+
+func (m *mockBackupsAPI) Create(notes string) (*params.BackupsMetadataResult, error) {
+	m.notes = notes
+	return &m.metas[0], nil
+}
+
+func (m *mockBackupsAPI) List() ([]params.BackupsMetadataResult, error) {
+	return m.metas, nil
+}
+
+func (m *mockBackupsAPI) Download(id string) (io.ReadCloser, error) {
+	m.downloaded = id
+	return ioutil.NopCloser(strings.NewReader(m.archive)), nil
+}
+
+func (m *mockBackupsAPI) Remove(id string) error {
+	for _, meta := range m.metas {
+		if meta.ID == id {
+			m.removed = append(m.removed, id)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
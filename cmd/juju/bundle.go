@@ -0,0 +1,463 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/juju/names"
+	"launchpad.net/goyaml"
+
+	"github.com/juju/juju/charm"
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/api"
+)
+
+// serviceBundle holds the services and relations of a bundle file, as
+// read by "juju deploy" and written by "juju export-bundle".
+type serviceBundle struct {
+	Services  map[string]*bundleService `yaml:"services"`
+	Relations [][]string                `yaml:"relations,omitempty"`
+}
+
+// bundleService holds the description of a service in a bundle.
+type bundleService struct {
+	Charm string `yaml:"charm"`
+	// NumUnits holds the number of units the service should have.
+	// When it is nil, a principal service has one unit and a
+	// subordinate service none.
+	NumUnits    *int                   `yaml:"num_units,omitempty"`
+	Options     map[string]interface{} `yaml:"options,omitempty"`
+	Constraints string                 `yaml:"constraints,omitempty"`
+	Expose      bool                   `yaml:"expose,omitempty"`
+	Annotations map[string]string      `yaml:"annotations,omitempty"`
+	// To holds the machines the first units of the service are
+	// placed on, in the form accepted by "juju deploy --to".
+	To []string `yaml:"to,omitempty"`
+}
+
+// isBundlePath returns whether the deploy argument names a bundle file
+// rather than a charm.
+func isBundlePath(arg string) bool {
+	return strings.HasSuffix(arg, ".yaml") || strings.HasSuffix(arg, ".yml")
+}
+
+// readBundle reads and validates the bundle held in the given file.
+func readBundle(path string) (*serviceBundle, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b serviceBundle
+	if err := goyaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("cannot parse bundle %q: %v", path, err)
+	}
+	if err := b.validate(); err != nil {
+		return nil, fmt.Errorf("invalid bundle %q: %v", path, err)
+	}
+	return &b, nil
+}
+
+func (b *serviceBundle) validate() error {
+	if len(b.Services) == 0 {
+		return fmt.Errorf("no services specified")
+	}
+	for _, name := range b.serviceNames() {
+		svc := b.Services[name]
+		if !names.IsService(name) {
+			return fmt.Errorf("invalid service name %q", name)
+		}
+		if svc == nil || svc.Charm == "" {
+			return fmt.Errorf("service %q has no charm", name)
+		}
+		if _, err := charm.InferURL(svc.Charm, "fake"); err != nil {
+			return fmt.Errorf("service %q: invalid charm name %q", name, svc.Charm)
+		}
+		// Without num_units, at most one unit is deployed.
+		maxUnits := 1
+		if svc.NumUnits != nil {
+			maxUnits = *svc.NumUnits
+		}
+		if maxUnits < 0 {
+			return fmt.Errorf("service %q: num_units must not be negative", name)
+		}
+		if len(svc.To) > maxUnits {
+			return fmt.Errorf("service %q: cannot place %d units of %d", name, len(svc.To), maxUnits)
+		}
+		for _, to := range svc.To {
+			if !cmd.IsMachineOrNewContainer(to) {
+				return fmt.Errorf("service %q: invalid placement %q", name, to)
+			}
+		}
+		if _, err := constraints.Parse(svc.Constraints); err != nil {
+			return fmt.Errorf("service %q: %v", name, err)
+		}
+	}
+	for _, rel := range b.Relations {
+		if len(rel) != 2 {
+			return fmt.Errorf("relation %v must have two endpoints", rel)
+		}
+		for _, ep := range rel {
+			if _, ok := b.Services[endpointService(ep)]; !ok {
+				return fmt.Errorf("relation %v: unknown service %q", rel, endpointService(ep))
+			}
+		}
+	}
+	return nil
+}
+
+// serviceNames returns the names of the bundle's services, sorted so
+// that plans are reproducible.
+func (b *serviceBundle) serviceNames() []string {
+	var serviceNames []string
+	for name := range b.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	return serviceNames
+}
+
+// endpointService returns the service named by a relation endpoint,
+// given as "service" or "service:relation".
+func endpointService(ep string) string {
+	if i := strings.Index(ep, ":"); i >= 0 {
+		return ep[:i]
+	}
+	return ep
+}
+
+// bundleChange holds a single change needed to bring the environment
+// in line with a bundle.
+type bundleChange struct {
+	description string
+	apply       func() error
+}
+
+// bundleDeployer works out and makes the changes needed to deploy a
+// bundle.
+type bundleDeployer struct {
+	client   *api.Client
+	ctx      *cmd.Context
+	conf     *config.Config
+	repoPath string
+	// dryRun causes the changes to be reported but not made.
+	dryRun bool
+}
+
+// plan returns the changes needed to bring the environment in line
+// with the bundle. Services and relations already in the environment
+// are left alone, except that units are added, options, constraints
+// and annotations set and services exposed as the bundle requires, so
+// deploying the same bundle twice makes no changes the second time.
+func (d *bundleDeployer) plan(b *serviceBundle) ([]bundleChange, error) {
+	status, err := d.client.Status(nil)
+	if err != nil {
+		return nil, err
+	}
+	var changes []bundleChange
+	for _, name := range b.serviceNames() {
+		svcChanges, err := d.planService(name, b.Services[name], status)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, svcChanges...)
+	}
+	for _, rel := range b.Relations {
+		if relationExists(status, rel) {
+			continue
+		}
+		endpoints := rel
+		changes = append(changes, bundleChange{
+			description: fmt.Sprintf("add relation %s", strings.Join(endpoints, " ")),
+			apply: func() error {
+				_, err := d.client.AddRelation(endpoints...)
+				return err
+			},
+		})
+	}
+	return changes, nil
+}
+
+func (d *bundleDeployer) planService(name string, svc *bundleService, status *api.Status) ([]bundleChange, error) {
+	curl, err := resolveCharmURL(svc.Charm, d.client, d.conf)
+	if err != nil {
+		return nil, err
+	}
+	cons, err := constraints.Parse(svc.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	existing, deployed := status.Services[name]
+	numUnits, err := d.numUnits(name, svc, curl, existing, deployed)
+	if err != nil {
+		return nil, err
+	}
+	var changes []bundleChange
+	if deployed {
+		changes, err = d.planExistingService(name, svc, curl, cons, existing)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		repo, err := d.charmRepo(curl)
+		if err != nil {
+			return nil, err
+		}
+		configYAML := ""
+		if len(svc.Options) > 0 {
+			data, err := goyaml.Marshal(map[string]interface{}{name: svc.Options})
+			if err != nil {
+				return nil, err
+			}
+			configYAML = string(data)
+		}
+		changes = append(changes, bundleChange{
+			description: fmt.Sprintf("deploy service %s using %s", name, curl),
+			apply: func() error {
+				curl, err := addCharmViaAPI(d.client, d.ctx, curl, repo)
+				if err != nil {
+					return err
+				}
+				return d.client.ServiceDeploy(curl.String(), name, 0, configYAML, cons, "")
+			},
+		})
+		if len(svc.Annotations) > 0 {
+			changes = append(changes, d.annotateChange(name, svc.Annotations))
+		}
+	}
+
+	units := len(existing.Units)
+	for ; units < len(svc.To); units++ {
+		to := svc.To[units]
+		changes = append(changes, bundleChange{
+			description: fmt.Sprintf("add unit of %s to %s", name, to),
+			apply: func() error {
+				_, err := d.client.AddServiceUnits(name, 1, to)
+				return err
+			},
+		})
+	}
+	if n := numUnits - units; n > 0 {
+		changes = append(changes, bundleChange{
+			description: fmt.Sprintf("add %d unit(s) of %s", n, name),
+			apply: func() error {
+				_, err := d.client.AddServiceUnits(name, n, "")
+				return err
+			},
+		})
+	}
+	if svc.Expose && !existing.Exposed {
+		changes = append(changes, bundleChange{
+			description: fmt.Sprintf("expose %s", name),
+			apply: func() error {
+				return d.client.ServiceExpose(name)
+			},
+		})
+	}
+	return changes, nil
+}
+
+// numUnits returns the number of units the service should have. When
+// the bundle does not say, that depends on whether the service's charm
+// is a subordinate, as read from the environment for a deployed
+// service and from the charm repository otherwise.
+func (d *bundleDeployer) numUnits(name string, svc *bundleService, curl *charm.URL, existing api.ServiceStatus, deployed bool) (int, error) {
+	if svc.NumUnits != nil {
+		return *svc.NumUnits, nil
+	}
+	var meta *charm.Meta
+	if deployed {
+		info, err := d.client.CharmInfo(existing.Charm)
+		if err != nil {
+			return 0, err
+		}
+		meta = info.Meta
+	} else {
+		repo, err := d.charmRepo(curl)
+		if err != nil {
+			return 0, err
+		}
+		ch, err := repo.Get(curl)
+		if err != nil {
+			return 0, err
+		}
+		meta = ch.Meta()
+	}
+	if !meta.Subordinate {
+		return 1, nil
+	}
+	if len(svc.To) > 0 {
+		return 0, fmt.Errorf("service %q: cannot place units of subordinate charm %q", name, curl)
+	}
+	return 0, nil
+}
+
+// charmRepo returns the repository holding the given charm.
+func (d *bundleDeployer) charmRepo(curl *charm.URL) (charm.Repository, error) {
+	repo, err := charm.InferRepository(curl.Reference, d.repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return config.SpecializeCharmRepo(repo, d.conf), nil
+}
+
+// planExistingService returns the changes needed to bring the options
+// and constraints of a deployed service in line with the bundle.
+func (d *bundleDeployer) planExistingService(name string, svc *bundleService, curl *charm.URL, cons constraints.Value, existing api.ServiceStatus) ([]bundleChange, error) {
+	existingURL, err := charm.ParseURL(existing.Charm)
+	if err != nil {
+		return nil, err
+	}
+	if curl.Revision < 0 {
+		existingURL = existingURL.WithRevision(-1)
+	}
+	if *existingURL != *curl {
+		return nil, fmt.Errorf("service %q is deployed with charm %q, not %q", name, existing.Charm, curl)
+	}
+	var changes []bundleChange
+	if len(svc.Options) > 0 {
+		current, err := d.client.ServiceGet(name)
+		if err != nil {
+			return nil, err
+		}
+		changed := make(map[string]interface{})
+		var keys []string
+		for key, value := range svc.Options {
+			info, _ := current.Config[key].(map[string]interface{})
+			if info == nil || fmt.Sprint(info["value"]) != fmt.Sprint(value) {
+				changed[key] = value
+				keys = append(keys, key)
+			}
+		}
+		if len(changed) > 0 {
+			sort.Strings(keys)
+			data, err := goyaml.Marshal(map[string]interface{}{name: changed})
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, bundleChange{
+				description: fmt.Sprintf("set %s options %s", name, strings.Join(keys, ", ")),
+				apply: func() error {
+					return d.client.ServiceSetYAML(name, string(data))
+				},
+			})
+		}
+	}
+	if len(svc.Annotations) > 0 {
+		current, err := d.client.GetAnnotations(names.ServiceTag(name))
+		if err != nil {
+			return nil, err
+		}
+		changed := make(map[string]string)
+		for key, value := range svc.Annotations {
+			if current[key] != value {
+				changed[key] = value
+			}
+		}
+		if len(changed) > 0 {
+			changes = append(changes, d.annotateChange(name, changed))
+		}
+	}
+	if svc.Constraints != "" {
+		current, err := d.client.GetServiceConstraints(name)
+		if err != nil {
+			return nil, err
+		}
+		if current.String() != cons.String() {
+			changes = append(changes, bundleChange{
+				description: fmt.Sprintf("set %s constraints %q", name, cons),
+				apply: func() error {
+					return d.client.SetServiceConstraints(name, cons)
+				},
+			})
+		}
+	}
+	return changes, nil
+}
+
+// annotateChange returns the change setting the given annotations on
+// a service.
+func (d *bundleDeployer) annotateChange(name string, annotations map[string]string) bundleChange {
+	var keys []string
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return bundleChange{
+		description: fmt.Sprintf("set %s annotations %s", name, strings.Join(keys, ", ")),
+		apply: func() error {
+			return d.client.SetAnnotations(names.ServiceTag(name), annotations)
+		},
+	}
+}
+
+// relationExists returns whether the environment already has a
+// relation between the given endpoints.
+func relationExists(status *api.Status, endpoints []string) bool {
+	for _, rel := range status.Relations {
+		if len(rel.Endpoints) != len(endpoints) {
+			continue
+		}
+		matched := true
+		for _, ep := range endpoints {
+			if !relationHasEndpoint(rel, ep) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func relationHasEndpoint(rel api.RelationStatus, ep string) bool {
+	svcName, relName := ep, ""
+	if i := strings.Index(ep, ":"); i >= 0 {
+		svcName, relName = ep[:i], ep[i+1:]
+	}
+	for _, relEp := range rel.Endpoints {
+		if relEp.ServiceName == svcName && (relName == "" || relEp.Name == relName) {
+			return true
+		}
+	}
+	return false
+}
+
+// deployBundle prints the changes needed to deploy the bundle held in
+// the given file, and then makes them unless this is a dry run.
+func (d *bundleDeployer) deployBundle(path string) error {
+	b, err := readBundle(path)
+	if err != nil {
+		return err
+	}
+	changes, err := d.plan(b)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(d.ctx.Stdout, "no changes needed to deploy %s\n", path)
+		return nil
+	}
+	fmt.Fprintf(d.ctx.Stdout, "changes needed to deploy %s:\n", path)
+	for _, change := range changes {
+		fmt.Fprintf(d.ctx.Stdout, "  %s\n", change.description)
+	}
+	if d.dryRun {
+		return nil
+	}
+	for _, change := range changes {
+		d.ctx.Infof("%s", change.description)
+		if err := change.apply(); err != nil {
+			return fmt.Errorf("cannot %s: %v", change.description, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/charm"
+	charmtesting "github.com/juju/juju/charm/testing"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type BundleSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&BundleSuite{})
+
+const testBundle = `
+services:
+  dummy:
+    charm: local:dummy
+    num_units: 1
+    options:
+      title: bundled
+      skill-level: 9
+    constraints: mem=2G
+    expose: true
+    annotations:
+      gui-x: "100"
+  wordpress:
+    charm: local:wordpress
+    num_units: 2
+  mysql:
+    charm: local:mysql
+    num_units: 1
+relations:
+  - [wordpress:db, mysql:server]
+`
+
+func writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, gc.IsNil)
+	return path
+}
+
+func deployBundle(c *gc.C, path string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *BundleSuite) TestDeployBundle(c *gc.C) {
+	for _, name := range []string{"dummy", "wordpress", "mysql"} {
+		charmtesting.Charms.ClonedDirPath(s.SeriesPath, name)
+	}
+	path := writeBundle(c, testBundle)
+	out, err := deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "changes needed to deploy "+path+":\n"+
+		"  deploy service dummy using local:precise/dummy\n"+
+		"  set dummy annotations gui-x\n"+
+		"  add 1 unit(s) of dummy\n"+
+		"  expose dummy\n"+
+		"  deploy service mysql using local:precise/mysql\n"+
+		"  add 1 unit(s) of mysql\n"+
+		"  deploy service wordpress using local:precise/wordpress\n"+
+		"  add 2 unit(s) of wordpress\n"+
+		"  add relation wordpress:db mysql:server\n",
+	)
+
+	dummy, _ := s.AssertService(c, "dummy", charm.MustParseURL("local:precise/dummy-1"), 1, 0)
+	s.AssertService(c, "mysql", charm.MustParseURL("local:precise/mysql-1"), 1, 1)
+	s.AssertService(c, "wordpress", charm.MustParseURL("local:precise/wordpress-3"), 2, 1)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title":       "bundled",
+		"skill-level": int64(9),
+	})
+	cons, err := dummy.Constraints()
+	c.Assert(err, gc.IsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G"))
+	c.Assert(dummy.IsExposed(), jc.IsTrue)
+	annotations, err := dummy.Annotations()
+	c.Assert(err, gc.IsNil)
+	c.Assert(annotations, gc.DeepEquals, map[string]string{"gui-x": "100"})
+
+	// Deploying the bundle again makes no changes.
+	out, err = deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "no changes needed to deploy "+path+"\n")
+}
+
+func (s *BundleSuite) TestDeployBundleUpdatesServices(c *gc.C) {
+	charmtesting.Charms.ClonedDirPath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy")
+	c.Assert(err, gc.IsNil)
+	m, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	path := writeBundle(c, `
+services:
+  dummy:
+    charm: local:dummy
+    num_units: 3
+    options:
+      title: changed
+    to: ["0", "`+m.Id()+`"]
+`)
+	out, err := deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "changes needed to deploy "+path+":\n"+
+		"  set dummy options title\n"+
+		"  add unit of dummy to "+m.Id()+"\n"+
+		"  add 1 unit(s) of dummy\n",
+	)
+	dummy, _ := s.AssertService(c, "dummy", charm.MustParseURL("local:precise/dummy-1"), 3, 0)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings["title"], gc.Equals, "changed")
+	units, err := m.Units()
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *BundleSuite) TestDeployBundleDifferentCharm(c *gc.C) {
+	charmtesting.Charms.ClonedDirPath(s.SeriesPath, "dummy")
+	charmtesting.Charms.ClonedDirPath(s.SeriesPath, "mysql")
+	err := runDeploy(c, "local:dummy", "db")
+	c.Assert(err, gc.IsNil)
+	path := writeBundle(c, `
+services:
+  db:
+    charm: local:mysql
+`)
+	_, err = deployBundle(c, path)
+	c.Assert(err, gc.ErrorMatches, `service "db" is deployed with charm "local:precise/dummy-1", not "local:precise/mysql"`)
+}
+
+func (s *BundleSuite) TestDeployBundleDefaultNumUnits(c *gc.C) {
+	for _, name := range []string{"wordpress", "logging"} {
+		charmtesting.Charms.ClonedDirPath(s.SeriesPath, name)
+	}
+	path := writeBundle(c, `
+services:
+  wordpress:
+    charm: local:wordpress
+  logging:
+    charm: local:logging
+relations:
+  - [wordpress, logging]
+`)
+	out, err := deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "changes needed to deploy "+path+":\n"+
+		"  deploy service logging using local:precise/logging\n"+
+		"  deploy service wordpress using local:precise/wordpress\n"+
+		"  add 1 unit(s) of wordpress\n"+
+		"  add relation wordpress logging\n",
+	)
+	s.AssertService(c, "wordpress", charm.MustParseURL("local:precise/wordpress-3"), 1, 1)
+	s.AssertService(c, "logging", charm.MustParseURL("local:precise/logging-1"), 0, 1)
+
+	// The defaults are worked out from the deployed charms when the
+	// bundle is deployed again.
+	out, err = deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "no changes needed to deploy "+path+"\n")
+}
+
+func (s *BundleSuite) TestDeployBundleSubordinatePlacement(c *gc.C) {
+	charmtesting.Charms.ClonedDirPath(s.SeriesPath, "logging")
+	path := writeBundle(c, `services: {logging: {charm: "local:logging", to: ["0"]}}`)
+	_, err := deployBundle(c, path)
+	c.Assert(err, gc.ErrorMatches, `service "logging": cannot place units of subordinate charm "local:precise/logging"`)
+}
+
+var invalidBundleTests = []struct {
+	about  string
+	bundle string
+	err    string
+}{{
+	about:  "no services",
+	bundle: "relations: []",
+	err:    "no services specified",
+}, {
+	about:  "no charm",
+	bundle: "services: {wordpress: {num_units: 1}}",
+	err:    `service "wordpress" has no charm`,
+}, {
+	about:  "invalid service name",
+	bundle: "services: {wordpress_1: {charm: wordpress}}",
+	err:    `invalid service name "wordpress_1"`,
+}, {
+	about:  "invalid charm",
+	bundle: "services: {wordpress: {charm: craz~ness}}",
+	err:    `service "wordpress": invalid charm name "craz~ness"`,
+}, {
+	about:  "too many placements",
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, to: ["0", "1"]}}`,
+	err:    `service "wordpress": cannot place 2 units of 1`,
+}, {
+	about:  "invalid placement",
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, to: ["bad"]}}`,
+	err:    `service "wordpress": invalid placement "bad"`,
+}, {
+	about:  "invalid constraints",
+	bundle: "services: {wordpress: {charm: wordpress, constraints: bad=1}}",
+	err:    `service "wordpress": unknown constraint "bad"`,
+}, {
+	about:  "relation with one endpoint",
+	bundle: "services: {wordpress: {charm: wordpress}}\nrelations: [[wordpress]]",
+	err:    `relation \[wordpress\] must have two endpoints`,
+}, {
+	about:  "relation with unknown service",
+	bundle: "services: {wordpress: {charm: wordpress}}\nrelations: [[wordpress, mysql:server]]",
+	err:    `relation \[wordpress mysql:server\]: unknown service "mysql"`,
+}}
+
+func (s *BundleSuite) TestInvalidBundle(c *gc.C) {
+	for i, test := range invalidBundleTests {
+		c.Logf("test %d: %s", i, test.about)
+		path := writeBundle(c, test.bundle)
+		_, err := deployBundle(c, path)
+		c.Check(err, gc.ErrorMatches, `invalid bundle ".*": `+test.err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
 )
 
 // destroyPreparedEnviron destroys the environment and logs an error if it fails.
@@ -78,3 +79,22 @@ func resolveCharmURL(url string, client *api.Client, conf *config.Config) (*char
 	}
 	return &charm.URL{Reference: ref, Series: series}, nil
 }
+
+// reportDryRun writes the changes that a command run with --dry-run
+// would make, as returned by the dry-run API call with the given error.
+func reportDryRun(ctx *cmd.Context, changes []string, err error) error {
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("--dry-run is not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(ctx.Stdout, "no changes")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(ctx.Stdout, change)
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ package main
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -21,6 +23,9 @@ type DebugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	since  string
+	until  string
+	format string
 	params api.DebugLogParams
 }
 
@@ -33,6 +38,23 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+The messages shown can be restricted to those logged in a time range with
+--since and --until, given as dates (2014-09-01), as RFC3339 times
+(2014-09-01T12:00:00Z), or as durations before now (90m). The log is then
+read from the start, and the command finishes when the messages logged
+before the --until time have been shown.
+
+The messages can also be restricted to those matching a regular expression
+with --grep, written in Go's RE2 syntax (see "go doc regexp/syntax"). With --format=json, each message is shown as a JSON object on
+a line of its own, holding its entity, time, level, module, location and
+message.
+
+Examples:
+
+    juju debug-log --since 1h --until 30m
+    juju debug-log --replay --grep "cannot (start|stop)"
+    juju debug-log --format=json --include unit-mysql-0
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -58,6 +80,10 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.since, "since", "", "only show log messages logged at or after this time")
+	f.StringVar(&c.until, "until", "", "only show log messages logged before this time")
+	f.StringVar(&c.params.Grep, "grep", "", "only show log messages matching this regular expression")
+	f.StringVar(&c.format, "format", "text", "show log messages as text or json")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
@@ -69,9 +95,45 @@ func (c *DebugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	now := time.Now()
+	var err error
+	if c.since != "" {
+		if c.params.Since, err = parseLogTime(c.since, now); err != nil {
+			return err
+		}
+	}
+	if c.until != "" {
+		if c.params.Until, err = parseLogTime(c.until, now); err != nil {
+			return err
+		}
+	}
+	if c.params.Grep != "" {
+		if _, err := regexp.Compile(c.params.Grep); err != nil {
+			return fmt.Errorf("invalid --grep value %q: %v", c.params.Grep, err)
+		}
+	}
+	switch c.format {
+	case "text":
+	case "json":
+		c.params.Format = c.format
+	default:
+		return fmt.Errorf("format value %q is not one of %q, %q", c.format, "text", "json")
+	}
 	return cmd.CheckEmpty(args)
 }
 
+// parseLogTime parses a time given as parseTime accepts it, or as a
+// duration before now.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := parseTime(value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a date, an RFC3339 time or a duration", value)
+}
+
 type DebugLogAPI interface {
 	WatchDebugLog(params api.DebugLogParams) (io.ReadCloser, error)
 	Close() error
@@ -8,6 +8,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -88,6 +89,39 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--since", "2014-09-01", "--until", "2014-09-01T12:30:00Z"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Since:   time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC),
+				Until:   time.Date(2014, 9, 1, 12, 30, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid time "yesterday": expected a date, an RFC3339 time or a duration`,
+		}, {
+			args: []string{"--grep", "^worker: start"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Grep:    "^worker: start",
+			},
+		}, {
+			args:     []string{"--grep", "("},
+			errMatch: `invalid --grep value "\(": .*`,
+		}, {
+			args: []string{"--format", "text"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+			},
+		}, {
+			args: []string{"--format", "json"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Format:  "json",
+			},
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format value "yaml" is not one of "text", "json"`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -102,6 +136,18 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 	}
 }
 
+func (s *DebugLogSuite) TestRelativeTimes(c *gc.C) {
+	command := &DebugLogCommand{}
+	before := time.Now()
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--since", "1h", "--until", "30m"})
+	c.Assert(err, gc.IsNil)
+	after := time.Now()
+	since, until := command.params.Since, command.params.Until
+	c.Assert(since.Before(before.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(since.After(after.Add(-time.Hour)), jc.IsFalse)
+	c.Assert(until.Sub(since), gc.Equals, 30*time.Minute)
+}
+
 func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	fake := &fakeDebugLogAPI{}
 	s.PatchValue(&getDebugLogAPI, func(envName string) (DebugLogAPI, error) {
@@ -27,12 +27,14 @@ type DeployCommand struct {
 	envcmd.EnvCommandBase
 	UnitCommandBase
 	CharmName    string
+	BundlePath   string
 	ServiceName  string
 	Config       cmd.FileVar
 	Constraints  constraints.Value
 	Networks     string
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
+	DryRun       bool
 }
 
 const deployDoc = `
@@ -82,6 +84,44 @@ explicitly ask Juju to create full containers and not overlays by specifying
 the following in the provider configuration:
   lxc-clone-aufs: false
 
+A set of related services can be deployed at once by giving the path of a
+bundle file, ending in .yaml or .yml, in place of the charm name. A bundle
+describes each service's charm, number of units, options, constraints,
+annotations, placement and whether it is exposed, and the relations between
+services:
+
+  services:
+    wordpress:
+      charm: wordpress
+      num_units: 2
+      options:
+        blog-title: My Blog
+      expose: true
+    mysql:
+      charm: mysql
+      num_units: 1
+      constraints: mem=4G
+      to: ["lxc:0"]
+  relations:
+    - [wordpress, mysql]
+
+A service without num_units gets one unit if its charm is a principal, and
+none if it is a subordinate, whose units come with those of the services it
+is related to.
+
+The changes needed to deploy the bundle are printed before they are made;
+with --dry-run, they are printed but not made.
+Services and relations already in the environment are kept, so deploying a
+bundle again only makes the changes needed to bring the environment in line
+with it: adding units, setting options, constraints and annotations,
+exposing services and adding relations. Units are never removed.
+
+With --dry-run, deploy reports the changes it would make, as checked by the
+API server, without making them. Errors the deployment would fail with, such
+as invalid config options, constraints or placement, are reported instead.
+The charm is not added to the environment, so its config options are checked
+by deploy itself against the charm read from the repository.
+
 Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
@@ -90,6 +130,12 @@ Examples:
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
 
+   juju deploy ./wordpress-bundle.yaml
+   (deploy the services and relations described in wordpress-bundle.yaml)
+
+   juju deploy mysql -n 3 --dry-run
+   (report the changes deploying 3 units of mysql would make)
+
    juju deploy mysql --networks=storage,mynet --constraints networks=^logging,db
    (deploy mysql on machines with "storage", "mynet" and "db" networks,
     but not on machines with "logging" network, also configure "storage" and
@@ -112,7 +158,7 @@ See Also:
 func (c *DeployCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "deploy",
-		Args:    "<charm name> [<service name>]",
+		Args:    "<charm name> [<service name>] | <bundle file>",
 		Purpose: "deploy a new service",
 		Doc:     deployDoc,
 	}
@@ -127,9 +173,18 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set service constraints")
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
+	f.BoolVar(&c.DryRun, "dry-run", false, "report the changes that would be made, without making them")
 }
 
 func (c *DeployCommand) Init(args []string) error {
+	if len(args) > 0 && isBundlePath(args[0]) {
+		if c.NumUnits != 1 || c.ToMachineSpec != "" || c.Config.Path != "" ||
+			!constraints.IsEmpty(&c.Constraints) || c.Networks != "" {
+			return errors.New("cannot use -n, --to, --config, --constraints or --networks with a bundle")
+		}
+		c.BundlePath = args[0]
+		return cmd.CheckEmpty(args[1:])
+	}
 	switch len(args) {
 	case 2:
 		if !names.IsService(args[1]) {
@@ -166,6 +221,17 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.BundlePath != "" {
+		d := &bundleDeployer{
+			client:   client,
+			ctx:      ctx,
+			conf:     conf,
+			repoPath: ctx.AbsPath(c.RepoPath),
+			dryRun:   c.DryRun,
+		}
+		return d.deployBundle(ctx.AbsPath(c.BundlePath))
+	}
+
 	curl, err := resolveCharmURL(c.CharmName, client, conf)
 	if err != nil {
 		return err
@@ -178,6 +244,10 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 
 	repo = config.SpecializeCharmRepo(repo, conf)
 
+	if c.DryRun {
+		return c.dryRun(ctx, client, curl, repo)
+	}
+
 	curl, err = addCharmViaAPI(client, ctx, curl, repo)
 	if err != nil {
 		return err
@@ -207,16 +277,9 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	numUnits := c.NumUnits
-	if charmInfo.Meta.Subordinate {
-		if !constraints.IsEmpty(&c.Constraints) {
-			return errors.New("cannot use --constraints with subordinate service")
-		}
-		if numUnits == 1 && c.ToMachineSpec == "" {
-			numUnits = 0
-		} else {
-			return errors.New("cannot use --num-units or --to with subordinate service")
-		}
+	numUnits, err := c.numUnits(charmInfo.Meta)
+	if err != nil {
+		return err
 	}
 	serviceName := c.ServiceName
 	if serviceName == "" {
@@ -254,6 +317,67 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	return err
 }
 
+// numUnits returns the number of units to deploy of a service using
+// the charm with the given metadata.
+func (c *DeployCommand) numUnits(meta *charm.Meta) (int, error) {
+	if !meta.Subordinate {
+		return c.NumUnits, nil
+	}
+	if !constraints.IsEmpty(&c.Constraints) {
+		return 0, errors.New("cannot use --constraints with subordinate service")
+	}
+	if c.NumUnits != 1 || c.ToMachineSpec != "" {
+		return 0, errors.New("cannot use --num-units or --to with subordinate service")
+	}
+	return 0, nil
+}
+
+// dryRun reports the changes deploying the charm would make, as
+// checked by the API server, without making them. The charm is read
+// from the repository rather than added to the environment.
+func (c *DeployCommand) dryRun(ctx *cmd.Context, client *api.Client, curl *charm.URL, repo charm.Repository) error {
+	if curl.Revision < 0 {
+		latest, err := charm.Latest(repo, curl)
+		if err != nil {
+			return err
+		}
+		curl = curl.WithRevision(latest)
+	}
+	ch, err := repo.Get(curl)
+	if err != nil {
+		return err
+	}
+	numUnits, err := c.numUnits(ch.Meta())
+	if err != nil {
+		return err
+	}
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = ch.Meta().Name
+	}
+	var configYAML []byte
+	if c.Config.Path != "" {
+		configYAML, err = c.Config.Read(ctx)
+		if err != nil {
+			return err
+		}
+		// The API server can only check the settings for charms
+		// that have been added to the environment.
+		if _, err := ch.Config().ParseSettingsYAML(configYAML, serviceName); err != nil {
+			return err
+		}
+	}
+	changes, err := client.ServiceDeployDryRun(
+		curl.String(),
+		serviceName,
+		numUnits,
+		string(configYAML),
+		c.Constraints,
+		c.ToMachineSpec,
+	)
+	return reportDryRun(ctx, changes, err)
+}
+
 // addCharmViaAPI calls the appropriate client API calls to add the
 // given charm URL to state. Also displays the charm URL of the added
 // charm on stdout.
@@ -60,6 +60,15 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"bundle.yaml", "burble1"},
+		err:  `unrecognized args: \["burble1"\]`,
+	}, {
+		args: []string{"bundle.yaml", "-n", "2"},
+		err:  `cannot use -n, --to, --config, --constraints or --networks with a bundle`,
+	}, {
+		args: []string{"bundle.yml", "--to", "0"},
+		err:  `cannot use -n, --to, --config, --constraints or --networks with a bundle`,
 	},
 }
 
@@ -258,7 +267,7 @@ func (s *DeploySuite) TestForceMachineNewContainer(c *gc.C) {
 func (s *DeploySuite) TestForceMachineNotFound(c *gc.C) {
 	charmtesting.Charms.BundlePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "--to", "42", "local:dummy", "portlandia")
-	c.Assert(err, gc.ErrorMatches, `cannot assign unit "portlandia/0" to machine: machine 42 not found`)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit to machine: machine 42 not found`)
 	_, err = s.State.Service("dummy")
 	c.Assert(err, gc.ErrorMatches, `service "dummy" not found`)
 }
@@ -272,3 +281,26 @@ func (s *DeploySuite) TestForceMachineSubordinate(c *gc.C) {
 	_, err = s.State.Service("dummy")
 	c.Assert(err, gc.ErrorMatches, `service "dummy" not found`)
 }
+
+func (s *DeploySuite) TestDryRun(c *gc.C) {
+	charmtesting.Charms.BundlePath(s.SeriesPath, "dummy")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "local:dummy", "-n", "2", "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"add charm local:precise/dummy-1\n"+
+		"add service dummy using charm local:precise/dummy-1\n"+
+		"add 2 unit(s) of service dummy\n")
+	_, err = s.State.Service("dummy")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Charm(charm.MustParseURL("local:precise/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeploySuite) TestDryRunChecksConfig(c *gc.C) {
+	charmtesting.Charms.BundlePath(s.SeriesPath, "dummy")
+	path := setupConfigFile(c, c.MkDir())
+	err := runDeploy(c, "local:dummy", "other-service", "--config", path, "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `no settings found for "other-service"`)
+	_, err = s.State.Charm(charm.MustParseURL("local:precise/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
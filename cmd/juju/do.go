@@ -0,0 +1,206 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+	"launchpad.net/goyaml"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+const doCommandDoc = `
+Queue an action for execution on a unit, or on every unit of a service.
+
+The action parameters are given as key=value pairs. Values are parsed as
+YAML scalars, so numbers and booleans keep their type; keys containing
+dots are expanded into nested parameters.
+
+The ids of the queued actions are printed; use "juju action fetch <id>"
+to retrieve the outcome of an action once it has run.
+
+An action queued on a service is tracked as a whole by a service action,
+whose id is printed along with those of the unit actions; use
+"juju action progress <id>" to see how far it has got on each unit. With
+--include-future, the action is also queued on every unit added to the
+service later.
+
+An action that runs for longer than its timeout is stopped, and reported
+with a "timeout" status. The timeout defaults to the one declared for the
+action by the charm, if any, and may be overridden with --timeout.
+
+Examples:
+  juju do mysql/0 backup                  (Queue "backup" on unit mysql/0)
+  juju do mysql backup outfile=out.tar    (Queue "backup" on every mysql unit)
+  juju do mysql/0 snapshot disk.size=10   (Pass {"disk": {"size": 10}})
+  juju do --include-future mysql tune     (Queue "tune" on current and future mysql units)
+  juju do --timeout 10m mysql/0 backup    (Stop the backup if it takes over 10 minutes)
+`
+
+// DoCommand queues an action on a unit or service.
+type DoCommand struct {
+	envcmd.EnvCommandBase
+	out           cmd.Output
+	receiver      string
+	actionName    string
+	params        map[string]interface{}
+	includeFuture bool
+	timeout       time.Duration
+}
+
+func (c *DoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "do",
+		Args:    "<unit | service> <action> [key=value ...]",
+		Purpose: "queue an action for execution",
+		Doc:     doCommandDoc,
+	}
+}
+
+func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.BoolVar(&c.includeFuture, "include-future", false, "also queue the action on units added to the service later")
+	f.DurationVar(&c.timeout, "timeout", 0, "stop the action if it runs for longer than this")
+}
+
+func (c *DoCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no unit or service specified")
+	}
+	if c.timeout < 0 {
+		return fmt.Errorf("invalid timeout %v", c.timeout)
+	}
+	receiver := args[0]
+	switch {
+	case names.IsUnit(receiver):
+		if c.includeFuture {
+			return fmt.Errorf("--include-future can only be used with a service")
+		}
+		c.receiver = names.UnitTag(receiver)
+	case names.IsService(receiver):
+		c.receiver = names.ServiceTag(receiver)
+	default:
+		return fmt.Errorf("invalid unit or service name %q", receiver)
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("no action specified")
+	}
+	c.actionName = args[1]
+	params, err := parseActionParams(args[2:])
+	if err != nil {
+		return err
+	}
+	c.params = params
+	return nil
+}
+
+// parseActionParams converts a list of key=value pairs into an action
+// parameter map. Values are parsed as YAML scalars, and dotted keys
+// produce nested maps.
+func parseActionParams(args []string) (map[string]interface{}, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]interface{})
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("expected key=value parameter, got %q", arg)
+		}
+		var value interface{}
+		if err := goyaml.Unmarshal([]byte(parts[1]), &value); err != nil {
+			return nil, fmt.Errorf("invalid value for parameter %q: %v", parts[0], err)
+		}
+		keys := strings.Split(parts[0], ".")
+		m := params
+		for _, key := range keys[:len(keys)-1] {
+			if key == "" {
+				return nil, fmt.Errorf("invalid parameter name %q", parts[0])
+			}
+			child, ok := m[key].(map[string]interface{})
+			if !ok {
+				if _, exists := m[key]; exists {
+					return nil, fmt.Errorf("parameter %q conflicts with %q", parts[0], key)
+				}
+				child = make(map[string]interface{})
+				m[key] = child
+			}
+			m = child
+		}
+		last := keys[len(keys)-1]
+		if last == "" {
+			return nil, fmt.Errorf("invalid parameter name %q", parts[0])
+		}
+		if _, exists := m[last]; exists {
+			return nil, fmt.Errorf("parameter %q specified more than once", parts[0])
+		}
+		m[last] = value
+	}
+	return params, nil
+}
+
+type doAPI interface {
+	Enqueue(receiver, name string, params map[string]interface{}, timeout time.Duration) ([]params.Action, error)
+	EnqueueService(service, name string, params map[string]interface{}, timeout time.Duration, includeFuture bool) (string, []params.Action, error)
+	Close() error
+}
+
+var getDoAPI = func(c *DoCommand) (doAPI, error) {
+	return juju.NewActionsClient(c.EnvName)
+}
+
+func (c *DoCommand) Run(ctx *cmd.Context) error {
+	client, err := getDoAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if kind, service, err := names.ParseTag(c.receiver, names.ServiceTagKind); err == nil && kind == names.ServiceTagKind {
+		id, queued, err := client.EnqueueService(c.receiver, c.actionName, c.params, c.timeout, c.includeFuture)
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctx, map[string]interface{}{
+			"id":      id,
+			"service": service,
+			"actions": formatQueuedActions(queued),
+		})
+	}
+	queued, err := client.Enqueue(c.receiver, c.actionName, c.params, c.timeout)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatQueuedActions(queued))
+}
+
+// formatQueuedActions returns the printable form of a list of queued
+// actions.
+func formatQueuedActions(queued []params.Action) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(queued))
+	for i, action := range queued {
+		out[i] = map[string]interface{}{
+			"id":   action.Id,
+			"unit": receiverName(action.Receiver),
+		}
+	}
+	return out
+}
+
+// receiverName returns the unit name for the given unit tag, or the tag
+// itself if it cannot be parsed.
+func receiverName(tag string) string {
+	_, name, err := names.ParseTag(tag, names.UnitTagKind)
+	if err != nil {
+		return tag
+	}
+	return name
+}
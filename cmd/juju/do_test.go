@@ -0,0 +1,195 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type DoCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockDoAPI
+}
+
+var _ = gc.Suite(&DoCommandSuite{})
+
+func (s *DoCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockDoAPI{}
+	s.PatchValue(&getDoAPI, func(c *DoCommand) (doAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newDoCommand() cmd.Command {
+	return envcmd.Wrap(&DoCommand{})
+}
+
+func (s *DoCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		receiver    string
+		actionName  string
+		params      map[string]interface{}
+		errorString string
+	}{{
+		errorString: "no unit or service specified",
+	}, {
+		args:        []string{"mysql/0"},
+		errorString: "no action specified",
+	}, {
+		args:        []string{"mysql/x", "backup"},
+		errorString: `invalid unit or service name "mysql/x"`,
+	}, {
+		args:       []string{"mysql/0", "backup"},
+		receiver:   "unit-mysql-0",
+		actionName: "backup",
+	}, {
+		args:       []string{"mysql", "backup", "outfile=out.tar", "compress=true", "level=9"},
+		receiver:   "service-mysql",
+		actionName: "backup",
+		params: map[string]interface{}{
+			"outfile":  "out.tar",
+			"compress": true,
+			"level":    9,
+		},
+	}, {
+		args:       []string{"mysql", "snapshot", "disk.size=10", "disk.name=sda"},
+		receiver:   "service-mysql",
+		actionName: "snapshot",
+		params: map[string]interface{}{
+			"disk": map[string]interface{}{"size": 10, "name": "sda"},
+		},
+	}, {
+		args:        []string{"mysql", "backup", "outfile"},
+		errorString: `expected key=value parameter, got "outfile"`,
+	}, {
+		args:        []string{"mysql", "backup", "a=1", "a=2"},
+		errorString: `parameter "a" specified more than once`,
+	}, {
+		args:        []string{"mysql", "backup", "a=1", "a.b=2"},
+		errorString: `parameter "a.b" conflicts with "a"`,
+	}, {
+		args:        []string{"mysql", "backup", "a.=1"},
+		errorString: `invalid parameter name "a."`,
+	}} {
+		c.Logf("test %d", i)
+		doCmd := &DoCommand{}
+		err := testing.InitCommand(doCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(doCmd.receiver, gc.Equals, test.receiver)
+			c.Check(doCmd.actionName, gc.Equals, test.actionName)
+			c.Check(doCmd.params, gc.DeepEquals, test.params)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *DoCommandSuite) TestInitIncludeFuture(c *gc.C) {
+	doCmd := &DoCommand{}
+	err := testing.InitCommand(doCmd, []string{"--include-future", "mysql", "backup"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(doCmd.includeFuture, gc.Equals, true)
+
+	err = testing.InitCommand(&DoCommand{}, []string{"--include-future", "mysql/0", "backup"})
+	c.Assert(err, gc.ErrorMatches, "--include-future can only be used with a service")
+}
+
+func (s *DoCommandSuite) TestInitTimeout(c *gc.C) {
+	doCmd := &DoCommand{}
+	err := testing.InitCommand(doCmd, []string{"--timeout", "90s", "mysql/0", "backup"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(doCmd.timeout, gc.Equals, 90*time.Second)
+
+	err = testing.InitCommand(&DoCommand{}, []string{"--timeout", "-1m", "mysql/0", "backup"})
+	c.Assert(err, gc.ErrorMatches, "invalid timeout -1m0s")
+}
+
+func (s *DoCommandSuite) TestRun(c *gc.C) {
+	s.mockAPI.queued = []params.Action{
+		{Id: "u#mysql/0#a#0", Receiver: "unit-mysql-0"},
+	}
+	context, err := testing.RunCommand(c, newDoCommand(), "--timeout", "10m", "mysql/0", "backup", "outfile=out.tar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.receiver, gc.Equals, "unit-mysql-0")
+	c.Assert(s.mockAPI.timeout, gc.Equals, 10*time.Minute)
+	c.Assert(s.mockAPI.name, gc.Equals, "backup")
+	c.Assert(s.mockAPI.params, gc.DeepEquals, map[string]interface{}{"outfile": "out.tar"})
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"- id: u#mysql/0#a#0\n"+
+		"  unit: mysql/0\n")
+}
+
+func (s *DoCommandSuite) TestRunService(c *gc.C) {
+	s.mockAPI.serviceActionId = "s#mysql#a#0"
+	s.mockAPI.queued = []params.Action{
+		{Id: "u#mysql/0#a#0", Receiver: "unit-mysql-0"},
+		{Id: "u#mysql/1#a#0", Receiver: "unit-mysql-1"},
+	}
+	context, err := testing.RunCommand(c, newDoCommand(), "--include-future", "mysql", "backup", "outfile=out.tar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.receiver, gc.Equals, "service-mysql")
+	c.Assert(s.mockAPI.name, gc.Equals, "backup")
+	c.Assert(s.mockAPI.params, gc.DeepEquals, map[string]interface{}{"outfile": "out.tar"})
+	c.Assert(s.mockAPI.includeFuture, gc.Equals, true)
+	c.Assert(testing.Stdout(context), gc.Equals, ""+
+		"actions:\n"+
+		"- id: u#mysql/0#a#0\n"+
+		"  unit: mysql/0\n"+
+		"- id: u#mysql/1#a#0\n"+
+		"  unit: mysql/1\n"+
+		"id: s#mysql#a#0\n"+
+		"service: mysql\n")
+}
+
+func (s *DoCommandSuite) TestRunError(c *gc.C) {
+	s.mockAPI.failMessage = "no such unit"
+	_, err := testing.RunCommand(c, newDoCommand(), "mysql/0", "backup")
+	c.Assert(err, gc.ErrorMatches, "no such unit")
+}
+
+type mockDoAPI struct {
+	failMessage     string
+	serviceActionId string
+	queued          []params.Action
+	receiver        string
+	name            string
+	params          map[string]interface{}
+	timeout         time.Duration
+	includeFuture   bool
+}
+
+func (m *mockDoAPI) Close() error {
+	return nil
+}
+
+func (m *mockDoAPI) Enqueue(receiver, name string, params map[string]interface{}, timeout time.Duration) ([]params.Action, error) {
+	m.receiver = receiver
+	m.timeout = timeout
+	m.name = name
+	m.params = params
+	if m.failMessage != "" {
+		return nil, errors.New(m.failMessage)
+	}
+	return m.queued, nil
+}
+
+func (m *mockDoAPI) EnqueueService(service, name string, params map[string]interface{}, timeout time.Duration, includeFuture bool) (string, []params.Action, error) {
+	m.includeFuture = includeFuture
+	queued, err := m.Enqueue(service, name, params, timeout)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.serviceActionId, queued, nil
+}
@@ -5,7 +5,9 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd"
@@ -23,6 +25,15 @@ type EnsureAvailabilityCommand struct {
 	// If specified, these constraints will be merged with those
 	// already in the environment when creating new machines.
 	Constraints constraints.Value
+	// If specified, these placement directives are used, in order,
+	// for the state servers needed.
+	Placement []string
+	// If specified, these state server machines are demoted, and
+	// replaced.
+	Demote []string
+
+	placement string
+	demote    string
 }
 
 const ensureAvailabilityDoc = `
@@ -45,6 +56,18 @@ Examples:
      Ensure that 7 state servers are available, with newly created
      state server machines having the default series, and at least
      8GB RAM.
+ juju ensure-availability -n 3 --to 1,2
+     Ensure that 3 state servers are available, converting the
+     existing machines 1 and 2 into state servers if more are needed.
+     Placement directives other than machine ids are passed to the
+     provider for new machines, as they are by "juju add-machine".
+ juju ensure-availability --demote 1
+     Demote state server machine 1 so that it no longer has a vote,
+     and replace it. Demoting a voting state server leaves an even
+     number of voters, so the demoted machines are always replaced by
+     the same command, even if some could not be demoted. Once its vote
+     has been removed, a later call to ensure-availability removes it
+     from the state servers, after which it may be destroyed.
 `
 
 func (c *EnsureAvailabilityCommand) Info() *cmd.Info {
@@ -59,22 +82,62 @@ func (c *EnsureAvailabilityCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumStateServers, "n", 0, "number of state servers to make available")
 	f.StringVar(&c.Series, "series", "", "the charm series")
 	f.Var(constraints.ConstraintsValue{&c.Constraints}, "constraints", "additional machine constraints")
+	f.StringVar(&c.placement, "to", "", "comma-separated placement directives for the state servers needed")
+	f.StringVar(&c.demote, "demote", "", "comma-separated ids of state server machines to demote; they are replaced by this same command")
 }
 
 func (c *EnsureAvailabilityCommand) Init(args []string) error {
 	if c.NumStateServers < 0 || (c.NumStateServers%2 != 1 && c.NumStateServers != 0) {
 		return fmt.Errorf("must specify a number of state servers odd and non-negative")
 	}
+	if c.placement != "" {
+		c.Placement = strings.Split(c.placement, ",")
+		for _, directive := range c.Placement {
+			if directive == "" {
+				return fmt.Errorf("empty placement directive in %q", c.placement)
+			}
+		}
+	}
+	if c.demote != "" {
+		c.Demote = strings.Split(c.demote, ",")
+		for _, id := range c.Demote {
+			if !names.IsMachine(id) {
+				return fmt.Errorf("invalid machine id %q", id)
+			}
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
-// Run connects to the environment specified on the command line
-// and calls EnsureAvailability.
+// Run connects to the environment specified on the command line,
+// demotes any state servers requested, and calls EnsureAvailability.
+// EnsureAvailability is called even if some machines could not be
+// demoted, so that those that were are replaced.
 func (c *EnsureAvailabilityCommand) Run(_ *cmd.Context) error {
 	client, err := juju.NewAPIClientFromName(c.EnvName)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	return client.EnsureAvailability(c.NumStateServers, c.Constraints, c.Series)
+	var demoteErr error
+	if len(c.Demote) > 0 {
+		tags := make([]string, len(c.Demote))
+		for i, id := range c.Demote {
+			tags[i] = names.MachineTag(id)
+		}
+		results, err := client.DemoteStateServers(tags...)
+		if err != nil {
+			return err
+		}
+		for i, result := range results {
+			if result.Error != nil {
+				demoteErr = fmt.Errorf("cannot demote machine %s: %v", c.Demote[i], result.Error)
+				break
+			}
+		}
+	}
+	if err := client.EnsureAvailability(c.NumStateServers, c.Constraints, c.Series, c.Placement); err != nil {
+		return err
+	}
+	return demoteErr
 }
@@ -155,3 +155,70 @@ func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityDefaultsTo3(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(machines, gc.HasLen, 3)
 }
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityPlacement(c *gc.C) {
+	_, err := s.State.AddMachine("precise", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	err = runEnsureAvailability(c, "-n", "3", "--to", "1")
+	c.Assert(err, gc.IsNil)
+	m, err := s.State.Machine("1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m.IsManager(), jc.IsTrue)
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "1", "2"})
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityDemote(c *gc.C) {
+	err := runEnsureAvailability(c, "-n", "3")
+	c.Assert(err, gc.IsNil)
+	for _, id := range []string{"1", "2"} {
+		m, err := s.BackingState.Machine(id)
+		c.Assert(err, gc.IsNil)
+		pinger, err := m.SetAgentAlive()
+		c.Assert(err, gc.IsNil)
+		defer pinger.Kill()
+		s.BackingState.StartSync()
+		err = m.WaitAgentAlive(coretesting.LongWait)
+		c.Assert(err, gc.IsNil)
+	}
+
+	// Machine 2 never had a vote, so it is replaced and removed from
+	// the state servers at once.
+	err = runEnsureAvailability(c, "--demote", "2")
+	c.Assert(err, gc.IsNil)
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.MachineIds, jc.SameContents, []string{"0", "1", "3"})
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "1", "3"})
+	m, err := s.State.Machine("2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m.IsManager(), jc.IsFalse)
+
+	err = runEnsureAvailability(c, "--demote", "42")
+	c.Assert(err, gc.ErrorMatches, "cannot demote machine 42: machine 42 not found")
+
+	m, err = s.BackingState.Machine("3")
+	c.Assert(err, gc.IsNil)
+	pinger, err := m.SetAgentAlive()
+	c.Assert(err, gc.IsNil)
+	defer pinger.Kill()
+	s.BackingState.StartSync()
+	err = m.WaitAgentAlive(coretesting.LongWait)
+	c.Assert(err, gc.IsNil)
+
+	// Demoting machine 1 leaves two voters, so it is replaced even
+	// though machine 42 cannot be demoted.
+	err = runEnsureAvailability(c, "--demote", "1,42")
+	c.Assert(err, gc.ErrorMatches, "cannot demote machine 42: machine 42 not found")
+	info, err = s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "3", "4"})
+}
+
+func (s *EnsureAvailabilitySuite) TestEnsureAvailabilityInitErrors(c *gc.C) {
+	err := runEnsureAvailability(c, "--to", "1,,2")
+	c.Assert(err, gc.ErrorMatches, `empty placement directive in "1,,2"`)
+	err = runEnsureAvailability(c, "--demote", "foo")
+	c.Assert(err, gc.ErrorMatches, `invalid machine id "foo"`)
+}
@@ -0,0 +1,89 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+const exportBundleDoc = `
+Write the services of the environment and the relations between them as
+a bundle, which "juju deploy" can use to reproduce them in another
+environment. The bundle records each service's charm, number of units,
+constraints, annotations and whether it is exposed, and the config
+options set to values other than the charm defaults. Units are not
+placed on particular machines, as those are specific to the environment.
+
+Examples:
+
+    juju export-bundle
+    juju export-bundle --output production.yaml
+`
+
+// ExportBundleCommand writes the services and relations of the
+// environment as a bundle.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "write the services and relations of the environment as a bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+	})
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := juju.NewAPIClientFromName(c.EnvName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ExportBundle()
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, exportedBundle(results))
+}
+
+// exportedBundle returns the bundle describing the exported services
+// and relations.
+func exportedBundle(results *params.ExportBundleResults) *serviceBundle {
+	b := &serviceBundle{
+		Services:  make(map[string]*bundleService),
+		Relations: results.Relations,
+	}
+	for name, svc := range results.Services {
+		// The number of units is always given, as a principal
+		// service without num_units would be deployed with one.
+		numUnits := svc.NumUnits
+		b.Services[name] = &bundleService{
+			Charm:       svc.Charm,
+			NumUnits:    &numUnits,
+			Options:     svc.Options,
+			Constraints: svc.Constraints.String(),
+			Expose:      svc.Exposed,
+			Annotations: svc.Annotations,
+		}
+	}
+	return b
+}
@@ -0,0 +1,96 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"path/filepath"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/charm"
+	charmtesting "github.com/juju/juju/charm/testing"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func runExportBundle(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *ExportBundleSuite) TestExportBundle(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := dummy.UpdateConfigSettings(charm.Settings{"title": "My Title", "username": "bob"})
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetExposed()
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetAnnotations(map[string]string{"gui-x": "100"})
+	c.Assert(err, gc.IsNil)
+	_, err = dummy.AddUnit()
+	c.Assert(err, gc.IsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints([]string{"wordpress", "mysql"})
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, gc.IsNil)
+
+	out, err := runExportBundle(c)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, `
+services:
+  dummy:
+    charm: local:quantal/dummy-1
+    num_units: 1
+    options:
+      username: bob
+    constraints: mem=2048M
+    expose: true
+    annotations:
+      gui-x: "100"
+  mysql:
+    charm: local:quantal/mysql-1
+    num_units: 0
+  wordpress:
+    charm: local:quantal/wordpress-3
+    num_units: 0
+relations:
+- - mysql:server
+  - wordpress:db
+`[1:])
+}
+
+func (s *ExportBundleSuite) TestExportedBundleDeploysWithoutChanges(c *gc.C) {
+	for _, name := range []string{"dummy", "wordpress", "mysql"} {
+		charmtesting.Charms.ClonedDirPath(s.SeriesPath, name)
+	}
+	path := writeBundle(c, testBundle)
+	_, err := deployBundle(c, path)
+	c.Assert(err, gc.IsNil)
+
+	exported := filepath.Join(c.MkDir(), "exported.yaml")
+	_, err = runExportBundle(c, "--output", exported)
+	c.Assert(err, gc.IsNil)
+	out, err := deployBundle(c, exported)
+	c.Assert(err, gc.IsNil)
+	c.Assert(out, gc.Equals, "no changes needed to deploy "+exported+"\n")
+}
+
+func (s *ExportBundleSuite) TestInitErrors(c *gc.C) {
+	err := coretesting.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
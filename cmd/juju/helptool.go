@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/charm"
 	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/worker/uniter/jujuc"
 )
 
@@ -52,6 +53,33 @@ func (dummyHookContext) OwnerTag() string {
 	return ""
 }
 
+func (dummyHookContext) WorkloadStatus() (params.Status, string, error) {
+	return params.StatusUnknown, "", nil
+}
+func (dummyHookContext) SetWorkloadStatus(status params.Status, info string) error {
+	return nil
+}
+
+func (dummyHookContext) IsLeader() (bool, error) {
+	return false, nil
+}
+func (dummyHookContext) LeaderSettings() (map[string]string, error) {
+	return nil, nil
+}
+func (dummyHookContext) WriteLeaderSettings(settings map[string]string) error {
+	return nil
+}
+
+func (dummyHookContext) ActionParams() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("not running an action")
+}
+func (dummyHookContext) UpdateActionResults(keys []string, value string) error {
+	return fmt.Errorf("not running an action")
+}
+func (dummyHookContext) SetActionFailed(message string) error {
+	return fmt.Errorf("not running an action")
+}
+
 type HelpToolCommand struct {
 	cmd.CommandBase
 	tool string
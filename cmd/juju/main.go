@@ -126,6 +126,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&GetEnvironmentCommand{}))
 	r.Register(wrapEnvCommand(&SetEnvironmentCommand{}))
 	r.Register(wrapEnvCommand(&UnsetEnvironmentCommand{}))
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
 	r.Register(wrapEnvCommand(&ExposeCommand{}))
 	r.Register(wrapEnvCommand(&SyncToolsCommand{}))
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
@@ -144,6 +145,16 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage users and access
 	r.Register(NewUserCommand())
 
+	// Queue and inspect actions.
+	r.Register(wrapEnvCommand(&DoCommand{}))
+	r.Register(NewActionCommand())
+
+	// Manage state server backups.
+	r.Register(NewBackupsCommand())
+
+	// Show the changes made by users.
+	r.Register(wrapEnvCommand(&AuditLogCommand{}))
+
 	// Manage state server availability.
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
 
@@ -187,12 +187,15 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 }
 
 var commandNames = []string{
+	"action",
 	"add-machine",
 	"add-relation",
 	"add-unit",
 	"api-endpoints",
+	"audit-log",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
+	"backups",
 	"bootstrap",
 	"debug-hooks",
 	"debug-log",
@@ -202,9 +205,11 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"do",
 	"ensure-availability",
 	"env", // alias for switch
 	"expose",
+	"export-bundle",
 	"generate-config", // alias for init
 	"get",
 	"get-constraints",
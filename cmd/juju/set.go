@@ -13,6 +13,8 @@ import (
 	"github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
 )
 
 // SetCommand updates the configuration of a service.
@@ -21,6 +23,7 @@ type SetCommand struct {
 	ServiceName     string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	DryRun          bool
 }
 
 const setDoc = `
@@ -40,6 +43,7 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.BoolVar(&c.DryRun, "dry-run", false, "report the options that would be changed, without changing them")
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -66,6 +70,9 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.DryRun {
+		return c.dryRun(ctx, api)
+	}
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
@@ -78,6 +85,24 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	return api.ServiceSet(c.ServiceName, c.SettingsStrings)
 }
 
+// dryRun reports the options that setting them would change, as
+// checked by the API server, without changing them.
+func (c *SetCommand) dryRun(ctx *cmd.Context, client *api.Client) error {
+	args := params.ServiceUpdate{
+		ServiceName:     c.ServiceName,
+		SettingsStrings: c.SettingsStrings,
+	}
+	if c.SettingsYAML.Path != "" {
+		b, err := c.SettingsYAML.Read(ctx)
+		if err != nil {
+			return err
+		}
+		args.SettingsYAML = string(b)
+	}
+	changes, err := client.ServiceUpdateDryRun(args)
+	return reportDryRun(ctx, changes, err)
+}
+
 // parse parses the option k=v strings into a map of options to be
 // updated in the config. Keys with empty values are returned separately
 // and should be removed.
@@ -99,3 +99,13 @@ func setupConfigFile(c *gc.C, dir string) string {
 	c.Assert(err, gc.IsNil)
 	return path
 }
+
+func (s *SetSuite) TestSetDryRun(c *gc.C) {
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(&SetCommand{}), ctx, []string{"dummy-service", "--dry-run", "username=hello"})
+	c.Check(code, gc.Equals, 0)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `set service dummy-service option username to "hello"`+"\n")
+	settings, err := s.svc.ConfigSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
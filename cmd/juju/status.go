@@ -6,6 +6,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"launchpad.net/gnuflag"
 
@@ -23,6 +24,11 @@ type StatusCommand struct {
 	envcmd.EnvCommandBase
 	out      cmd.Output
 	patterns []string
+	colour   bool
+
+	// outPath holds the value of the --output flag added by out, which
+	// does not otherwise expose where the status will be written.
+	outPath gnuflag.Value
 }
 
 var statusDoc = `
@@ -37,6 +43,29 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+Machine ids, which may also contain wildcards, filter the status to the
+units on those machines and their containers; '1/lxc/*' matches all the
+LXC containers on machine 1. Units and machines may also be filtered by
+attribute:
+
+    agent-state=<state>     agents in the given state, e.g. error or pending
+    exposed=<true|false>    units of services that are (not) exposed
+    charm=<charm URL>       units of services using the charm; wildcards
+                            are allowed, as in charm=cs:*/mysql-*
+    network=<name>          units of services, and machines, on the network
+    machine=<machine id>    the same as giving the machine id alone
+
+When several kinds of filter are given, only entities matching all of
+them are shown; when several filters of one kind are given, matching any
+of them is enough. The machines hosting matched units are always shown,
+as are the subordinates of matched principal units.
+
+Besides yaml and json, the status can be shown as tables of machines,
+services and units with --format=tabular, or as an overview of how many
+of each are in each state with --format=summary. Both use colour to
+highlight states when writing to a terminal, but not when the status is
+written to a file with -o.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -53,7 +82,14 @@ func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
 		"yaml": cmd.FormatYaml,
 		"json": cmd.FormatJson,
+		"tabular": func(value interface{}) ([]byte, error) {
+			return formatTabular(value, c.colour)
+		},
+		"summary": func(value interface{}) ([]byte, error) {
+			return formatSummary(value, c.colour)
+		},
 	})
+	c.outPath = f.Lookup("output").Value
 }
 
 func (c *StatusCommand) Init(args []string) error {
@@ -70,7 +106,7 @@ Error details:
 
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	// Just verify the pattern validity client side, do not use the matcher
-	_, err := client.NewUnitMatcher(c.patterns)
+	_, err := client.NewStatusFilter(c.patterns)
 	if err != nil {
 		return err
 	}
@@ -86,14 +122,31 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		fmt.Fprintf(ctx.Stderr, "%v\n", err)
 	}
 	result := formatStatus(status)
+	c.colour = c.writesToTerminal(ctx)
 	return c.out.Write(ctx, result)
 }
 
+// writesToTerminal returns whether the status will be written to a
+// terminal, rather than to a file or pipe, and so may be coloured.
+func (c *StatusCommand) writesToTerminal(ctx *cmd.Context) bool {
+	return c.outPath.String() == "" && isTerminal(ctx.Stdout)
+}
+
 type formattedStatus struct {
 	Environment string                   `json:"environment"`
 	Machines    map[string]machineStatus `json:"machines"`
 	Services    map[string]serviceStatus `json:"services"`
 	Networks    map[string]networkStatus `json:"networks,omitempty" yaml:",omitempty"`
+	Backups     *backupsStatus           `json:"backups,omitempty" yaml:",omitempty"`
+}
+
+// backupsStatus reports the outcome of the most recent scheduled
+// backups of the state server.
+type backupsStatus struct {
+	LastSuccess string `json:"last-success,omitempty" yaml:"last-success,omitempty"`
+	LastBackup  string `json:"last-backup,omitempty" yaml:"last-backup,omitempty"`
+	LastFailure string `json:"last-failure,omitempty" yaml:"last-failure,omitempty"`
+	LastError   string `json:"last-error,omitempty" yaml:"last-error,omitempty"`
 }
 
 type errorStatus struct {
@@ -114,6 +167,16 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	ReplicaSet     *replicaSetMemberStatus  `json:"replicaset-member,omitempty" yaml:"replicaset-member,omitempty"`
+}
+
+// replicaSetMemberStatus holds the status of a state server machine's
+// member of the Mongo replica set.
+type replicaSetMemberStatus struct {
+	State   string `json:"state" yaml:"state"`
+	Healthy bool   `json:"healthy" yaml:"healthy"`
+	Lag     string `json:"lag,omitempty" yaml:"lag,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -174,6 +237,8 @@ type unitStatus struct {
 	AgentState     params.Status         `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
 	AgentStateInfo string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
 	AgentVersion   string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	WorkloadStatus string                `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+	WorkloadInfo   string                `json:"workload-status-info,omitempty" yaml:"workload-status-info,omitempty"`
 	Life           string                `json:"life,omitempty" yaml:"life,omitempty"`
 	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
 	OpenedPorts    []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
@@ -244,6 +309,23 @@ func formatStatus(status *api.Status) formattedStatus {
 		}
 		out.Networks[k] = formatNetwork(n)
 	}
+	if status.Backups != nil {
+		out.Backups = formatBackups(*status.Backups)
+	}
+	return out
+}
+
+func formatBackups(backups api.BackupsStatus) *backupsStatus {
+	out := &backupsStatus{
+		LastBackup: backups.LastBackupId,
+		LastError:  backups.LastError,
+	}
+	if !backups.LastSuccess.IsZero() {
+		out.LastSuccess = backups.LastSuccess.UTC().Format(time.RFC3339)
+	}
+	if !backups.LastFailure.IsZero() {
+		out.LastFailure = backups.LastFailure.UTC().Format(time.RFC3339)
+	}
 	return out
 }
 
@@ -272,6 +354,16 @@ func formatMachine(machine api.MachineStatus) machineStatus {
 			break
 		}
 	}
+	if member := machine.StateServerMember; member != nil {
+		out.ReplicaSet = &replicaSetMemberStatus{
+			State:   member.State,
+			Healthy: member.Healthy,
+			Error:   member.Error,
+		}
+		if member.Lag > 0 {
+			out.ReplicaSet.Lag = member.Lag.String()
+		}
+	}
 	return out
 }
 
@@ -327,6 +419,11 @@ func formatUnit(unit api.UnitStatus) unitStatus {
 		Charm:          unit.Charm,
 		Subordinates:   make(map[string]unitStatus),
 	}
+	// The workload status is only shown once the charm has set it.
+	if unit.Workload.Status != params.StatusUnknown {
+		out.WorkloadStatus = string(unit.Workload.Status)
+		out.WorkloadInfo = unit.Workload.Info
+	}
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = formatUnit(m)
 	}
@@ -0,0 +1,353 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/juju/state/api/params"
+)
+
+// formatTabular returns a compact, column-aligned rendering of the
+// status value, which must be a formattedStatus. Machines, services and
+// units are each listed in their own table, with agent and workload
+// states coloured if colour is set.
+func formatTabular(value interface{}, colour bool) ([]byte, error) {
+	fs, ok := value.(formattedStatus)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", fs, value)
+	}
+	var out bytes.Buffer
+
+	machines := newStatusTable(colour, "ID", "STATE", "VERSION", "DNS", "INS-ID", "SERIES", "HARDWARE")
+	machines.stateColumns(1)
+	var addMachines func(map[string]machineStatus)
+	addMachines = func(ms map[string]machineStatus) {
+		for _, id := range sortedMachineIds(ms) {
+			m := ms[id]
+			if m.Err != nil {
+				machines.addRow(id, m.Err.Error())
+				continue
+			}
+			machines.addRow(id, string(m.AgentState), m.AgentVersion, m.DNSName, string(m.InstanceId), m.Series, m.Hardware)
+			addMachines(m.Containers)
+		}
+	}
+	addMachines(fs.Machines)
+	out.WriteString("[Machines]\n")
+	machines.writeTo(&out)
+
+	services := newStatusTable(colour, "NAME", "EXPOSED", "CHARM")
+	units := newStatusTable(colour, "ID", "STATE", "WORKLOAD", "VERSION", "MACHINE", "PORTS", "PUBLIC-ADDRESS", "WORKLOAD-INFO")
+	units.stateColumns(1, 2)
+	var addUnits func(indent string, us map[string]unitStatus)
+	addUnits = func(indent string, us map[string]unitStatus) {
+		for _, name := range sortedUnitNames(us) {
+			u := us[name]
+			if u.Err != nil {
+				units.addRow(indent+name, u.Err.Error())
+				continue
+			}
+			units.addRow(
+				indent+name,
+				string(u.AgentState),
+				u.WorkloadStatus,
+				u.AgentVersion,
+				u.Machine,
+				strings.Join(u.OpenedPorts, ","),
+				u.PublicAddress,
+				u.WorkloadInfo,
+			)
+			addUnits(indent+"  ", u.Subordinates)
+		}
+	}
+	for _, name := range sortedServiceNames(fs.Services) {
+		s := fs.Services[name]
+		if s.Err != nil {
+			services.addRow(name, s.Err.Error())
+			continue
+		}
+		services.addRow(name, strconv.FormatBool(s.Exposed), s.Charm)
+		addUnits("", s.Units)
+	}
+	out.WriteString("\n[Services]\n")
+	services.writeTo(&out)
+	out.WriteString("\n[Units]\n")
+	units.writeTo(&out)
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
+
+// formatSummary returns an overview of the status value, which must be
+// a formattedStatus: the number of machines, units and services in each
+// state, and of units in each workload state, along with the subnets and ports in use. States are coloured
+// if colour is set.
+func formatSummary(value interface{}, colour bool) ([]byte, error) {
+	fs, ok := value.(formattedStatus)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", fs, value)
+	}
+	machineStates := make(map[string]int)
+	var machineCount int
+	var countMachines func(map[string]machineStatus)
+	countMachines = func(ms map[string]machineStatus) {
+		for _, m := range ms {
+			machineCount++
+			machineStates[stateOrError(string(m.AgentState), m.Err)]++
+			countMachines(m.Containers)
+		}
+	}
+	countMachines(fs.Machines)
+
+	unitStates := make(map[string]int)
+	workloadStates := make(map[string]int)
+	var unitCount int
+	ports := make(map[string]int)
+	var countUnits func(map[string]unitStatus)
+	countUnits = func(us map[string]unitStatus) {
+		for _, u := range us {
+			unitCount++
+			unitStates[stateOrError(string(u.AgentState), u.Err)]++
+			workloadStates[stateOrError(u.WorkloadStatus, u.Err)]++
+			for _, port := range u.OpenedPorts {
+				ports[port]++
+			}
+			countUnits(u.Subordinates)
+		}
+	}
+	var exposed int
+	for _, s := range fs.Services {
+		if s.Exposed {
+			exposed++
+		}
+		countUnits(s.Units)
+	}
+
+	var subnets []string
+	for _, n := range fs.Networks {
+		if n.CIDR != "" {
+			subnets = append(subnets, n.CIDR)
+		}
+	}
+	sort.Strings(subnets)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "Running on subnets: %s\n", strings.Join(subnets, ", "))
+	fmt.Fprintf(&out, "Utilizing ports: %s\n", strings.Join(sortedCounted(ports), ", "))
+	writeCounts := func(title string, total int, states map[string]int) {
+		fmt.Fprintf(&out, "# %s: (%d)\n", title, total)
+		for _, state := range sortedCounted(states) {
+			label := state + ":"
+			padded := fmt.Sprintf("%-12s", label)
+			if colour {
+				padded = colourize(state, label) + padded[len(label):]
+			}
+			fmt.Fprintf(&out, "    %s %d\n", padded, states[state])
+		}
+	}
+	writeCounts("MACHINES", machineCount, machineStates)
+	writeCounts("UNITS", unitCount, unitStates)
+	writeCounts("WORKLOADS", unitCount, workloadStates)
+	writeCounts("SERVICES", len(fs.Services), map[string]int{
+		"exposed":     exposed,
+		"not exposed": len(fs.Services) - exposed,
+	})
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
+
+// stateOrError returns the state to count an entity under: "error" if
+// its status could not be retrieved, and "unknown" if it reports none.
+func stateOrError(state string, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case state == "":
+		return "unknown"
+	}
+	return state
+}
+
+// statusTable accumulates rows of cells, and writes them out with each
+// column padded to the width of its widest cell. Cells in the state
+// columns are coloured if colour is set and they hold a known state;
+// anything else there, such as an error message, is left as it is.
+type statusTable struct {
+	colour bool
+	states map[int]bool
+	rows   [][]string
+}
+
+func newStatusTable(colour bool, headers ...string) *statusTable {
+	return &statusTable{colour: colour, rows: [][]string{headers}}
+}
+
+// stateColumns marks the columns at the given indices as holding
+// states.
+func (t *statusTable) stateColumns(columns ...int) {
+	if t.states == nil {
+		t.states = make(map[int]bool)
+	}
+	for _, i := range columns {
+		t.states[i] = true
+	}
+}
+
+func (t *statusTable) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+func (t *statusTable) writeTo(w io.Writer) {
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	for r, row := range t.rows {
+		var line bytes.Buffer
+		for i, cell := range row {
+			text := cell
+			if t.colour && r > 0 && t.states[i] {
+				text = colourize(cell, cell)
+			}
+			line.WriteString(text)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-len(cell)+2))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// colourize wraps text in the terminal colour appropriate to the given
+// agent or workload state: green for healthy, red for failed or
+// blocked, and yellow for any other known state. Text for anything that
+// is not a known state is returned unchanged.
+func colourize(state, text string) string {
+	var colour string
+	switch params.Status(state) {
+	case params.StatusStarted, params.StatusActive:
+		colour = ansiGreen
+	case params.StatusError, params.StatusDown, params.StatusBlocked, "dead":
+		colour = ansiRed
+	case params.StatusPending, params.StatusInstalled, params.StatusStopped,
+		params.StatusUnknown, params.StatusMaintenance, params.StatusWaiting:
+		colour = ansiYellow
+	default:
+		return text
+	}
+	return colour + text + ansiReset
+}
+
+// isTerminal returns whether w is attached to a terminal.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// sortedMachineIds returns the ids of the machines in ms, in natural
+// order: "2" comes before "10", and "2/lxc/2" before "2/lxc/10".
+func sortedMachineIds(ms map[string]machineStatus) []string {
+	ids := make([]string, 0, len(ms))
+	for id := range ms {
+		ids = append(ids, id)
+	}
+	sort.Sort(naturally(ids))
+	return ids
+}
+
+// sortedServiceNames returns the names of the services in ss, in
+// natural order.
+func sortedServiceNames(ss map[string]serviceStatus) []string {
+	names := make([]string, 0, len(ss))
+	for name := range ss {
+		names = append(names, name)
+	}
+	sort.Sort(naturally(names))
+	return names
+}
+
+// sortedUnitNames returns the names of the units in us, in natural
+// order: "mysql/2" comes before "mysql/10".
+func sortedUnitNames(us map[string]unitStatus) []string {
+	names := make([]string, 0, len(us))
+	for name := range us {
+		names = append(names, name)
+	}
+	sort.Sort(naturally(names))
+	return names
+}
+
+// sortedCounted returns the keys of counts, in natural order.
+func sortedCounted(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Sort(naturally(keys))
+	return keys
+}
+
+// naturally sorts strings so that runs of digits compare numerically.
+type naturally []string
+
+func (n naturally) Len() int      { return len(n) }
+func (n naturally) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+
+func (n naturally) Less(i, j int) bool {
+	a, b := n[i], n[j]
+	for a != "" && b != "" {
+		aNum, aRest := splitDigits(a)
+		bNum, bRest := splitDigits(b)
+		if aNum != "" && bNum != "" {
+			if len(aNum) != len(bNum) {
+				return len(aNum) < len(bNum)
+			}
+			if aNum != bNum {
+				return aNum < bNum
+			}
+			a, b = aRest, bRest
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// splitDigits returns the run of digits that s starts with, if any, and
+// the remainder of s.
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i], s[i:]
+}
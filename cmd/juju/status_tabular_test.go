@@ -0,0 +1,218 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+type StatusFormatSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&StatusFormatSuite{})
+
+var sampleStatus = formattedStatus{
+	Environment: "dummyenv",
+	Machines: map[string]machineStatus{
+		"0": {
+			AgentState:   params.StatusStarted,
+			AgentVersion: "1.20.0",
+			DNSName:      "dummyenv-0.dns",
+			InstanceId:   "dummyenv-0",
+			Series:       "quantal",
+			Hardware:     "arch=amd64",
+		},
+		"10": {
+			AgentState: params.StatusPending,
+			Series:     "quantal",
+		},
+		"2": {
+			AgentState:   params.StatusStarted,
+			AgentVersion: "1.20.0",
+			DNSName:      "dummyenv-2.dns",
+			InstanceId:   "dummyenv-2",
+			Series:       "quantal",
+			Containers: map[string]machineStatus{
+				"2/lxc/0": {
+					AgentState: params.StatusError,
+					Series:     "quantal",
+				},
+			},
+		},
+	},
+	Services: map[string]serviceStatus{
+		"wordpress": {
+			Charm:   "cs:quantal/wordpress-3",
+			Exposed: true,
+			Units: map[string]unitStatus{
+				"wordpress/0": {
+					AgentState:     params.StatusStarted,
+					AgentVersion:   "1.20.0",
+					WorkloadStatus: string(params.StatusActive),
+					WorkloadInfo:   "serving",
+					Machine:        "2",
+					OpenedPorts:    []string{"80/tcp", "443/tcp"},
+					PublicAddress:  "dummyenv-2.dns",
+					Subordinates: map[string]unitStatus{
+						"logging/0": {
+							AgentState:  params.StatusStarted,
+							OpenedPorts: []string{"514/udp"},
+						},
+					},
+				},
+			},
+		},
+		"mysql": {
+			Err: errors.New("cannot get charm"),
+		},
+	},
+	Networks: map[string]networkStatus{
+		"net1": {ProviderId: "foo", CIDR: "10.0.0.0/8"},
+		"net2": {ProviderId: "bar", CIDR: "0.1.2.0/24"},
+	},
+}
+
+func (s *StatusFormatSuite) TestFormatTabular(c *gc.C) {
+	out, err := formatTabular(sampleStatus, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"[Machines]\n"+
+		"ID       STATE    VERSION  DNS             INS-ID      SERIES   HARDWARE\n"+
+		"0        started  1.20.0   dummyenv-0.dns  dummyenv-0  quantal  arch=amd64\n"+
+		"2        started  1.20.0   dummyenv-2.dns  dummyenv-2  quantal\n"+
+		"2/lxc/0  error                                         quantal\n"+
+		"10       pending                                       quantal\n"+
+		"\n"+
+		"[Services]\n"+
+		"NAME       EXPOSED           CHARM\n"+
+		"mysql      cannot get charm\n"+
+		"wordpress  true              cs:quantal/wordpress-3\n"+
+		"\n"+
+		"[Units]\n"+
+		"ID           STATE    WORKLOAD  VERSION  MACHINE  PORTS           PUBLIC-ADDRESS  WORKLOAD-INFO\n"+
+		"wordpress/0  started  active    1.20.0   2        80/tcp,443/tcp  dummyenv-2.dns  serving\n"+
+		"  logging/0  started                              514/udp")
+}
+
+func (s *StatusFormatSuite) TestFormatSummary(c *gc.C) {
+	out, err := formatSummary(sampleStatus, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"Running on subnets: 0.1.2.0/24, 10.0.0.0/8\n"+
+		"Utilizing ports: 80/tcp, 443/tcp, 514/udp\n"+
+		"# MACHINES: (4)\n"+
+		"    error:       1\n"+
+		"    pending:     1\n"+
+		"    started:     2\n"+
+		"# UNITS: (2)\n"+
+		"    started:     2\n"+
+		"# WORKLOADS: (2)\n"+
+		"    active:      1\n"+
+		"    unknown:     1\n"+
+		"# SERVICES: (2)\n"+
+		"    exposed:     1\n"+
+		"    not exposed: 1")
+}
+
+func (s *StatusFormatSuite) TestFormatColour(c *gc.C) {
+	status := formattedStatus{
+		Machines: map[string]machineStatus{
+			"0": {AgentState: params.StatusStarted},
+			"1": {AgentState: params.StatusDown},
+			"2": {Err: errors.New("cannot get instance")},
+		},
+		Services: map[string]serviceStatus{
+			"mysql": {
+				Units: map[string]unitStatus{
+					"mysql/0": {
+						AgentState:     params.StatusStarted,
+						WorkloadStatus: string(params.StatusBlocked),
+						WorkloadInfo:   "needs a relation",
+					},
+				},
+			},
+		},
+	}
+	out, err := formatTabular(status, true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"[Machines]\n"+
+		"ID  STATE                VERSION  DNS  INS-ID  SERIES  HARDWARE\n"+
+		"0   \x1b[32mstarted\x1b[0m\n"+
+		"1   \x1b[31mdown\x1b[0m\n"+
+		"2   cannot get instance\n"+
+		"\n"+
+		"[Services]\n"+
+		"NAME   EXPOSED  CHARM\n"+
+		"mysql  false\n"+
+		"\n"+
+		"[Units]\n"+
+		"ID       STATE    WORKLOAD  VERSION  MACHINE  PORTS  PUBLIC-ADDRESS  WORKLOAD-INFO\n"+
+		"mysql/0  \x1b[32mstarted\x1b[0m  \x1b[31mblocked\x1b[0m                                            needs a relation")
+
+	out, err = formatSummary(status, true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(out), gc.Equals, ""+
+		"Running on subnets: \n"+
+		"Utilizing ports: \n"+
+		"# MACHINES: (3)\n"+
+		"    \x1b[31mdown:\x1b[0m        1\n"+
+		"    \x1b[31merror:\x1b[0m       1\n"+
+		"    \x1b[32mstarted:\x1b[0m     1\n"+
+		"# UNITS: (1)\n"+
+		"    \x1b[32mstarted:\x1b[0m     1\n"+
+		"# WORKLOADS: (1)\n"+
+		"    \x1b[31mblocked:\x1b[0m     1\n"+
+		"# SERVICES: (1)\n"+
+		"    exposed:     0\n"+
+		"    not exposed: 1")
+}
+
+func (s *StatusFormatSuite) TestFormatRejectsOtherValues(c *gc.C) {
+	_, err := formatTabular("foo", false)
+	c.Assert(err, gc.ErrorMatches, "expected value of type main.formattedStatus, got string")
+	_, err = formatSummary(42, false)
+	c.Assert(err, gc.ErrorMatches, "expected value of type main.formattedStatus, got int")
+}
+
+func (s *StatusFormatSuite) TestColourOnlyForTerminal(c *gc.C) {
+	s.PatchValue(&isTerminal, func(io.Writer) bool { return true })
+	for i, test := range []struct {
+		args   []string
+		colour bool
+	}{
+		{nil, true},
+		{[]string{"--format", "tabular"}, true},
+		{[]string{"-o", "status.txt"}, false},
+		{[]string{"--output", "status.txt", "--format", "summary"}, false},
+	} {
+		c.Logf("test %d: %q", i, test.args)
+		command := &StatusCommand{}
+		err := testing.InitCommand(command, test.args)
+		c.Assert(err, gc.IsNil)
+		c.Assert(command.writesToTerminal(testing.Context(c)), gc.Equals, test.colour)
+	}
+
+	s.PatchValue(&isTerminal, func(io.Writer) bool { return false })
+	command := &StatusCommand{}
+	err := testing.InitCommand(command, nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(command.writesToTerminal(testing.Context(c)), gc.Equals, false)
+}
+
+func (s *StatusFormatSuite) TestNaturalSort(c *gc.C) {
+	keys := []string{"mysql/10", "10", "2/lxc/10", "mysql/2", "2", "2/lxc/9", "1", "mysql"}
+	sort.Sort(naturally(keys))
+	c.Assert(keys, gc.DeepEquals, []string{
+		"1", "2", "2/lxc/9", "2/lxc/10", "10", "mysql", "mysql/2", "mysql/10",
+	})
+}
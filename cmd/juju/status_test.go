@@ -6,6 +6,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/presence"
 	coretesting "github.com/juju/juju/testing"
@@ -1400,6 +1402,92 @@ var statusTests = []testCase{
 				},
 			},
 		},
+
+		// scoped on the containers of machine 1
+		scopedExpect{
+			"machines with nested containers",
+			[]string{"1/lxc/*"},
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"1": machine1WithContainers,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":   "cs:quantal/mysql-1",
+						"exposed": true,
+						"units": M{
+							"mysql/1": M{
+								"machine":        "1/lxc/0",
+								"agent-state":    "started",
+								"public-address": "dummyenv-2.dns",
+							},
+						},
+					},
+				},
+			},
+		},
+
+		// a service and a machine must both match
+		scopedExpect{
+			"machines with nested containers",
+			[]string{"mysql", "machine=1/lxc/0"},
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"1": machine1WithContainersScoped,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":   "cs:quantal/mysql-1",
+						"exposed": true,
+						"units": M{
+							"mysql/1": M{
+								"machine":        "1/lxc/0",
+								"agent-state":    "started",
+								"public-address": "dummyenv-2.dns",
+							},
+						},
+					},
+				},
+			},
+		},
+
+		// scoped on agent state
+		scopedExpect{
+			"machines with nested containers",
+			[]string{"agent-state=pending"},
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"1": M{
+						"agent-state": "started",
+						"containers": M{
+							"1/lxc/1": M{
+								"instance-id": "pending",
+								"series":      "quantal",
+							},
+						},
+						"dns-name":    "dummyenv-1.dns",
+						"instance-id": "dummyenv-1",
+						"series":      "quantal",
+						"hardware":    "arch=amd64 cpu-cores=1 mem=1024M root-disk=8192M",
+					},
+				},
+				"services": M{},
+			},
+		},
+
+		// scoped on the exposed flag
+		scopedExpect{
+			"machines with nested containers",
+			[]string{"exposed=false"},
+			M{
+				"environment": "dummyenv",
+				"machines":    M{},
+				"services":    M{},
+			},
+		},
 	), test(
 		"service with out of date charm",
 		addMachine{machineId: "0", job: state.JobManageEnviron},
@@ -1569,6 +1657,72 @@ var statusTests = []testCase{
 				},
 			},
 		},
+	), test(
+		"unit with workload status set by its charm",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", []network.Address{network.NewAddress("dummyenv-0.dns", network.ScopeUnknown)}},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", params.StatusStarted, ""},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", []network.Address{network.NewAddress("dummyenv-1.dns", network.ScopeUnknown)}},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", params.StatusStarted, ""},
+		addCharm{"mysql"},
+		addService{name: "mysql", charm: "mysql"},
+		addAliveUnit{"mysql", "1"},
+		setUnitStatus{"mysql/0", params.StatusStarted, ""},
+		setWorkloadStatus{"mysql/0", params.StatusBlocked, "waiting for db relation"},
+
+		expect{
+			"workload status is reported alongside the agent state",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+				},
+				"services": M{
+					"mysql": M{
+						"charm":   "cs:quantal/mysql-1",
+						"exposed": false,
+						"units": M{
+							"mysql/0": M{
+								"machine":              "1",
+								"agent-state":          "started",
+								"workload-status":      "blocked",
+								"workload-status-info": "waiting for db relation",
+								"public-address":       "dummyenv-1.dns",
+							},
+						},
+					},
+				},
+			},
+		},
+	), test(
+		"outcome of scheduled backups",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", []network.Address{network.NewAddress("dummyenv-0.dns", network.ScopeUnknown)}},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", params.StatusStarted, ""},
+		setScheduledBackupSucceeded{"backup-0", time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC)},
+		setScheduledBackupFailed{time.Date(2014, 8, 2, 12, 0, 0, 0, time.UTC), "cannot dump database"},
+
+		expect{
+			"the last success and failure are reported",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+				},
+				"services": M{},
+				"backups": M{
+					"last-success": "2014-08-01T12:00:00Z",
+					"last-backup":  "backup-0",
+					"last-failure": "2014-08-02T12:00:00Z",
+					"last-error":   "cannot dump database",
+				},
+			},
+		},
 	),
 }
 
@@ -1844,6 +1998,39 @@ func (sus setUnitStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, gc.IsNil)
 }
 
+type setWorkloadStatus struct {
+	unitName   string
+	status     params.Status
+	statusInfo string
+}
+
+func (sws setWorkloadStatus) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(sws.unitName)
+	c.Assert(err, gc.IsNil)
+	err = u.SetWorkloadStatus(sws.status, sws.statusInfo)
+	c.Assert(err, gc.IsNil)
+}
+
+type setScheduledBackupSucceeded struct {
+	id       string
+	finished time.Time
+}
+
+func (sbs setScheduledBackupSucceeded) step(c *gc.C, ctx *context) {
+	err := ctx.st.SetScheduledBackupSucceeded(sbs.id, sbs.finished)
+	c.Assert(err, gc.IsNil)
+}
+
+type setScheduledBackupFailed struct {
+	attempted time.Time
+	err       string
+}
+
+func (sbf setScheduledBackupFailed) step(c *gc.C, ctx *context) {
+	err := ctx.st.SetScheduledBackupFailed(sbf.attempted, errors.New(sbf.err))
+	c.Assert(err, gc.IsNil)
+}
+
 type setUnitCharmURL struct {
 	unitName string
 	charm    string
@@ -2008,6 +2195,34 @@ func (s *StatusSuite) TestStatusAllFormats(c *gc.C) {
 	}
 }
 
+func (s *StatusSuite) TestFormatMachineReplicaSetMember(c *gc.C) {
+	out := formatMachine(api.MachineStatus{
+		Id:        "0",
+		Jobs:      []params.MachineJob{params.JobManageEnviron},
+		HasVote:   true,
+		WantsVote: true,
+		StateServerMember: &api.StateServerMemberStatus{
+			State:   "SECONDARY",
+			Healthy: true,
+			Lag:     3 * time.Second,
+		},
+	})
+	c.Assert(out.HAStatus, gc.Equals, "has-vote")
+	c.Assert(out.ReplicaSet, gc.DeepEquals, &replicaSetMemberStatus{
+		State:   "SECONDARY",
+		Healthy: true,
+		Lag:     "3s",
+	})
+	data, err := goyaml.Marshal(out)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, ""+
+		"state-server-member-status: has-vote\n"+
+		"replicaset-member:\n"+
+		"  state: SECONDARY\n"+
+		"  healthy: true\n"+
+		"  lag: 3s\n")
+}
+
 func (s *StatusSuite) TestStatusFilterErrors(c *gc.C) {
 	steps := []stepper{
 		addMachine{machineId: "0", job: state.JobManageEnviron},
@@ -2035,4 +2250,21 @@ func (s *StatusSuite) TestStatusFilterErrors(c *gc.C) {
 	code, _, stderr = runStatus(c, "*", "[*")
 	c.Assert(code, gc.Not(gc.Equals), 0)
 	c.Assert(string(stderr), gc.Equals, `error: pattern "[*" contains invalid characters`+"\n")
+
+	// Filters on attributes must be known, and have valid values.
+	code, _, stderr = runStatus(c, "foo=bar")
+	c.Assert(code, gc.Not(gc.Equals), 0)
+	c.Assert(string(stderr), gc.Equals, `error: unknown status filter "foo"`+"\n")
+
+	code, _, stderr = runStatus(c, "agent-state=bogus")
+	c.Assert(code, gc.Not(gc.Equals), 0)
+	c.Assert(string(stderr), gc.Equals, `error: unknown agent state "bogus"`+"\n")
+
+	code, _, stderr = runStatus(c, "exposed=maybe")
+	c.Assert(code, gc.Not(gc.Equals), 0)
+	c.Assert(string(stderr), gc.Equals, `error: invalid value "maybe" for exposed: must be true or false`+"\n")
+
+	code, _, stderr = runStatus(c, "1/lxc")
+	c.Assert(code, gc.Not(gc.Equals), 0)
+	c.Assert(string(stderr), gc.Equals, `error: pattern "1/lxc" is not a valid machine id`+"\n")
 }
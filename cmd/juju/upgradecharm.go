@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
 )
 
 // UpgradeCharm is responsible for upgrading a service's charm.
@@ -25,6 +26,7 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	DryRun      bool
 }
 
 const upgradeCharmDoc = `
@@ -63,6 +65,10 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+With --dry-run, upgrade-charm reports the changes it would make, as checked by
+the API server, without making them. The new charm is not added to the
+environment, so its relations can only be checked once it has been.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -79,6 +85,7 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.DryRun, "dry-run", false, "report the changes that would be made, without making them")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -160,6 +167,21 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	if c.DryRun {
+		dryRunURL := newURL
+		if *newURL == *oldURL {
+			// Uploading the same local charm again adds it with
+			// the next revision.
+			dryRunURL = newURL.WithRevision(newURL.Revision + 1)
+		}
+		changes, err := client.ServiceUpdateDryRun(params.ServiceUpdate{
+			ServiceName:   c.ServiceName,
+			CharmUrl:      dryRunURL.String(),
+			ForceCharmUrl: c.Force,
+		})
+		return reportDryRun(ctx, changes, err)
+	}
+
 	addedURL, err := addCharmViaAPI(client, ctx, newURL, repo)
 	if err != nil {
 		return err
@@ -217,3 +217,16 @@ func (s *UpgradeCharmSuccessSuite) TestSwitch(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, "local:precise/myriak-42")
 	s.assertLocalRevision(c, 42, myriakPath)
 }
+
+func (s *UpgradeCharmSuccessSuite) TestDryRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeCharmCommand{}), "riak", "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"add charm local:precise/riak-8\n"+
+		"upgrade service riak from charm local:precise/riak-7 to local:precise/riak-8\n")
+	err = s.riak.Refresh()
+	c.Assert(err, gc.IsNil)
+	ch, _, err := s.riak.Charm()
+	c.Assert(err, gc.IsNil)
+	c.Assert(ch.Revision(), gc.Equals, 7)
+}
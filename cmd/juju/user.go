@@ -31,5 +31,12 @@ func NewUserCommand() cmd.Command {
 	// Define each subcommand in a separate "user_FOO.go" source file
 	// (with tests in user_FOO_test.go) and wire in here.
 	usercmd.Register(envcmd.Wrap(&UserAddCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserChangePasswordCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserDisableCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserEnableCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserGrantCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserInfoCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserListCommand{}))
+	usercmd.Register(envcmd.Wrap(&UserRevokeCommand{}))
 	return usercmd
 }
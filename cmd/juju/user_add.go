@@ -26,6 +26,9 @@ will be lost when the environent is destroyed.  An environment file
 (.jenv) identifying the new user and the environment can be generated
 using --output.
 
+A new user has no access to the environment until given a role with
+"juju user grant".
+
 Examples:
   juju user add foobar                    (Add user "foobar". A strong password will be generated and printed)
   juju user add foobar --password=mypass  (Add user "foobar" with password "mypass")
@@ -0,0 +1,118 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+)
+
+const userChangePasswordCommandDoc = `
+Change the password of a user. With no user name, change the password
+of the current user, and record the new password in the environment
+file (.jenv) so that later commands keep working.
+
+Users without the admin role may only change their own password.
+
+Examples:
+  juju user change-password                    (Generate and print a new password for the current user)
+  juju user change-password --password=mypass  (Change the current user's password to "mypass")
+  juju user change-password foobar             (Generate and print a new password for user "foobar")
+`
+
+type UserChangePasswordCommand struct {
+	envcmd.EnvCommandBase
+	User     string
+	Password string
+}
+
+func (c *UserChangePasswordCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "change-password",
+		Args:    "[<username>]",
+		Purpose: "changes the password of a user",
+		Doc:     userChangePasswordCommandDoc,
+	}
+}
+
+func (c *UserChangePasswordCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Password, "password", "", "New password for the user")
+}
+
+func (c *UserChangePasswordCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.User, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+type setPasswordAPI interface {
+	SetPassword(username, password string) error
+	Close() error
+}
+
+var getSetPasswordAPI = func(c *UserChangePasswordCommand) (setPasswordAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserChangePasswordCommand) Run(ctx *cmd.Context) error {
+	currentUser, err := currentUserName(c.EnvName)
+	if err != nil {
+		return err
+	}
+	if c.User == "" {
+		c.User = currentUser
+	}
+	generated := c.Password == ""
+	if generated {
+		c.Password, err = utils.RandomPassword()
+		if err != nil {
+			return errors.Annotate(err, "failed to generate password")
+		}
+	}
+	client, err := getSetPasswordAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.SetPassword(c.User, c.Password); err != nil {
+		return err
+	}
+	if generated {
+		fmt.Fprintf(ctx.Stdout, "password of user %q changed to %q\n", c.User, c.Password)
+	} else {
+		fmt.Fprintf(ctx.Stdout, "password of user %q changed\n", c.User)
+	}
+	if c.User == currentUser {
+		if err := writePassword(c.EnvName, c.Password); err != nil {
+			return errors.Annotate(err, "cannot record new password")
+		}
+	}
+	return nil
+}
+
+// writePassword records the password of the current
+// user in the named environment's information.
+func writePassword(envName, password string) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return err
+	}
+	info, err := store.ReadInfo(envName)
+	if err != nil {
+		return err
+	}
+	creds := info.APICredentials()
+	creds.Password = password
+	info.SetAPICredentials(creds)
+	return info.Write()
+}
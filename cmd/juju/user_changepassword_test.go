@@ -0,0 +1,107 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+// All of the functionality of the SetPassword api call is contained
+// elsewhere. This suite provides basic tests for the "user
+// change-password" command.
+type UserChangePasswordCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockSetPasswordAPI
+}
+
+var _ = gc.Suite(&UserChangePasswordCommandSuite{})
+
+func (s *UserChangePasswordCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockSetPasswordAPI{}
+	s.PatchValue(&getSetPasswordAPI, func(c *UserChangePasswordCommand) (setPasswordAPI, error) {
+		return s.mockAPI, nil
+	})
+	fakeBootstrapEnvironment(c, "erewhemos")
+}
+
+func newUserChangePasswordCommand() cmd.Command {
+	return envcmd.Wrap(&UserChangePasswordCommand{})
+}
+
+func (s *UserChangePasswordCommandSuite) assertStoredPassword(c *gc.C, password string) {
+	store, err := configstore.Default()
+	c.Assert(err, gc.IsNil)
+	info, err := store.ReadInfo("erewhemos")
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.APICredentials(), gc.Equals, configstore.APICredentials{
+		User:     "admin",
+		Password: password,
+	})
+}
+
+func (s *UserChangePasswordCommandSuite) TestChangeCurrentUserPassword(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserChangePasswordCommand(), "--password", "new-password")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "admin")
+	c.Assert(s.mockAPI.password, gc.Equals, "new-password")
+	c.Assert(testing.Stdout(context), gc.Equals, `password of user "admin" changed`+"\n")
+	s.assertStoredPassword(c, "new-password")
+}
+
+func (s *UserChangePasswordCommandSuite) TestChangeOtherUserPassword(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserChangePasswordCommand(), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
+	// Password is generated
+	c.Assert(s.mockAPI.password, gc.Not(gc.Equals), "")
+	expected := fmt.Sprintf(`password of user "foobar" changed to %q`, s.mockAPI.password)
+	c.Assert(testing.Stdout(context), gc.Equals, expected+"\n")
+	s.assertStoredPassword(c, "password")
+}
+
+func (s *UserChangePasswordCommandSuite) TestErrorResponse(c *gc.C) {
+	s.mockAPI.failMessage = "permission denied"
+	context, err := testing.RunCommand(c, newUserChangePasswordCommand(), "--password", "new-password")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	s.assertStoredPassword(c, "password")
+}
+
+func (s *UserChangePasswordCommandSuite) TestInit(c *gc.C) {
+	command := &UserChangePasswordCommand{}
+	err := testing.InitCommand(command, []string{"foobar", "--password", "mypass"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(command.User, gc.Equals, "foobar")
+	c.Assert(command.Password, gc.Equals, "mypass")
+	err = testing.InitCommand(&UserChangePasswordCommand{}, []string{"foobar", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+type mockSetPasswordAPI struct {
+	failMessage string
+	username    string
+	password    string
+}
+
+func (m *mockSetPasswordAPI) SetPassword(username, password string) error {
+	if m.failMessage != "" {
+		return errors.New(m.failMessage)
+	}
+	m.username = username
+	m.password = password
+	return nil
+}
+
+func (*mockSetPasswordAPI) Close() error {
+	return nil
+}
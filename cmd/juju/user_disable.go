@@ -0,0 +1,65 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+)
+
+const userDisableCommandDoc = `
+Disable a user, so that the user can no longer log in to the
+environment. The user's details are kept, and "juju user enable"
+allows the user to log in again. The admin user cannot be disabled.
+
+Examples:
+  juju user disable foobar  (Stop user "foobar" from logging in)
+`
+
+type UserDisableCommand struct {
+	envcmd.EnvCommandBase
+	User string
+}
+
+func (c *UserDisableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "disable",
+		Args:    "<username>",
+		Purpose: "stops a user from logging in",
+		Doc:     userDisableCommandDoc,
+	}
+}
+
+func (c *UserDisableCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no username supplied")
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type disableUserAPI interface {
+	DisableUser(username string) error
+	Close() error
+}
+
+var getDisableUserAPI = func(c *UserDisableCommand) (disableUserAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserDisableCommand) Run(ctx *cmd.Context) error {
+	client, err := getDisableUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.DisableUser(c.User); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q disabled\n", c.User)
+	return nil
+}
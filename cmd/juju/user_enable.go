@@ -0,0 +1,64 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+)
+
+const userEnableCommandDoc = `
+Enable a user disabled with "juju user disable" or "juju remove-user",
+so that the user can log in to the environment again.
+
+Examples:
+  juju user enable foobar  (Allow user "foobar" to log in again)
+`
+
+type UserEnableCommand struct {
+	envcmd.EnvCommandBase
+	User string
+}
+
+func (c *UserEnableCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "enable",
+		Args:    "<username>",
+		Purpose: "allows a disabled user to log in again",
+		Doc:     userEnableCommandDoc,
+	}
+}
+
+func (c *UserEnableCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no username supplied")
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+type enableUserAPI interface {
+	EnableUser(username string) error
+	Close() error
+}
+
+var getEnableUserAPI = func(c *UserEnableCommand) (enableUserAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserEnableCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnableUserAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.EnableUser(c.User); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "user %q enabled\n", c.User)
+	return nil
+}
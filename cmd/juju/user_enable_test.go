@@ -0,0 +1,99 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+// All of the functionality of the EnableUser and DisableUser api calls
+// is contained elsewhere. This suite provides basic tests for the "user
+// enable" and "user disable" commands.
+type UserEnableCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockEnableUserAPI
+}
+
+var _ = gc.Suite(&UserEnableCommandSuite{})
+
+func (s *UserEnableCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockEnableUserAPI{}
+	s.PatchValue(&getEnableUserAPI, func(c *UserEnableCommand) (enableUserAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(&getDisableUserAPI, func(c *UserDisableCommand) (disableUserAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserEnableCommand() cmd.Command {
+	return envcmd.Wrap(&UserEnableCommand{})
+}
+
+func newUserDisableCommand() cmd.Command {
+	return envcmd.Wrap(&UserDisableCommand{})
+}
+
+func (s *UserEnableCommandSuite) TestEnable(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserEnableCommand(), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.DeepEquals, []string{"EnableUser foobar"})
+	c.Assert(testing.Stdout(context), gc.Equals, `user "foobar" enabled`+"\n")
+}
+
+func (s *UserEnableCommandSuite) TestDisable(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserDisableCommand(), "foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.DeepEquals, []string{"DisableUser foobar"})
+	c.Assert(testing.Stdout(context), gc.Equals, `user "foobar" disabled`+"\n")
+}
+
+func (s *UserEnableCommandSuite) TestErrorResponse(c *gc.C) {
+	s.mockAPI.failMessage = "Can't deactivate admin user"
+	context, err := testing.RunCommand(c, newUserDisableCommand(), "admin")
+	c.Assert(err, gc.ErrorMatches, "Can't deactivate admin user")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+}
+
+func (s *UserEnableCommandSuite) TestInit(c *gc.C) {
+	for i, command := range []cmd.Command{&UserEnableCommand{}, &UserDisableCommand{}} {
+		c.Logf("test %d", i)
+		err := testing.InitCommand(command, nil)
+		c.Check(err, gc.ErrorMatches, "no username supplied")
+		err = testing.InitCommand(command, []string{"foobar", "extra"})
+		c.Check(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	}
+}
+
+type mockEnableUserAPI struct {
+	failMessage string
+	calls       []string
+}
+
+func (m *mockEnableUserAPI) EnableUser(username string) error {
+	return m.call("EnableUser", username)
+}
+
+func (m *mockEnableUserAPI) DisableUser(username string) error {
+	return m.call("DisableUser", username)
+}
+
+func (m *mockEnableUserAPI) call(method, username string) error {
+	m.calls = append(m.calls, method+" "+username)
+	if m.failMessage == "" {
+		return nil
+	}
+	return errors.New(m.failMessage)
+}
+
+func (*mockEnableUserAPI) Close() error {
+	return nil
+}
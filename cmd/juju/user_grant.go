@@ -0,0 +1,85 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+)
+
+const userGrantCommandDoc = `
+Grant a user a role in the environment.
+
+The roles are, from least to most privileged:
+  read   inspect the environment, for example with "juju status"
+  write  also deploy, change and remove services and machines
+  admin  also change the environment configuration, manage users
+         and destroy the environment
+
+Each role includes the permissions of the roles before it. Granting a
+role to a user who already has it, or a more privileged one, leaves the
+user unchanged.
+
+Examples:
+  juju user grant foobar read   (Allow user "foobar" to inspect the environment)
+  juju user grant foobar write  (Allow user "foobar" to change the environment)
+`
+
+type UserGrantCommand struct {
+	envcmd.EnvCommandBase
+	User string
+	Role string
+}
+
+func (c *UserGrantCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant",
+		Args:    "<username> <role>",
+		Purpose: "grants a user a role in the environment",
+		Doc:     userGrantCommandDoc,
+	}
+}
+
+func (c *UserGrantCommand) Init(args []string) error {
+	var err error
+	c.User, c.Role, err = parseUserRoleArgs(args)
+	return err
+}
+
+// parseUserRoleArgs returns the user name and role
+// given to the grant and revoke commands.
+func parseUserRoleArgs(args []string) (user, role string, err error) {
+	switch len(args) {
+	case 0:
+		return "", "", fmt.Errorf("no username supplied")
+	case 1:
+		return "", "", fmt.Errorf("no role supplied")
+	}
+	return args[0], args[1], cmd.CheckEmpty(args[2:])
+}
+
+type grantRoleAPI interface {
+	GrantRole(username, role string) error
+	Close() error
+}
+
+var getGrantRoleAPI = func(c *UserGrantCommand) (grantRoleAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserGrantCommand) Run(ctx *cmd.Context) error {
+	client, err := getGrantRoleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.GrantRole(c.User, c.Role); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "granted role %q to user %q\n", c.Role, c.User)
+	return nil
+}
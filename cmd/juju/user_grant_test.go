@@ -0,0 +1,129 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+// All of the functionality of the GrantRole and RevokeRole api calls is
+// contained elsewhere. This suite provides basic tests for the "user
+// grant" and "user revoke" commands.
+type UserRoleCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockUserRoleAPI
+}
+
+var _ = gc.Suite(&UserRoleCommandSuite{})
+
+func (s *UserRoleCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &mockUserRoleAPI{}
+	s.PatchValue(&getGrantRoleAPI, func(c *UserGrantCommand) (grantRoleAPI, error) {
+		return s.mockAPI, nil
+	})
+	s.PatchValue(&getRevokeRoleAPI, func(c *UserRevokeCommand) (revokeRoleAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserGrantCommand() cmd.Command {
+	return envcmd.Wrap(&UserGrantCommand{})
+}
+
+func newUserRevokeCommand() cmd.Command {
+	return envcmd.Wrap(&UserRevokeCommand{})
+}
+
+func (s *UserRoleCommandSuite) TestGrant(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserGrantCommand(), "foobar", "write")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.DeepEquals, []string{"GrantRole foobar write"})
+	c.Assert(testing.Stdout(context), gc.Equals, `granted role "write" to user "foobar"`+"\n")
+}
+
+func (s *UserRoleCommandSuite) TestRevoke(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserRevokeCommand(), "foobar", "read")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.mockAPI.calls, gc.DeepEquals, []string{"RevokeRole foobar read"})
+	c.Assert(testing.Stdout(context), gc.Equals, `revoked role "read" from user "foobar"`+"\n")
+}
+
+func (s *UserRoleCommandSuite) TestErrorResponse(c *gc.C) {
+	s.mockAPI.failMessage = `invalid role "root"`
+	context, err := testing.RunCommand(c, newUserGrantCommand(), "foobar", "root")
+	c.Assert(err, gc.ErrorMatches, `invalid role "root"`)
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+}
+
+func (s *UserRoleCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		user        string
+		role        string
+		errorString string
+	}{
+		{
+			errorString: "no username supplied",
+		}, {
+			args:        []string{"foobar"},
+			errorString: "no role supplied",
+		}, {
+			args: []string{"foobar", "read"},
+			user: "foobar",
+			role: "read",
+		}, {
+			args:        []string{"foobar", "read", "extra"},
+			errorString: `unrecognized args: \["extra"\]`,
+		},
+	} {
+		c.Logf("test %d", i)
+		grantCmd := &UserGrantCommand{}
+		err := testing.InitCommand(grantCmd, test.args)
+		revokeCmd := &UserRevokeCommand{}
+		revokeErr := testing.InitCommand(revokeCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, gc.IsNil)
+			c.Check(grantCmd.User, gc.Equals, test.user)
+			c.Check(grantCmd.Role, gc.Equals, test.role)
+			c.Check(revokeErr, gc.IsNil)
+			c.Check(revokeCmd.User, gc.Equals, test.user)
+			c.Check(revokeCmd.Role, gc.Equals, test.role)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+			c.Check(revokeErr, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+type mockUserRoleAPI struct {
+	failMessage string
+	calls       []string
+}
+
+func (m *mockUserRoleAPI) GrantRole(username, role string) error {
+	return m.call("GrantRole", username, role)
+}
+
+func (m *mockUserRoleAPI) RevokeRole(username, role string) error {
+	return m.call("RevokeRole", username, role)
+}
+
+func (m *mockUserRoleAPI) call(method, username, role string) error {
+	m.calls = append(m.calls, method+" "+username+" "+role)
+	if m.failMessage == "" {
+		return nil
+	}
+	return errors.New(m.failMessage)
+}
+
+func (*mockUserRoleAPI) Close() error {
+	return nil
+}
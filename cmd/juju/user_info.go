@@ -0,0 +1,127 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+const userInfoCommandDoc = `
+Show information about a user of the environment: when and by whom the
+user was added, when the user last connected, and whether the user is
+disabled. With no user name, show information about the current user.
+
+Users without the admin role may only see their own information.
+
+Examples:
+  juju user info          (Show information about the current user)
+  juju user info foobar   (Show information about user "foobar")
+`
+
+type UserInfoCommand struct {
+	envcmd.EnvCommandBase
+	User string
+	out  cmd.Output
+}
+
+func (c *UserInfoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "info",
+		Args:    "[<username>]",
+		Purpose: "shows information about a user",
+		Doc:     userInfoCommandDoc,
+	}
+}
+
+func (c *UserInfoCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *UserInfoCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.User, args = args[0], args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+type userInfoAPI interface {
+	UserInfo(username string) (params.UserInfo, error)
+	Close() error
+}
+
+var getUserInfoAPI = func(c *UserInfoCommand) (userInfoAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserInfoCommand) Run(ctx *cmd.Context) error {
+	if c.User == "" {
+		var err error
+		if c.User, err = currentUserName(c.EnvName); err != nil {
+			return err
+		}
+	}
+	client, err := getUserInfoAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	info, err := client.UserInfo(c.User)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatUserInfo(info))
+}
+
+// formattedUserInfo is the printable form of params.UserInfo.
+type formattedUserInfo struct {
+	Username       string `yaml:"user-name" json:"user-name"`
+	DisplayName    string `yaml:"display-name,omitempty" json:"display-name,omitempty"`
+	CreatedBy      string `yaml:"created-by,omitempty" json:"created-by,omitempty"`
+	DateCreated    string `yaml:"date-created,omitempty" json:"date-created,omitempty"`
+	LastConnection string `yaml:"last-connection" json:"last-connection"`
+	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+func formatUserInfo(info params.UserInfo) formattedUserInfo {
+	out := formattedUserInfo{
+		Username:       info.Username,
+		DisplayName:    info.DisplayName,
+		CreatedBy:      info.CreatedBy,
+		LastConnection: "never connected",
+		Disabled:       info.Disabled,
+	}
+	if !info.DateCreated.IsZero() {
+		out.DateCreated = info.DateCreated.UTC().Format(time.RFC3339)
+	}
+	if info.LastConnection != nil {
+		out.LastConnection = info.LastConnection.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+// currentUserName returns the name of the user that
+// connects to the named environment.
+func currentUserName(envName string) (string, error) {
+	store, err := configstore.Default()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	info, err := store.ReadInfo(envName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if user := info.APICredentials().User; user != "" {
+		return user, nil
+	}
+	return "", errors.Errorf("no user found for environment %q", envName)
+}
@@ -0,0 +1,102 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	gc "launchpad.net/gocheck"
+	"launchpad.net/goyaml"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+// All of the functionality of the UserInfo api call is contained
+// elsewhere. This suite provides basic tests for the "user info"
+// command.
+type UserInfoCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockUserInfoAPI
+}
+
+var _ = gc.Suite(&UserInfoCommandSuite{})
+
+func (s *UserInfoCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	created := time.Date(2014, 7, 1, 12, 0, 0, 0, time.UTC)
+	connected := time.Date(2014, 7, 2, 8, 30, 0, 0, time.UTC)
+	s.mockAPI = &mockUserInfoAPI{users: []params.UserInfo{{
+		Username:       "admin",
+		DateCreated:    created,
+		LastConnection: &connected,
+	}, {
+		Username:    "foobar",
+		DisplayName: "Foo Bar",
+		CreatedBy:   "admin",
+		DateCreated: created,
+		Disabled:    true,
+	}}}
+	s.PatchValue(&getUserInfoAPI, func(c *UserInfoCommand) (userInfoAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserInfoCommand() cmd.Command {
+	return envcmd.Wrap(&UserInfoCommand{})
+}
+
+func (s *UserInfoCommandSuite) TestUserInfo(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserInfoCommand(), "foobar")
+	c.Assert(err, gc.IsNil)
+	var info map[string]interface{}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(context)), &info)
+	c.Assert(err, gc.IsNil)
+	c.Assert(info, gc.DeepEquals, map[string]interface{}{
+		"user-name":       "foobar",
+		"display-name":    "Foo Bar",
+		"created-by":      "admin",
+		"date-created":    "2014-07-01T12:00:00Z",
+		"last-connection": "never connected",
+		"disabled":        true,
+	})
+}
+
+func (s *UserInfoCommandSuite) TestUserInfoCurrentUser(c *gc.C) {
+	fakeBootstrapEnvironment(c, "erewhemos")
+	context, err := testing.RunCommand(c, newUserInfoCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals,
+		`{"user-name":"admin","date-created":"2014-07-01T12:00:00Z","last-connection":"2014-07-02T08:30:00Z"}`+"\n")
+}
+
+func (s *UserInfoCommandSuite) TestUserInfoError(c *gc.C) {
+	_, err := testing.RunCommand(c, newUserInfoCommand(), "nobody")
+	c.Assert(err, gc.ErrorMatches, `user "nobody" not found`)
+}
+
+func (s *UserInfoCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&UserInfoCommand{}, []string{"foobar", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+type mockUserInfoAPI struct {
+	users []params.UserInfo
+}
+
+func (m *mockUserInfoAPI) UserInfo(username string) (params.UserInfo, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return params.UserInfo{}, errors.New(`user "` + username + `" not found`)
+}
+
+func (*mockUserInfoAPI) Close() error {
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state/api/params"
+)
+
+const userListCommandDoc = `
+List the users of the environment, including the disabled ones.
+
+Only users with the admin role may list the users.
+`
+
+type UserListCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+func (c *UserListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "lists the users of the environment",
+		Doc:     userListCommandDoc,
+	}
+}
+
+func (c *UserListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatUsersTabular,
+	})
+}
+
+func (c *UserListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+type listUsersAPI interface {
+	ListUsers() ([]params.UserInfo, error)
+	Close() error
+}
+
+var getListUsersAPI = func(c *UserListCommand) (listUsersAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserListCommand) Run(ctx *cmd.Context) error {
+	client, err := getListUsersAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	users, err := client.ListUsers()
+	if err != nil {
+		return err
+	}
+	out := make([]formattedUserInfo, len(users))
+	for i, user := range users {
+		out[i] = formatUserInfo(user)
+	}
+	return c.out.Write(ctx, out)
+}
+
+// formatUsersTabular returns a table of the users, one per line.
+func formatUsersTabular(value interface{}) ([]byte, error) {
+	users, ok := value.([]formattedUserInfo)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type %T, got %T", users, value)
+	}
+	table := newStatusTable(false, "NAME", "DISPLAY-NAME", "CREATED-BY", "DATE-CREATED", "LAST-CONNECTION", "ACTIVE")
+	for _, user := range users {
+		active := "yes"
+		if user.Disabled {
+			active = "no"
+		}
+		table.addRow(user.Username, user.DisplayName, user.CreatedBy, user.DateCreated, user.LastConnection, active)
+	}
+	var out bytes.Buffer
+	table.writeTo(&out)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	gc "launchpad.net/gocheck"
+	"launchpad.net/goyaml"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+)
+
+// All of the functionality of the ListUsers api call is contained
+// elsewhere. This suite provides basic tests for the "user list"
+// command.
+type UserListCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *mockListUsersAPI
+}
+
+var _ = gc.Suite(&UserListCommandSuite{})
+
+func (s *UserListCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	created := time.Date(2014, 7, 1, 12, 0, 0, 0, time.UTC)
+	connected := time.Date(2014, 7, 2, 8, 30, 0, 0, time.UTC)
+	s.mockAPI = &mockListUsersAPI{users: []params.UserInfo{{
+		Username:       "admin",
+		DateCreated:    created,
+		LastConnection: &connected,
+	}, {
+		Username:    "foobar",
+		DisplayName: "Foo Bar",
+		CreatedBy:   "admin",
+		DateCreated: created,
+		Disabled:    true,
+	}}}
+	s.PatchValue(&getListUsersAPI, func(c *UserListCommand) (listUsersAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func newUserListCommand() cmd.Command {
+	return envcmd.Wrap(&UserListCommand{})
+}
+
+func (s *UserListCommandSuite) TestUserList(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserListCommand())
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+NAME    DISPLAY-NAME  CREATED-BY  DATE-CREATED          LAST-CONNECTION       ACTIVE
+admin                             2014-07-01T12:00:00Z  2014-07-02T08:30:00Z  yes
+foobar  Foo Bar       admin       2014-07-01T12:00:00Z  never connected       no
+`[1:])
+}
+
+func (s *UserListCommandSuite) TestUserListYAML(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserListCommand(), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	var users []map[string]interface{}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(context)), &users)
+	c.Assert(err, gc.IsNil)
+	c.Assert(users, gc.DeepEquals, []map[string]interface{}{{
+		"user-name":       "admin",
+		"date-created":    "2014-07-01T12:00:00Z",
+		"last-connection": "2014-07-02T08:30:00Z",
+	}, {
+		"user-name":       "foobar",
+		"display-name":    "Foo Bar",
+		"created-by":      "admin",
+		"date-created":    "2014-07-01T12:00:00Z",
+		"last-connection": "never connected",
+		"disabled":        true,
+	}})
+}
+
+func (s *UserListCommandSuite) TestUserListJSON(c *gc.C) {
+	s.mockAPI.users = s.mockAPI.users[:1]
+	context, err := testing.RunCommand(c, newUserListCommand(), "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(testing.Stdout(context), gc.Equals,
+		`[{"user-name":"admin","date-created":"2014-07-01T12:00:00Z","last-connection":"2014-07-02T08:30:00Z"}]`+"\n")
+}
+
+func (s *UserListCommandSuite) TestErrorResponse(c *gc.C) {
+	s.mockAPI.failMessage = "permission denied"
+	context, err := testing.RunCommand(c, newUserListCommand())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+}
+
+func (s *UserListCommandSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&UserListCommand{}, []string{"extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+type mockListUsersAPI struct {
+	failMessage string
+	users       []params.UserInfo
+}
+
+func (m *mockListUsersAPI) ListUsers() ([]params.UserInfo, error) {
+	if m.failMessage != "" {
+		return nil, errors.New(m.failMessage)
+	}
+	return m.users, nil
+}
+
+func (*mockListUsersAPI) Close() error {
+	return nil
+}
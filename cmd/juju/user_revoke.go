@@ -0,0 +1,68 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju"
+)
+
+const userRevokeCommandDoc = `
+Revoke a role from a user in the environment.
+
+The user is left with the role below the revoked one: revoking admin
+leaves write, revoking write leaves read, and revoking read removes all
+access to the environment. Revoking a role the user does not have leaves
+the user unchanged. See "juju help user grant" for the roles.
+
+Examples:
+  juju user revoke foobar write  (Make user "foobar" read-only)
+  juju user revoke foobar read   (Remove all access for user "foobar")
+`
+
+type UserRevokeCommand struct {
+	envcmd.EnvCommandBase
+	User string
+	Role string
+}
+
+func (c *UserRevokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<username> <role>",
+		Purpose: "revokes a role from a user in the environment",
+		Doc:     userRevokeCommandDoc,
+	}
+}
+
+func (c *UserRevokeCommand) Init(args []string) error {
+	var err error
+	c.User, c.Role, err = parseUserRoleArgs(args)
+	return err
+}
+
+type revokeRoleAPI interface {
+	RevokeRole(username, role string) error
+	Close() error
+}
+
+var getRevokeRoleAPI = func(c *UserRevokeCommand) (revokeRoleAPI, error) {
+	return juju.NewUserManagerClient(c.EnvName)
+}
+
+func (c *UserRevokeCommand) Run(ctx *cmd.Context) error {
+	client, err := getRevokeRoleAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.RevokeRole(c.User, c.Role); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "revoked role %q from user %q\n", c.Role, c.User)
+	return nil
+}
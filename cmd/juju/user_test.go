@@ -19,7 +19,14 @@ var _ = gc.Suite(&UserCommandSuite{})
 
 var expectedUserCommmandNames = []string{
 	"add",
+	"change-password",
+	"disable",
+	"enable",
+	"grant",
 	"help",
+	"info",
+	"list",
+	"revoke",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
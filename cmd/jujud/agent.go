@@ -24,6 +24,7 @@ import (
 	apideployer "github.com/juju/juju/state/api/deployer"
 	"github.com/juju/juju/state/api/params"
 	apirsyslog "github.com/juju/juju/state/api/rsyslog"
+	"github.com/juju/juju/state/apiserver"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/deployer"
@@ -145,6 +146,11 @@ func isFatal(err error) bool {
 	if err == worker.ErrTerminateAgent {
 		return true
 	}
+	// Once the state has been restored from a backup, the agent exits
+	// so that upstart restarts it with the restored configuration.
+	if err == apiserver.ErrRestored {
+		return true
+	}
 	if isUpgraded(err) {
 		return true
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api"
 	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver"
 	coretesting "github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
@@ -67,6 +68,9 @@ var isFatalTests = []struct {
 		Code:    params.CodeNotProvisioned,
 	},
 	isFatal: false,
+}, {
+	err:     apiserver.ErrRestored,
+	isFatal: true,
 }, {
 	err:     &fatalError{"some fatal error"},
 	isFatal: true,
@@ -34,12 +34,14 @@ import (
 	apiagent "github.com/juju/juju/state/api/agent"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/apiserver"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/upgrades"
 	"github.com/juju/juju/upstart"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/deployer"
@@ -47,6 +49,7 @@ import (
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machineenvironmentworker"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/minunitsworker"
@@ -294,6 +297,9 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 	a.startWorkerAfterUpgrade(runner, "rsyslog", func() (worker.Worker, error) {
 		return newRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslogMode)
 	})
+	a.startWorkerAfterUpgrade(runner, "logsender", func() (worker.Worker, error) {
+		return logsender.New(logBuffer, st.LogSink()), nil
+	})
 
 	// If not a local provider bootstrap machine, start the worker to
 	// manage SSH keys.
@@ -308,6 +314,17 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 	if err := a.setupContainerSupport(runner, st, entity, agentConfig); err != nil {
 		return nil, fmt.Errorf("setting up container support: %v", err)
 	}
+	isStateServer := false
+	for _, job := range entity.Jobs() {
+		if job == params.JobManageEnviron {
+			isStateServer = true
+		}
+	}
+	if !isStateServer {
+		a.startWorkerAfterUpgrade(runner, "stateconverter", func() (worker.Worker, error) {
+			return newStateConverter(apiStateConverter{st, entity.Tag()}), nil
+		})
+	}
 	for _, job := range entity.Jobs() {
 		switch job {
 		case params.JobHostUnits:
@@ -491,6 +508,13 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "minunitsworker", func() (worker.Worker, error) {
 				return minunitsworker.NewMinUnitsWorker(st), nil
 			})
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				paths := backups.Paths{
+					DataDir: agentConfig.DataDir(),
+					LogDir:  agentConfig.LogDir(),
+				}
+				return backupscheduler.NewBackupScheduler(st, paths), nil
+			})
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/uniter/jujuc"
 	"github.com/juju/utils/exec"
 
@@ -92,6 +93,10 @@ func jujuCMain(commandName string, args []string) (code int, err error) {
 	return resp.Code, nil
 }
 
+// logBuffer holds the messages logged by the agent until the logsender
+// worker sends them to the API server.
+var logBuffer = logsender.NewLogBuffer(10000)
+
 // Main registers subcommands for the jujud executable, and hands over control
 // to the cmd package.
 func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
@@ -104,6 +109,9 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	jujud.Register(&MachineAgent{})
 	jujud.Register(&UnitAgent{})
 	jujud.Register(&cmd.VersionCommand{})
+	if err := loggo.RegisterWriter("logsender", logBuffer, loggo.TRACE); err != nil {
+		return 1, err
+	}
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
 }
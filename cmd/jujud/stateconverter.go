@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/watcher"
+	"github.com/juju/juju/worker"
+)
+
+// stateConverterAPI holds the calls the state converter makes on the
+// API for the agent's machine.
+type stateConverterAPI interface {
+	// WatchMachine returns a watcher notifying of changes to the
+	// machine.
+	WatchMachine() (watcher.NotifyWatcher, error)
+	// Jobs returns the current jobs of the machine.
+	Jobs() ([]params.MachineJob, error)
+}
+
+// stateConverter is a NotifyWatchHandler that stops the agent when its
+// machine is given the job of managing the environment, as it is when
+// "juju ensure-availability --to" converts it into a state server. The
+// agent is then restarted, and starts the state server workers.
+type stateConverter struct {
+	api stateConverterAPI
+}
+
+// newStateConverter returns a worker that stops the agent when its
+// machine becomes a state server.
+func newStateConverter(api stateConverterAPI) worker.Worker {
+	return worker.NewNotifyWorker(&stateConverter{api: api})
+}
+
+func (c *stateConverter) SetUp() (watcher.NotifyWatcher, error) {
+	return c.api.WatchMachine()
+}
+
+func (c *stateConverter) Handle() error {
+	jobs, err := c.api.Jobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job == params.JobManageEnviron {
+			return &fatalError{"machine is now a state server; restarting agent"}
+		}
+	}
+	return nil
+}
+
+func (c *stateConverter) TearDown() error {
+	return nil
+}
+
+// apiStateConverter implements stateConverterAPI with an API connection.
+type apiStateConverter struct {
+	st  *api.State
+	tag string
+}
+
+func (a apiStateConverter) WatchMachine() (watcher.NotifyWatcher, error) {
+	m, err := a.st.Machiner().Machine(a.tag)
+	if err != nil {
+		return nil, err
+	}
+	return m.Watch()
+}
+
+func (a apiStateConverter) Jobs() ([]params.MachineJob, error) {
+	entity, err := a.st.Agent().Entity(a.tag)
+	if err != nil {
+		return nil, err
+	}
+	return entity.Jobs(), nil
+}
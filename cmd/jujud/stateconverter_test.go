@@ -0,0 +1,80 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/watcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type stateConverterSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&stateConverterSuite{})
+
+func (s *stateConverterSuite) TestStopsAgentWhenStateServer(c *gc.C) {
+	api := &fakeStateConverterAPI{
+		jobs:    []params.MachineJob{params.JobHostUnits},
+		changes: make(chan struct{}, 1),
+	}
+	api.changes <- struct{}{}
+	w := newStateConverter(api)
+	defer w.Kill()
+
+	// The worker keeps running while the machine is not a state server.
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Wait()
+	}()
+	select {
+	case err := <-done:
+		c.Fatalf("worker stopped unexpectedly: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	api.jobs = []params.MachineJob{params.JobHostUnits, params.JobManageEnviron}
+	api.changes <- struct{}{}
+	select {
+	case err := <-done:
+		c.Assert(err, gc.ErrorMatches, "machine is now a state server; restarting agent")
+		c.Assert(isFatal(err), gc.Equals, true)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for worker to stop")
+	}
+}
+
+type fakeStateConverterAPI struct {
+	jobs    []params.MachineJob
+	changes chan struct{}
+}
+
+func (f *fakeStateConverterAPI) WatchMachine() (watcher.NotifyWatcher, error) {
+	return &fakeNotifyWatcher{changes: f.changes}, nil
+}
+
+func (f *fakeStateConverterAPI) Jobs() ([]params.MachineJob, error) {
+	return f.jobs, nil
+}
+
+type fakeNotifyWatcher struct {
+	changes chan struct{}
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (*fakeNotifyWatcher) Stop() error {
+	return nil
+}
+
+func (*fakeNotifyWatcher) Err() error {
+	return nil
+}
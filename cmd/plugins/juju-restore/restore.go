@@ -4,21 +4,16 @@
 package main
 
 import (
-	"archive/tar"
-	"bytes"
-	"compress/gzip"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
-	"path"
-	"strconv"
-	"text/template"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
 	"launchpad.net/gnuflag"
-	"launchpad.net/goyaml"
 
 	"github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/envcmd"
@@ -29,11 +24,10 @@ import (
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
-	"github.com/juju/juju/network"
 	_ "github.com/juju/juju/provider/all"
-	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api"
-	"github.com/juju/juju/utils/ssh"
+	"github.com/juju/juju/state/api/backups"
+	"github.com/juju/juju/state/api/params"
 )
 
 func main() {
@@ -56,7 +50,7 @@ func Main(args []string) {
 var logger = loggo.GetLogger("juju.plugins.restore")
 
 const restoreDoc = `
-Restore restores a backup created with juju backup
+Restore restores a backup created with juju backups
 by creating a new juju bootstrap instance and arranging
 it so that the existing instances in the environment
 talk to it.
@@ -64,6 +58,14 @@ talk to it.
 It verifies that the existing bootstrap instance is
 not running. The given constraints will be used
 to choose the new instance.
+
+The backup is uploaded to the new instance, which
+restores the environment's state from it and restarts
+its machine agent. The restored state holds the API
+addresses of the new instance, which the agents of the
+existing instances learn and record in their
+configuration when they connect. Restore waits until
+they have all reconnected.
 `
 
 type restoreCommand struct {
@@ -77,7 +79,7 @@ type restoreCommand struct {
 func (c *restoreCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "juju-restore",
-		Purpose: "Restore a backup made with juju backup",
+		Purpose: "Restore a backup made with juju backups",
 		Args:    "<backupfile.tar.gz>",
 		Doc:     restoreDoc,
 	}
@@ -100,108 +102,6 @@ func (c *restoreCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args[1:])
 }
 
-var updateBootstrapMachineTemplate = mustParseTemplate(`
-	set -exu
-
-	export LC_ALL=C
-	tar xzf juju-backup.tgz
-	test -d juju-backup
-	apt-get --option=Dpkg::Options::=--force-confold --option=Dpkg::options::=--force-unsafe-io --assume-yes --quiet install mongodb-clients
-	
-	initctl stop jujud-machine-0
-
-	initctl stop juju-db
-	rm -r /var/lib/juju
-	rm -r /var/log/juju
-
-	tar -C / -xvp -f juju-backup/root.tar
-	mkdir -p /var/lib/juju/db
-
-	# Prefer jujud-mongodb binaries if available 
-	export MONGORESTORE=mongorestore
-	if [ -f /usr/lib/juju/bin/mongorestore ]; then
-		export MONGORESTORE=/usr/lib/juju/bin/mongorestore;
-	fi	
-	$MONGORESTORE --drop --dbpath /var/lib/juju/db juju-backup/dump
-
-	initctl start juju-db
-
-	mongoAdminEval() {
-		mongo --ssl -u admin -p {{.AgentConfig.Credentials.OldPassword | shquote}} localhost:{{.AgentConfig.StatePort}}/admin --eval "$1"
-	}
-
-
-	mongoEval() {
-		mongo --ssl -u {{.AgentConfig.Credentials.Tag}} -p {{.AgentConfig.Credentials.Password | shquote}} localhost:{{.AgentConfig.StatePort}}/juju --eval "$1"
-	}
-
-	# wait for mongo to come up after starting the juju-db upstart service.
-	for i in $(seq 1 100)
-	do
-		mongoEval ' ' && break
-		sleep 5
-	done
-
-	# Create a new replicaSet conf and re initiate it
-	mongoAdminEval '
-		conf = { "_id" : "juju", "version" : 1, "members" : [ { "_id" : 1, "host" : "{{ .PrivateAddress | printf "%s:"}}{{.AgentConfig.StatePort}}" , "tags" : { "juju-machine-id" : "0" } }]}
-		rs.initiate(conf)
-	'
-
-	sleep 60
-
-	# Remove all state machines but 0, to restore HA
-	mongoEval '
-		db = db.getSiblingDB("juju")
-		db.machines.update({_id: "0"}, {$set: {instanceid: {{.NewInstanceId | printf "%q" }} } })
-		db.instanceData.update({_id: "0"}, {$set: {instanceid: {{.NewInstanceId | printf "%q" }} } })
-		db.machines.remove({_id: {$ne:"0"}, hasvote: true})
-		db.stateServers.update({"_id":"e"}, {$set:{"machineids" : [0]}})
-		db.stateServers.update({"_id":"e"}, {$set:{"votingmachineids" : [0]}})
-	'
-	
-
-
-	# Give time to replset to initiate
-	for i in $(seq 1 20)
-	do
-		mongoEval ' ' && break
-		sleep 5
-	done
-
-	initctl stop juju-db
-
-	# Update the agent.conf for machine-0 with the new addresses
-	cd /var/lib/juju/agents
-
-	# Remove extra state machines from conf
-	REMOVECOUNT=$(grep -Ec "^-.*{{.AgentConfig.ApiPort}}$" /var/lib/juju/agents/machine-0/agent.conf )
-	awk '/\-.*{{.AgentConfig.ApiPort}}$/{i++}i<1' machine-0/agent.conf > machine-0/agent.conf.new
-	awk -v removecount=$REMOVECOUNT '/\-.*{{.AgentConfig.ApiPort}}$/{i++}i==removecount' machine-0/agent.conf >> machine-0/agent.conf.new
-	mv machine-0/agent.conf.new  machine-0/agent.conf
-
-	sed -i.old -r -e "/^(stateaddresses):/{
-		n
-		s/- .*(:[0-9]+)/- {{.Address}}\1/
-	}" -e "/^(apiaddresses):/{
-		n
-		s/- .*(:[0-9]+)/- {{.PrivateAddress}}\1/
-	}"  machine-0/agent.conf
-	
-
-	initctl start juju-db
-	initctl start jujud-machine-0
-`)
-
-func updateBootstrapMachineScript(instanceId instance.Id, agentConf agentConfig, addr, paddr string) string {
-	return execTemplate(updateBootstrapMachineTemplate, struct {
-		NewInstanceId  instance.Id
-		AgentConfig    agentConfig
-		Address        string
-		PrivateAddress string
-	}{instanceId, agentConf, addr, paddr})
-}
-
 func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	if c.showDescription {
 		fmt.Fprintf(ctx.Stdout, "%s\n", c.Info().Purpose)
@@ -210,11 +110,11 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	if err := c.Log.Start(ctx); err != nil {
 		return err
 	}
-	agentConf, err := extractConfig(c.backupFile)
+	archive, err := os.Open(c.backupFile)
 	if err != nil {
-		return fmt.Errorf("cannot extract configuration from backup file: %v", err)
+		return fmt.Errorf("cannot open backup file: %v", err)
 	}
-	progress("extracted credentials from backup file")
+	defer archive.Close()
 	store, err := configstore.Default()
 	if err != nil {
 		return err
@@ -227,13 +127,21 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return fmt.Errorf("cannot re-bootstrap environment: %v", err)
 	}
+	return restore(env, archive)
+}
+
+// restore restores the environment's state from the backup archive
+// onto the newly bootstrapped instance of env, and waits for the agents
+// of the existing instances to reconnect to it.
+func restore(env environs.Environ, archive io.Reader) error {
 	progress("connecting to newly bootstrapped instance")
 	conn, err := juju.NewAPIConn(env, api.DefaultDialOpts())
 	if err != nil {
 		return fmt.Errorf("cannot connect to bootstrap instance: %v", err)
 	}
 	progress("restoring bootstrap machine")
-	newInstId, machine0Addr, err := restoreBootstrapMachine(conn, c.backupFile, agentConf)
+	newInstId, err := restoreBootstrapMachine(conn.State, archive)
+	conn.Close()
 	if err != nil {
 		return fmt.Errorf("cannot restore bootstrap machine: %v", err)
 	}
@@ -244,28 +152,14 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	}); err != nil {
 		return fmt.Errorf("cannot update environ bootstrap state storage: %v", err)
 	}
-	// Construct our own state info rather than using juju.NewConn so
-	// that we can avoid storage eventual-consistency issues
-	// (and it's faster too).
-	caCert, ok := cfg.CACert()
-	if !ok {
-		return fmt.Errorf("configuration has no CA certificate")
-	}
-	progress("opening state")
-	st, err := state.Open(&state.Info{
-		Addrs:    []string{fmt.Sprintf("%s:%d", machine0Addr, cfg.StatePort())},
-		CACert:   caCert,
-		Tag:      agentConf.Credentials.Tag,
-		Password: agentConf.Credentials.Password,
-	}, state.DefaultDialOpts(), environs.NewStatePolicy())
+	progress("reconnecting to restored bootstrap machine")
+	conn, err = reconnect(env)
 	if err != nil {
-		return fmt.Errorf("cannot open state: %v", err)
-	}
-	progress("updating all machines")
-	if err := updateAllMachines(st, machine0Addr); err != nil {
-		return fmt.Errorf("cannot update machines: %v", err)
+		return fmt.Errorf("cannot connect to restored bootstrap machine: %v", err)
 	}
-	return nil
+	defer conn.Close()
+	progress("waiting for agents to reconnect")
+	return waitForAgents(conn.State.Client())
 }
 
 func progress(f string, a ...interface{}) {
@@ -321,237 +215,110 @@ func rebootstrap(cfg *config.Config, ctx *cmd.Context, cons constraints.Value) (
 	return env, nil
 }
 
-func restoreBootstrapMachine(conn *juju.APIConn, backupFile string, agentConf agentConfig) (newInstId instance.Id, addr string, err error) {
-	client := conn.State.Client()
-	addr, err = client.PublicAddress("0")
+// restoreBootstrapMachine uploads the backup archive read from r to the
+// newly bootstrapped machine and restores the environment's state from
+// it. The machine agent restarts by itself once the state has been
+// restored. It returns the machine's instance id.
+func restoreBootstrapMachine(st *api.State, archive io.Reader) (instance.Id, error) {
+	status, err := st.Client().Status(nil)
 	if err != nil {
-		return "", "", fmt.Errorf("cannot get public address of bootstrap machine: %v", err)
-	}
-	paddr, err := client.PrivateAddress("0")
-	if err != nil {
-		return "", "", fmt.Errorf("cannot get private address of bootstrap machine: %v", err)
-	}
-	status, err := client.Status(nil)
-	if err != nil {
-		return "", "", fmt.Errorf("cannot get environment status: %v", err)
+		return "", fmt.Errorf("cannot get environment status: %v", err)
 	}
 	info, ok := status.Machines["0"]
 	if !ok {
-		return "", "", fmt.Errorf("cannot find bootstrap machine in status")
+		return "", fmt.Errorf("cannot find bootstrap machine in status")
 	}
-	newInstId = instance.Id(info.InstanceId)
-
-	progress("copying backup file to bootstrap host")
-	if err := sendViaScp(backupFile, addr, "~/juju-backup.tgz"); err != nil {
-		return "", "", fmt.Errorf("cannot copy backup file to bootstrap instance: %v", err)
+	backupsClient := backups.NewClient(st)
+	progress("uploading backup file to bootstrap machine")
+	meta, err := backupsClient.Upload(archive, "uploaded by juju-restore")
+	if err != nil {
+		return "", fmt.Errorf("cannot upload backup file: %v", err)
 	}
-	progress("updating bootstrap machine")
-	if err := runViaSsh(addr, updateBootstrapMachineScript(newInstId, agentConf, addr, paddr)); err != nil {
-		return "", "", fmt.Errorf("update script failed: %v", err)
+	progress("restoring state from backup %s", meta.ID)
+	if err := backupsClient.Restore(meta.ID); err != nil {
+		return "", fmt.Errorf("cannot restore backup: %v", err)
 	}
-	return newInstId, addr, nil
-}
-
-type credentials struct {
-	Tag         string
-	Password    string
-	OldPassword string
+	return info.InstanceId, nil
 }
 
-type agentConfig struct {
-	Credentials credentials
-	ApiPort     string
-	StatePort   string
+// reconnectAttempt governs how long to keep trying to connect to the
+// restored bootstrap machine while its agent restarts.
+var reconnectAttempt = utils.AttemptStrategy{
+	Total: 5 * time.Minute,
+	Delay: 5 * time.Second,
 }
 
-func extractConfig(backupFile string) (agentConfig, error) {
-	f, err := os.Open(backupFile)
-	if err != nil {
-		return agentConfig{}, err
-	}
-	defer f.Close()
-	gzr, err := gzip.NewReader(f)
-	if err != nil {
-		return agentConfig{}, fmt.Errorf("cannot unzip %q: %v", backupFile, err)
-	}
-	defer gzr.Close()
-	outerTar, err := findFileInTar(gzr, "juju-backup/root.tar")
-	if err != nil {
-		return agentConfig{}, err
-	}
-	agentConf, err := findFileInTar(outerTar, "var/lib/juju/agents/machine-0/agent.conf")
-	if err != nil {
-		return agentConfig{}, err
-	}
-	data, err := ioutil.ReadAll(agentConf)
-	if err != nil {
-		return agentConfig{}, fmt.Errorf("failed to read agent config file: %v", err)
-	}
-	var conf interface{}
-	if err := goyaml.Unmarshal(data, &conf); err != nil {
-		return agentConfig{}, fmt.Errorf("cannot unmarshal agent config file: %v", err)
-	}
-	m, ok := conf.(map[interface{}]interface{})
-	if !ok {
-		return agentConfig{}, fmt.Errorf("config file unmarshalled to %T not %T", conf, m)
-	}
-	password, ok := m["statepassword"].(string)
-	if !ok || password == "" {
-		return agentConfig{}, fmt.Errorf("agent password not found in configuration")
-	}
-	oldPassword, ok := m["oldpassword"].(string)
-	if !ok || oldPassword == "" {
-		return agentConfig{}, fmt.Errorf("agent old password not found in configuration")
-	}
-	statePortNum, ok := m["stateport"].(int)
-	if !ok {
-		return agentConfig{}, fmt.Errorf("state port not found in configuration")
+// reconnect connects to the API server of the given environment once
+// it is available again.
+func reconnect(env environs.Environ) (conn *juju.APIConn, err error) {
+	for a := reconnectAttempt.Start(); a.Next(); {
+		conn, err = juju.NewAPIConn(env, api.DefaultDialOpts())
+		if err == nil {
+			return conn, nil
+		}
+		logger.Debugf("cannot connect yet: %v", err)
 	}
+	return nil, err
+}
 
-	statePort := strconv.Itoa(statePortNum)
-	apiPortNum, ok := m["apiport"].(int)
-	if !ok {
-		return agentConfig{}, fmt.Errorf("api port not found in configuration")
-	}
-	apiPort := strconv.Itoa(apiPortNum)
+// statusAPI is the part of the API client used to check the agents.
+type statusAPI interface {
+	Status(patterns []string) (*api.Status, error)
+}
 
-	return agentConfig{
-		Credentials: credentials{
-			Tag:         "machine-0",
-			Password:    password,
-			OldPassword: oldPassword,
-		},
-		StatePort: statePort,
-		ApiPort:   apiPort,
-	}, nil
+// agentsAttempt governs how long to wait for the agents to reconnect
+// to the restored state server.
+var agentsAttempt = utils.AttemptStrategy{
+	Total: 10 * time.Minute,
+	Delay: 10 * time.Second,
 }
 
-func findFileInTar(r io.Reader, name string) (io.Reader, error) {
-	tarr := tar.NewReader(r)
-	for {
-		hdr, err := tarr.Next()
+// waitForAgents waits until no machine or unit agent is reported as
+// down, which shows that all the agents have reconnected to the
+// restored state server.
+func waitForAgents(client statusAPI) error {
+	var down []string
+	for a := agentsAttempt.Start(); a.Next(); {
+		status, err := client.Status(nil)
 		if err != nil {
-			return nil, fmt.Errorf("%q not found: %v", name, err)
+			return fmt.Errorf("cannot get environment status: %v", err)
 		}
-		if path.Clean(hdr.Name) == name {
-			return tarr, nil
+		down = downAgents(status)
+		if len(down) == 0 {
+			progress("all agents have reconnected")
+			return nil
 		}
+		logger.Debugf("agents still down: %s", strings.Join(down, ", "))
 	}
+	return fmt.Errorf("agents have not reconnected: %s", strings.Join(down, ", "))
 }
 
-var agentAddressTemplate = mustParseTemplate(`
-set -exu
-cd /var/lib/juju/agents
-for agent in *
-do
-	initctl stop jujud-$agent
-	sed -i.old -r "/^(stateaddresses|apiaddresses):/{
-		n
-		s/- .*(:[0-9]+)/- {{.Address}}\1/
-	}" $agent/agent.conf
-
-	# If we're processing a unit agent's directly
-	# and it has some relations, reset
-	# the stored version of all of them to
-	# ensure that any relation hooks will
-	# fire.
-	if [[ $agent = unit-* ]]
-	then
-		find $agent/state/relations -type f -exec sed -i -r 's/change-version: [0-9]+$/change-version: 0/' {} \;
-	fi
-	initctl start jujud-$agent
-done
-`)
-
-// setAgentAddressScript generates an ssh script argument to update state addresses
-func setAgentAddressScript(stateAddr string) string {
-	return execTemplate(agentAddressTemplate, struct {
-		Address string
-	}{stateAddr})
-}
-
-// updateAllMachines finds all machines and resets the stored state address
-// in each of them. The address does not include the port.
-func updateAllMachines(st *state.State, stateAddr string) error {
-	machines, err := st.AllMachines()
-	if err != nil {
-		return err
-	}
-	pendingMachineCount := 0
-	done := make(chan error)
-	for _, machine := range machines {
-		// A newly resumed state server requires no updating, and more
-		// than one state server is not yet support by this plugin.
-		if machine.IsManager() || machine.Life() == state.Dead {
-			continue
-		}
-		pendingMachineCount++
-		machine := machine
-		go func() {
-			err := runMachineUpdate(machine, setAgentAddressScript(stateAddr))
-			if err != nil {
-				logger.Errorf("failed to update machine %s: %v", machine, err)
-			} else {
-				progress("updated machine %s", machine)
+// downAgents returns the sorted names of the agents reported as down
+// in the given status.
+func downAgents(status *api.Status) []string {
+	var down []string
+	var addMachines func(map[string]api.MachineStatus)
+	addMachines = func(machines map[string]api.MachineStatus) {
+		for id, machine := range machines {
+			if machine.AgentState == params.StatusDown {
+				down = append(down, "machine-"+id)
 			}
-			done <- err
-		}()
-	}
-	err = nil
-	for ; pendingMachineCount > 0; pendingMachineCount-- {
-		if updateErr := <-done; updateErr != nil && err == nil {
-			err = fmt.Errorf("machine update failed")
+			addMachines(machine.Containers)
 		}
 	}
-	return err
-}
-
-// runMachineUpdate connects via ssh to the machine and runs the update script
-func runMachineUpdate(m *state.Machine, sshArg string) error {
-	progress("updating machine: %v\n", m)
-	addr := network.SelectPublicAddress(m.Addresses())
-	if addr == "" {
-		return fmt.Errorf("no appropriate public address found")
-	}
-	return runViaSsh(addr, sshArg)
-}
-
-func runViaSsh(addr string, script string) error {
-	// This is taken from cmd/juju/ssh.go there is no other clear way to set user
-	userAddr := "ubuntu@" + addr
-	cmd := ssh.Command(userAddr, []string{"sudo", "-n", "bash", "-c " + utils.ShQuote(script)}, nil)
-	var stderrBuf bytes.Buffer
-	var stdoutBuf bytes.Buffer
-	cmd.Stderr = &stderrBuf
-	cmd.Stdout = &stdoutBuf
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("ssh command failed: %v (%q)", err, stderrBuf.String())
-	}
-	progress("ssh command succedded: %q", stdoutBuf.String())
-	return nil
-}
-
-func sendViaScp(file, host, destFile string) error {
-	err := ssh.Copy([]string{file, "ubuntu@" + host + ":" + destFile}, nil)
-	if err != nil {
-		return fmt.Errorf("scp command failed: %v", err)
+	var addUnits func(map[string]api.UnitStatus)
+	addUnits = func(units map[string]api.UnitStatus) {
+		for name, unit := range units {
+			if unit.AgentState == params.StatusDown {
+				down = append(down, name)
+			}
+			addUnits(unit.Subordinates)
+		}
 	}
-	return nil
-}
-
-func mustParseTemplate(templ string) *template.Template {
-	t := template.New("").Funcs(template.FuncMap{
-		"shquote": utils.ShQuote,
-	})
-	return template.Must(t.Parse(templ))
-}
-
-func execTemplate(tmpl *template.Template, data interface{}) string {
-	var buf bytes.Buffer
-	err := tmpl.Execute(&buf, data)
-	if err != nil {
-		panic(fmt.Errorf("template error: %v", err))
+	addMachines(status.Machines)
+	for _, service := range status.Services {
+		addUnits(service.Units)
 	}
-	return buf.String()
+	sort.Strings(down)
+	return down
 }
@@ -0,0 +1,215 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/utils"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/instance"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+func Test(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+type restoreSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+// fakeStatusAPI returns each of its statuses in turn, repeating the
+// last one.
+type fakeStatusAPI struct {
+	statuses []*api.Status
+	calls    int
+}
+
+func (f *fakeStatusAPI) Status(patterns []string) (*api.Status, error) {
+	i := f.calls
+	if i >= len(f.statuses) {
+		i = len(f.statuses) - 1
+	}
+	f.calls++
+	return f.statuses[i], nil
+}
+
+func (s *restoreSuite) TestWaitForAgents(c *gc.C) {
+	s.PatchValue(&agentsAttempt, utils.AttemptStrategy{Min: 3})
+	down := &api.Status{
+		Machines: map[string]api.MachineStatus{
+			"0": {AgentState: params.StatusStarted},
+			"1": {AgentState: params.StatusDown},
+		},
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {Units: map[string]api.UnitStatus{
+				"wordpress/0": {
+					AgentState: params.StatusStarted,
+					Subordinates: map[string]api.UnitStatus{
+						"logging/0": {AgentState: params.StatusDown},
+					},
+				},
+			}},
+		},
+	}
+	up := &api.Status{
+		Machines: map[string]api.MachineStatus{
+			"0": {AgentState: params.StatusStarted},
+			"1": {AgentState: params.StatusStarted},
+		},
+	}
+	c.Assert(downAgents(down), gc.DeepEquals, []string{"logging/0", "machine-1"})
+
+	client := &fakeStatusAPI{statuses: []*api.Status{down, up}}
+	err := waitForAgents(client)
+	c.Assert(err, gc.IsNil)
+	c.Assert(client.calls, gc.Equals, 2)
+}
+
+func (s *restoreSuite) TestWaitForAgentsTimesOut(c *gc.C) {
+	s.PatchValue(&agentsAttempt, utils.AttemptStrategy{
+		Total: 10 * time.Millisecond,
+		Delay: time.Millisecond,
+	})
+	client := &fakeStatusAPI{statuses: []*api.Status{{
+		Machines: map[string]api.MachineStatus{
+			"1": {AgentState: params.StatusDown},
+		},
+	}}}
+	err := waitForAgents(client)
+	c.Assert(err, gc.ErrorMatches, "agents have not reconnected: machine-1")
+}
+
+// restoreEnvSuite restores backups onto the state server of a dummy
+// environment, as if it had just been bootstrapped.
+type restoreEnvSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&restoreEnvSuite{})
+
+// writeAgentConfig writes the agent configuration of the state server's
+// machine agent, with the given password and value for the "origin"
+// key, and returns its contents.
+func (s *restoreEnvSuite) writeAgentConfig(c *gc.C, password, origin string) []byte {
+	conf, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		DataDir:           s.DataDir(),
+		Tag:               "machine-0",
+		Password:          password,
+		UpgradedToVersion: version.Current.Number,
+		CACert:            testing.CACert,
+		StateAddresses:    []string{"localhost:37017"},
+		APIAddresses:      []string{"old-host:17070"},
+		Values:            map[string]string{"origin": origin},
+	})
+	c.Assert(err, gc.IsNil)
+	conf.SetPassword(password)
+	err = conf.Write()
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(agent.ConfigPath(s.DataDir(), "machine-0"))
+	c.Assert(err, gc.IsNil)
+	return data
+}
+
+// backupArchive returns a backup archive holding the given agent
+// configuration of the state server's machine agent and an empty
+// database dump.
+func (s *restoreEnvSuite) backupArchive(c *gc.C, agentConfig []byte) []byte {
+	var files bytes.Buffer
+	tw := tar.NewWriter(&files)
+	name := strings.TrimPrefix(agent.ConfigPath(s.DataDir(), "machine-0"), "/")
+	err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(agentConfig))})
+	c.Assert(err, gc.IsNil)
+	_, err = tw.Write(agentConfig)
+	c.Assert(err, gc.IsNil)
+	c.Assert(tw.Close(), gc.IsNil)
+
+	var archive bytes.Buffer
+	gzw := gzip.NewWriter(&archive)
+	tw = tar.NewWriter(gzw)
+	for _, dir := range []string{"juju-backup/", "juju-backup/dump/"} {
+		err = tw.WriteHeader(&tar.Header{Name: dir, Mode: 0700, Typeflag: tar.TypeDir})
+		c.Assert(err, gc.IsNil)
+	}
+	err = tw.WriteHeader(&tar.Header{Name: "juju-backup/root.tar", Mode: 0600, Size: int64(files.Len())})
+	c.Assert(err, gc.IsNil)
+	_, err = tw.Write(files.Bytes())
+	c.Assert(err, gc.IsNil)
+	c.Assert(tw.Close(), gc.IsNil)
+	c.Assert(gzw.Close(), gc.IsNil)
+	return archive.Bytes()
+}
+
+func (s *restoreEnvSuite) TestRestoreBootstrapMachine(c *gc.C) {
+	// Add the bootstrap machine and publish its API addresses, as
+	// bootstrapping the new instance does.
+	m, err := s.BackingState.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	err = m.SetProvisioned("new-instance", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	err = s.BackingState.SetAPIHostPorts(hostPorts)
+	c.Assert(err, gc.IsNil)
+
+	archive := s.backupArchive(c, s.writeAgentConfig(c, "backed-up-password-0", "backup"))
+	s.writeAgentConfig(c, "new-server-password-0", "new server")
+	bin := c.MkDir()
+	err = ioutil.WriteFile(filepath.Join(bin, "mongorestore"), []byte("#!/bin/sh\ncat >/dev/null\n"), 0755)
+	c.Assert(err, gc.IsNil)
+	s.PatchEnvPathPrepend(bin)
+
+	instId, err := restoreBootstrapMachine(s.APIState, bytes.NewReader(archive))
+	c.Assert(err, gc.IsNil)
+	c.Assert(instId, gc.Equals, instance.Id("new-instance"))
+
+	// The API server stops once the backup has been restored, so that
+	// the machine agent restarts.
+	for a := testing.LongAttempt.Start(); ; {
+		if err := s.APIState.Ping(); err != nil {
+			break
+		}
+		if !a.Next() {
+			c.Fatalf("API server still running after restore")
+		}
+	}
+
+	// The restored state holds the new state server's API addresses,
+	// which the agents pick up when they connect.
+	restoredHostPorts, err := s.BackingState.APIHostPorts()
+	c.Assert(err, gc.IsNil)
+	c.Assert(restoredHostPorts, gc.DeepEquals, hostPorts)
+	err = m.Refresh()
+	c.Assert(err, gc.IsNil)
+	instId, err = m.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instId, gc.Equals, instance.Id("new-instance"))
+
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.DataDir(), "machine-0"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(conf.Value("origin"), gc.Equals, "backup")
+	c.Assert(conf.APIInfo().Password, gc.Equals, "new-server-password-0")
+	addrs, err := conf.APIAddresses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(addrs, gc.DeepEquals, []string{"10.0.0.1:17070"})
+}
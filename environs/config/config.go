@@ -59,6 +59,10 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultUpdateStatusInterval is the default interval at
+	// which the update-status hook is run on each unit.
+	DefaultUpdateStatusInterval = 5 * time.Minute
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "precise"
@@ -322,6 +326,29 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Check the backup schedule and retention policy.
+	for _, attr := range []string{"backup-schedule", "backup-retention-age"} {
+		if v, ok := cfg.defined[attr].(string); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s in environment configuration: %q", attr, v)
+			}
+			if d < 0 {
+				return fmt.Errorf("negative %s in environment configuration: %q", attr, v)
+			}
+		}
+	}
+	if count := cfg.BackupRetentionCount(); count < 0 {
+		return fmt.Errorf("negative backup-retention-count in environment configuration: %d", count)
+	}
+
+	// Check the update-status hook interval.
+	if v, ok := cfg.defined["update-status-interval"].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("invalid update-status-interval in environment configuration: %q", v)
+		}
+	}
+
 	// Check firewall mode.
 	if mode := cfg.FirewallMode(); mode != FwInstance && mode != FwGlobal {
 		return fmt.Errorf("invalid firewall mode in environment configuration: %q", mode)
@@ -691,6 +718,43 @@ func (c *Config) ProvisionerSafeMode() bool {
 	return v
 }
 
+// DisableRsyslog reports whether agents should stop forwarding their
+// logs through rsyslog, leaving them to be sent over the API only.
+func (c *Config) DisableRsyslog() bool {
+	v, _ := c.defined["disable-rsyslog"].(bool)
+	return v
+}
+
+// BackupSchedule returns the interval at which backups of the state
+// server are taken, or zero if no backups are scheduled.
+func (c *Config) BackupSchedule() time.Duration {
+	d, _ := time.ParseDuration(c.asString("backup-schedule"))
+	return d
+}
+
+// BackupRetentionCount returns the number of scheduled backups to
+// keep, or zero if they are not limited by number.
+func (c *Config) BackupRetentionCount() int {
+	v, _ := c.defined["backup-retention-count"].(int)
+	return v
+}
+
+// BackupRetentionAge returns the age beyond which scheduled backups are
+// removed, or zero if they are not limited by age.
+func (c *Config) BackupRetentionAge() time.Duration {
+	d, _ := time.ParseDuration(c.asString("backup-retention-age"))
+	return d
+}
+
+// UpdateStatusInterval returns the interval at which the
+// update-status hook is run on each unit.
+func (c *Config) UpdateStatusInterval() time.Duration {
+	if d, err := time.ParseDuration(c.asString("update-status-interval")); err == nil {
+		return d
+	}
+	return DefaultUpdateStatusInterval
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -801,6 +865,11 @@ var fields = schema.Fields{
 	"proxy-ssh":                 schema.Bool(),
 	"lxc-clone":                 schema.Bool(),
 	"lxc-clone-aufs":            schema.Bool(),
+	"backup-schedule":           schema.String(),
+	"backup-retention-count":    schema.ForceInt(),
+	"backup-retention-age":      schema.String(),
+	"disable-rsyslog":           schema.Bool(),
+	"update-status-interval":    schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	"tools-url":     schema.String(),
@@ -836,6 +905,11 @@ var alwaysOptional = schema.Defaults{
 	"apt-https-proxy":           schema.Omit,
 	"apt-ftp-proxy":             schema.Omit,
 	"lxc-clone":                 schema.Omit,
+	"backup-schedule":           schema.Omit,
+	"backup-retention-count":    schema.Omit,
+	"backup-retention-age":      schema.Omit,
+	"disable-rsyslog":           schema.Omit,
+	"update-status-interval":    schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
 	"tools-url":     "",
@@ -475,6 +475,77 @@ var configTests = []configTest{
 			"provisioner-safe-mode": "yes please",
 		},
 		err: `provisioner-safe-mode: expected bool, got string\("yes please"\)`,
+	}, {
+		about:       "disable-rsyslog on",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"disable-rsyslog": true,
+		},
+	}, {
+		about:       "disable-rsyslog incorrect",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"disable-rsyslog": "yes please",
+		},
+		err: `disable-rsyslog: expected bool, got string\("yes please"\)`,
+	}, {
+		about:       "backup schedule and retention policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"backup-schedule":        "24h",
+			"backup-retention-count": 7,
+			"backup-retention-age":   "720h",
+		},
+	}, {
+		about:       "invalid backup schedule",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backup-schedule": "daily",
+		},
+		err: `invalid backup-schedule in environment configuration: "daily"`,
+	}, {
+		about:       "negative backup retention age",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-retention-age": "-1h",
+		},
+		err: `negative backup-retention-age in environment configuration: "-1h"`,
+	}, {
+		about:       "negative backup retention count",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"backup-retention-count": -1,
+		},
+		err: `negative backup-retention-count in environment configuration: -1`,
+	}, {
+		about:       "update-status interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"update-status-interval": "30m",
+		},
+	}, {
+		about:       "invalid update-status interval",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"update-status-interval": "0s",
+		},
+		err: `invalid update-status-interval in environment configuration: "0s"`,
 	}, {
 		about:       "default image stream",
 		useDefaults: config.UseDefaults,
@@ -885,6 +956,9 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	testmode, _ := test.attrs["test-mode"].(bool)
 	c.Assert(cfg.TestMode(), gc.Equals, testmode)
 
+	disableRsyslog, _ := test.attrs["disable-rsyslog"].(bool)
+	c.Assert(cfg.DisableRsyslog(), gc.Equals, disableRsyslog)
+
 	series, _ := test.attrs["default-series"].(string)
 	if defaultSeries, ok := cfg.DefaultSeries(); ok {
 		c.Assert(defaultSeries, gc.Equals, series)
@@ -955,6 +1029,32 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerSafeMode(), gc.Equals, false)
 	}
+	if v, ok := test.attrs["backup-schedule"].(string); ok {
+		d, err := time.ParseDuration(v)
+		c.Assert(err, gc.IsNil)
+		c.Assert(cfg.BackupSchedule(), gc.Equals, d)
+	} else {
+		c.Assert(cfg.BackupSchedule(), gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["backup-retention-count"]; ok {
+		c.Assert(cfg.BackupRetentionCount(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.BackupRetentionCount(), gc.Equals, 0)
+	}
+	if v, ok := test.attrs["backup-retention-age"].(string); ok {
+		d, err := time.ParseDuration(v)
+		c.Assert(err, gc.IsNil)
+		c.Assert(cfg.BackupRetentionAge(), gc.Equals, d)
+	} else {
+		c.Assert(cfg.BackupRetentionAge(), gc.Equals, time.Duration(0))
+	}
+	if v, ok := test.attrs["update-status-interval"].(string); ok {
+		d, err := time.ParseDuration(v)
+		c.Assert(err, gc.IsNil)
+		c.Assert(cfg.UpdateStatusInterval(), gc.Equals, d)
+	} else {
+		c.Assert(cfg.UpdateStatusInterval(), gc.Equals, config.DefaultUpdateStatusInterval)
+	}
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
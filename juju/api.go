@@ -17,6 +17,9 @@ import (
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/actions"
+	"github.com/juju/juju/state/api/auditlog"
+	"github.com/juju/juju/state/api/backups"
 	"github.com/juju/juju/state/api/keymanager"
 	"github.com/juju/juju/state/api/usermanager"
 )
@@ -100,6 +103,36 @@ func NewKeyManagerClient(envName string) (*keymanager.Client, error) {
 	return keymanager.NewClient(st), nil
 }
 
+// NewActionsClient returns an api.actions.Client connected to the API Server for
+// the named environment. If envName is "", the default environment will be used.
+func NewActionsClient(envName string) (*actions.Client, error) {
+	st, err := newAPIClient(envName)
+	if err != nil {
+		return nil, err
+	}
+	return actions.NewClient(st), nil
+}
+
+// NewBackupsClient returns an api.backups.Client connected to the API Server for
+// the named environment. If envName is "", the default environment will be used.
+func NewBackupsClient(envName string) (*backups.Client, error) {
+	st, err := newAPIClient(envName)
+	if err != nil {
+		return nil, err
+	}
+	return backups.NewClient(st), nil
+}
+
+// NewAuditLogClient returns an api.auditlog.Client connected to the API Server for
+// the named environment. If envName is "", the default environment will be used.
+func NewAuditLogClient(envName string) (*auditlog.Client, error) {
+	st, err := newAPIClient(envName)
+	if err != nil {
+		return nil, err
+	}
+	return auditlog.NewClient(st), nil
+}
+
 func NewUserManagerClient(envName string) (*usermanager.Client, error) {
 	st, err := newAPIClient(envName)
 	if err != nil {
@@ -34,11 +34,19 @@ type DeployServiceParams struct {
 	Networks []string
 }
 
-// DeployService takes a charm and various parameters and deploys it.
-func DeployService(st *state.State, args DeployServiceParams) (*state.Service, error) {
+// CheckDeployService checks the parameters of a DeployService call
+// without changing the environment, and returns the config settings
+// the service would be deployed with. When args.Charm is nil, as it is
+// for a charm that has not been added to the environment yet, the
+// checks that depend on the charm are skipped. The placement of the
+// service's units is checked by CheckAddUnits.
+func CheckDeployService(args DeployServiceParams) (charm.Settings, error) {
 	if args.NumUnits > 1 && args.ToMachineSpec != "" {
 		return nil, fmt.Errorf("cannot use --num-units with --to")
 	}
+	if args.Charm == nil {
+		return nil, nil
+	}
 	settings, err := args.Charm.Config().ValidateSettings(args.ConfigSettings)
 	if err != nil {
 		return nil, err
@@ -51,6 +59,20 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, fmt.Errorf("subordinate service must be deployed without constraints")
 		}
 	}
+	return settings, nil
+}
+
+// DeployService takes a charm and various parameters and deploys it.
+func DeployService(st *state.State, args DeployServiceParams) (*state.Service, error) {
+	settings, err := CheckDeployService(args)
+	if err != nil {
+		return nil, err
+	}
+	if args.NumUnits > 0 {
+		if _, err := CheckAddUnits(st, args.ServiceName, args.NumUnits, args.ToMachineSpec); err != nil {
+			return nil, err
+		}
+	}
 	if args.ServiceOwner == "" {
 		args.ServiceOwner = "user-admin"
 	}
@@ -99,9 +121,70 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 	return service, nil
 }
 
+// UnitPlacement describes where AddUnits places a unit on a given
+// machine.
+type UnitPlacement struct {
+	// Machine holds the machine the unit is placed on, or inside
+	// which a new container is created for it.
+	Machine *state.Machine
+	// ContainerType holds the type of that new container, if any.
+	ContainerType instance.ContainerType
+}
+
+// CheckAddUnits checks that AddUnits can add n units of the named
+// service placed as machineIdSpec requires, without changing the
+// environment. It returns their placement, which is nil when no
+// machine is given and the units are assigned by policy.
+func CheckAddUnits(st *state.State, serviceName string, n int, machineIdSpec string) (*UnitPlacement, error) {
+	if machineIdSpec == "" {
+		return nil, nil
+	}
+	if n != 1 {
+		return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", serviceName)
+	}
+	// machineIdSpec may be an existing machine or container, eg 3/lxc/2
+	// or a new container on a machine, eg lxc:3
+	mid := machineIdSpec
+	var containerType instance.ContainerType
+	if specParts := strings.SplitN(machineIdSpec, ":", 2); len(specParts) > 1 {
+		if ctype, err := instance.ParseContainerType(specParts[0]); err == nil {
+			containerType, mid = ctype, specParts[1]
+		}
+	}
+	if !names.IsMachine(mid) {
+		return nil, fmt.Errorf("invalid force machine id %q", mid)
+	}
+	m, err := st.Machine(mid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot assign unit to machine: %v", err)
+	}
+	if m.Life() != state.Alive {
+		return nil, fmt.Errorf("cannot assign unit to machine: machine %s is not alive", mid)
+	}
+	if containerType != "" {
+		if supported, known := m.SupportedContainers(); known && !containerSupported(supported, containerType) {
+			return nil, fmt.Errorf("cannot assign unit to machine: machine %s cannot host %s containers", mid, containerType)
+		}
+	}
+	return &UnitPlacement{Machine: m, ContainerType: containerType}, nil
+}
+
+func containerSupported(supported []instance.ContainerType, containerType instance.ContainerType) bool {
+	for _, ctype := range supported {
+		if ctype == containerType {
+			return true
+		}
+	}
+	return false
+}
+
 // AddUnits starts n units of the given service and allocates machines
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
+	placement, err := CheckAddUnits(st, svc.Name(), n, machineIdSpec)
+	if err != nil {
+		return nil, err
+	}
 	units := make([]*state.Unit, n)
 	// Hard code for now till we implement a different approach.
 	policy := state.AssignCleanEmpty
@@ -116,37 +199,14 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 		if err != nil {
 			return nil, fmt.Errorf("cannot add unit %d/%d to service %q: %v", i+1, n, svc.Name(), err)
 		}
-		if machineIdSpec != "" {
-			if n != 1 {
-				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
-			}
-			// machineIdSpec may be an existing machine or container, eg 3/lxc/2
-			// or a new container on a machine, eg lxc:3
-			mid := machineIdSpec
-			var containerType instance.ContainerType
-			specParts := strings.SplitN(machineIdSpec, ":", 2)
-			if len(specParts) > 1 {
-				firstPart := specParts[0]
-				var err error
-				if containerType, err = instance.ParseContainerType(firstPart); err == nil {
-					mid = specParts[1]
-				} else {
-					mid = machineIdSpec
-				}
-			}
-			if !names.IsMachine(mid) {
-				return nil, fmt.Errorf("invalid force machine id %q", mid)
-			}
-			var unitCons *constraints.Value
-			unitCons, err = unit.Constraints()
-			if err != nil {
-				return nil, err
-			}
-
-			var err error
-			var m *state.Machine
+		if placement != nil {
+			m := placement.Machine
 			// If a container is to be used, create it.
-			if containerType != "" {
+			if placement.ContainerType != "" {
+				unitCons, err := unit.Constraints()
+				if err != nil {
+					return nil, err
+				}
 				// Create the new machine marked as dirty so that
 				// nothing else will grab it before we assign the unit to it.
 				template := state.MachineTemplate{
@@ -156,16 +216,12 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 					Constraints:       *unitCons,
 					RequestedNetworks: networks,
 				}
-				m, err = st.AddMachineInsideMachine(template, mid, containerType)
-			} else {
-				m, err = st.Machine(mid)
-			}
-			if err != nil {
-				return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unit.Name(), err)
+				m, err = st.AddMachineInsideMachine(template, m.Id(), placement.ContainerType)
+				if err != nil {
+					return nil, fmt.Errorf("cannot assign unit %q to machine: %v", unit.Name(), err)
+				}
 			}
-			err = unit.AssignToMachine(m)
-
-			if err != nil {
+			if err := unit.AssignToMachine(m); err != nil {
 				return nil, err
 			}
 		} else if err := st.AssignUnit(unit, policy); err != nil {
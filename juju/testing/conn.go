@@ -102,7 +102,7 @@ func (s *JujuConnSuite) Reset(c *gc.C) {
 }
 
 func (s *JujuConnSuite) AddUser(c *gc.C, username string) *state.User {
-	user, err := s.State.AddUser(username, "", "password")
+	user, err := s.State.AddUser(username, "", "password", "admin")
 	c.Assert(err, gc.IsNil)
 	return user
 }
@@ -248,7 +248,9 @@ func (state *environState) destroy() {
 		return
 	}
 	if state.apiServer != nil {
-		if err := state.apiServer.Stop(); err != nil && mongoAlive() {
+		// The API server stops by itself once a backup has been
+		// restored; a machine agent would then be restarted.
+		if err := state.apiServer.Stop(); err != nil && err != apiserver.ErrRestored && mongoAlive() {
 			panic(err)
 		}
 		state.apiServer = nil
@@ -628,7 +630,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 		if err := st.SetAdminMongoPassword(utils.UserPasswordHash(password, utils.CompatSalt)); err != nil {
 			panic(err)
 		}
-		_, err = st.AddUser("admin", "", password)
+		_, err = st.AddUser("admin", "", password, "")
 		if err != nil {
 			panic(err)
 		}
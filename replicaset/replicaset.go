@@ -372,6 +372,11 @@ type MemberStatus struct {
 	// between the remote member and the local instance.  It is zero for the
 	// member that the session is connected to.
 	Ping time.Duration `bson:"pingMS"`
+
+	// OpTime holds the time of the last operation the member applied
+	// from the oplog. The difference between the OpTime of the primary
+	// and that of a secondary is how far the secondary lags behind.
+	OpTime time.Time `bson:"optimeDate"`
 }
 
 // MemberState represents the state of a replica set member.
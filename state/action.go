@@ -6,9 +6,13 @@ package state
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
+	"labix.org/v2/mgo/bson"
 	"labix.org/v2/mgo/txn"
 )
 
@@ -27,8 +31,31 @@ type actionDoc struct {
 	// Payload holds the action's parameters, if any; it should validate
 	// against the schema defined by the named action in the unit's charm
 	Payload map[string]interface{}
+
+	// Enqueued is the time the action was added.
+	Enqueued time.Time
+
+	// Started is the time the unit began running the action; it is zero
+	// while the action is waiting to run.
+	Started time.Time
+
+	// ServiceActionId holds the id of the ServiceAction that queued
+	// this action, if any.
+	ServiceActionId string `bson:",omitempty"`
+
+	// Timeout is how long the unit may run the action before stopping
+	// it; zero means the action may run indefinitely.
+	Timeout time.Duration
+
+	// Cancelling is set when cancellation of the action is requested
+	// after the unit has started running it.
+	Cancelling bool
 }
 
+// nowToTheSecond returns the current time in UTC, truncated to the
+// second so that it survives a round trip through the database intact.
+var nowToTheSecond = func() time.Time { return time.Now().Round(time.Second).UTC() }
+
 // Action represents an instruction to do some "action" and is expected
 // to match an action definition in a charm.
 type Action struct {
@@ -66,6 +93,58 @@ func newActionId(st *State, globalKey string) (string, error) {
 	return fmt.Sprintf("%s%d", prefix, suffix), nil
 }
 
+// newActionOp returns the id of a new Action for the unit with the given
+// global key, along with the operation that inserts it. The payload and
+// timeout are recorded as given.
+func newActionOp(st *State, globalKey, name string, payload map[string]interface{}, timeout time.Duration, serviceActionId string) (string, txn.Op, error) {
+	actionId, err := newActionId(st, globalKey)
+	if err != nil {
+		return "", txn.Op{}, fmt.Errorf("cannot add action; error generating key: %v", err)
+	}
+	doc := actionDoc{
+		Id:              actionId,
+		Name:            name,
+		Payload:         payload,
+		Enqueued:        nowToTheSecond(),
+		ServiceActionId: serviceActionId,
+		Timeout:         timeout,
+	}
+	return actionId, txn.Op{
+		C:      st.actions.Name,
+		Id:     doc.Id,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}, nil
+}
+
+// actionIdSequence returns the sequence number of the given action id,
+// or -1 if the id is malformed.
+func actionIdSequence(actionId string) int {
+	parts := strings.Split(actionId, actionMarker)
+	seq, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return -1
+	}
+	return seq
+}
+
+// actionIdsBySequence sorts action ids in the order in which their
+// actions were queued.
+type actionIdsBySequence []string
+
+func (ids actionIdsBySequence) Len() int      { return len(ids) }
+func (ids actionIdsBySequence) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+func (ids actionIdsBySequence) Less(i, j int) bool {
+	return actionIdSequence(ids[i]) < actionIdSequence(ids[j])
+}
+
+// sortedActionIds sorts the given action ids in the order in which
+// their actions were queued, and returns them.
+func sortedActionIds(ids []string) []string {
+	sort.Sort(actionIdsBySequence(ids))
+	return ids
+}
+
 // getActionIdPrefix returns the prefix for the given action id.
 // Useful when finding a prefix to filter on.
 func getActionIdPrefix(actionId string) string {
@@ -82,6 +161,11 @@ func (a *Action) Name() string {
 	return a.doc.Name
 }
 
+// UnitName returns the name of the unit the Action is queued for.
+func (a *Action) UnitName() string {
+	return unitNameFromGlobalKey(getActionIdPrefix(a.doc.Id))
+}
+
 // Payload will contain a structure representing arguments or parameters to
 // an action, and is expected to be validated by the Unit using the Charm
 // definition of the Action
@@ -89,33 +173,161 @@ func (a *Action) Payload() map[string]interface{} {
 	return a.doc.Payload
 }
 
+// Enqueued returns the time the action was added.
+func (a *Action) Enqueued() time.Time {
+	return a.doc.Enqueued
+}
+
+// Started returns the time the unit began running the action, or the
+// zero time if it has not started yet.
+func (a *Action) Started() time.Time {
+	return a.doc.Started
+}
+
+// Status returns ActionPending while the action is waiting to run, and
+// ActionRunning once the unit has started it.
+func (a *Action) Status() ActionStatus {
+	if a.doc.Started.IsZero() {
+		return ActionPending
+	}
+	return ActionRunning
+}
+
+// ServiceActionId returns the id of the ServiceAction that queued the
+// action, or "" if it was queued directly on the unit.
+func (a *Action) ServiceActionId() string {
+	return a.doc.ServiceActionId
+}
+
+// Timeout returns how long the unit may run the action before stopping
+// it, or zero if the action may run indefinitely.
+func (a *Action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// Cancelling returns whether cancellation of the running action has
+// been requested.
+func (a *Action) Cancelling() bool {
+	return a.doc.Cancelling
+}
+
+// Cancel stops the action. An action that is still waiting to run is
+// removed from the queue, and its result recorded with the status
+// ActionCancelled. An action that is already running is marked for
+// cancellation; the unit running it is expected to stop it and record
+// its result.
+func (a *Action) Cancel() error {
+	for i := 0; i < 3; i++ {
+		var ops []txn.Op
+		if a.doc.Started.IsZero() {
+			result, err := newActionResultDoc(a, ActionCancelled, nil, "", "action cancelled")
+			if err != nil {
+				return err
+			}
+			ops = []txn.Op{
+				addActionResultOp(a.st, result),
+				{
+					C:      a.st.actions.Name,
+					Id:     a.doc.Id,
+					Assert: bson.D{{"started", a.doc.Started}},
+					Remove: true,
+				},
+			}
+		} else {
+			ops = []txn.Op{{
+				C:      a.st.actions.Name,
+				Id:     a.doc.Id,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"cancelling", true}}}},
+			}}
+		}
+		switch err := a.st.runTransaction(ops); err {
+		case txn.ErrAborted:
+			// The action has either started or finished since we
+			// last looked; refresh it and try again.
+			action, err := a.st.Action(a.doc.Id)
+			if err != nil {
+				return err
+			}
+			a.doc = action.doc
+		case nil:
+			if !a.doc.Started.IsZero() {
+				a.doc.Cancelling = true
+			}
+			return nil
+		default:
+			return err
+		}
+	}
+	return ErrExcessiveContention
+}
+
+// Begin records that the unit has started running the action.
+func (a *Action) Begin() error {
+	started := nowToTheSecond()
+	ops := []txn.Op{{
+		C:      a.st.actions.Name,
+		Id:     a.doc.Id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"started", started}}}},
+	}}
+	switch err := a.st.runTransaction(ops); err {
+	case txn.ErrAborted:
+		return errors.NotFoundf("action %q", a.doc.Id)
+	case nil:
+		a.doc.Started = started
+		return nil
+	default:
+		return err
+	}
+}
+
 // Complete removes action from the pending queue and creates an ActionResult
 // to capture the output and end state of the action.
 func (a *Action) Complete(output string) error {
-	return a.removeAndLog(ActionCompleted, output)
+	return a.removeAndLog(ActionCompleted, nil, output, "")
 }
 
 // Fail removes an Action from the queue, and creates an ActionResult that
 // will capture the reason for the failure.
 func (a *Action) Fail(reason string) error {
-	return a.removeAndLog(ActionFailed, reason)
+	return a.removeAndLog(ActionFailed, nil, reason, "")
+}
+
+// Finish removes the action from the queue, and creates an ActionResult
+// recording the given final status, the structured results set by the
+// action, and a message describing the outcome.
+func (a *Action) Finish(status ActionStatus, results map[string]interface{}, message string) error {
+	switch status {
+	case ActionCompleted, ActionFailed, ActionCancelled, ActionTimedOut:
+	default:
+		return errors.Errorf("cannot finish action %q with status %q", a.doc.Id, status)
+	}
+	return a.removeAndLog(status, results, "", message)
 }
 
 // removeAndLog takes the action off of the pending queue, and creates an
-// actionresult to capture the outcome of the action.
-func (a *Action) removeAndLog(finalStatus ActionStatus, output string) error {
-	result, err := newActionResultDoc(a, finalStatus, output)
+// actionresult to capture the outcome of the action. It returns a not
+// found error if the action has already been finished or cancelled.
+func (a *Action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, output, message string) error {
+	result, err := newActionResultDoc(a, finalStatus, results, output, message)
 	if err != nil {
 		return err
 	}
-	return a.st.runTransaction([]txn.Op{
+	switch err := a.st.runTransaction([]txn.Op{
 		addActionResultOp(a.st, result),
 		{
 			C:      a.st.actions.Name,
 			Id:     a.doc.Id,
+			Assert: txn.DocExists,
 			Remove: true,
 		},
-	})
+	}); err {
+	case txn.ErrAborted:
+		return errors.NewNotFound(nil, fmt.Sprintf("action %q already finished or cancelled", a.doc.Id))
+	default:
+		return err
+	}
 }
 
 var validAction = regexp.MustCompile("^.+" + regexp.QuoteMeta(actionMarker) + "\\d+$")
@@ -4,10 +4,15 @@
 package state_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type ActionSuite struct {
@@ -31,7 +36,7 @@ func (s *ActionSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *ActionSuite) TestAddAction(c *gc.C) {
-	name := "fakeaction"
+	name := "snapshot"
 	params := map[string]interface{}{"outfile": "outfile.tar.bz2"}
 
 	// verify can add an Action
@@ -50,10 +55,36 @@ func (s *ActionSuite) TestAddAction(c *gc.C) {
 	c.Assert(action.Payload(), jc.DeepEquals, params)
 }
 
+func (s *ActionSuite) TestAddActionValidatesPayload(c *gc.C) {
+	// The dummy charm declares a "snapshot" action taking an optional
+	// string "outfile", which defaults to "foo.bz2".
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	unit, err := dummy.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	_, err = unit.AddAction("fakeaction", nil)
+	c.Assert(err, gc.ErrorMatches, `action "fakeaction" not defined by charm "local:quantal/quantal-dummy-[0-9]+"`)
+
+	_, err = unit.AddAction("snapshot", map[string]interface{}{"outfile": 5})
+	c.Assert(err, gc.ErrorMatches, "invalid action parameters: outfile: .*string.*")
+
+	id, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+
+	id, err = unit.AddAction("snapshot", map[string]interface{}{"outfile": "out.tar"})
+	c.Assert(err, gc.IsNil)
+	action, err = s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tar"})
+}
+
 func (s *ActionSuite) TestAddActionAcceptsDuplicateNames(c *gc.C) {
-	name := "fakeaction"
+	name := "snapshot"
 	params_1 := map[string]interface{}{"outfile": "outfile.tar.bz2"}
-	params_2 := map[string]interface{}{"infile": "infile.zip"}
+	params_2 := map[string]interface{}{"outfile": "outfile.zip"}
 
 	// verify can add two actions with same name
 	id_1, err := s.unit.AddAction(name, params_1)
@@ -98,7 +129,7 @@ func (s *ActionSuite) TestAddActionLifecycle(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// can add action to a dying unit
-	id, err := unit.AddAction("fakeaction1", map[string]interface{}{})
+	id, err := unit.AddAction("snapshot", map[string]interface{}{})
 	c.Assert(err, gc.IsNil)
 	assertSaneActionId(c, id, s.unit.Name())
 
@@ -107,7 +138,7 @@ func (s *ActionSuite) TestAddActionLifecycle(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// cannot add action to a dead unit
-	_, err = unit.AddAction("fakeaction2", map[string]interface{}{})
+	_, err = unit.AddAction("backup", map[string]interface{}{})
 	c.Assert(err, gc.ErrorMatches, "unit .* is dead")
 }
 
@@ -124,7 +155,7 @@ func (s *ActionSuite) TestAddActionFailsOnDeadUnitInTransaction(c *gc.C) {
 	}
 	defer state.SetTransactionHooks(c, s.State, killUnit).Check()
 
-	_, err = unit.AddAction("fakeaction", map[string]interface{}{})
+	_, err = unit.AddAction("snapshot", map[string]interface{}{})
 	c.Assert(err, gc.ErrorMatches, "unit .* is dead")
 }
 
@@ -134,7 +165,7 @@ func (s *ActionSuite) TestFail(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	preventUnitDestroyRemove(c, unit)
 
-	id, err := unit.AddAction("action1", nil)
+	id, err := unit.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
 
 	action, err := s.State.Action(id)
@@ -180,7 +211,7 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	preventUnitDestroyRemove(c, unit)
 
-	id, err := unit.AddAction("action1", nil)
+	id, err := unit.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
 
 	action, err := s.State.Action(id)
@@ -202,6 +233,8 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(results), gc.Equals, 1)
 
 	c.Assert(results[0].ActionName(), gc.Equals, action.Name())
+	c.Assert(results[0].ActionId(), gc.Equals, action.Id())
+	c.Assert(results[0].UnitName(), gc.Equals, unit.Name())
 	c.Assert(results[0].Status(), gc.Equals, state.ActionCompleted)
 	c.Assert(results[0].Output(), gc.Equals, output)
 
@@ -220,6 +253,122 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestFinish(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+
+	err = action.Finish(state.ActionStatus("running"), nil, "")
+	c.Assert(err, gc.ErrorMatches, `cannot finish action ".*" with status "running"`)
+
+	results := map[string]interface{}{
+		"outfile": "out.tar",
+		"stats": map[string]interface{}{
+			"size":  1024,
+			"files": []interface{}{"a", "b"},
+		},
+	}
+	err = action.Finish(state.ActionFailed, results, "disk full")
+	c.Assert(err, gc.IsNil)
+
+	actionResults, err := s.State.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(actionResults, gc.HasLen, 1)
+	c.Assert(actionResults[0].Status(), gc.Equals, state.ActionFailed)
+	c.Assert(actionResults[0].Output(), gc.Equals, "disk full")
+	c.Assert(actionResults[0].Results(), jc.DeepEquals, results)
+
+	_, err = s.State.Action(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestUnitAction(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	action, err := s.unit.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Id(), gc.Equals, id)
+	c.Assert(action.Name(), gc.Equals, "snapshot")
+	c.Assert(action.UnitName(), gc.Equals, s.unit.Name())
+
+	actions, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Id(), gc.Equals, id)
+
+	// An action queued for another unit is not found.
+	unit2, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	_, err = unit2.Action(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestUnitWatchActions(c *gc.C) {
+	// Add an action before the watcher starts.
+	id0, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	w := s.unit.WatchActions()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(id0)
+	wc.AssertNoChange()
+
+	// Add a couple more actions and check they are reported.
+	id1, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	id2, err := s.unit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertChange(id1, id2)
+	wc.AssertNoChange()
+
+	// Actions queued for other units are not reported.
+	unit2, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	_, err = unit2.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+
+	// Starting or completing an action does not produce an event.
+	action, err := s.State.Action(id2)
+	c.Assert(err, gc.IsNil)
+	err = action.Begin()
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+	action, err = s.State.Action(id1)
+	c.Assert(err, gc.IsNil)
+	err = action.Complete("done")
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *ActionSuite) TestUnitWatchActionsOrder(c *gc.C) {
+	// Queue enough actions that their ids do not sort lexically in
+	// the order in which they were queued.
+	var expect []string
+	for i := 0; i < 12; i++ {
+		id, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, gc.IsNil)
+		expect = append(expect, id)
+	}
+
+	w := s.unit.WatchActions()
+	defer testing.AssertStop(c, w)
+	s.State.StartSync()
+	select {
+	case ids, ok := <-w.Changes():
+		c.Assert(ok, gc.Equals, true)
+		c.Assert(ids, gc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for actions")
+	}
+}
+
 func (s *ActionSuite) TestGetActionIdPrefix(c *gc.C) {
 	getPrefixTest(c, state.GetActionIdPrefix, state.ActionMarker)
 }
@@ -256,3 +405,186 @@ func getPrefixTest(c *gc.C, fn getPrefixFn, marker string) {
 func assertSaneActionId(c *gc.C, id, unitName string) {
 	c.Assert(id, gc.Matches, "^u#"+unitName+"#a#\\d+")
 }
+
+func (s *ActionSuite) TestActionTimestamps(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Enqueued().IsZero(), jc.IsFalse)
+	c.Assert(action.Started().IsZero(), jc.IsTrue)
+
+	err = action.Begin()
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Started().IsZero(), jc.IsFalse)
+
+	// The start time is persisted.
+	action, err = s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Started().IsZero(), jc.IsFalse)
+	c.Assert(action.Started().Before(action.Enqueued()), jc.IsFalse)
+
+	err = action.Finish(state.ActionCompleted, nil, "all done")
+	c.Assert(err, gc.IsNil)
+	results, err := s.State.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	result := results[0]
+	c.Assert(result.Message(), gc.Equals, "all done")
+	c.Assert(result.Output(), gc.Equals, "")
+	c.Assert(result.Enqueued().Equal(action.Enqueued()), jc.IsTrue)
+	c.Assert(result.Started().Equal(action.Started()), jc.IsTrue)
+	c.Assert(result.Completed().Before(result.Started()), jc.IsFalse)
+
+	// An action that has finished can no longer be started.
+	err = action.Begin()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	id, err := s.unit.AddActionWithTimeout("snapshot", nil, 90*time.Second)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Timeout(), gc.Equals, 90*time.Second)
+
+	_, err = s.unit.AddActionWithTimeout("snapshot", nil, -time.Second)
+	c.Assert(err, gc.ErrorMatches, "invalid action timeout -1s")
+}
+
+func (s *ActionSuite) TestCancelPending(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+
+	err = action.Cancel()
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.Action(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	results, err := s.State.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionCancelled)
+	c.Assert(results[0].Message(), gc.Equals, "action cancelled")
+}
+
+func (s *ActionSuite) TestCancelRunning(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Cancelling(), jc.IsFalse)
+
+	// Another client starts the action before it is cancelled.
+	running, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = running.Begin()
+	c.Assert(err, gc.IsNil)
+
+	err = action.Cancel()
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Cancelling(), jc.IsTrue)
+
+	// The action stays queued until the unit stops it.
+	action, err = s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Cancelling(), jc.IsTrue)
+	err = action.Finish(state.ActionCancelled, nil, "action cancelled")
+	c.Assert(err, gc.IsNil)
+
+	// A finished action cannot be cancelled.
+	err = action.Cancel()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionSuite) TestFinishFinishedAction(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	cancelled, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+
+	err = cancelled.Cancel()
+	c.Assert(err, gc.IsNil)
+	err = action.Finish(state.ActionCompleted, nil, "")
+	c.Assert(err, gc.ErrorMatches, `action ".*" already finished or cancelled`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Only the cancellation was recorded.
+	results, err := s.State.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionCancelled)
+}
+
+func (s *ActionSuite) TestActionWatch(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = action.Begin()
+	c.Assert(err, gc.IsNil)
+
+	w := action.Watch()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Requesting cancellation is reported.
+	err = action.Cancel()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// So is the action's removal from the queue.
+	err = action.Finish(state.ActionCancelled, nil, "action cancelled")
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *ActionSuite) TestUnitActionResults(c *gc.C) {
+	id0, err := s.unit.AddAction("snapshot", map[string]interface{}{"outfile": "a"})
+	c.Assert(err, gc.IsNil)
+	id1, err := s.unit.AddAction("backup", map[string]interface{}{"outfile": "b"})
+	c.Assert(err, gc.IsNil)
+	unit2, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	id2, err := unit2.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	for _, id := range []string{id0, id1, id2} {
+		action, err := s.State.Action(id)
+		c.Assert(err, gc.IsNil)
+		err = action.Finish(state.ActionCompleted, map[string]interface{}{"id": id}, "")
+		c.Assert(err, gc.IsNil)
+	}
+
+	results, err := s.unit.ActionResults()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	got := map[string]map[string]interface{}{}
+	for _, result := range results {
+		got[result.ActionId()] = result.Results()
+	}
+	c.Assert(got, jc.DeepEquals, map[string]map[string]interface{}{
+		id0: {"id": id0},
+		id1: {"id": id1},
+	})
+
+	results, err = s.State.ActionResultsByName("snapshot")
+	c.Assert(err, gc.IsNil)
+	var ids []string
+	for _, result := range results {
+		c.Assert(result.ActionName(), gc.Equals, "snapshot")
+		ids = append(ids, result.ActionId())
+	}
+	c.Assert(ids, jc.SameContents, []string{id0, id2})
+
+	results, err = s.State.ActionResultsByName("missing")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 0)
+}
@@ -6,20 +6,36 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"labix.org/v2/mgo/txn"
 )
 
-// ActionStatus represents the possible end states for an action.
+// ActionStatus represents the possible states of an action.
 type ActionStatus string
 
 const (
+	// ActionPending indicates that the action is queued, waiting for
+	// the unit to run it.
+	ActionPending ActionStatus = "pending"
+
+	// ActionRunning indicates that the unit has started the action.
+	ActionRunning ActionStatus = "running"
+
 	// Fail signifies that the action did not complete successfully.
 	ActionFailed ActionStatus = "fail"
 
 	// Complete indicates that the action ran to completion as intended.
 	ActionCompleted ActionStatus = "complete"
+
+	// ActionCancelled indicates that the action was cancelled, either
+	// before it started or while it was running.
+	ActionCancelled ActionStatus = "cancelled"
+
+	// ActionTimedOut indicates that the action was stopped because it
+	// ran for longer than its timeout.
+	ActionTimedOut ActionStatus = "timeout"
 )
 
 type actionResultDoc struct {
@@ -40,8 +56,26 @@ type actionResultDoc struct {
 	// ActionCompleted for an action that successfully completed.
 	Status ActionStatus
 
+	// Results holds the structured results set by the action, if any.
+	Results map[string]interface{}
+
 	// Output captures any text emitted by the action.
 	Output string
+
+	// Message describes the outcome of the action, such as the reason
+	// it failed.
+	Message string
+
+	// Enqueued, Started and Completed record when the action was added,
+	// when the unit began running it, and when it finished. Started is
+	// zero if the action finished without being run.
+	Enqueued  time.Time
+	Started   time.Time
+	Completed time.Time
+
+	// ServiceActionId holds the id of the ServiceAction that queued the
+	// action, if any.
+	ServiceActionId string `bson:",omitempty"`
 }
 
 // ActionResult represents an instruction to do some "action" and is
@@ -82,7 +116,7 @@ func newActionResultId(st *State, globalKey string) (string, error) {
 }
 
 // newActionResultDoc builds a new doc
-func newActionResultDoc(action *Action, status ActionStatus, output string) (*actionResultDoc, error) {
+func newActionResultDoc(action *Action, status ActionStatus, results map[string]interface{}, output, message string) (*actionResultDoc, error) {
 	id, err := newActionResultId(action.st, action.Id())
 	if err != nil {
 		return nil, err
@@ -92,7 +126,14 @@ func newActionResultDoc(action *Action, status ActionStatus, output string) (*ac
 		ActionName: action.Name(),
 		Payload:    action.Payload(),
 		Status:     status,
+		Results:    results,
 		Output:     output,
+		Message:    message,
+		Enqueued:   action.Enqueued(),
+		Started:    action.Started(),
+		Completed:  nowToTheSecond(),
+
+		ServiceActionId: action.ServiceActionId(),
 	}, nil
 }
 
@@ -117,6 +158,16 @@ func (a *ActionResult) Id() string {
 	return a.doc.Id
 }
 
+// ActionId returns the id of the Action that produced this ActionResult.
+func (a *ActionResult) ActionId() string {
+	return getActionResultIdPrefix(a.doc.Id)
+}
+
+// UnitName returns the name of the unit the Action was run on.
+func (a *ActionResult) UnitName() string {
+	return unitNameFromGlobalKey(getActionIdPrefix(a.ActionId()))
+}
+
 // ActionName returns the name of the Action.
 func (a *ActionResult) ActionName() string {
 	return a.doc.ActionName
@@ -133,7 +184,40 @@ func (a *ActionResult) Status() ActionStatus {
 	return a.doc.Status
 }
 
+// Results returns the structured results set by the action as it was
+// executed.
+func (a *ActionResult) Results() map[string]interface{} {
+	return a.doc.Results
+}
+
 // Output returns the text caputured from the action as it was executed.
 func (a *ActionResult) Output() string {
 	return a.doc.Output
 }
+
+// Message returns the message describing the outcome of the action.
+func (a *ActionResult) Message() string {
+	return a.doc.Message
+}
+
+// Enqueued returns the time the action was added.
+func (a *ActionResult) Enqueued() time.Time {
+	return a.doc.Enqueued
+}
+
+// Started returns the time the unit began running the action, or the
+// zero time if it never ran.
+func (a *ActionResult) Started() time.Time {
+	return a.doc.Started
+}
+
+// Completed returns the time the action finished.
+func (a *ActionResult) Completed() time.Time {
+	return a.doc.Completed
+}
+
+// ServiceActionId returns the id of the ServiceAction that queued the
+// action, or "" if it was queued directly on the unit.
+func (a *ActionResult) ServiceActionId() string {
+	return a.doc.ServiceActionId
+}
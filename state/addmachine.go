@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"labix.org/v2/mgo/bson"
 	"labix.org/v2/mgo/txn"
 
@@ -495,7 +496,12 @@ func (st *State) maintainStateServersOps(mdocs []*machineDoc, currentInfo *State
 // EnsureAvailability adds state server machines as necessary to make
 // the number of live state servers equal to numStateServers. The given
 // constraints and series will be attached to any new machines.
-func (st *State) EnsureAvailability(numStateServers int, cons constraints.Value, series string) error {
+//
+// The placement directives are used, in order, for the state servers
+// needed. A directive holding a machine id converts that existing
+// machine into a state server; any other directive is associated with
+// a new machine, to be interpreted by the provider.
+func (st *State) EnsureAvailability(numStateServers int, cons constraints.Value, series string, placement []string) error {
 	if numStateServers < 0 || (numStateServers != 0 && numStateServers%2 != 1) {
 		return fmt.Errorf("number of state servers must be odd and non-negative")
 	}
@@ -512,6 +518,9 @@ func (st *State) EnsureAvailability(numStateServers int, cons constraints.Value,
 			desiredStateServerCount = len(currentInfo.VotingMachineIds)
 			if desiredStateServerCount <= 1 {
 				desiredStateServerCount = 3
+			} else if desiredStateServerCount%2 == 0 {
+				// A state server has been demoted; replace it.
+				desiredStateServerCount++
 			}
 		}
 		if len(currentInfo.VotingMachineIds) > desiredStateServerCount {
@@ -537,7 +546,10 @@ func (st *State) EnsureAvailability(numStateServers int, cons constraints.Value,
 		}
 		voteCount += len(intent.promote)
 		intent.newCount = desiredStateServerCount - voteCount
-		logger.Infof("%d new machines; promoting %v", intent.newCount, intent.promote)
+		if err := st.placeStateServers(intent, placement); err != nil {
+			return err
+		}
+		logger.Infof("%d new machines; promoting %v; converting %v", intent.newCount, intent.promote, intent.convert)
 		ops, err := st.ensureAvailabilityIntentionOps(intent, currentInfo, cons, series)
 		if err != nil {
 			return err
@@ -556,6 +568,53 @@ func (st *State) EnsureAvailability(numStateServers int, cons constraints.Value,
 	return ErrExcessiveContention
 }
 
+// DemoteStateServer demotes the state server machine with the given id,
+// so that it no longer has a vote in the replicaset. Demoting a voting
+// machine leaves an even number of voters, so EnsureAvailability should
+// be called straight afterwards to replace it; once its vote has been
+// removed, a later call removes its state server job.
+func (st *State) DemoteStateServer(machineId string) error {
+	m, err := st.Machine(machineId)
+	if err != nil {
+		return err
+	}
+	if !hasJob(m.Jobs(), JobManageEnviron) {
+		return fmt.Errorf("machine %s is not a state server", m)
+	}
+	if m.doc.Demoted {
+		return nil
+	}
+	currentInfo, err := st.StateServerInfo()
+	if err != nil {
+		return err
+	}
+	if m.WantsVote() && len(currentInfo.VotingMachineIds) <= 1 {
+		return fmt.Errorf("cannot demote the only voting state server")
+	}
+	// The number of voters is asserted, so that concurrent demotions
+	// cannot leave no voter at all.
+	stateServersOp := txn.Op{
+		C:      st.stateServers.Name,
+		Id:     environGlobalKey,
+		Assert: bson.D{{"votingmachineids", bson.D{{"$size", len(currentInfo.VotingMachineIds)}}}},
+	}
+	if m.WantsVote() {
+		stateServersOp.Update = bson.D{{"$pull", bson.D{{"votingmachineids", m.doc.Id}}}}
+	}
+	ops := []txn.Op{stateServersOp, {
+		C:      st.machines.Name,
+		Id:     m.doc.Id,
+		Assert: bson.D{{"novote", m.doc.NoVote}},
+		Update: bson.D{{"$set", bson.D{{"novote", true}, {"demoted", true}}}},
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return ErrExcessiveContention
+	} else if err != nil {
+		return fmt.Errorf("cannot demote state server %s: %v", m, err)
+	}
+	return nil
+}
+
 // ensureAvailabilityIntentionOps returns operations to fulfil the desired intent.
 func (st *State) ensureAvailabilityIntentionOps(
 	intent *ensureAvailabilityIntent,
@@ -570,8 +629,15 @@ func (st *State) ensureAvailabilityIntentionOps(
 	for _, m := range intent.demote {
 		ops = append(ops, demoteStateServerOps(m)...)
 	}
-	mdocs := make([]*machineDoc, intent.newCount)
-	for i := range mdocs {
+	var mdocs []*machineDoc
+	for _, m := range intent.convert {
+		mdoc := m.doc
+		mdoc.Jobs = append(append([]MachineJob(nil), m.doc.Jobs...), JobManageEnviron)
+		mdoc.NoVote = false
+		mdocs = append(mdocs, &mdoc)
+		ops = append(ops, convertToStateServerOps(m)...)
+	}
+	for i := 0; i < intent.newCount; i++ {
 		template := MachineTemplate{
 			Series: series,
 			Jobs: []MachineJob{
@@ -580,11 +646,14 @@ func (st *State) ensureAvailabilityIntentionOps(
 			},
 			Constraints: cons,
 		}
+		if i < len(intent.placement) {
+			template.Placement = intent.placement[i]
+		}
 		mdoc, addOps, err := st.addMachineOps(template)
 		if err != nil {
 			return nil, err
 		}
-		mdocs[i] = mdoc
+		mdocs = append(mdocs, mdoc)
 		ops = append(ops, addOps...)
 	}
 	for _, m := range intent.remove {
@@ -609,7 +678,40 @@ var stateServerAvailable = func(m *Machine) (bool, error) {
 
 type ensureAvailabilityIntent struct {
 	newCount                          int
+	placement                         []string
 	promote, maintain, demote, remove []*Machine
+	convert                           []*Machine
+}
+
+// placeStateServers uses the given placement directives for the new
+// state servers of the intent, converting the machines named by any
+// machine ids instead of adding new machines for them.
+func (st *State) placeStateServers(intent *ensureAvailabilityIntent, placement []string) error {
+	for _, directive := range placement {
+		if intent.newCount == 0 {
+			break
+		}
+		intent.newCount--
+		if !names.IsMachine(directive) {
+			intent.placement = append(intent.placement, directive)
+			continue
+		}
+		m, err := st.Machine(directive)
+		if err != nil {
+			return err
+		}
+		if m.Life() != Alive {
+			return fmt.Errorf("machine %s is not alive", m)
+		}
+		if hasJob(m.Jobs(), JobManageEnviron) {
+			return fmt.Errorf("machine %s is already a state server", m)
+		}
+		if m.ContainerType() != "" {
+			return fmt.Errorf("container %s cannot be a state server", m)
+		}
+		intent.convert = append(intent.convert, m)
+	}
+	return nil
 }
 
 // ensureAvailabilityIntentions returns what we would like
@@ -630,6 +732,18 @@ func (st *State) ensureAvailabilityIntentions(info *StateServerInfo) (*ensureAva
 			return nil, err
 		}
 		logger.Infof("machine %q, available %v, wants vote %v, has vote %v", m, available, m.WantsVote(), m.HasVote())
+		if m.doc.Demoted {
+			// The machine was demoted by the user, so its vote is
+			// not restored. Once the vote has been removed by the
+			// worker that maintains the replicaset, its state server
+			// job is removed.
+			if m.HasVote() {
+				intent.maintain = append(intent.maintain, m)
+			} else {
+				intent.remove = append(intent.remove, m)
+			}
+			continue
+		}
 		if available {
 			if m.WantsVote() {
 				intent.maintain = append(intent.maintain, m)
@@ -684,6 +798,20 @@ func demoteStateServerOps(m *Machine) []txn.Op {
 	}}
 }
 
+func convertToStateServerOps(m *Machine) []txn.Op {
+	return []txn.Op{{
+		C:  m.st.machines.Name,
+		Id: m.doc.Id,
+		Assert: append(isAliveDoc, bson.DocElem{
+			"jobs", bson.D{{"$nin", []MachineJob{JobManageEnviron}}},
+		}),
+		Update: bson.D{
+			{"$addToSet", bson.D{{"jobs", JobManageEnviron}}},
+			{"$set", bson.D{{"novote", false}}},
+		},
+	}}
+}
+
 func removeStateServerOps(m *Machine) []txn.Op {
 	return []txn.Op{{
 		C:      m.st.machines.Name,
@@ -691,7 +819,7 @@ func removeStateServerOps(m *Machine) []txn.Op {
 		Assert: bson.D{{"novote", true}, {"hasvote", false}},
 		Update: bson.D{
 			{"$pull", bson.D{{"jobs", JobManageEnviron}}},
-			{"$set", bson.D{{"novote", false}}},
+			{"$set", bson.D{{"novote", false}, {"demoted", false}}},
 		},
 	}, {
 		C:      m.st.stateServers.Name,
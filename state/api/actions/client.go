@@ -0,0 +1,139 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+)
+
+// Client provides access to the Actions API facade.
+type Client struct {
+	st *api.State
+}
+
+func (c *Client) call(method string, params, result interface{}) error {
+	return c.st.Call("Actions", "", method, params, result)
+}
+
+// NewClient returns a new Actions client.
+func NewClient(st *api.State) *Client {
+	return &Client{st}
+}
+
+// Close closes the underlying API connection.
+func (c *Client) Close() error {
+	return c.st.Close()
+}
+
+// Enqueue queues the named action with the given parameters on the unit
+// or service identified by receiver, and returns the queued actions.
+// A service receiver queues one action per unit. A non-zero timeout
+// overrides the timeout declared for the action by the charm.
+func (c *Client) Enqueue(receiver, name string, actionParams map[string]interface{}, timeout time.Duration) ([]params.Action, error) {
+	result, err := c.enqueue(params.Action{
+		Receiver: receiver,
+		Name:     name,
+		Params:   actionParams,
+		Timeout:  timeout,
+	})
+	return result.Actions, err
+}
+
+// EnqueueService queues the named action with the given parameters on
+// every unit of the service identified by the given tag, and returns
+// the id of the service action tracking them along with the queued
+// actions. A non-zero timeout overrides the timeout declared for the
+// action by the charm. If includeFuture is true, the action is also
+// queued on units added to the service later.
+func (c *Client) EnqueueService(service, name string, actionParams map[string]interface{}, timeout time.Duration, includeFuture bool) (string, []params.Action, error) {
+	result, err := c.enqueue(params.Action{
+		Receiver:      service,
+		Name:          name,
+		Params:        actionParams,
+		Timeout:       timeout,
+		IncludeFuture: includeFuture,
+	})
+	return result.ServiceActionId, result.Actions, err
+}
+
+func (c *Client) enqueue(action params.Action) (params.ActionEnqueueResult, error) {
+	args := params.Actions{Actions: []params.Action{action}}
+	var results params.ActionEnqueueResults
+	if err := c.call("Enqueue", args, &results); err != nil {
+		return params.ActionEnqueueResult{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.ActionEnqueueResult{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result, result.Error
+	}
+	return result, nil
+}
+
+// List returns the pending and completed actions of the given units or
+// services. Service tags are expanded into one result per unit.
+func (c *Client) List(tags ...string) ([]params.ActionListResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(tags))}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag
+	}
+	var results params.ActionListResults
+	if err := c.call("List", args, &results); err != nil {
+		return nil, err
+	}
+	return results.Results, nil
+}
+
+// Result returns the outcome of the action with the given id.
+func (c *Client) Result(id string) (*params.ActionResult, error) {
+	args := params.ActionIds{Ids: []string{id}}
+	var results params.ActionResultQueryResults
+	if err := c.call("Results", args, &results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// ServiceResult returns the progress of the service action with the
+// given id, including the state of the action on each unit.
+func (c *Client) ServiceResult(id string) (*params.ServiceActionResult, error) {
+	args := params.ActionIds{Ids: []string{id}}
+	var results params.ServiceActionQueryResults
+	if err := c.call("ServiceResults", args, &results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// Cancel cancels the actions with the given ids, and returns the
+// outcome of each cancellation. Queued actions are removed from the
+// queue; running actions are stopped by the units running them.
+func (c *Client) Cancel(ids ...string) ([]params.ErrorResult, error) {
+	args := params.ActionIds{Ids: ids}
+	var results params.ErrorResults
+	if err := c.call("Cancel", args, &results); err != nil {
+		return nil, err
+	}
+	return results.Results, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actions_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/actions"
+)
+
+type actionsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *actions.Client
+	unit   *state.Unit
+}
+
+var _ = gc.Suite(&actionsSuite{})
+
+func (s *actionsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = actions.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *actionsSuite) TestEnqueue(c *gc.C) {
+	queued, err := s.client.Enqueue("unit-wordpress-0", "snapshot", map[string]interface{}{"outfile": "foo.tar"}, 0)
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	c.Assert(queued[0].Receiver, gc.Equals, "unit-wordpress-0")
+
+	pending, err := s.unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Id(), gc.Equals, queued[0].Id)
+	c.Assert(pending[0].Payload(), gc.DeepEquals, map[string]interface{}{"outfile": "foo.tar"})
+}
+
+func (s *actionsSuite) TestEnqueueError(c *gc.C) {
+	_, err := s.client.Enqueue("unit-wordpress-9", "snapshot", nil, 0)
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/9" not found`)
+}
+
+func (s *actionsSuite) TestListAndResult(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	list, err := s.client.List("service-wordpress")
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 1)
+	c.Assert(list[0].Error, gc.IsNil)
+	c.Assert(list[0].Pending, gc.HasLen, 1)
+	c.Assert(list[0].Pending[0].Id, gc.Equals, id)
+
+	result, err := s.client.Result(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Status, gc.Equals, "pending")
+
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = action.Complete("done")
+	c.Assert(err, gc.IsNil)
+
+	result, err = s.client.Result(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Status, gc.Equals, "complete")
+	c.Assert(result.Output, gc.Equals, "done")
+}
+
+func (s *actionsSuite) TestEnqueueServiceAndServiceResult(c *gc.C) {
+	id, queued, err := s.client.EnqueueService("service-wordpress", "snapshot", nil, time.Minute, true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(queued, gc.HasLen, 1)
+	c.Assert(queued[0].Receiver, gc.Equals, "unit-wordpress-0")
+	c.Assert(queued[0].Timeout, gc.Equals, time.Minute)
+
+	result, err := s.client.ServiceResult(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Id, gc.Equals, id)
+	c.Assert(result.Receiver, gc.Equals, "service-wordpress")
+	c.Assert(result.IncludeFuture, gc.Equals, true)
+	c.Assert(result.Units, gc.HasLen, 1)
+	c.Assert(result.Units[0].Status, gc.Equals, "pending")
+
+	_, err = s.client.ServiceResult("s#wordpress#a#99")
+	c.Assert(err, gc.ErrorMatches, `service action "s#wordpress#a#99" not found`)
+}
+
+func (s *actionsSuite) TestCancel(c *gc.C) {
+	id, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.client.Cancel(id, "u#wordpress/0#a#99")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `action "u#wordpress/0#a#99" not found`)
+
+	result, err := s.client.Result(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Status, gc.Equals, "cancelled")
+}
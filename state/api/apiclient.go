@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -296,6 +297,24 @@ func (s *State) RPCClient() *rpc.Conn {
 	return s.client
 }
 
+// SendHTTPRequest sends a request with the given method and body to
+// the given path on the API server, authenticated with the credentials
+// used to log in, and returns the response. The content type is only
+// set if it is not empty.
+func (s *State) SendHTTPRequest(method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.serverRoot+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create HTTP request: %v", err)
+	}
+	req.SetBasicAuth(s.tag, s.password)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// See the note in Client.AddLocalCharm about why the server
+	// certificate is not validated.
+	return utils.GetNonValidatingHTTPClient().Do(req)
+}
+
 // Addr returns the address used to connect to the API server.
 func (s *State) Addr() string {
 	return s.addr
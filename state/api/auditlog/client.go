@@ -0,0 +1,36 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+)
+
+// Client provides access to the AuditLog API facade.
+type Client struct {
+	st *api.State
+}
+
+// NewClient returns a new AuditLog client.
+func NewClient(st *api.State) *Client {
+	return &Client{st}
+}
+
+// Close closes the underlying API connection.
+func (c *Client) Close() error {
+	return c.st.Close()
+}
+
+// Entries returns the calls recorded in the audit log that are
+// selected by the filter, oldest first. The server returns a limited
+// number of calls at a time; the rest are read by asking for the calls
+// after the last one returned.
+func (c *Client) Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	var result params.AuditLogEntries
+	if err := c.st.Call("AuditLog", "", "Entries", filter, &result); err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/api/params"
+)
+
+// Client provides access to the Backups API facade.
+type Client struct {
+	st *api.State
+}
+
+func (c *Client) call(method string, params, result interface{}) error {
+	return c.st.Call("Backups", "", method, params, result)
+}
+
+// NewClient returns a new Backups client.
+func NewClient(st *api.State) *Client {
+	return &Client{st}
+}
+
+// Close closes the underlying API connection.
+func (c *Client) Close() error {
+	return c.st.Close()
+}
+
+// Create takes a new backup of the state server, recording the given
+// notes with it, and returns its metadata.
+func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+	var result params.BackupsMetadataResult
+	args := params.BackupsCreateArgs{Notes: notes}
+	if err := c.call("Create", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Info returns the metadata of the identified backup.
+func (c *Client) Info(id string) (*params.BackupsMetadataResult, error) {
+	var result params.BackupsMetadataResult
+	args := params.BackupsInfoArgs{ID: id}
+	if err := c.call("Info", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// List returns the metadata of every backup, oldest first.
+func (c *Client) List() ([]params.BackupsMetadataResult, error) {
+	var result params.BackupsListResult
+	if err := c.call("List", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.List, nil
+}
+
+// Remove removes the identified backup and its archive.
+func (c *Client) Remove(id string) error {
+	args := params.BackupsRemoveArgs{ID: id}
+	return c.call("Remove", args, nil)
+}
+
+// Download returns a reader for the archive of the identified backup.
+// The caller is responsible for closing it.
+func (c *Client) Download(id string) (io.ReadCloser, error) {
+	query := url.Values{"id": {id}}
+	resp, err := c.st.SendHTTPRequest("GET", "/backups?"+query.Encode(), "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot download backup: %v", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
+	}
+	defer resp.Body.Close()
+	return nil, responseError(resp, "download")
+}
+
+// Upload stores the backup archive read from r, which was taken
+// elsewhere, recording the given notes with it, and returns its
+// metadata.
+func (c *Client) Upload(archive io.Reader, notes string) (*params.BackupsMetadataResult, error) {
+	query := url.Values{"notes": {notes}}
+	resp, err := c.st.SendHTTPRequest("POST", "/backups?"+query.Encode(), "application/x-tar-gz", archive)
+	if err != nil {
+		return nil, fmt.Errorf("cannot upload backup: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "upload")
+	}
+	var result params.BackupsMetadataResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("cannot read backup upload response: %v", err)
+	}
+	return &result, nil
+}
+
+// Restore replaces the state of the state server with that held in the
+// identified backup. The state server's machine agent restarts once the
+// backup has been restored, closing the connection.
+func (c *Client) Restore(id string) error {
+	args := params.BackupsRestoreArgs{ID: id}
+	return c.call("Restore", args, nil)
+}
+
+// responseError returns the error held in the body of the given failed
+// response to a backup request of the given kind.
+func responseError(resp *http.Response, what string) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read backup %s response: %v", what, err)
+	}
+	var result params.ErrorResult
+	if err := json.Unmarshal(body, &result); err != nil || result.Error == nil {
+		return fmt.Errorf("cannot %s backup: %s", what, resp.Status)
+	}
+	return result.Error
+}
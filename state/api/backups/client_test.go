@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/backups"
+	"github.com/juju/juju/state/api/params"
+)
+
+type backupsSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *backups.Client
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = backups.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+}
+
+func (s *backupsSuite) addMetadata(c *gc.C, id string) {
+	err := s.State.AddBackupMetadata(state.BackupMetadata{
+		Id:      id,
+		Notes:   "nightly",
+		Started: time.Now(),
+		Size:    42,
+	})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *backupsSuite) TestInfo(c *gc.C) {
+	s.addMetadata(c, "backup-0")
+	result, err := s.client.Info("backup-0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ID, gc.Equals, "backup-0")
+	c.Assert(result.Notes, gc.Equals, "nightly")
+	c.Assert(result.Size, gc.Equals, int64(42))
+
+	_, err = s.client.Info("backup-1")
+	c.Assert(err, gc.ErrorMatches, `backup "backup-1" not found`)
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeNotFound)
+}
+
+func (s *backupsSuite) TestListAndRemove(c *gc.C) {
+	list, err := s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 0)
+
+	s.addMetadata(c, "backup-0")
+	list, err = s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 1)
+	c.Assert(list[0].ID, gc.Equals, "backup-0")
+
+	err = s.client.Remove("backup-0")
+	c.Assert(err, gc.IsNil)
+	list, err = s.client.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(list, gc.HasLen, 0)
+
+	err = s.client.Remove("backup-0")
+	c.Assert(err, gc.ErrorMatches, `backup "backup-0" not found`)
+}
+
+func (s *backupsSuite) TestDownloadNotStored(c *gc.C) {
+	s.addMetadata(c, "backup-0")
+	_, err := s.client.Download("backup-0")
+	c.Assert(err, gc.ErrorMatches, `archive for backup "backup-0" not found`)
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeNotFound)
+}
+
+func (s *backupsSuite) TestUploadAndDownload(c *gc.C) {
+	result, err := s.client.Upload(strings.NewReader("archive data"), "old server")
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Notes, gc.Equals, "old server")
+	c.Assert(result.Size, gc.Equals, int64(len("archive data")))
+	c.Assert(result.Stored, gc.Equals, true)
+
+	archive, err := s.client.Download(result.ID)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "archive data")
+}
+
+func (s *backupsSuite) TestRestoreUnknownBackup(c *gc.C) {
+	err := s.client.Restore("backup-0")
+	c.Assert(err, gc.ErrorMatches, `backup "backup-0" not found`)
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeNotFound)
+}
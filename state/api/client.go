@@ -79,6 +79,24 @@ type MachineStatus struct {
 	Jobs          []params.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// StateServerMember holds the status of the machine's member of
+	// the state server replica set. It is nil for machines that are
+	// not members, and when the replica set status is unavailable.
+	StateServerMember *StateServerMemberStatus
+}
+
+// StateServerMemberStatus holds the status of a state server machine's
+// member of the Mongo replica set.
+type StateServerMemberStatus struct {
+	// State holds the replica set state of the member, such as
+	// PRIMARY or SECONDARY.
+	State   string
+	Healthy bool
+	// Lag holds how far the member lags behind the primary.
+	Lag time.Duration
+	// Error holds the most recent error reported for the member.
+	Error string
 }
 
 // ServiceStatus holds status info about a service.
@@ -98,6 +116,10 @@ type ServiceStatus struct {
 type UnitStatus struct {
 	Agent AgentStatus
 
+	// Workload holds the status of the unit's workload, as set by
+	// its charm.
+	Workload WorkloadStatus
+
 	// See the comment in MachineStatus regarding these fields.
 	AgentState     params.Status
 	AgentStateInfo string
@@ -112,6 +134,13 @@ type UnitStatus struct {
 	Subordinates  map[string]UnitStatus
 }
 
+// WorkloadStatus holds the status of a unit's workload.
+type WorkloadStatus struct {
+	Status params.Status
+	Info   string
+	Err    error
+}
+
 // RelationStatus holds status info about a relation.
 type RelationStatus struct {
 	Id        int
@@ -148,6 +177,17 @@ type Status struct {
 	Services        map[string]ServiceStatus
 	Networks        map[string]NetworkStatus
 	Relations       []RelationStatus
+	// Backups is nil if no scheduled backup has been attempted.
+	Backups *BackupsStatus
+}
+
+// BackupsStatus holds the outcome of the most recent scheduled backups
+// of the state server.
+type BackupsStatus struct {
+	LastSuccess  time.Time
+	LastBackupId string
+	LastFailure  time.Time
+	LastError    string
 }
 
 // Status returns the status of the juju environment.
@@ -280,6 +320,14 @@ func (c *Client) ServiceCharmRelations(service string) ([]string, error) {
 	return results.CharmRelations, err
 }
 
+// ExportBundle returns the services of the environment and the
+// relations between them, in a form that can be written as a bundle.
+func (c *Client) ExportBundle() (*params.ExportBundleResults, error) {
+	var results params.ExportBundleResults
+	err := c.call("ExportBundle", nil, &results)
+	return &results, err
+}
+
 // AddMachines1dot18 adds new machines with the supplied parameters.
 //
 // TODO(axw) 2014-04-11 #XXX
@@ -370,6 +418,32 @@ func (c *Client) ServiceDeploy(charmURL string, serviceName string, numUnits int
 	return c.call("ServiceDeploy", params, nil)
 }
 
+// ServiceDeployDryRun returns the changes ServiceDeploy would make
+// with the given arguments, or the error it would fail with, without
+// changing the environment.
+func (c *Client) ServiceDeployDryRun(charmURL string, serviceName string, numUnits int, configYAML string, cons constraints.Value, toMachineSpec string) ([]string, error) {
+	args := params.ServiceDeploy{
+		ServiceName:   serviceName,
+		CharmUrl:      charmURL,
+		NumUnits:      numUnits,
+		ConfigYAML:    configYAML,
+		Constraints:   cons,
+		ToMachineSpec: toMachineSpec,
+	}
+	var results params.DryRunResults
+	err := c.call("ServiceDeployDryRun", args, &results)
+	return results.Changes, err
+}
+
+// ServiceUpdateDryRun returns the changes ServiceUpdate would make
+// with the given arguments, or the error it would fail with, without
+// changing the environment.
+func (c *Client) ServiceUpdateDryRun(args params.ServiceUpdate) ([]string, error) {
+	var results params.DryRunResults
+	err := c.call("ServiceUpdateDryRun", args, &results)
+	return results.Changes, err
+}
+
 // ServiceUpdate updates the service attributes, including charm URL,
 // minimum number of units, settings and constraints.
 // TODO(frankban) deprecate redundant API calls that this supercedes.
@@ -411,6 +485,20 @@ func (c *Client) AddServiceUnits(service string, numUnits int, machineSpec strin
 	return results.Units, err
 }
 
+// AddServiceUnitsDryRun returns the changes AddServiceUnits would make
+// with the given arguments, or the error it would fail with, without
+// changing the environment.
+func (c *Client) AddServiceUnitsDryRun(service string, numUnits int, machineSpec string) ([]string, error) {
+	args := params.AddServiceUnits{
+		ServiceName:   service,
+		NumUnits:      numUnits,
+		ToMachineSpec: machineSpec,
+	}
+	var results params.DryRunResults
+	err := c.call("AddServiceUnitsDryRun", args, &results)
+	return results.Changes, err
+}
+
 // DestroyServiceUnits decreases the number of units dedicated to a service.
 func (c *Client) DestroyServiceUnits(unitNames ...string) error {
 	params := params.DestroyServiceUnits{unitNames}
@@ -771,15 +859,32 @@ func (c *Client) APIHostPorts() ([][]network.HostPort, error) {
 }
 
 // EnsureAvailability ensures the availability of Juju state servers.
-func (c *Client) EnsureAvailability(numStateServers int, cons constraints.Value, series string) error {
+// The placement directives are used, in order, for the state servers
+// needed; a machine id converts that machine into a state server.
+func (c *Client) EnsureAvailability(numStateServers int, cons constraints.Value, series string, placement []string) error {
 	args := params.EnsureAvailability{
 		NumStateServers: numStateServers,
 		Constraints:     cons,
 		Series:          series,
+		Placement:       placement,
 	}
 	return c.call("EnsureAvailability", args, nil)
 }
 
+// DemoteStateServers removes the votes of the state server machines
+// with the given tags. They are replaced by the next EnsureAvailability
+// call, and their state server jobs are removed once their votes are.
+func (c *Client) DemoteStateServers(machines ...string) ([]params.ErrorResult, error) {
+	p := params.Entities{}
+	p.Entities = make([]params.Entity, len(machines))
+	for i, machine := range machines {
+		p.Entities[i] = params.Entity{Tag: machine}
+	}
+	var results params.ErrorResults
+	err := c.call("DemoteStateServers", p, &results)
+	return results.Results, err
+}
+
 // AgentVersion reports the version number of the api server.
 func (c *Client) AgentVersion() (version.Number, error) {
 	var result params.AgentVersionResult
@@ -827,6 +932,19 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// Since and Until restrict the response to the lines logged at or
+	// after Since, and before Until. The log is read from the start
+	// when either is set, and the socket is closed once the lines
+	// logged before Until have been sent.
+	Since time.Time
+	Until time.Time
+	// Grep holds a regular expression restricting the response to the
+	// lines whose messages match it.
+	Grep string
+	// Format selects how the lines are sent: as they are written to the
+	// log when empty or "text", or as JSON encoded params.LogMessage
+	// values, one per line, when "json".
+	Format string
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -859,6 +977,18 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if !args.Since.IsZero() {
+		attrs.Set("since", args.Since.UTC().Format(time.RFC3339))
+	}
+	if !args.Until.IsZero() {
+		attrs.Set("until", args.Until.UTC().Format(time.RFC3339))
+	}
+	if args.Grep != "" {
+		attrs.Set("grep", args.Grep)
+	}
+	if args.Format != "" {
+		attrs.Set("format", args.Format)
+	}
 	attrs["includeEntity"] = args.IncludeEntity
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
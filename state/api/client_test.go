@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/loggo"
@@ -156,6 +157,10 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		Backlog:       200,
 		Level:         loggo.ERROR,
 		Replay:        true,
+		Since:         time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC),
+		Until:         time.Date(2014, 9, 2, 0, 0, 0, 0, time.UTC),
+		Grep:          "^worker",
+		Format:        "json",
 	}
 
 	client := s.APIState.Client()
@@ -175,6 +180,10 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
 		"replay":        {"true"},
+		"since":         {"2014-09-01T12:00:00Z"},
+		"until":         {"2014-09-02T00:00:00Z"},
+		"grep":          {"^worker"},
+		"format":        {"json"},
 	})
 }
 
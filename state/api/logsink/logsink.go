@@ -0,0 +1,27 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink
+
+import (
+	"github.com/juju/juju/state/api/base"
+	"github.com/juju/juju/state/api/params"
+)
+
+// State provides access to the LogSink API facade.
+type State struct {
+	caller base.Caller
+}
+
+// NewState returns a version of the state that provides functionality
+// required by the log sender worker.
+func NewState(caller base.Caller) *State {
+	return &State{caller}
+}
+
+// WriteLogs adds the given records, logged by the connected agent, to
+// the environment's log.
+func (st *State) WriteLogs(records []params.LogRecord) error {
+	args := params.LogRecords{Records: records}
+	return st.caller.Call("LogSink", "", "WriteLogs", args, nil)
+}
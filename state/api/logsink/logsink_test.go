@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/logsink"
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type logSinkSuite struct {
+	jujutesting.JujuConnSuite
+
+	rawMachine *state.Machine
+	logsink    *logsink.State
+}
+
+var _ = gc.Suite(&logSinkSuite{})
+
+func (s *logSinkSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	stateAPI, rawMachine := s.OpenAPIAsNewMachine(c)
+	s.rawMachine = rawMachine
+	s.logsink = stateAPI.LogSink()
+	c.Assert(s.logsink, gc.NotNil)
+}
+
+func (s *logSinkSuite) TestWriteLogs(c *gc.C) {
+	err := s.logsink.WriteLogs([]params.LogRecord{{
+		Time:     time.Now(),
+		Module:   "juju.worker.machiner",
+		Location: "machiner.go:42",
+		Level:    loggo.INFO,
+		Message:  "machine started",
+	}})
+	c.Assert(err, gc.IsNil)
+
+	tailer := s.State.NewLogTailer(state.LogTailerParams{Replay: true})
+	defer tailer.Stop()
+	select {
+	case record := <-tailer.Records():
+		c.Assert(record.Entity, gc.Equals, s.rawMachine.Tag())
+		c.Assert(record.Message, gc.Equals, "machine started")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
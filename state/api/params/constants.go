@@ -67,6 +67,43 @@ const (
 	StatusDown Status = "down"
 )
 
+const (
+	// The charm has not yet reported the status of the unit's
+	// workload.
+	StatusUnknown Status = "unknown"
+
+	// The unit is not yet providing service, but is actively doing
+	// work in preparation for providing service.
+	StatusMaintenance Status = "maintenance"
+
+	// The unit is unable to progress to an active state because a
+	// service to which it is related is not running.
+	StatusWaiting Status = "waiting"
+
+	// The unit needs manual intervention, such as missing
+	// configuration, to get it into a working state.
+	StatusBlocked Status = "blocked"
+
+	// The unit believes it is correctly offering all the services it
+	// has been asked to offer.
+	StatusActive Status = "active"
+)
+
+// ValidWorkload returns true if status is a workload status that may
+// be set by a unit's charm.
+func (status Status) ValidWorkload() bool {
+	switch status {
+	case
+		StatusMaintenance,
+		StatusWaiting,
+		StatusBlocked,
+		StatusActive:
+	default:
+		return false
+	}
+	return true
+}
+
 // Valid returns true if status has a known value.
 func (status Status) Valid() bool {
 	switch status {
@@ -149,6 +149,21 @@ type BoolResults struct {
 	Results []BoolResult
 }
 
+// ClaimLeadershipResult holds the result of a unit's claim to the
+// leadership of its service: whether the unit is the leader and, if
+// so, the duration of the lease it has been granted.
+type ClaimLeadershipResult struct {
+	Error    *Error
+	IsLeader bool
+	Duration time.Duration
+}
+
+// ClaimLeadershipResults holds multiple results with
+// ClaimLeadershipResult each.
+type ClaimLeadershipResults struct {
+	Results []ClaimLeadershipResult
+}
+
 // RelationSettings holds relation settings names and values.
 type RelationSettings map[string]string
 
@@ -179,6 +194,31 @@ type ConfigSettingsResults struct {
 	Results []ConfigSettingsResult
 }
 
+// LeaderSettingsResult holds the leader settings of a unit's service
+// or an error.
+type LeaderSettingsResult struct {
+	Error    *Error
+	Settings map[string]string
+}
+
+// LeaderSettingsResults holds multiple leader settings or errors.
+type LeaderSettingsResults struct {
+	Results []LeaderSettingsResult
+}
+
+// EntityLeaderSettings holds a unit's tag and the leader settings
+// it writes.
+type EntityLeaderSettings struct {
+	Tag      string
+	Settings map[string]string
+}
+
+// EntitiesLeaderSettings holds the parameters for making a
+// MergeLeaderSettings API call.
+type EntitiesLeaderSettings struct {
+	Entities []EntityLeaderSettings
+}
+
 // EnvironConfig holds an environment configuration.
 type EnvironConfig map[string]interface{}
 
@@ -629,3 +669,51 @@ type ProvisioningInfoResult struct {
 type ProvisioningInfoResults struct {
 	Results []ProvisioningInfoResult
 }
+
+// ActionIds holds the ids of multiple actions.
+type ActionIds struct {
+	Ids []string
+}
+
+// Action describes an action queued for execution on a unit.
+// Receiver holds the tag of the unit the action is queued for.
+// When enqueueing an action on a service, IncludeFuture requests
+// that the action is also queued on units added to the service later.
+// Timeout, if non-zero, is how long the unit may run the action before
+// stopping it. Cancelling is set once cancellation of a running action
+// has been requested.
+type Action struct {
+	Id            string
+	Receiver      string
+	Name          string
+	Params        map[string]interface{}
+	IncludeFuture bool
+	Timeout       time.Duration
+	Cancelling    bool
+}
+
+// ActionQueryResult holds an action or an error.
+type ActionQueryResult struct {
+	Error  *Error
+	Action *Action
+}
+
+// ActionQueryResults holds multiple actions or errors.
+type ActionQueryResults struct {
+	Results []ActionQueryResult
+}
+
+// ActionExecutionResult holds the outcome of running an action, as
+// reported by the uniter.
+type ActionExecutionResult struct {
+	ActionId string
+	Status   string
+	Results  map[string]interface{}
+	Message  string
+}
+
+// ActionExecutionResults holds the arguments for making a
+// FinishActions API call.
+type ActionExecutionResults struct {
+	Results []ActionExecutionResult
+}
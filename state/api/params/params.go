@@ -7,7 +7,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/juju/loggo"
 	"github.com/juju/utils/proxy"
 
 	"github.com/juju/juju/charm"
@@ -219,6 +221,34 @@ type ServiceGetResults struct {
 	Constraints constraints.Value
 }
 
+// DryRunResults holds the changes that a call would make to the
+// environment, as reported by its dry-run form, which checks the call
+// without making them.
+type DryRunResults struct {
+	Changes []string
+}
+
+// BundleService describes a deployed service, as exported by the
+// ExportBundle call.
+type BundleService struct {
+	Charm    string
+	NumUnits int
+	// Options holds the service's config settings that differ from
+	// the charm defaults.
+	Options     map[string]interface{}
+	Constraints constraints.Value
+	Exposed     bool
+	Annotations map[string]string
+}
+
+// ExportBundleResults holds the results of the ExportBundle call:
+// the services of the environment, and the relations between them
+// as pairs of "service:relation" endpoints.
+type ExportBundleResults struct {
+	Services  map[string]BundleService
+	Relations [][]string
+}
+
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
 type ServiceCharmRelations struct {
 	ServiceName string
@@ -397,6 +427,42 @@ type ModifyUser struct {
 	Password    string
 }
 
+// UserInfo holds information about a user.
+type UserInfo struct {
+	Username       string
+	DisplayName    string
+	CreatedBy      string
+	DateCreated    time.Time
+	LastConnection *time.Time
+	Disabled       bool
+}
+
+// UserInfoResult holds the result of a UserManager.UserInfo call
+// for a single user.
+type UserInfoResult struct {
+	Result *UserInfo
+	Error  *Error
+}
+
+// UserInfoResults holds the results of UserManager UserInfo
+// and ListUsers calls.
+type UserInfoResults struct {
+	Results []UserInfoResult
+}
+
+// ModifyUserRoles holds the parameters for making UserManager
+// GrantRole or RevokeRole calls.
+type ModifyUserRoles struct {
+	Changes []ModifyUserRole
+}
+
+// ModifyUserRole holds the role to grant to or revoke from a user
+// in the environment.
+type ModifyUserRole struct {
+	Username string
+	Role     string
+}
+
 // MarshalJSON implements json.Marshaler.
 func (d *Delta) MarshalJSON() ([]byte, error) {
 	b, err := json.Marshal(d.Entity)
@@ -567,6 +633,10 @@ type UnitInfo struct {
 	Status         Status
 	StatusInfo     string
 	StatusData     StatusData
+	// WorkloadStatus and WorkloadStatusInfo hold the status of the
+	// unit's workload, as set by its charm.
+	WorkloadStatus     Status
+	WorkloadStatusInfo string
 }
 
 func (i *UnitInfo) EntityId() EntityId {
@@ -699,6 +769,9 @@ type RsyslogConfigResult struct {
 	// logs to.
 	Port      int
 	HostPorts []network.HostPort
+	// Disabled is true when agents should not send their logs
+	// through rsyslog.
+	Disabled bool
 }
 
 // RsyslogConfigResults is the bulk form of RyslogConfigResult
@@ -740,4 +813,197 @@ type EnsureAvailability struct {
 	// Series is the series to associate with new state server machines.
 	// If this is empty, then the environment's default series is used.
 	Series string
+	// Placement holds the placement directives used, in order, for
+	// the state servers needed. A machine id converts that machine
+	// into a state server.
+	Placement []string
+}
+
+// Actions holds the actions to enqueue with an Enqueue call.
+type Actions struct {
+	Actions []Action
+}
+
+// ActionEnqueueResult holds the actions queued for a single receiver,
+// or an error. ServiceActionId is set when the receiver is a service,
+// and identifies the service action tracking the queued actions.
+type ActionEnqueueResult struct {
+	Error           *Error
+	ServiceActionId string
+	Actions         []Action
+}
+
+// ActionEnqueueResults holds the results of an Enqueue call.
+type ActionEnqueueResults struct {
+	Results []ActionEnqueueResult
+}
+
+// ActionResult describes the outcome of an action run on a unit. The
+// Status is "pending" while the action is still queued, and "running"
+// once the unit has started it. Started and Completed are zero until
+// the action has started and finished respectively.
+type ActionResult struct {
+	ActionId  string
+	Receiver  string
+	Name      string
+	Params    map[string]interface{}
+	Status    string
+	Results   map[string]interface{}
+	Output    string
+	Message   string
+	Enqueued  time.Time
+	Started   time.Time
+	Completed time.Time
+}
+
+// ActionListResult holds the pending actions and the results of the
+// completed actions of a single receiver, or an error.
+type ActionListResult struct {
+	Error     *Error
+	Receiver  string
+	Pending   []Action
+	Completed []ActionResult
+}
+
+// ActionListResults holds the results of a List call.
+type ActionListResults struct {
+	Results []ActionListResult
+}
+
+// ActionResultQueryResult holds the result of a single action, or
+// an error.
+type ActionResultQueryResult struct {
+	Error  *Error
+	Result *ActionResult
+}
+
+// ActionResultQueryResults holds the results of a Results call.
+type ActionResultQueryResults struct {
+	Results []ActionResultQueryResult
+}
+
+// ServiceActionResult describes the progress of an action queued on
+// every unit of a service, with the state of the action on each unit.
+type ServiceActionResult struct {
+	Id            string
+	Receiver      string
+	Name          string
+	Params        map[string]interface{}
+	IncludeFuture bool
+	Enqueued      time.Time
+	Units         []ActionResult
+}
+
+// ServiceActionQueryResult holds the progress of a single service
+// action, or an error.
+type ServiceActionQueryResult struct {
+	Error  *Error
+	Result *ServiceActionResult
+}
+
+// ServiceActionQueryResults holds the results of a ServiceResults call.
+type ServiceActionQueryResults struct {
+	Results []ServiceActionQueryResult
+}
+
+// BackupsCreateArgs holds the arguments to a Backups Create call.
+type BackupsCreateArgs struct {
+	Notes string
+}
+
+// BackupsInfoArgs holds the arguments to a Backups Info call.
+type BackupsInfoArgs struct {
+	ID string
+}
+
+// BackupsRemoveArgs holds the arguments to a Backups Remove call.
+type BackupsRemoveArgs struct {
+	ID string
+}
+
+// BackupsRestoreArgs holds the arguments to a Backups Restore call.
+type BackupsRestoreArgs struct {
+	ID string
+}
+
+// BackupsMetadataResult describes a backup of the state server.
+type BackupsMetadataResult struct {
+	ID             string
+	Notes          string
+	Started        time.Time
+	Finished       time.Time
+	Checksum       string
+	ChecksumFormat string
+	Size           int64
+	Stored         bool
+	Environment    string
+	Machine        string
+	Hostname       string
+	Version        version.Number
+}
+
+// BackupsListResult holds the result of a Backups List call.
+type BackupsListResult struct {
+	List []BackupsMetadataResult
+}
+
+// AuditLogFilter holds the arguments to an AuditLog Entries call. Zero
+// valued fields select every entry.
+type AuditLogFilter struct {
+	// User holds the tag of the user whose calls are selected.
+	User string
+	// After and Before select the calls made at or after After, and
+	// before Before.
+	After  time.Time
+	Before time.Time
+	// Entity selects the calls that touched the entity.
+	Entity string
+	// AfterId selects the calls recorded after the identified entry,
+	// so that the log can be read a page at a time.
+	AfterId string
+	// Limit holds the maximum number of calls to return. The server
+	// returns no more than its own maximum, whatever the limit.
+	Limit int
+}
+
+// AuditLogEntry describes a call recorded in the audit log.
+type AuditLogEntry struct {
+	Id       string
+	Time     time.Time
+	User     string
+	Facade   string
+	Method   string
+	Args     string
+	Entities []string
+	Error    string
+}
+
+// AuditLogEntries holds the result of an AuditLog Entries call.
+type AuditLogEntries struct {
+	Entries []AuditLogEntry
+}
+
+// LogRecord holds a log message written by an agent.
+type LogRecord struct {
+	Time     time.Time
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// LogRecords holds the arguments to a LogSink WriteLogs call.
+type LogRecords struct {
+	Records []LogRecord
+}
+
+// LogMessage is a log message as sent by the debug-log end point when
+// messages are requested in JSON format, one per line.
+type LogMessage struct {
+	Entity   string    `json:"entity"`
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	Module   string    `json:"module"`
+	Location string    `json:"location,omitempty"`
+	Message  string    `json:"message"`
 }
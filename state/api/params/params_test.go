@@ -80,14 +80,16 @@ var marshalTestCases = []struct {
 					Protocol: "http",
 					Number:   80},
 			},
-			PublicAddress:  "testing.invalid",
-			PrivateAddress: "10.0.0.1",
-			MachineId:      "1",
-			Status:         "error",
-			StatusInfo:     "foo",
+			PublicAddress:      "testing.invalid",
+			PrivateAddress:     "10.0.0.1",
+			MachineId:          "1",
+			Status:             "error",
+			StatusInfo:         "foo",
+			WorkloadStatus:     "blocked",
+			WorkloadStatusInfo: "missing config",
 		},
 	},
-	json: `["unit", "change", {"CharmURL": "cs:~user/precise/wordpress-42", "MachineId": "1", "Series": "precise", "Name": "Benji", "PublicAddress": "testing.invalid", "Service": "Shazam", "PrivateAddress": "10.0.0.1", "Ports": [{"Protocol": "http", "Number": 80}], "Status": "error", "StatusInfo": "foo","StatusData":null}]`,
+	json: `["unit", "change", {"CharmURL": "cs:~user/precise/wordpress-42", "MachineId": "1", "Series": "precise", "Name": "Benji", "PublicAddress": "testing.invalid", "Service": "Shazam", "PrivateAddress": "10.0.0.1", "Ports": [{"Protocol": "http", "Number": 80}], "Status": "error", "StatusInfo": "foo","StatusData":null,"WorkloadStatus":"blocked","WorkloadStatusInfo":"missing config"}]`,
 }, {
 	about: "RelationInfo Delta",
 	value: params.Delta{
@@ -20,6 +20,9 @@ type RsyslogConfig struct {
 	// Port is only used by state servers as the port to listen on.
 	Port      int
 	HostPorts []network.HostPort
+	// Disabled is true when agents should not send their logs
+	// through rsyslog.
+	Disabled bool
 }
 
 // State provides access to the Rsyslog API facade.
@@ -94,5 +97,6 @@ func (st *State) GetRsyslogConfig(agentTag string) (*RsyslogConfig, error) {
 		CACert:    result.CACert,
 		Port:      result.Port,
 		HostPorts: result.HostPorts,
+		Disabled:  result.Disabled,
 	}, nil
 }
@@ -4,6 +4,7 @@
 package rsyslog_test
 
 import (
+	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
 	"github.com/juju/juju/juju/testing"
@@ -52,6 +53,16 @@ func (s *rsyslogSuite) TestGetRsyslogConfig(c *gc.C) {
 
 	// the rsyslog port is set by the provider/dummy/environs.go
 	c.Assert(hostPort.Port, gc.Equals, 2345)
+	c.Assert(cfg.Disabled, jc.IsFalse)
+}
+
+func (s *rsyslogSuite) TestGetRsyslogConfigDisabled(c *gc.C) {
+	err := s.APIState.Client().EnvironmentSet(map[string]interface{}{"disable-rsyslog": true})
+	c.Assert(err, gc.IsNil)
+
+	cfg, err := s.rsyslog.GetRsyslogConfig(s.machine.Tag())
+	c.Assert(err, gc.IsNil)
+	c.Assert(cfg.Disabled, jc.IsTrue)
 }
 
 func (s *rsyslogSuite) TestWatchForRsyslogChanges(c *gc.C) {
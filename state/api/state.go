@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/state/api/firewaller"
 	"github.com/juju/juju/state/api/keyupdater"
 	apilogger "github.com/juju/juju/state/api/logger"
+	"github.com/juju/juju/state/api/logsink"
 	"github.com/juju/juju/state/api/machiner"
 	"github.com/juju/juju/state/api/networker"
 	"github.com/juju/juju/state/api/params"
@@ -170,3 +171,8 @@ func (st *State) CharmRevisionUpdater() *charmrevisionupdater.State {
 func (st *State) Rsyslog() *rsyslog.State {
 	return rsyslog.NewState(st)
 }
+
+// LogSink returns access to the LogSink API
+func (st *State) LogSink() *logsink.State {
+	return logsink.NewState(st)
+}
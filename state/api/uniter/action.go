@@ -0,0 +1,49 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+)
+
+// Action represents a single instance of an Action call, by name and params.
+type Action struct {
+	id         string
+	name       string
+	params     map[string]interface{}
+	timeout    time.Duration
+	cancelling bool
+}
+
+// NewAction makes a new Action with specified id, name and params.
+func NewAction(id, name string, params map[string]interface{}) *Action {
+	return &Action{id: id, name: name, params: params}
+}
+
+// Id retrieves the id of the Action.
+func (a *Action) Id() string {
+	return a.id
+}
+
+// Name retrieves the name of the Action.
+func (a *Action) Name() string {
+	return a.name
+}
+
+// Params retrieves the params map of the Action.
+func (a *Action) Params() map[string]interface{} {
+	return a.params
+}
+
+// Timeout returns how long the action may run before the unit stops it,
+// or zero if it may run indefinitely.
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+// Cancelling returns whether cancellation of the action had been
+// requested when it was retrieved.
+func (a *Action) Cancelling() bool {
+	return a.cancelling
+}
@@ -0,0 +1,150 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type actionSuite struct {
+	uniterSuite
+}
+
+var _ = gc.Suite(&actionSuite{})
+
+func (s *actionSuite) TestAction(c *gc.C) {
+	payload := map[string]interface{}{"outfile": "foo.tgz"}
+	id, err := s.wordpressUnit.AddAction("snapshot", payload)
+	c.Assert(err, gc.IsNil)
+
+	action, err := s.uniter.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Id(), gc.Equals, id)
+	c.Assert(action.Name(), gc.Equals, "snapshot")
+	c.Assert(action.Params(), jc.DeepEquals, payload)
+	c.Assert(action.Timeout(), gc.Equals, time.Duration(0))
+	c.Assert(action.Cancelling(), jc.IsFalse)
+}
+
+func (s *actionSuite) TestActionTimeoutAndCancelling(c *gc.C) {
+	id, err := s.wordpressUnit.AddActionWithTimeout("snapshot", nil, time.Minute)
+	c.Assert(err, gc.IsNil)
+	err = s.uniter.ActionBegin(id)
+	c.Assert(err, gc.IsNil)
+	stateAction, err := s.BackingState.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = stateAction.Cancel()
+	c.Assert(err, gc.IsNil)
+
+	action, err := s.uniter.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Minute)
+	c.Assert(action.Cancelling(), jc.IsTrue)
+}
+
+func (s *actionSuite) TestActionNotForThisUnit(c *gc.C) {
+	_, _, _, mysqlUnit := s.addMachineServiceCharmAndUnit(c, "mysql")
+	id, err := mysqlUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.uniter.Action(id)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *actionSuite) TestWatchAction(c *gc.C) {
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	err = s.uniter.ActionBegin(id)
+	c.Assert(err, gc.IsNil)
+
+	w, err := s.uniter.WatchAction(id)
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+	wc.AssertOneChange()
+
+	stateAction, err := s.BackingState.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = stateAction.Cancel()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *actionSuite) TestActionBegin(c *gc.C) {
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.uniter.ActionBegin(id)
+	c.Assert(err, gc.IsNil)
+
+	action, err := s.BackingState.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Started().IsZero(), gc.Equals, false)
+}
+
+func (s *actionSuite) TestActionComplete(c *gc.C) {
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.uniter.ActionComplete(id, map[string]interface{}{"outfile": "out.tar"})
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.BackingState.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionCompleted)
+	c.Assert(results[0].Results(), gc.DeepEquals, map[string]interface{}{"outfile": "out.tar"})
+	c.Assert(results[0].Message(), gc.Equals, "")
+}
+
+func (s *actionSuite) TestActionFail(c *gc.C) {
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.uniter.ActionFail(id, nil, "it broke")
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.BackingState.ActionResultsForAction(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionFailed)
+	c.Assert(results[0].Message(), gc.Equals, "it broke")
+
+	// The action is no longer available.
+	_, err = s.uniter.Action(id)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *actionSuite) TestActionCancelledAndTimedOut(c *gc.C) {
+	cancelledId, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	timedOutId, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	err = s.uniter.ActionCancelled(cancelledId, nil, "action cancelled")
+	c.Assert(err, gc.IsNil)
+	err = s.uniter.ActionTimedOut(timedOutId, map[string]interface{}{"done": "half"}, "action timed out")
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.BackingState.ActionResultsForAction(cancelledId)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionCancelled)
+	c.Assert(results[0].Message(), gc.Equals, "action cancelled")
+
+	results, err = s.BackingState.ActionResultsForAction(timedOutId)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionTimedOut)
+	c.Assert(results[0].Results(), gc.DeepEquals, map[string]interface{}{"done": "half"})
+}
@@ -6,6 +6,7 @@ package uniter
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/juju/names"
 
@@ -70,6 +71,43 @@ func (u *Unit) SetStatus(status params.Status, info string, data params.StatusDa
 	return result.OneError()
 }
 
+// SetWorkloadStatus sets the status of the unit's workload, as
+// reported by its charm, along with a message describing it.
+func (u *Unit) SetWorkloadStatus(status params.Status, info string) error {
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: u.tag, Status: status, Info: info},
+		},
+	}
+	err := u.st.call("SetWorkloadStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WorkloadStatus returns the status of the unit's workload, along with
+// the message describing it.
+func (u *Unit) WorkloadStatus() (params.Status, string, error) {
+	var results params.StatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag}},
+	}
+	err := u.st.call("WorkloadStatus", args, &results)
+	if err != nil {
+		return "", "", err
+	}
+	if len(results.Results) != 1 {
+		return "", "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", result.Error
+	}
+	return result.Status, result.Info, nil
+}
+
 // EnsureDead sets the unit lifecycle to Dead if it is Alive or
 // Dying. It does nothing otherwise.
 func (u *Unit) EnsureDead() error {
@@ -432,6 +470,89 @@ func (u *Unit) WatchConfigSettings() (watcher.NotifyWatcher, error) {
 	return w, nil
 }
 
+// ClaimLeadership claims the leadership of the unit's service, or
+// renews the unit's existing claim, and reports whether the unit is
+// the leader and, if so, the duration of the lease it has been granted.
+// Leadership lapses unless it is claimed again before the lease expires.
+func (u *Unit) ClaimLeadership() (bool, time.Duration, error) {
+	var results params.ClaimLeadershipResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag}},
+	}
+	err := u.st.call("ClaimLeadership", args, &results)
+	if err != nil {
+		return false, 0, err
+	}
+	if len(results.Results) != 1 {
+		return false, 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, 0, result.Error
+	}
+	return result.IsLeader, result.Duration, nil
+}
+
+// LeaderSettings returns the settings that the leader of the unit's
+// service shares with the other units of the service.
+func (u *Unit) LeaderSettings() (map[string]string, error) {
+	var results params.LeaderSettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag}},
+	}
+	err := u.st.call("LeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// MergeLeaderSettings merges the given settings into the leader
+// settings of the unit's service; settings with empty values are
+// removed. The unit must be the leader of the service.
+func (u *Unit) MergeLeaderSettings(settings map[string]string) error {
+	var result params.ErrorResults
+	args := params.EntitiesLeaderSettings{
+		Entities: []params.EntityLeaderSettings{
+			{Tag: u.tag, Settings: settings},
+		},
+	}
+	err := u.st.call("MergeLeaderSettings", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WatchLeaderSettings returns a watcher for observing changes to the
+// leader settings of the unit's service.
+func (u *Unit) WatchLeaderSettings() (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag}},
+	}
+	err := u.st.call("WatchLeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.caller, result)
+	return w, nil
+}
+
 // JoinedRelations returns the tags of the relations the unit has joined.
 func (u *Unit) JoinedRelations() ([]string, error) {
 	var results params.StringsResults
@@ -451,3 +572,26 @@ func (u *Unit) JoinedRelations() ([]string, error) {
 	}
 	return result.Result, nil
 }
+
+// WatchActions returns a StringsWatcher for observing the ids of
+// actions queued for the unit. The initial event contains the ids of
+// all actions currently pending.
+func (u *Unit) WatchActions() (watcher.StringsWatcher, error) {
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag}},
+	}
+	err := u.st.call("WatchActions", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewStringsWatcher(u.st.caller, result)
+	return w, nil
+}
@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -64,6 +65,26 @@ func (s *unitSuite) TestSetStatus(c *gc.C) {
 	c.Assert(data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestWorkloadStatus(c *gc.C) {
+	status, info, err := s.apiUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.apiUnit.SetWorkloadStatus(params.StatusMaintenance, "installing packages")
+	c.Assert(err, gc.IsNil)
+
+	status, info, err = s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusMaintenance)
+	c.Assert(info, gc.Equals, "installing packages")
+
+	status, info, err = s.apiUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusMaintenance)
+	c.Assert(info, gc.Equals, "installing packages")
+}
+
 func (s *unitSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
@@ -353,6 +374,49 @@ func (s *unitSuite) TestWatchConfigSettings(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *unitSuite) TestLeadership(c *gc.C) {
+	settings, err := s.apiUnit.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.ErrorMatches, "unit is not the service leader")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotLeader)
+
+	isLeader, lease, err := s.apiUnit.ClaimLeadership()
+	c.Assert(err, gc.IsNil)
+	c.Assert(isLeader, gc.Equals, true)
+	c.Assert(lease, gc.Equals, time.Minute)
+	leader, err := s.wordpressService.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	settings, err = s.apiUnit.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *unitSuite) TestWatchLeaderSettings(c *gc.C) {
+	w, err := s.apiUnit.WatchLeaderSettings()
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	_, _, err = s.apiUnit.ClaimLeadership()
+	c.Assert(err, gc.IsNil)
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *unitSuite) TestServiceNameAndTag(c *gc.C) {
 	c.Assert(s.apiUnit.ServiceName(), gc.Equals, "wordpress")
 	c.Assert(s.apiUnit.ServiceTag(), gc.Equals, "service-wordpress")
@@ -374,3 +438,24 @@ func (s *unitSuite) TestJoinedRelations(c *gc.C) {
 	sort.Strings(joinedRelations)
 	c.Assert(joinedRelations, gc.DeepEquals, []string{rel2.Tag(), rel1.Tag()})
 }
+
+func (s *unitSuite) TestWatchActions(c *gc.C) {
+	w, err := s.apiUnit.WatchActions()
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertChange()
+
+	// Queue a couple of actions, check they're reported.
+	id1, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	id2, err := s.wordpressUnit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertChange(id1, id2)
+	wc.AssertNoChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
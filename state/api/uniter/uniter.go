@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/state/api/base"
 	"github.com/juju/juju/state/api/common"
 	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/watcher"
 )
 
 const uniterFacade = "Uniter"
@@ -196,3 +197,100 @@ func (st *State) environment1dot16() (*Environment, error) {
 		uuid: result.Result,
 	}, nil
 }
+
+// Action returns the Action with the given id, which must have been
+// queued for the authenticated unit.
+func (st *State) Action(id string) (*Action, error) {
+	var results params.ActionQueryResults
+	args := params.ActionIds{Ids: []string{id}}
+	err := st.call("Actions", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	action := NewAction(result.Action.Id, result.Action.Name, result.Action.Params)
+	action.timeout = result.Action.Timeout
+	action.cancelling = result.Action.Cancelling
+	return action, nil
+}
+
+// WatchAction returns a watcher for observing changes to the action
+// with the given id, which must have been queued for the authenticated
+// unit.
+func (st *State) WatchAction(id string) (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.ActionIds{Ids: []string{id}}
+	err := st.call("WatchAction", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(st.caller, result)
+	return w, nil
+}
+
+// ActionBegin records that the unit has started running the action
+// with the given id.
+func (st *State) ActionBegin(id string) error {
+	var results params.ErrorResults
+	args := params.ActionIds{Ids: []string{id}}
+	err := st.call("BeginActions", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// ActionComplete records the successful completion of the action
+// with the given id, along with the results it set.
+func (st *State) ActionComplete(id string, results map[string]interface{}) error {
+	return st.finishAction(id, "complete", results, "")
+}
+
+// ActionFail records the failure of the action with the given id,
+// along with any results it set and a message explaining the failure.
+func (st *State) ActionFail(id string, results map[string]interface{}, message string) error {
+	return st.finishAction(id, "fail", results, message)
+}
+
+// ActionCancelled records that the action with the given id was stopped
+// because its cancellation was requested, along with any results it set.
+func (st *State) ActionCancelled(id string, results map[string]interface{}, message string) error {
+	return st.finishAction(id, "cancelled", results, message)
+}
+
+// ActionTimedOut records that the action with the given id was stopped
+// because it ran for longer than its timeout, along with any results it
+// set.
+func (st *State) ActionTimedOut(id string, results map[string]interface{}, message string) error {
+	return st.finishAction(id, "timeout", results, message)
+}
+
+func (st *State) finishAction(id, status string, actionResults map[string]interface{}, message string) error {
+	var results params.ErrorResults
+	args := params.ActionExecutionResults{
+		Results: []params.ActionExecutionResult{{
+			ActionId: id,
+			Status:   status,
+			Results:  actionResults,
+			Message:  message,
+		}},
+	}
+	err := st.call("FinishActions", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
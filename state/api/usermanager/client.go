@@ -12,8 +12,6 @@ import (
 	"github.com/juju/juju/state/api/params"
 )
 
-// TODO(mattyw) 2014-03-07 bug #1288750
-// Need a SetPassword method.
 type Client struct {
 	st *api.State
 }
@@ -55,3 +53,90 @@ func (c *Client) RemoveUser(tag string) error {
 	}
 	return results.OneError()
 }
+
+// GrantRole gives the user at least the given role in the environment.
+func (c *Client) GrantRole(username, role string) error {
+	return c.modifyRole("GrantRole", username, role)
+}
+
+// RevokeRole takes the given role away from the user in the
+// environment, leaving the user with the role below it.
+func (c *Client) RevokeRole(username, role string) error {
+	return c.modifyRole("RevokeRole", username, role)
+}
+
+func (c *Client) modifyRole(method, username, role string) error {
+	args := params.ModifyUserRoles{
+		Changes: []params.ModifyUserRole{{Username: username, Role: role}},
+	}
+	results := new(params.ErrorResults)
+	err := c.call(method, args, results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// UserInfo returns information about the named user.
+func (c *Client) UserInfo(username string) (params.UserInfo, error) {
+	p := params.Entities{Entities: []params.Entity{{Tag: names.UserTag(username)}}}
+	results := new(params.UserInfoResults)
+	if err := c.call("UserInfo", p, results); err != nil {
+		return params.UserInfo{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.UserInfo{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return params.UserInfo{}, err
+	}
+	return *results.Results[0].Result, nil
+}
+
+// ListUsers returns information about all the users,
+// including the disabled ones.
+func (c *Client) ListUsers() ([]params.UserInfo, error) {
+	results := new(params.UserInfoResults)
+	if err := c.call("ListUsers", nil, results); err != nil {
+		return nil, err
+	}
+	users := make([]params.UserInfo, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		users[i] = *result.Result
+	}
+	return users, nil
+}
+
+// EnableUser allows the named user to log in again.
+func (c *Client) EnableUser(username string) error {
+	return c.userCall("EnableUser", username)
+}
+
+// DisableUser stops the named user from logging in.
+func (c *Client) DisableUser(username string) error {
+	return c.userCall("DisableUser", username)
+}
+
+func (c *Client) userCall(method, username string) error {
+	p := params.Entities{Entities: []params.Entity{{Tag: names.UserTag(username)}}}
+	results := new(params.ErrorResults)
+	if err := c.call(method, p, results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// SetPassword changes the password of the named user.
+func (c *Client) SetPassword(username, password string) error {
+	p := params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: names.UserTag(username), Password: password}},
+	}
+	results := new(params.ErrorResults)
+	if err := c.call("SetPassword", p, results); err != nil {
+		return err
+	}
+	return results.OneError()
+}
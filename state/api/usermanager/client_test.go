@@ -72,3 +72,79 @@ func (s *usermanagerSuite) TestCantRemoveAdminUser(c *gc.C) {
 	err := s.usermanager.RemoveUser(state.AdminUser)
 	c.Assert(err, gc.ErrorMatches, "Failed to remove user: Can't deactivate admin user")
 }
+
+func (s *usermanagerSuite) TestGrantAndRevokeRole(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+
+	err = s.usermanager.GrantRole("foobar", "admin")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Role(env.UUID()), gc.Equals, state.RoleAdmin)
+
+	err = s.usermanager.RevokeRole("foobar", "write")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Role(env.UUID()), gc.Equals, state.RoleRead)
+}
+
+func (s *usermanagerSuite) TestGrantRoleInvalid(c *gc.C) {
+	s.AddUser(c, "foobar")
+	err := s.usermanager.GrantRole("foobar", "root")
+	c.Assert(err, gc.ErrorMatches, `invalid role "root"`)
+}
+
+func (s *usermanagerSuite) TestUserInfo(c *gc.C) {
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	c.Assert(err, gc.IsNil)
+
+	info, err := s.usermanager.UserInfo("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.Username, gc.Equals, "foobar")
+	c.Assert(info.DisplayName, gc.Equals, "Foo Bar")
+	c.Assert(info.CreatedBy, gc.Equals, "admin")
+	c.Assert(info.LastConnection, gc.IsNil)
+
+	_, err = s.usermanager.UserInfo("nobody")
+	c.Assert(err, gc.ErrorMatches, `user "nobody" not found`)
+}
+
+func (s *usermanagerSuite) TestListUsers(c *gc.C) {
+	err := s.usermanager.AddUser("foobar", "Foo Bar", "password")
+	c.Assert(err, gc.IsNil)
+
+	users, err := s.usermanager.ListUsers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(users, gc.HasLen, 2)
+	c.Assert(users[0].Username, gc.Equals, "admin")
+	c.Assert(users[0].LastConnection, gc.NotNil)
+	c.Assert(users[1].Username, gc.Equals, "foobar")
+}
+
+func (s *usermanagerSuite) TestDisableAndEnableUser(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+
+	err := s.usermanager.DisableUser("foobar")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), gc.Equals, true)
+
+	err = s.usermanager.EnableUser("foobar")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), gc.Equals, false)
+}
+
+func (s *usermanagerSuite) TestSetPassword(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	err := s.usermanager.SetPassword("foobar", "new-password")
+	c.Assert(err, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.PasswordValid("new-password"), gc.Equals, true)
+}
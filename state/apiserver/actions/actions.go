@@ -0,0 +1,386 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actions
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// Actions defines the methods on the actions API end point.
+type Actions interface {
+	Enqueue(args params.Actions) (params.ActionEnqueueResults, error)
+	List(args params.Entities) (params.ActionListResults, error)
+	Results(args params.ActionIds) (params.ActionResultQueryResults, error)
+	ServiceResults(args params.ActionIds) (params.ServiceActionQueryResults, error)
+	Cancel(args params.ActionIds) (params.ErrorResults, error)
+}
+
+// ActionsAPI implements the Actions interface and is the concrete
+// implementation of the api end point.
+type ActionsAPI struct {
+	state      *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+var _ Actions = (*ActionsAPI)(nil)
+
+// NewActionsAPI creates a new server-side actions API end point. Any
+// user who may read the environment may list actions and their
+// results; queueing and cancelling actions requires write access.
+func NewActionsAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*ActionsAPI, error) {
+	if err := common.RequireUserRole(st, authorizer, state.RoleRead); err != nil {
+		return nil, err
+	}
+	return &ActionsAPI{
+		state:      st,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// requireRole returns an error unless the authenticated user has at
+// least the given role in the environment.
+func (a *ActionsAPI) requireRole(role state.Role) error {
+	return common.RequireUserRole(a.state, a.authorizer, role)
+}
+
+// Enqueue queues the given actions. A unit receiver gets a single
+// action; a service receiver gets one action for each of its units,
+// tracked by a service action, and optionally one for each unit added
+// to the service later.
+func (a *ActionsAPI) Enqueue(args params.Actions) (params.ActionEnqueueResults, error) {
+	if err := a.requireRole(state.RoleWrite); err != nil {
+		return params.ActionEnqueueResults{}, err
+	}
+	result := params.ActionEnqueueResults{
+		Results: make([]params.ActionEnqueueResult, len(args.Actions)),
+	}
+	for i, arg := range args.Actions {
+		result.Results[i] = a.enqueue(arg)
+	}
+	return result, nil
+}
+
+// enqueue adds the action described by arg to every unit targeted by
+// its receiver.
+func (a *ActionsAPI) enqueue(arg params.Action) (result params.ActionEnqueueResult) {
+	if arg.Name == "" {
+		result.Error = common.ServerError(errors.New("no action name given"))
+		return result
+	}
+	if kind, id, err := names.ParseTag(arg.Receiver, names.ServiceTagKind); err == nil && kind == names.ServiceTagKind {
+		serviceActionId, queued, err := a.enqueueService(id, arg)
+		result.ServiceActionId = serviceActionId
+		result.Actions = queued
+		result.Error = common.ServerError(err)
+		return result
+	}
+	units, err := a.receiverUnits(arg.Receiver)
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result
+	}
+	for _, unit := range units {
+		id, err := unit.AddActionWithTimeout(arg.Name, arg.Params, arg.Timeout)
+		if err != nil {
+			result.Error = common.ServerError(err)
+			return result
+		}
+		result.Actions = append(result.Actions, params.Action{
+			Id:       id,
+			Receiver: unit.Tag(),
+			Name:     arg.Name,
+			Params:   arg.Params,
+			Timeout:  arg.Timeout,
+		})
+	}
+	return result
+}
+
+// enqueueService adds the action described by arg to every unit of the
+// named service, and returns the id of the service action tracking
+// them along with the queued actions.
+func (a *ActionsAPI) enqueueService(name string, arg params.Action) (string, []params.Action, error) {
+	service, err := a.state.Service(name)
+	if err != nil {
+		return "", nil, err
+	}
+	if !arg.IncludeFuture {
+		units, err := service.AllUnits()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(units) == 0 {
+			return "", nil, errors.Errorf("service %q has no units", name)
+		}
+	}
+	serviceAction, err := service.AddAction(arg.Name, arg.Params, arg.Timeout, arg.IncludeFuture)
+	if err != nil {
+		return "", nil, err
+	}
+	actions, err := serviceAction.Actions()
+	if err != nil {
+		return serviceAction.Id(), nil, err
+	}
+	queued := make([]params.Action, len(actions))
+	for i, action := range actions {
+		queued[i] = params.Action{
+			Id:       action.Id(),
+			Receiver: names.UnitTag(action.UnitName()),
+			Name:     action.Name(),
+			Params:   arg.Params,
+			Timeout:  action.Timeout(),
+		}
+	}
+	return serviceAction.Id(), queued, nil
+}
+
+// receiverUnits returns the units addressed by the given unit or
+// service tag.
+func (a *ActionsAPI) receiverUnits(tag string) ([]*state.Unit, error) {
+	kind, id, err := names.ParseTag(tag, "")
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case names.UnitTagKind:
+		unit, err := a.state.Unit(id)
+		if err != nil {
+			return nil, err
+		}
+		return []*state.Unit{unit}, nil
+	case names.ServiceTagKind:
+		service, err := a.state.Service(id)
+		if err != nil {
+			return nil, err
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, err
+		}
+		if len(units) == 0 {
+			return nil, errors.Errorf("service %q has no units", id)
+		}
+		return units, nil
+	}
+	return nil, errors.Errorf("%q is not a valid action receiver", tag)
+}
+
+// List returns the pending and completed actions for each of the given
+// units or services.
+func (a *ActionsAPI) List(args params.Entities) (params.ActionListResults, error) {
+	var result params.ActionListResults
+	for _, entity := range args.Entities {
+		units, err := a.receiverUnits(entity.Tag)
+		if err != nil {
+			result.Results = append(result.Results, params.ActionListResult{
+				Receiver: entity.Tag,
+				Error:    common.ServerError(err),
+			})
+			continue
+		}
+		for _, unit := range units {
+			result.Results = append(result.Results, a.listUnit(unit))
+		}
+	}
+	return result, nil
+}
+
+// listUnit returns the pending and completed actions for a single unit.
+func (a *ActionsAPI) listUnit(unit *state.Unit) params.ActionListResult {
+	result := params.ActionListResult{Receiver: unit.Tag()}
+	pending, err := unit.Actions()
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result
+	}
+	for _, action := range pending {
+		result.Pending = append(result.Pending, params.Action{
+			Id:       action.Id(),
+			Receiver: unit.Tag(),
+			Name:     action.Name(),
+			Params:   action.Payload(),
+		})
+	}
+	completed, err := a.state.ActionResultsForUnit(unit.Name())
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result
+	}
+	for _, actionResult := range completed {
+		result.Completed = append(result.Completed, actionResultParams(actionResult))
+	}
+	return result
+}
+
+// Results returns the outcome of each of the given actions. Actions
+// that are still queued are reported with a "pending" status, or a
+// "running" status once the unit has started them.
+func (a *ActionsAPI) Results(args params.ActionIds) (params.ActionResultQueryResults, error) {
+	result := params.ActionResultQueryResults{
+		Results: make([]params.ActionResultQueryResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		actionResult, err := a.result(id)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = actionResult
+	}
+	return result, nil
+}
+
+// result returns the outcome of the action with the given id.
+func (a *ActionsAPI) result(id string) (*params.ActionResult, error) {
+	if !state.IsAction(id) {
+		return nil, errors.NotFoundf("action %q", id)
+	}
+	action, err := a.state.Action(id)
+	if err == nil {
+		r := actionParams(action)
+		return &r, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	actionResults, err := a.state.ActionResultsForAction(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(actionResults) == 0 {
+		return nil, errors.NotFoundf("action %q", id)
+	}
+	r := actionResultParams(actionResults[len(actionResults)-1])
+	return &r, nil
+}
+
+// Cancel cancels each of the given actions. Actions that are still
+// queued are removed from the queue; running actions are stopped by the
+// units running them. Cancelling a service action cancels its unit
+// actions, and stops it being queued on units added to the service.
+func (a *ActionsAPI) Cancel(args params.ActionIds) (params.ErrorResults, error) {
+	if err := a.requireRole(state.RoleWrite); err != nil {
+		return params.ErrorResults{}, err
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		result.Results[i].Error = common.ServerError(a.cancel(id))
+	}
+	return result, nil
+}
+
+// cancel cancels the action or service action with the given id.
+func (a *ActionsAPI) cancel(id string) error {
+	if state.IsServiceAction(id) {
+		serviceAction, err := a.state.ServiceAction(id)
+		if err != nil {
+			return err
+		}
+		return serviceAction.Cancel()
+	}
+	if !state.IsAction(id) {
+		return errors.NotFoundf("action %q", id)
+	}
+	action, err := a.state.Action(id)
+	if err != nil {
+		return err
+	}
+	return action.Cancel()
+}
+
+// ServiceResults returns the progress of each of the given service
+// actions, reporting the state of the action on every unit it was
+// queued on.
+func (a *ActionsAPI) ServiceResults(args params.ActionIds) (params.ServiceActionQueryResults, error) {
+	result := params.ServiceActionQueryResults{
+		Results: make([]params.ServiceActionQueryResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		serviceResult, err := a.serviceResult(id)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = serviceResult
+	}
+	return result, nil
+}
+
+// serviceResult returns the progress of the service action with the
+// given id.
+func (a *ActionsAPI) serviceResult(id string) (*params.ServiceActionResult, error) {
+	if !state.IsServiceAction(id) {
+		return nil, errors.NotFoundf("service action %q", id)
+	}
+	serviceAction, err := a.state.ServiceAction(id)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := serviceAction.Actions()
+	if err != nil {
+		return nil, err
+	}
+	actionResults, err := serviceAction.Results()
+	if err != nil {
+		return nil, err
+	}
+	result := &params.ServiceActionResult{
+		Id:            serviceAction.Id(),
+		Receiver:      names.ServiceTag(serviceAction.ServiceName()),
+		Name:          serviceAction.Name(),
+		Params:        serviceAction.Payload(),
+		IncludeFuture: serviceAction.IncludeFuture(),
+		Enqueued:      serviceAction.Enqueued(),
+	}
+	for _, action := range actions {
+		result.Units = append(result.Units, actionParams(action))
+	}
+	for _, actionResult := range actionResults {
+		result.Units = append(result.Units, actionResultParams(actionResult))
+	}
+	return result, nil
+}
+
+// actionParams converts a state Action that has not finished yet into
+// the API form of its result.
+func actionParams(action *state.Action) params.ActionResult {
+	return params.ActionResult{
+		ActionId: action.Id(),
+		Receiver: names.UnitTag(action.UnitName()),
+		Name:     action.Name(),
+		Params:   action.Payload(),
+		Status:   string(action.Status()),
+		Enqueued: action.Enqueued(),
+		Started:  action.Started(),
+	}
+}
+
+// actionResultParams converts a state ActionResult into its API form.
+func actionResultParams(actionResult *state.ActionResult) params.ActionResult {
+	return params.ActionResult{
+		ActionId:  actionResult.ActionId(),
+		Receiver:  names.UnitTag(actionResult.UnitName()),
+		Name:      actionResult.ActionName(),
+		Params:    actionResult.Payload(),
+		Status:    string(actionResult.Status()),
+		Results:   actionResult.Results(),
+		Output:    actionResult.Output(),
+		Message:   actionResult.Message(),
+		Enqueued:  actionResult.Enqueued(),
+		Started:   actionResult.Started(),
+		Completed: actionResult.Completed(),
+	}
+}
@@ -0,0 +1,451 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actions_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/actions"
+	"github.com/juju/juju/state/apiserver/common"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+)
+
+type actionsSuite struct {
+	jujutesting.JujuConnSuite
+
+	actions    *actions.ActionsAPI
+	authorizer apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+
+	wordpress *state.Service
+	unit0     *state.Unit
+	unit1     *state.Unit
+}
+
+var _ = gc.Suite(&actionsSuite{})
+
+func (s *actionsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      "user-admin",
+		LoggedIn: true,
+		Client:   true,
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	var err error
+	s.actions, err = actions.NewActionsAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.IsNil)
+
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.unit0, err = s.wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	s.unit1, err = s.wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *actionsSuite) TestNewActionsAPIRefusesNonClient(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Client = false
+	endPoint, err := actions.NewActionsAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *actionsSuite) TestReadOnlyUserCannotQueueOrCancel(c *gc.C) {
+	id, err := s.unit0.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	user := s.AddUser(c, "bob")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = user.Tag()
+
+	// A user without any role may not use the facade at all.
+	_, err = actions.NewActionsAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+
+	err = user.GrantRole(env.UUID(), state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	api, err := actions.NewActionsAPI(s.State, s.resources, anAuthorizer)
+	c.Assert(err, gc.IsNil)
+
+	listed, err := api.List(params.Entities{Entities: []params.Entity{{Tag: "unit-wordpress-0"}}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(listed.Results[0].Error, gc.IsNil)
+	_, err = api.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "unit-wordpress-0", Name: "snapshot"},
+	}})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	_, err = api.Cancel(params.ActionIds{Ids: []string{id}})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+
+	// The action is still queued.
+	_, err = s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	_, err = api.Cancel(params.ActionIds{Ids: []string{id}})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *actionsSuite) TestEnqueueUnit(c *gc.C) {
+	payload := map[string]interface{}{"outfile": "foo.tar"}
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "unit-wordpress-0", Name: "snapshot", Params: payload},
+		{Receiver: "unit-wordpress-9", Name: "snapshot"},
+		{Receiver: "machine-0", Name: "snapshot"},
+		{Receiver: "unit-wordpress-0"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Actions, gc.HasLen, 1)
+	queued := result.Results[0].Actions[0]
+	c.Assert(state.IsAction(queued.Id), jc.IsTrue)
+	c.Assert(queued, gc.DeepEquals, params.Action{
+		Id:       queued.Id,
+		Receiver: "unit-wordpress-0",
+		Name:     "snapshot",
+		Params:   payload,
+	})
+	pending, err := s.unit0.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Id(), gc.Equals, queued.Id)
+
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `unit "wordpress/9" not found`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid action receiver`)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, "no action name given")
+}
+
+func (s *actionsSuite) TestEnqueueService(c *gc.C) {
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "service-wordpress", Name: "snapshot"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(state.IsServiceAction(result.Results[0].ServiceActionId), jc.IsTrue)
+	c.Assert(result.Results[0].Actions, gc.HasLen, 2)
+
+	var receivers []string
+	for _, queued := range result.Results[0].Actions {
+		receivers = append(receivers, queued.Receiver)
+	}
+	c.Assert(receivers, jc.SameContents, []string{"unit-wordpress-0", "unit-wordpress-1"})
+}
+
+func (s *actionsSuite) TestEnqueueServiceWithoutUnits(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "service-mysql", Name: "backup"},
+		{Receiver: "service-mysql", Name: "backup", IncludeFuture: true},
+		{Receiver: "service-foo", Name: "backup"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `service "mysql" has no units`)
+
+	// An action that includes future units may be queued on a service
+	// that has no units yet.
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Actions, gc.HasLen, 0)
+	serviceActionId := result.Results[1].ServiceActionId
+	c.Assert(state.IsServiceAction(serviceActionId), jc.IsTrue)
+
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `service "foo" not found`)
+
+	service, err := s.State.Service("mysql")
+	c.Assert(err, gc.IsNil)
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	pending, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].ServiceActionId(), gc.Equals, serviceActionId)
+}
+
+func (s *actionsSuite) TestServiceResults(c *gc.C) {
+	enqueued, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "service-wordpress", Name: "snapshot"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(enqueued.Results[0].Error, gc.IsNil)
+	serviceActionId := enqueued.Results[0].ServiceActionId
+
+	pending, err := s.unit1.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	err = pending[0].Finish(state.ActionCompleted, map[string]interface{}{"size": "10"}, "")
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.actions.ServiceResults(params.ActionIds{Ids: []string{
+		serviceActionId,
+		"s#wordpress#a#99",
+		"u#wordpress/0#a#0",
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	progress := result.Results[0].Result
+	c.Assert(progress.Id, gc.Equals, serviceActionId)
+	c.Assert(progress.Receiver, gc.Equals, "service-wordpress")
+	c.Assert(progress.Name, gc.Equals, "snapshot")
+	c.Assert(progress.IncludeFuture, jc.IsFalse)
+	c.Assert(progress.Enqueued.IsZero(), jc.IsFalse)
+	c.Assert(progress.Units, gc.HasLen, 2)
+	statuses := make(map[string]string)
+	for _, unit := range progress.Units {
+		statuses[unit.Receiver] = unit.Status
+	}
+	c.Assert(statuses, gc.DeepEquals, map[string]string{
+		"unit-wordpress-0": "pending",
+		"unit-wordpress-1": "complete",
+	})
+
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `service action "s#wordpress#a#99" not found`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `service action "u#wordpress/0#a#0" not found`)
+}
+
+func (s *actionsSuite) TestList(c *gc.C) {
+	payload := map[string]interface{}{"outfile": "foo.tar"}
+	pendingId, err := s.unit0.AddAction("snapshot", payload)
+	c.Assert(err, gc.IsNil)
+	doneId, err := s.unit0.AddAction("backup", payload)
+	c.Assert(err, gc.IsNil)
+	done, err := s.State.Action(doneId)
+	c.Assert(err, gc.IsNil)
+	err = done.Complete("all done")
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.actions.List(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-wordpress-9"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Receiver, gc.Equals, "unit-wordpress-0")
+	c.Assert(result.Results[0].Pending, gc.HasLen, 1)
+	c.Assert(result.Results[0].Pending[0].Id, gc.Equals, pendingId)
+	c.Assert(result.Results[0].Completed, gc.HasLen, 1)
+	completed := clearTimestamps(c, result.Results[0].Completed[0], false)
+	c.Assert(completed, gc.DeepEquals, params.ActionResult{
+		ActionId: doneId,
+		Receiver: "unit-wordpress-0",
+		Name:     "backup",
+		Params:   payload,
+		Status:   "complete",
+		Output:   "all done",
+	})
+
+	c.Assert(result.Results[1].Receiver, gc.Equals, "unit-wordpress-9")
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `unit "wordpress/9" not found`)
+}
+
+func (s *actionsSuite) TestListService(c *gc.C) {
+	result, err := s.actions.List(params.Entities{Entities: []params.Entity{
+		{Tag: "service-wordpress"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	var receivers []string
+	for _, r := range result.Results {
+		c.Assert(r.Error, gc.IsNil)
+		receivers = append(receivers, r.Receiver)
+	}
+	c.Assert(receivers, jc.SameContents, []string{"unit-wordpress-0", "unit-wordpress-1"})
+}
+
+func (s *actionsSuite) TestResults(c *gc.C) {
+	payload := map[string]interface{}{"outfile": "foo.tar"}
+	pendingId, err := s.unit0.AddAction("snapshot", payload)
+	c.Assert(err, gc.IsNil)
+	failedId, err := s.unit1.AddAction("backup", payload)
+	c.Assert(err, gc.IsNil)
+	failed, err := s.State.Action(failedId)
+	c.Assert(err, gc.IsNil)
+	err = failed.Begin()
+	c.Assert(err, gc.IsNil)
+	err = failed.Finish(state.ActionFailed, map[string]interface{}{"free": "0"}, "disk full")
+	c.Assert(err, gc.IsNil)
+	runningId, err := s.unit1.AddAction("snapshot", payload)
+	c.Assert(err, gc.IsNil)
+	running, err := s.State.Action(runningId)
+	c.Assert(err, gc.IsNil)
+	err = running.Begin()
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.actions.Results(params.ActionIds{Ids: []string{
+		pendingId,
+		failedId,
+		runningId,
+		"u#wordpress/0#a#99",
+		"foo",
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 5)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	pending := clearTimestamps(c, *result.Results[0].Result, false)
+	c.Assert(pending, gc.DeepEquals, params.ActionResult{
+		ActionId: pendingId,
+		Receiver: "unit-wordpress-0",
+		Name:     "snapshot",
+		Params:   payload,
+		Status:   "pending",
+	})
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	failedResult := clearTimestamps(c, *result.Results[1].Result, true)
+	c.Assert(failedResult, gc.DeepEquals, params.ActionResult{
+		ActionId: failedId,
+		Receiver: "unit-wordpress-1",
+		Name:     "backup",
+		Params:   payload,
+		Status:   "fail",
+		Results:  map[string]interface{}{"free": "0"},
+		Message:  "disk full",
+	})
+	c.Assert(result.Results[2].Error, gc.IsNil)
+	c.Assert(result.Results[2].Result.Status, gc.Equals, "running")
+	c.Assert(result.Results[2].Result.Started.IsZero(), jc.IsFalse)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `action "u#wordpress/0#a#99" not found`)
+	c.Assert(result.Results[4].Error, gc.ErrorMatches, `action "foo" not found`)
+}
+
+// clearTimestamps checks that the timestamps of the given result are set
+// as expected for its status, and returns it with them cleared so that
+// it can be compared with an expected value.
+func clearTimestamps(c *gc.C, result params.ActionResult, started bool) params.ActionResult {
+	c.Check(result.Enqueued.IsZero(), jc.IsFalse)
+	c.Check(result.Started.IsZero(), gc.Equals, !started)
+	c.Check(result.Completed.IsZero(), gc.Equals, result.Status == "pending")
+	result.Enqueued = time.Time{}
+	result.Started = time.Time{}
+	result.Completed = time.Time{}
+	return result
+}
+
+func (s *actionsSuite) TestEnqueueValidatesParams(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	_, err := dummy.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "unit-dummy-0", Name: "snapshot"},
+		{Receiver: "unit-dummy-0", Name: "snapshot", Params: map[string]interface{}{"outfile": 5}},
+		{Receiver: "unit-dummy-0", Name: "backup"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Actions, gc.HasLen, 1)
+	pending, err := s.State.Action(result.Results[0].Actions[0].Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending.Payload(), gc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "invalid action parameters: outfile: .*string.*")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `action "backup" not defined by charm .*`)
+}
+
+func (s *actionsSuite) TestEnqueueWithTimeout(c *gc.C) {
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "unit-wordpress-0", Name: "snapshot", Timeout: time.Minute},
+		{Receiver: "service-wordpress", Name: "snapshot", Timeout: time.Hour},
+		{Receiver: "unit-wordpress-0", Name: "snapshot", Timeout: -time.Minute},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	action, err := s.State.Action(result.Results[0].Actions[0].Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Minute)
+
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[1].Actions, gc.HasLen, 2)
+	for _, queued := range result.Results[1].Actions {
+		c.Assert(queued.Timeout, gc.Equals, time.Hour)
+	}
+
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, "invalid action timeout -1m0s")
+}
+
+func (s *actionsSuite) TestCancel(c *gc.C) {
+	pendingId, err := s.unit0.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	runningId, err := s.unit1.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	running, err := s.State.Action(runningId)
+	c.Assert(err, gc.IsNil)
+	err = running.Begin()
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.actions.Cancel(params.ActionIds{Ids: []string{
+		pendingId,
+		runningId,
+		"u#wordpress/0#a#99",
+		"foo",
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 4)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `action "u#wordpress/0#a#99" not found`)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, `action "foo" not found`)
+
+	// The pending action has been removed from the queue.
+	results, err := s.actions.Results(params.ActionIds{Ids: []string{pendingId}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Status, gc.Equals, "cancelled")
+
+	// The running action is left for the unit to stop.
+	running, err = s.State.Action(runningId)
+	c.Assert(err, gc.IsNil)
+	c.Assert(running.Cancelling(), jc.IsTrue)
+}
+
+func (s *actionsSuite) TestCancelServiceAction(c *gc.C) {
+	result, err := s.actions.Enqueue(params.Actions{Actions: []params.Action{
+		{Receiver: "service-wordpress", Name: "snapshot", IncludeFuture: true},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	serviceActionId := result.Results[0].ServiceActionId
+
+	cancelled, err := s.actions.Cancel(params.ActionIds{Ids: []string{
+		serviceActionId,
+		"s#wordpress#a#99",
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(cancelled.Results, gc.HasLen, 2)
+	c.Assert(cancelled.Results[0].Error, gc.IsNil)
+	c.Assert(cancelled.Results[1].Error, gc.ErrorMatches, `service action "s#wordpress#a#99" not found`)
+
+	for _, unit := range []*state.Unit{s.unit0, s.unit1} {
+		pending, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(pending, gc.HasLen, 0)
+	}
+
+	// Units added later do not get the cancelled action.
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, gc.IsNil)
+	pending, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
@@ -90,6 +90,13 @@ func (a *srvAdmin) Login(c params.Creds) (params.LoginResult, error) {
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag())
 	}
+	if user, ok := entity.(*state.User); ok {
+		// Failing to record the connection is not a
+		// reason to refuse the login.
+		if err := user.UpdateLastConnection(); err != nil {
+			logger.Warningf("cannot record connection of user %q: %v", user.Name(), err)
+		}
+	}
 	// We have authenticated the user; now choose an appropriate API
 	// to serve to them.
 	// TODO: consider switching the new root based on who is logging in
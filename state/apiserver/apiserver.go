@@ -14,6 +14,7 @@ import (
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/bmizerany/pat"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
 	"launchpad.net/tomb"
@@ -26,6 +27,12 @@ import (
 
 var logger = loggo.GetLogger("juju.state.apiserver")
 
+// ErrRestored is the error the server stops with once the state it
+// serves has been restored from a backup. The machine agent running
+// the server treats it as fatal, so that it restarts and picks up the
+// restored state and agent configuration.
+var ErrRestored = errors.New("state restored from backup")
+
 // loginRateLimit defines how many concurrent Login requests we will
 // accept
 const loginRateLimit = 10
@@ -93,21 +100,37 @@ func (srv *Server) Wait() error {
 	return srv.tomb.Wait()
 }
 
+// restored stops the server with ErrRestored. Requests in progress,
+// including the one that restored the state, still complete and send
+// their replies.
+func (srv *Server) restored() {
+	srv.tomb.Kill(ErrRestored)
+}
+
+// requestNotifier logs the requests made on an API connection, and
+// records the calls made by users that may change the environment in
+// the audit log.
 type requestNotifier struct {
 	id    int64
 	start time.Time
+	st    *state.State
 
 	mu   sync.Mutex
 	tag_ string
+	// auditArgs holds the arguments of the audited requests being
+	// served, by request id.
+	auditArgs map[uint64]interface{}
 }
 
 var globalCounter int64
 
-func newRequestNotifier() *requestNotifier {
+func newRequestNotifier(st *state.State) *requestNotifier {
 	return &requestNotifier{
-		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
-		start: time.Now(),
+		id:        atomic.AddInt64(&globalCounter, 1),
+		tag_:      "<unknown>",
+		start:     time.Now(),
+		st:        st,
+		auditArgs: make(map[uint64]interface{}),
 	}
 }
 
@@ -125,7 +148,15 @@ func (n *requestNotifier) tag() (tag string) {
 }
 
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
-	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
+	if isAudited(n.tag(), hdr.Request) {
+		n.mu.Lock()
+		n.auditArgs[hdr.RequestId] = body
+		n.mu.Unlock()
+	}
+	if logger.EffectiveLogLevel() > loggo.DEBUG {
+		return
+	}
+	if isQuiet(hdr.Request) {
 		return
 	}
 	// TODO(rog) 2013-10-11 remove secrets from some requests.
@@ -133,12 +164,54 @@ func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
 }
 
 func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
-	if req.Type == "Pinger" && req.Action == "Ping" {
+	n.mu.Lock()
+	args, audited := n.auditArgs[hdr.RequestId]
+	delete(n.auditArgs, hdr.RequestId)
+	n.mu.Unlock()
+	if audited {
+		n.audit(req, hdr, args)
+	}
+	if logger.EffectiveLogLevel() > loggo.DEBUG {
+		return
+	}
+	if isQuiet(req) {
 		return
 	}
 	logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, body), req.Type, req.Id, req.Action)
 }
 
+// isQuiet returns whether the given request is made too often to be
+// logged. Logging the requests that send agent logs would also make
+// every message logged by the server send another one.
+func isQuiet(req rpc.Request) bool {
+	switch req.Type + "." + req.Action {
+	case "Pinger.Ping", "LogSink.WriteLogs":
+		return true
+	}
+	return false
+}
+
+// audit records a call answered with the given reply header in the
+// audit log. Failing to record it does not fail the call.
+func (n *requestNotifier) audit(req rpc.Request, hdr *rpc.Header, args interface{}) {
+	encoded, entities, err := auditArgs(args)
+	if err != nil {
+		logger.Errorf("cannot encode arguments of %s.%s for the audit log: %v", req.Type, req.Action, err)
+	}
+	entry := state.AuditEntry{
+		Time:     time.Now(),
+		User:     n.tag(),
+		Facade:   req.Type,
+		Method:   req.Action,
+		Args:     encoded,
+		Entities: entities,
+		Error:    hdr.Error,
+	}
+	if err := n.st.AddAuditEntry(entry); err != nil {
+		logger.Errorf("cannot record %s.%s call in the audit log: %v", req.Type, req.Action, err)
+	}
+}
+
 func (n *requestNotifier) join(req *http.Request) {
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
@@ -199,6 +272,9 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/tools",
 		&toolsHandler{httpHandler{state: srv.state}},
 	)
+	handleAll(mux, "/environment/:envuuid/backups",
+		&backupsHandler{httpHandler{state: srv.state}},
+	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	// For backwards compatibility we register all the old paths
 	handleAll(mux, "/log",
@@ -214,13 +290,16 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/tools",
 		&toolsHandler{httpHandler{state: srv.state}},
 	)
+	handleAll(mux, "/backups",
+		&backupsHandler{httpHandler{state: srv.state}},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
 }
 
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
-	reqNotifier := newRequestNotifier()
+	reqNotifier := newRequestNotifier(srv.state)
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
 	wsServer := websocket.Server{
@@ -278,13 +357,7 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
-	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
-	}
-	conn := rpc.NewConn(codec, notifier)
+	conn := rpc.NewConn(codec, reqNotifier)
 	err := srv.validateEnvironUUID(envUUID)
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
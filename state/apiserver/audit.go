@@ -0,0 +1,160 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/rpc"
+)
+
+// readOnlyCalls holds the API methods available to users that cannot
+// change the environment. Calls to them are not audited.
+var readOnlyCalls = set.NewStrings(
+	"Admin.Login",
+	"Pinger.Ping",
+	"AllWatcher.Next",
+	"AllWatcher.Stop",
+	"Client.AddServiceUnitsDryRun",
+	"Client.APIHostPorts",
+	"Client.AgentVersion",
+	"Client.CharmInfo",
+	"Client.EnvironmentGet",
+	"Client.EnvironmentInfo",
+	"Client.ExportBundle",
+	"Client.FindTools",
+	"Client.FullStatus",
+	"Client.GetAnnotations",
+	"Client.GetEnvironmentConstraints",
+	"Client.GetServiceConstraints",
+	"Client.PrivateAddress",
+	"Client.PublicAddress",
+	"Client.ResolveCharms",
+	"Client.ServiceCharmRelations",
+	"Client.ServiceDeployDryRun",
+	"Client.ServiceGet",
+	"Client.ServiceGetCharmURL",
+	"Client.ServiceUpdateDryRun",
+	"Client.Status",
+	"Client.WatchAll",
+	"KeyManager.ListKeys",
+	"UserManager.UserInfo",
+	"UserManager.ListUsers",
+	"Backups.Info",
+	"Backups.List",
+	"Actions.List",
+	"Actions.Results",
+	"Actions.ServiceResults",
+	"AuditLog.Entries",
+)
+
+// isAudited returns whether the given request, made by the entity with
+// the given tag, should be recorded in the audit log.
+func isAudited(tag string, req rpc.Request) bool {
+	if kind, err := names.TagKind(tag); err != nil || kind != names.UserTagKind {
+		return false
+	}
+	return !readOnlyCalls.Contains(req.Type + "." + req.Action)
+}
+
+// secretPattern matches the names of the arguments holding secrets.
+var secretPattern = regexp.MustCompile(`(?i)password|secret|private|token|credential|certificate|access-key`)
+
+// redacted replaces the values of secret arguments in the audit log.
+const redacted = "(redacted)"
+
+// entityPattern matches the names of the arguments that may hold the
+// names of entities.
+var entityPattern = regexp.MustCompile(`(?i)(tag|name|id|entity|endpoint)s?$`)
+
+// auditArgs returns the JSON encoding of the arguments of an audited
+// call, with the values of any secrets replaced, along with the names
+// of the entities the arguments mention.
+func auditArgs(args interface{}) (string, []string, error) {
+	if args == nil {
+		return "", nil, nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", nil, err
+	}
+	entities := set.NewStrings()
+	findEntities(entities, "", decoded)
+	data, err = json.Marshal(removeSecrets(decoded))
+	if err != nil {
+		return "", nil, err
+	}
+	return string(data), entities.SortedValues(), nil
+}
+
+// findEntities adds to entities the names of the entities held in the
+// decoded JSON value v, found under the given argument name.
+func findEntities(entities set.Strings, name string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			findEntities(entities, key, value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			findEntities(entities, name, value)
+		}
+	case string:
+		if entityPattern.MatchString(name) {
+			addEntity(entities, v)
+		}
+	}
+}
+
+// addEntity adds to entities the entity named by s, if any. Tags are
+// added along with the ids they hold, and relation endpoints add their
+// service, so that calls can be found however the entity is given.
+func addEntity(entities set.Strings, s string) {
+	if kind, err := names.TagKind(s); err == nil {
+		if _, id, err := names.ParseTag(s, kind); err == nil {
+			entities.Add(s)
+			entities.Add(id)
+			return
+		}
+	}
+	switch {
+	case names.IsUnit(s), names.IsMachine(s), names.IsService(s):
+		entities.Add(s)
+	case strings.Contains(s, ":"):
+		if service := s[:strings.Index(s, ":")]; names.IsService(service) {
+			entities.Add(service)
+		}
+	}
+}
+
+// removeSecrets replaces the values held under secret names anywhere
+// in the decoded JSON value v, and returns the result.
+func removeSecrets(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if secretPattern.MatchString(key) {
+				if value != nil && value != "" {
+					v[key] = redacted
+				}
+			} else {
+				v[key] = removeSecrets(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = removeSecrets(value)
+		}
+	}
+	return v
+}
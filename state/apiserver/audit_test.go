@@ -0,0 +1,114 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/api/usermanager"
+	"github.com/juju/juju/state/apiserver"
+)
+
+type auditSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestAuditRecordsChanges(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	client := s.APIState.Client()
+	before := time.Now().Add(-time.Second)
+
+	_, err := client.Status(nil)
+	c.Assert(err, gc.IsNil)
+	err = client.ServiceExpose("wordpress")
+	c.Assert(err, gc.IsNil)
+	err = client.ServiceExpose("unknown")
+	c.Assert(err, gc.NotNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	for _, entry := range entries {
+		c.Check(entry.Time.After(before), jc.IsTrue)
+		c.Check(entry.User, gc.Equals, "user-admin")
+		c.Check(entry.Facade, gc.Equals, "Client")
+		c.Check(entry.Method, gc.Equals, "ServiceExpose")
+	}
+	c.Assert(entries[0].Args, gc.Equals, `{"ServiceName":"wordpress"}`)
+	c.Assert(entries[0].Entities, gc.DeepEquals, []string{"wordpress"})
+	c.Assert(entries[0].Error, gc.Equals, "")
+	c.Assert(entries[1].Args, gc.Equals, `{"ServiceName":"unknown"}`)
+	c.Assert(entries[1].Entities, gc.DeepEquals, []string{"unknown"})
+	c.Assert(entries[1].Error, gc.Equals, `service "unknown" not found`)
+
+	entries, err = s.State.AuditEntries(state.AuditFilter{Entity: "wordpress"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 1)
+}
+
+func (s *auditSuite) TestAuditArgsEntities(c *gc.C) {
+	for i, test := range []struct {
+		args     interface{}
+		entities []string
+	}{{
+		args:     params.ServiceExpose{ServiceName: "wordpress"},
+		entities: []string{"wordpress"},
+	}, {
+		args:     params.DestroyServiceUnits{UnitNames: []string{"wordpress/0", "wordpress/1"}},
+		entities: []string{"wordpress/0", "wordpress/1"},
+	}, {
+		args:     params.DestroyMachines{MachineNames: []string{"0", "1/lxc/0"}},
+		entities: []string{"0", "1/lxc/0"},
+	}, {
+		args:     params.Entities{Entities: []params.Entity{{Tag: "unit-mysql-0"}}},
+		entities: []string{"mysql/0", "unit-mysql-0"},
+	}, {
+		args:     params.AddRelation{Endpoints: []string{"wordpress:db", "mysql"}},
+		entities: []string{"mysql", "wordpress"},
+	}, {
+		// Values under other names are not taken for entities.
+		args:     map[string]string{"Series": "quantal", "ConfigYAML": "wordpress"},
+		entities: []string{},
+	}} {
+		c.Logf("test %d: %#v", i, test.args)
+		_, entities, err := apiserver.AuditArgs(test.args)
+		c.Assert(err, gc.IsNil)
+		c.Check(entities, jc.DeepEquals, test.entities)
+	}
+}
+
+func (s *auditSuite) TestAuditRemovesSecrets(c *gc.C) {
+	client := usermanager.NewClient(s.APIState)
+	err := client.AddUser("bob", "Bob Brown", "s3cr3t")
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Facade, gc.Equals, "UserManager")
+	c.Assert(entries[0].Method, gc.Equals, "AddUser")
+	c.Assert(entries[0].Args, gc.Equals,
+		`{"Changes":[{"DisplayName":"Bob Brown","Password":"(redacted)","Tag":"","Username":"bob"}]}`)
+}
+
+func (s *auditSuite) TestAuditIgnoresAgents(c *gc.C) {
+	st, m := s.OpenAPIAsNewMachine(c)
+	defer st.Close()
+	machine, err := st.Machiner().Machine(m.Tag())
+	c.Assert(err, gc.IsNil)
+	err = machine.SetStatus(params.StatusStarted, "", nil)
+	c.Assert(err, gc.IsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
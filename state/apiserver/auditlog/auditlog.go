@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// AuditLog defines the methods on the auditlog API end point.
+type AuditLog interface {
+	Entries(args params.AuditLogFilter) (params.AuditLogEntries, error)
+}
+
+// AuditLogAPI implements the AuditLog interface and is the concrete
+// implementation of the api end point.
+type AuditLogAPI struct {
+	state *state.State
+}
+
+var _ AuditLog = (*AuditLogAPI)(nil)
+
+// maxEntries is the most entries returned by a single Entries call.
+// Callers read more by asking for the entries after the last one they
+// were given.
+var maxEntries = 1000
+
+// NewAuditLogAPI creates a new server-side auditlog API end point.
+// Only environment administrators may read the audit log.
+func NewAuditLogAPI(st *state.State, authorizer common.Authorizer) (*AuditLogAPI, error) {
+	if err := common.RequireUserRole(st, authorizer, state.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return &AuditLogAPI{state: st}, nil
+}
+
+// Entries returns the calls recorded in the audit log that are
+// selected by the filter, oldest first. No more than maxEntries calls
+// are returned.
+func (api *AuditLogAPI) Entries(args params.AuditLogFilter) (params.AuditLogEntries, error) {
+	limit := args.Limit
+	if limit <= 0 || limit > maxEntries {
+		limit = maxEntries
+	}
+	entries, err := api.state.AuditEntries(state.AuditFilter{
+		User:    args.User,
+		After:   args.After,
+		Before:  args.Before,
+		Entity:  args.Entity,
+		AfterId: args.AfterId,
+		Limit:   limit,
+	})
+	if err != nil {
+		return params.AuditLogEntries{}, errors.Trace(err)
+	}
+	result := params.AuditLogEntries{
+		Entries: make([]params.AuditLogEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		result.Entries[i] = params.AuditLogEntry{
+			Id:       entry.Id,
+			Time:     entry.Time,
+			User:     entry.User,
+			Facade:   entry.Facade,
+			Method:   entry.Method,
+			Args:     entry.Args,
+			Entities: entry.Entities,
+			Error:    entry.Error,
+		}
+	}
+	return result, nil
+}
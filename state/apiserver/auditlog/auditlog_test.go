@@ -0,0 +1,142 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/auditlog"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *auditlog.AuditLogAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var auditTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      "user-admin",
+		LoggedIn: true,
+		Client:   true,
+	}
+	var err error
+	s.api, err = auditlog.NewAuditLogAPI(s.State, s.authorizer)
+	c.Assert(err, gc.IsNil)
+
+	for i, entry := range []state.AuditEntry{{
+		User:     "user-admin",
+		Facade:   "Client",
+		Method:   "ServiceExpose",
+		Args:     `{"ServiceName":"wordpress"}`,
+		Entities: []string{"wordpress"},
+	}, {
+		User:     "user-bob",
+		Facade:   "Client",
+		Method:   "ServiceExpose",
+		Args:     `{"ServiceName":"mysql"}`,
+		Entities: []string{"mysql"},
+		Error:    `service "mysql" not found`,
+	}} {
+		entry.Time = auditTime.Add(time.Duration(i) * time.Hour)
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *auditLogSuite) TestNewAuditLogAPIRefusesNonClient(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Client = false
+	endPoint, err := auditlog.NewAuditLogAPI(s.State, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *auditLogSuite) TestNewAuditLogAPIRequiresAdmin(c *gc.C) {
+	user := s.AddUser(c, "bob")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = user.Tag()
+	endPoint, err := auditlog.NewAuditLogAPI(s.State, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	err = user.GrantRole(env.UUID(), state.RoleAdmin)
+	c.Assert(err, gc.IsNil)
+	_, err = auditlog.NewAuditLogAPI(s.State, anAuthorizer)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *auditLogSuite) TestEntries(c *gc.C) {
+	result, err := s.api.Entries(params.AuditLogFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 2)
+	entry := result.Entries[1]
+	c.Assert(entry.Time.Equal(auditTime.Add(time.Hour)), gc.Equals, true)
+	c.Assert(entry.Id, gc.Not(gc.Equals), "")
+	entry.Time, entry.Id = time.Time{}, ""
+	c.Assert(entry, gc.DeepEquals, params.AuditLogEntry{
+		User:     "user-bob",
+		Facade:   "Client",
+		Method:   "ServiceExpose",
+		Args:     `{"ServiceName":"mysql"}`,
+		Entities: []string{"mysql"},
+		Error:    `service "mysql" not found`,
+	})
+}
+
+func (s *auditLogSuite) TestEntriesPaging(c *gc.C) {
+	result, err := s.api.Entries(params.AuditLogFilter{Limit: 1})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Assert(result.Entries[0].User, gc.Equals, "user-admin")
+
+	result, err = s.api.Entries(params.AuditLogFilter{AfterId: result.Entries[0].Id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Assert(result.Entries[0].User, gc.Equals, "user-bob")
+
+	result, err = s.api.Entries(params.AuditLogFilter{AfterId: result.Entries[0].Id})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Entries, gc.HasLen, 0)
+}
+
+func (s *auditLogSuite) TestEntriesLimitedByDefault(c *gc.C) {
+	s.PatchValue(auditlog.MaxEntries, 1)
+	for _, limit := range []int{0, 2} {
+		result, err := s.api.Entries(params.AuditLogFilter{Limit: limit})
+		c.Assert(err, gc.IsNil)
+		c.Assert(result.Entries, gc.HasLen, 1)
+	}
+}
+
+func (s *auditLogSuite) TestEntriesFilter(c *gc.C) {
+	for i, filter := range []params.AuditLogFilter{
+		{User: "user-bob"},
+		{After: auditTime.Add(time.Minute)},
+		{Entity: "mysql"},
+	} {
+		c.Logf("test %d: %#v", i, filter)
+		result, err := s.api.Entries(filter)
+		c.Assert(err, gc.IsNil)
+		c.Assert(result.Entries, gc.HasLen, 1)
+		c.Assert(result.Entries[0].User, gc.Equals, "user-bob")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	apibackups "github.com/juju/juju/state/apiserver/backups"
+	"github.com/juju/juju/state/apiserver/common"
+	"github.com/juju/juju/state/backups"
+)
+
+// newBackups returns the backups implementation used to serve backup
+// archives. It is a variable so that tests can change it.
+var newBackups = backups.NewBackups
+
+// backupsHandler handles the download and upload of backup archives
+// through HTTPS in the API server.
+type backupsHandler struct {
+	httpHandler
+}
+
+func (h *backupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authenticate(r)
+	if err != nil {
+		h.authError(w, h)
+		return
+	}
+	if err := h.validateEnvironUUID(r); err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	// Backup archives hold the credentials of every agent, so only
+	// environment administrators may download or upload them.
+	if !h.requireUserRole(w, h, user, state.RoleAdmin) {
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		// Download a backup archive.
+		// Requires an "id" query identifying the backup.
+		h.serveAudited(w, r, user, "Backups", "Download", h.processGet)
+	case "POST":
+		// Upload a backup archive taken elsewhere, such as that of a
+		// state server being restored. An optional "notes" query is
+		// recorded with it.
+		h.serveAudited(w, r, user, "Backups", "Upload", h.processPost)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// processGet sends the archive of the backup identified in the request.
+// The archive's checksum is sent in the Digest header.
+func (h *backupsHandler) processGet(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.sendError(w, http.StatusBadRequest, "expected id query argument")
+		return
+	}
+	meta, archive, err := newBackups(h.state).Get(id)
+	if errors.IsNotFound(err) {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer archive.Close()
+	w.Header().Set("Content-Type", "application/x-tar-gz")
+	w.Header().Set("Content-Length", strconv.FormatInt(meta.Size, 10))
+	w.Header().Set("Digest", "SHA="+meta.Checksum)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, archive); err != nil {
+		logger.Errorf("cannot send archive for backup %q: %v", id, err)
+	}
+}
+
+// processPost stores the backup archive held in the request body, and
+// sends its metadata.
+func (h *backupsHandler) processPost(w http.ResponseWriter, r *http.Request) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/x-tar-gz" {
+		h.sendError(w, http.StatusBadRequest, fmt.Sprintf("expected Content-Type: application/x-tar-gz, got: %v", contentType))
+		return
+	}
+	meta, err := newBackups(h.state).Add(r.Body, r.URL.Query().Get("notes"))
+	if err != nil {
+		h.sendError(w, http.StatusInternalServerError, fmt.Sprintf("cannot upload backup: %v", err))
+		return
+	}
+	h.sendJSON(w, http.StatusOK, apibackups.MetadataResult(meta))
+}
+
+// sendJSON sends a JSON-encoded response to the client.
+func (h *backupsHandler) sendJSON(w http.ResponseWriter, statusCode int, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	w.Write(body)
+	return nil
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *backupsHandler) sendError(w http.ResponseWriter, statusCode int, message string) error {
+	return h.sendJSON(w, statusCode, &params.ErrorResult{
+		Error: common.ServerError(fmt.Errorf(message)),
+	})
+}
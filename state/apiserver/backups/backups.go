@@ -0,0 +1,130 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+	"github.com/juju/juju/state/backups"
+)
+
+// Backups defines the methods on the backups API end point.
+type Backups interface {
+	Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error)
+	Info(args params.BackupsInfoArgs) (params.BackupsMetadataResult, error)
+	List() (params.BackupsListResult, error)
+	Remove(args params.BackupsRemoveArgs) error
+	Restore(args params.BackupsRestoreArgs) error
+}
+
+// BackupsAPI implements the Backups interface and is the concrete
+// implementation of the api end point.
+type BackupsAPI struct {
+	state    *state.State
+	paths    backups.Paths
+	backups  backups.Backups
+	restored func()
+}
+
+var _ Backups = (*BackupsAPI)(nil)
+
+// newBackups returns the backups implementation used by the API. It is
+// a variable so that tests can change it.
+var newBackups = backups.NewBackups
+
+// NewBackupsAPI creates a new server-side backups API end point. The
+// paths locate the state server's files to include in backups, and
+// restored is called once a backup has been restored, to restart the
+// state server's machine agent. Backups hold the credentials of every
+// agent, so only administrators of the environment may manage them.
+func NewBackupsAPI(
+	st *state.State,
+	authorizer common.Authorizer,
+	paths backups.Paths,
+	restored func(),
+) (*BackupsAPI, error) {
+	if err := common.RequireUserRole(st, authorizer, state.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return &BackupsAPI{
+		state:    st,
+		paths:    paths,
+		backups:  newBackups(st),
+		restored: restored,
+	}, nil
+}
+
+// Create takes a new backup of the state server, stores it, and
+// returns its metadata.
+func (b *BackupsAPI) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	meta, err := b.backups.Create(b.paths, args.Notes)
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	return MetadataResult(meta), nil
+}
+
+// Info returns the metadata of the identified backup.
+func (b *BackupsAPI) Info(args params.BackupsInfoArgs) (params.BackupsMetadataResult, error) {
+	meta, err := b.state.BackupMetadata(args.ID)
+	if err != nil {
+		return params.BackupsMetadataResult{}, errors.Trace(err)
+	}
+	return MetadataResult(meta), nil
+}
+
+// List returns the metadata of every backup, oldest first.
+func (b *BackupsAPI) List() (params.BackupsListResult, error) {
+	metas, err := b.backups.List()
+	if err != nil {
+		return params.BackupsListResult{}, errors.Trace(err)
+	}
+	result := params.BackupsListResult{
+		List: make([]params.BackupsMetadataResult, len(metas)),
+	}
+	for i, meta := range metas {
+		result.List[i] = MetadataResult(meta)
+	}
+	return result, nil
+}
+
+// Remove removes the identified backup and its archive.
+func (b *BackupsAPI) Remove(args params.BackupsRemoveArgs) error {
+	return errors.Trace(b.backups.Remove(args.ID))
+}
+
+// Restore replaces the state of the state server with that held in the
+// identified backup. The restored state holds the API addresses of this
+// state server, from which the agents of the environment learn where to
+// connect. Once the reply has been sent, the state server's machine
+// agent restarts to pick up the restored state.
+func (b *BackupsAPI) Restore(args params.BackupsRestoreArgs) error {
+	if err := b.backups.Restore(b.paths, args.ID); err != nil {
+		return errors.Trace(err)
+	}
+	b.restored()
+	return nil
+}
+
+// MetadataResult returns the API representation of the given backup
+// metadata.
+func MetadataResult(meta *state.BackupMetadata) params.BackupsMetadataResult {
+	return params.BackupsMetadataResult{
+		ID:             meta.Id,
+		Notes:          meta.Notes,
+		Started:        meta.Started,
+		Finished:       meta.Finished,
+		Checksum:       meta.Checksum,
+		ChecksumFormat: meta.ChecksumFormat,
+		Size:           meta.Size,
+		Stored:         meta.Stored,
+		Environment:    meta.Environment,
+		Machine:        meta.Machine,
+		Hostname:       meta.Hostname,
+		Version:        meta.Version,
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/backups"
+	"github.com/juju/juju/state/apiserver/common"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+	statebackups "github.com/juju/juju/state/backups"
+	"github.com/juju/juju/version"
+)
+
+type backupsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *backups.BackupsAPI
+	authorizer apiservertesting.FakeAuthorizer
+	fake       *fakeBackups
+	paths      statebackups.Paths
+	restarts   int
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+// fakeBackups records the backups created, and holds them in memory.
+type fakeBackups struct {
+	paths    statebackups.Paths
+	metas    []*state.BackupMetadata
+	restored string
+	err      error
+}
+
+func (f *fakeBackups) Create(paths statebackups.Paths, notes string) (*state.BackupMetadata, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.paths = paths
+	meta := &state.BackupMetadata{
+		Id:      "backup-0",
+		Notes:   notes,
+		Started: time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC),
+		Size:    42,
+		Stored:  true,
+		Version: version.MustParse("1.20.0"),
+	}
+	f.metas = append(f.metas, meta)
+	return meta, nil
+}
+
+func (f *fakeBackups) CreateScheduled(paths statebackups.Paths, notes string) (*state.BackupMetadata, error) {
+	panic("not expected")
+}
+
+func (f *fakeBackups) Get(id string) (*state.BackupMetadata, io.ReadCloser, error) {
+	panic("not expected")
+}
+
+func (f *fakeBackups) List() ([]*state.BackupMetadata, error) {
+	return f.metas, f.err
+}
+
+func (f *fakeBackups) Remove(id string) error {
+	for i, meta := range f.metas {
+		if meta.Id == id {
+			f.metas = append(f.metas[:i], f.metas[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
+
+func (f *fakeBackups) Add(archive io.Reader, notes string) (*state.BackupMetadata, error) {
+	panic("not expected")
+}
+
+func (f *fakeBackups) Restore(paths statebackups.Paths, id string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.paths = paths
+	f.restored = id
+	return nil
+}
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:      "user-admin",
+		LoggedIn: true,
+		Client:   true,
+	}
+	s.fake = &fakeBackups{}
+	s.PatchValue(backups.NewBackups, func(*state.State) statebackups.Backups {
+		return s.fake
+	})
+	s.paths = statebackups.Paths{DataDir: "/var/lib/juju", LogDir: "/var/log/juju"}
+	s.restarts = 0
+	var err error
+	s.api, err = backups.NewBackupsAPI(s.State, s.authorizer, s.paths, s.restart)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *backupsSuite) restart() {
+	s.restarts++
+}
+
+func (s *backupsSuite) TestNewBackupsAPIRefusesNonClient(c *gc.C) {
+	anAuthorizer := s.authorizer
+	anAuthorizer.Client = false
+	endPoint, err := backups.NewBackupsAPI(s.State, anAuthorizer, s.paths, s.restart)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *backupsSuite) TestNewBackupsAPIRequiresAdmin(c *gc.C) {
+	user := s.AddUser(c, "bob")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	anAuthorizer := s.authorizer
+	anAuthorizer.Tag = user.Tag()
+	for _, role := range []state.Role{state.RoleRead, state.RoleWrite} {
+		err = user.GrantRole(env.UUID(), role)
+		c.Assert(err, gc.IsNil)
+		endPoint, err := backups.NewBackupsAPI(s.State, anAuthorizer, s.paths, s.restart)
+		c.Assert(endPoint, gc.IsNil)
+		c.Assert(err, gc.Equals, common.ErrPerm)
+	}
+
+	err = user.GrantRole(env.UUID(), state.RoleAdmin)
+	c.Assert(err, gc.IsNil)
+	_, err = backups.NewBackupsAPI(s.State, anAuthorizer, s.paths, s.restart)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *backupsSuite) TestCreate(c *gc.C) {
+	result, err := s.api.Create(params.BackupsCreateArgs{Notes: "before upgrade"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.paths, gc.Equals, s.paths)
+	c.Assert(result, gc.DeepEquals, params.BackupsMetadataResult{
+		ID:      "backup-0",
+		Notes:   "before upgrade",
+		Started: time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC),
+		Size:    42,
+		Stored:  true,
+		Version: version.MustParse("1.20.0"),
+	})
+}
+
+func (s *backupsSuite) TestCreateError(c *gc.C) {
+	s.fake.err = errors.New("cannot dump database")
+	_, err := s.api.Create(params.BackupsCreateArgs{})
+	c.Assert(err, gc.ErrorMatches, "cannot dump database")
+}
+
+func (s *backupsSuite) TestInfo(c *gc.C) {
+	err := s.State.AddBackupMetadata(state.BackupMetadata{
+		Id:    "backup-1",
+		Notes: "nightly",
+		Size:  10,
+	})
+	c.Assert(err, gc.IsNil)
+	result, err := s.api.Info(params.BackupsInfoArgs{ID: "backup-1"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ID, gc.Equals, "backup-1")
+	c.Assert(result.Notes, gc.Equals, "nightly")
+	c.Assert(result.Size, gc.Equals, int64(10))
+	c.Assert(result.Stored, jc.IsFalse)
+
+	_, err = s.api.Info(params.BackupsInfoArgs{ID: "backup-2"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backupsSuite) TestListAndRemove(c *gc.C) {
+	result, err := s.api.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.List, gc.HasLen, 0)
+
+	_, err = s.api.Create(params.BackupsCreateArgs{})
+	c.Assert(err, gc.IsNil)
+	result, err = s.api.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.List, gc.HasLen, 1)
+	c.Assert(result.List[0].ID, gc.Equals, "backup-0")
+
+	err = s.api.Remove(params.BackupsRemoveArgs{ID: "backup-0"})
+	c.Assert(err, gc.IsNil)
+	result, err = s.api.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.List, gc.HasLen, 0)
+
+	err = s.api.Remove(params.BackupsRemoveArgs{ID: "backup-0"})
+	c.Assert(err, gc.ErrorMatches, `backup "backup-0" not found`)
+}
+
+func (s *backupsSuite) TestRestore(c *gc.C) {
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "backup-0"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.fake.restored, gc.Equals, "backup-0")
+	c.Assert(s.fake.paths, gc.Equals, s.paths)
+	c.Assert(s.restarts, gc.Equals, 1)
+}
+
+func (s *backupsSuite) TestRestoreError(c *gc.C) {
+	s.fake.err = errors.New("cannot restore database")
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "backup-0"})
+	c.Assert(err, gc.ErrorMatches, "cannot restore database")
+	c.Assert(s.restarts, gc.Equals, 0)
+}
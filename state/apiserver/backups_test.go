@@ -0,0 +1,168 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver"
+	"github.com/juju/juju/state/backups"
+)
+
+type backupsSuite struct {
+	authHttpSuite
+	fake *fakeBackups
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+// fakeBackups serves a single backup archive, and records the archive
+// uploaded.
+type fakeBackups struct {
+	backups.Backups
+	id      string
+	archive string
+	added   string
+	notes   string
+}
+
+func (f *fakeBackups) Get(id string) (*state.BackupMetadata, io.ReadCloser, error) {
+	if id != f.id {
+		return nil, nil, errors.NotFoundf("backup %q", id)
+	}
+	meta := &state.BackupMetadata{
+		Id:       id,
+		Size:     int64(len(f.archive)),
+		Checksum: "checksum",
+	}
+	return meta, ioutil.NopCloser(strings.NewReader(f.archive)), nil
+}
+
+func (f *fakeBackups) Add(archive io.Reader, notes string) (*state.BackupMetadata, error) {
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return nil, err
+	}
+	f.added = string(data)
+	f.notes = notes
+	return &state.BackupMetadata{
+		Id:     "backup-1",
+		Notes:  notes,
+		Size:   int64(len(data)),
+		Stored: true,
+	}, nil
+}
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.fake = &fakeBackups{id: "backup-0", archive: "archive data"}
+	s.PatchValue(apiserver.NewBackups, func(*state.State) backups.Backups {
+		return s.fake
+	})
+}
+
+func (s *backupsSuite) backupsURL(c *gc.C, query string) *url.URL {
+	uri := s.baseURL(c)
+	uri.Path += "/backups"
+	uri.RawQuery = query
+	return uri
+}
+
+func (s *backupsSuite) backupsURI(c *gc.C, query string) string {
+	return s.backupsURL(c, query).String()
+}
+
+func (s *backupsSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, "application/json")
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error, gc.ErrorMatches, expError)
+}
+
+func (s *backupsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.backupsURI(c, "id=backup-0"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *backupsSuite) TestRequiresAdmin(c *gc.C) {
+	for _, test := range []struct {
+		name string
+		role state.Role
+	}{
+		{"reader", state.RoleRead},
+		{"writer", state.RoleWrite},
+	} {
+		user := s.addUserWithRole(c, test.name, test.role)
+
+		resp, err := s.sendRequest(c, user.Tag(), "password", "GET", s.backupsURI(c, "id=backup-0"), "", nil)
+		c.Assert(err, gc.IsNil)
+		s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+
+		resp, err = s.sendRequest(c, user.Tag(), "password", "POST", s.backupsURI(c, ""), "application/x-tar-gz", strings.NewReader("uploaded data"))
+		c.Assert(err, gc.IsNil)
+		s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+	}
+	c.Assert(s.fake.added, gc.Equals, "")
+}
+
+func (s *backupsSuite) TestRequiresGETOrPOST(c *gc.C) {
+	resp, err := s.authRequest(c, "PUT", s.backupsURI(c, "id=backup-0"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *backupsSuite) TestDownloadRequiresId(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, ""), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected id query argument")
+}
+
+func (s *backupsSuite) TestDownloadUnknownBackup(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, "id=backup-1"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `backup "backup-1" not found`)
+}
+
+func (s *backupsSuite) TestDownload(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.backupsURI(c, "id=backup-0"), "", nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(resp.Header.Get("Digest"), gc.Equals, "SHA=checksum")
+	body := assertResponse(c, resp, http.StatusOK, "application/x-tar-gz")
+	c.Assert(string(body), gc.Equals, "archive data")
+	s.assertLastAudited(c, "Backups", "Download", `{"id":"backup-0"}`, "")
+}
+
+func (s *backupsSuite) TestUploadRequiresContentType(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.backupsURI(c, ""), "text/plain", strings.NewReader("archive data"))
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected Content-Type: application/x-tar-gz, got: text/plain")
+}
+
+func (s *backupsSuite) TestUpload(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.backupsURI(c, "notes=old+server"), "application/x-tar-gz", strings.NewReader("uploaded data"))
+	c.Assert(err, gc.IsNil)
+	body := assertResponse(c, resp, http.StatusOK, "application/json")
+	var result params.BackupsMetadataResult
+	err = json.Unmarshal(body, &result)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.ID, gc.Equals, "backup-1")
+	c.Assert(result.Notes, gc.Equals, "old server")
+	c.Assert(result.Size, gc.Equals, int64(len("uploaded data")))
+	c.Assert(s.fake.added, gc.Equals, "uploaded data")
+	c.Assert(s.fake.notes, gc.Equals, "old server")
+	s.assertLastAudited(c, "Backups", "Upload", `{"notes":"old server"}`, "")
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/juju/juju/charm"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 )
 
@@ -40,7 +41,8 @@ type charmsHandler struct {
 type bundleContentSenderFunc func(w http.ResponseWriter, r *http.Request, bundle *charm.Bundle)
 
 func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.authenticate(r); err != nil {
+	user, err := h.authenticate(r)
+	if err != nil {
 		h.authError(w, h)
 		return
 	}
@@ -53,16 +55,17 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "POST":
 		// Add a local charm to the store provider.
 		// Requires a "series" query specifying the series to use for the charm.
-		charmURL, err := h.processPost(r)
-		if err != nil {
-			h.sendError(w, http.StatusBadRequest, err.Error())
+		if !h.requireUserRole(w, h, user, state.RoleWrite) {
 			return
 		}
-		h.sendJSON(w, http.StatusOK, &params.CharmsResponse{CharmURL: charmURL.String()})
+		h.serveAudited(w, r, user, "Charms", "Upload", h.servePost)
 	case "GET":
 		// Retrieve or list charm files.
 		// Requires "url" (charm URL) and an optional "file" (the path to the
 		// charm file) to be included in the query.
+		if !h.requireUserRole(w, h, user, state.RoleRead) {
+			return
+		}
 		if charmArchivePath, filePath, err := h.processGet(r); err != nil {
 			// An error occurred retrieving the charm bundle.
 			h.sendError(w, http.StatusBadRequest, err.Error())
@@ -78,6 +81,16 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// servePost adds the local charm uploaded in the request.
+func (h *charmsHandler) servePost(w http.ResponseWriter, r *http.Request) {
+	charmURL, err := h.processPost(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendJSON(w, http.StatusOK, &params.CharmsResponse{CharmURL: charmURL.String()})
+}
+
 // sendJSON sends a JSON-encoded response to the client.
 func (h *charmsHandler) sendJSON(w http.ResponseWriter, statusCode int, response *params.CharmsResponse) error {
 	w.Header().Set("Content-Type", "application/json")
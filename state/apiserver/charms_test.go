@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "launchpad.net/gocheck"
@@ -39,6 +40,22 @@ func (s *authHttpSuite) SetUpTest(c *gc.C) {
 	user := s.AddUser(c, "joe")
 	s.userTag = user.Tag()
 	s.password = "password"
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	// The user administers the environment unless a test says otherwise.
+	err = user.GrantRole(env.UUID(), state.RoleAdmin)
+	c.Assert(err, gc.IsNil)
+}
+
+// addUserWithRole adds a user with the given role in the environment,
+// whose password is "password".
+func (s *authHttpSuite) addUserWithRole(c *gc.C, name string, role state.Role) *state.User {
+	user := s.AddUser(c, name)
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = user.GrantRole(env.UUID(), role)
+	c.Assert(err, gc.IsNil)
+	return user
 }
 
 func (s *authHttpSuite) sendRequest(c *gc.C, tag, password, method, uri, contentType string, body io.Reader) (*http.Response, error) {
@@ -63,6 +80,19 @@ func (s *authHttpSuite) baseURL(c *gc.C) *url.URL {
 	}
 }
 
+// assertLastAudited checks that the last entry in the audit log made
+// by the suite's user records the given request.
+func (s *authHttpSuite) assertLastAudited(c *gc.C, facade, method, args, errMsg string) {
+	entries, err := s.State.AuditEntries(state.AuditFilter{User: s.userTag})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.Not(gc.HasLen), 0)
+	entry := entries[len(entries)-1]
+	c.Check(entry.Facade, gc.Equals, facade)
+	c.Check(entry.Method, gc.Equals, method)
+	c.Check(entry.Args, gc.Equals, args)
+	c.Check(entry.Error, gc.Equals, errMsg)
+}
+
 func (s *authHttpSuite) authRequest(c *gc.C, method, uri, contentType string, body io.Reader) (*http.Response, error) {
 	return s.sendRequest(c, s.userTag, s.password, method, uri, contentType, body)
 }
@@ -135,10 +165,31 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected url=CharmURL query argument")
 }
 
+func (s *charmsSuite) TestUploadRequiresWrite(c *gc.C) {
+	user := s.addUserWithRole(c, "reader", state.RoleRead)
+	ch := charmtesting.Charms.Bundle(c.MkDir(), "dummy")
+	f, err := os.Open(ch.Path)
+	c.Assert(err, gc.IsNil)
+	defer f.Close()
+	resp, err := s.sendRequest(c, user.Tag(), "password", "POST", s.charmsURI(c, "?series=quantal"), "application/zip", f)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+	_, err = s.State.Charm(charm.MustParseURL("local:quantal/dummy-1"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *charmsSuite) TestGetRequiresRead(c *gc.C) {
+	user := s.addUserWithRole(c, "nobody", state.RoleNone)
+	resp, err := s.sendRequest(c, user.Tag(), "password", "GET", s.charmsURI(c, "?url=local:quantal/dummy-1"), "", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
+	s.assertLastAudited(c, "Charms", "Upload", "{}", "400 Bad Request")
 }
 
 func (s *charmsSuite) TestUploadFailsWithInvalidZip(c *gc.C) {
@@ -285,6 +285,11 @@ var scenarioStatus = &api.Status{
 // When the scenario is initialized, we have:
 // user-admin
 // user-other
+//  role=write
+// user-reader
+//  role=read
+// user-outsider
+//  no role
 // machine-0
 //  instance-id="i-machine-0"
 //  nonce="fake_nonce"
@@ -330,9 +335,22 @@ func (s *baseSuite) setUpScenario(c *gc.C) (entities []string) {
 	setDefaultPassword(c, u)
 	add(u)
 
-	u = s.AddUser(c, "other")
-	setDefaultPassword(c, u)
-	add(u)
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	for _, user := range []struct {
+		name string
+		role state.Role
+	}{
+		{"other", state.RoleWrite},
+		{"reader", state.RoleRead},
+		{"outsider", state.RoleNone},
+	} {
+		u = s.AddUser(c, user.name)
+		err = u.GrantRole(env.UUID(), user.role)
+		c.Assert(err, gc.IsNil)
+		setDefaultPassword(c, u)
+		add(u)
+	}
 
 	m, err := s.State.AddMachine("quantal", state.JobManageEnviron)
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,109 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// ExportBundle returns the services of the environment and the
+// relations between them, so that they can be deployed again
+// elsewhere. Config settings equal to the charm defaults are omitted,
+// as are peer relations, which are added with their services.
+func (c *Client) ExportBundle() (params.ExportBundleResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.ExportBundleResults{}, err
+	}
+	services, err := c.api.state.AllServices()
+	if err != nil {
+		return params.ExportBundleResults{}, err
+	}
+	results := params.ExportBundleResults{
+		Services: make(map[string]params.BundleService),
+	}
+	for _, service := range services {
+		bundleService, err := exportService(service)
+		if err != nil {
+			return params.ExportBundleResults{}, err
+		}
+		results.Services[service.Name()] = bundleService
+	}
+	relations, err := c.api.state.AllRelations()
+	if err != nil {
+		return params.ExportBundleResults{}, err
+	}
+	for _, rel := range relations {
+		eps := rel.Endpoints()
+		if len(eps) != 2 {
+			continue
+		}
+		endpoints := []string{eps[0].String(), eps[1].String()}
+		sort.Strings(endpoints)
+		results.Relations = append(results.Relations, endpoints)
+	}
+	sort.Sort(relationsByEndpoints(results.Relations))
+	return results, nil
+}
+
+// exportService returns the description of the service in a bundle.
+func exportService(service *state.Service) (params.BundleService, error) {
+	ch, _, err := service.Charm()
+	if err != nil {
+		return params.BundleService{}, err
+	}
+	settings, err := service.ConfigSettings()
+	if err != nil {
+		return params.BundleService{}, err
+	}
+	var options map[string]interface{}
+	for name, value := range settings {
+		if option, ok := ch.Config().Options[name]; ok && reflect.DeepEqual(value, option.Default) {
+			continue
+		}
+		if options == nil {
+			options = make(map[string]interface{})
+		}
+		options[name] = value
+	}
+	annotations, err := service.Annotations()
+	if err != nil {
+		return params.BundleService{}, err
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	result := params.BundleService{
+		Charm:       ch.URL().String(),
+		Options:     options,
+		Exposed:     service.IsExposed(),
+		Annotations: annotations,
+	}
+	// The units of a subordinate service are added with the units of
+	// the services it is related to, and it has no constraints.
+	if service.IsPrincipal() {
+		units, err := service.AllUnits()
+		if err != nil {
+			return params.BundleService{}, err
+		}
+		result.NumUnits = len(units)
+		if result.Constraints, err = service.Constraints(); err != nil {
+			return params.BundleService{}, err
+		}
+	}
+	return result, nil
+}
+
+// relationsByEndpoints sorts relations by their endpoints.
+type relationsByEndpoints [][]string
+
+func (r relationsByEndpoints) Len() int      { return len(r) }
+func (r relationsByEndpoints) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r relationsByEndpoints) Less(i, j int) bool {
+	return strings.Join(r[i], " ") < strings.Join(r[j], " ")
+}
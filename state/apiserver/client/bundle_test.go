@@ -0,0 +1,74 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/charm"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state/api/params"
+)
+
+type bundleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+func (s *bundleSuite) TestExportBundle(c *gc.C) {
+	s.setUpScenario(c)
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := dummy.UpdateConfigSettings(charm.Settings{
+		// Same as default.
+		"title": "My Title",
+		// Different from default.
+		"username": "bob",
+	})
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetExposed()
+	c.Assert(err, gc.IsNil)
+	err = dummy.SetAnnotations(map[string]string{"gui-x": "100"})
+	c.Assert(err, gc.IsNil)
+	_, err = dummy.AddUnit()
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, jc.DeepEquals, &params.ExportBundleResults{
+		Services: map[string]params.BundleService{
+			"dummy": {
+				Charm:       "local:quantal/dummy-1",
+				NumUnits:    1,
+				Options:     map[string]interface{}{"username": "bob"},
+				Constraints: constraints.MustParse("mem=2G"),
+				Exposed:     true,
+				Annotations: map[string]string{"gui-x": "100"},
+			},
+			"logging": {
+				Charm: "local:quantal/logging-1",
+			},
+			"mysql": {
+				Charm: "local:quantal/mysql-1",
+			},
+			"wordpress": {
+				Charm:    "local:quantal/wordpress-3",
+				NumUnits: 2,
+			},
+		},
+		Relations: [][]string{
+			{"logging:logging-directory", "wordpress:logging-dir"},
+		},
+	})
+}
+
+func (s *bundleSuite) TestExportBundleEmpty(c *gc.C) {
+	results, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Services, gc.HasLen, 0)
+	c.Assert(results.Relations, gc.HasLen, 0)
+}
@@ -73,7 +73,16 @@ func (r *API) Client(id string) (*Client, error) {
 	return r.client, nil
 }
 
+// requireRole returns an error unless the authenticated user has at
+// least the given role in the environment.
+func (c *Client) requireRole(role state.Role) error {
+	return common.RequireUserRole(c.api.state, c.api.auth, role)
+}
+
 func (c *Client) WatchAll() (params.AllWatcherId, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.AllWatcherId{}, err
+	}
 	w := c.api.state.Watch()
 	return params.AllWatcherId{
 		AllWatcherId: c.api.resources.Register(w),
@@ -86,6 +95,9 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 // (Deprecated) Use NewServiceSetForClientAPI instead, to preserve values set to
 // an empty string, and use ServiceUnset to unset values.
 func (c *Client) ServiceSet(p params.ServiceSet) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
@@ -101,6 +113,9 @@ func (c *Client) ServiceSet(p params.ServiceSet) error {
 // TODO(Nate): rename this to ServiceSet (and remove the deprecated ServiceSet)
 // when the GUI handles the new behavior.
 func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
@@ -110,6 +125,9 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 
 // ServiceUnset implements the server side of Client.ServiceUnset.
 func (c *Client) ServiceUnset(p params.ServiceUnset) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
@@ -123,6 +141,9 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
 func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
@@ -132,6 +153,9 @@ func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
 
 // ServiceCharmRelations implements the server side of Client.ServiceCharmRelations.
 func (c *Client) ServiceCharmRelations(p params.ServiceCharmRelations) (params.ServiceCharmRelationsResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.ServiceCharmRelationsResults{}, err
+	}
 	var results params.ServiceCharmRelationsResults
 	service, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
@@ -150,6 +174,9 @@ func (c *Client) ServiceCharmRelations(p params.ServiceCharmRelations) (params.S
 
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	unit, err := c.api.state.Unit(p.UnitName)
 	if err != nil {
 		return err
@@ -159,6 +186,9 @@ func (c *Client) Resolved(p params.Resolved) error {
 
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return results, err
+	}
 	switch {
 	case names.IsMachine(p.Target):
 		machine, err := c.api.state.Machine(p.Target)
@@ -187,6 +217,9 @@ func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAdd
 
 // PrivateAddress implements the server side of Client.PrivateAddress.
 func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.PrivateAddressResults, err error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return results, err
+	}
 	switch {
 	case names.IsMachine(p.Target):
 		machine, err := c.api.state.Machine(p.Target)
@@ -216,6 +249,9 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 // ServiceExpose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -226,6 +262,9 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -252,6 +291,9 @@ func networkTagsToNames(tags []string) ([]string, error) {
 // before calling ServiceDeploy, although for backward compatibility
 // this is not necessary until 1.16 support is removed.
 func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	curl, err := charm.ParseURL(args.CharmUrl)
 	if err != nil {
 		return err
@@ -279,13 +321,7 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 		return err
 	}
 
-	var settings charm.Settings
-	if len(args.ConfigYAML) > 0 {
-		settings, err = ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
-	} else if len(args.Config) > 0 {
-		// Parse config in a compatile way (see function comment).
-		settings, err = parseSettingsCompatible(ch, args.Config)
-	}
+	settings, err := deploySettings(ch, args)
 	if err != nil {
 		return err
 	}
@@ -309,6 +345,18 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 	return err
 }
 
+// deploySettings returns the config settings given to a ServiceDeploy
+// call deploying a service of the given charm.
+func deploySettings(ch *state.Charm, args params.ServiceDeploy) (charm.Settings, error) {
+	if len(args.ConfigYAML) > 0 {
+		return ch.Config().ParseSettingsYAML([]byte(args.ConfigYAML), args.ServiceName)
+	} else if len(args.Config) > 0 {
+		// Parse config in a compatile way (see function comment).
+		return parseSettingsCompatible(ch, args.Config)
+	}
+	return nil, nil
+}
+
 // ServiceDeployWithNetworks works exactly like ServiceDeploy, but
 // allows specifying networks to include or exclude on the machine
 // where the charm gets deployed (either with args.Network or with
@@ -321,6 +369,9 @@ func (c *Client) ServiceDeployWithNetworks(args params.ServiceDeploy) error {
 // minimum number of units, settings and constraints.
 // All parameters in params.ServiceUpdate except the service name are optional.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	service, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -338,12 +389,16 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 		}
 	}
 	// Set up service's settings.
-	if args.SettingsYAML != "" {
-		if err = serviceSetSettingsYAML(service, args.SettingsYAML); err != nil {
+	if args.SettingsYAML != "" || len(args.SettingsStrings) > 0 {
+		ch, _, err := service.Charm()
+		if err != nil {
+			return err
+		}
+		settings, err := updateSettings(ch, service.Name(), args)
+		if err != nil {
 			return err
 		}
-	} else if len(args.SettingsStrings) > 0 {
-		if err = serviceSetSettingsStrings(service, args.SettingsStrings); err != nil {
+		if err = service.UpdateConfigSettings(settings); err != nil {
 			return err
 		}
 	}
@@ -354,6 +409,16 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	return nil
 }
 
+// updateSettings returns the config settings given to a ServiceUpdate
+// call updating a service using the given charm.
+func updateSettings(ch *state.Charm, serviceName string, args params.ServiceUpdate) (charm.Settings, error) {
+	if args.SettingsYAML != "" {
+		return ch.Config().ParseSettingsYAML([]byte(args.SettingsYAML), serviceName)
+	}
+	// Parse config in a compatible way (see function comment).
+	return parseSettingsCompatible(ch, args.SettingsStrings)
+}
+
 // serviceSetCharm sets the charm for the given service.
 func (c *Client) serviceSetCharm(service *state.Service, url string, force bool) error {
 	curl, err := charm.ParseURL(url)
@@ -444,6 +509,9 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 
 // ServiceSetCharm sets the charm for a given service.
 func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	service, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -457,17 +525,36 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 	if err != nil {
 		return nil, err
 	}
+	if err := checkAddServiceUnits(service, args); err != nil {
+		return nil, err
+	}
+	return juju.AddUnits(state, service, args.NumUnits, args.ToMachineSpec)
+}
+
+// checkAddServiceUnits checks the arguments of an AddServiceUnits call
+// adding units to the given service. The placement of the units is
+// checked by juju.AddUnits.
+func checkAddServiceUnits(service *state.Service, args params.AddServiceUnits) error {
+	if service.Life() != state.Alive {
+		return fmt.Errorf("service %q is not alive", service)
+	}
+	if !service.IsPrincipal() {
+		return fmt.Errorf("cannot add units to subordinate service %q", service)
+	}
 	if args.NumUnits < 1 {
-		return nil, fmt.Errorf("must add at least one unit")
+		return fmt.Errorf("must add at least one unit")
 	}
 	if args.NumUnits > 1 && args.ToMachineSpec != "" {
-		return nil, fmt.Errorf("cannot use NumUnits with ToMachineSpec")
+		return fmt.Errorf("cannot use NumUnits with ToMachineSpec")
 	}
-	return juju.AddUnits(state, service, args.NumUnits, args.ToMachineSpec)
+	return nil
 }
 
 // AddServiceUnits adds a given number of units to a service.
 func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddServiceUnitsResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.AddServiceUnitsResults{}, err
+	}
 	units, err := addServiceUnits(c.api.state, args)
 	if err != nil {
 		return params.AddServiceUnitsResults{}, err
@@ -481,6 +568,9 @@ func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddService
 
 // DestroyServiceUnits removes a given set of service units.
 func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	var errs []string
 	for _, name := range args.UnitNames {
 		unit, err := c.api.state.Unit(name)
@@ -504,6 +594,9 @@ func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 
 // ServiceDestroy destroys a given service.
 func (c *Client) ServiceDestroy(args params.ServiceDestroy) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -513,6 +606,9 @@ func (c *Client) ServiceDestroy(args params.ServiceDestroy) error {
 
 // GetServiceConstraints returns the constraints for a given service.
 func (c *Client) GetServiceConstraints(args params.GetServiceConstraints) (params.GetConstraintsResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.GetConstraintsResults{}, err
+	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return params.GetConstraintsResults{}, err
@@ -523,6 +619,9 @@ func (c *Client) GetServiceConstraints(args params.GetServiceConstraints) (param
 
 // GetEnvironmentConstraints returns the constraints for the environment.
 func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.GetConstraintsResults{}, err
+	}
 	cons, err := c.api.state.EnvironConstraints()
 	if err != nil {
 		return params.GetConstraintsResults{}, err
@@ -532,6 +631,9 @@ func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, erro
 
 // SetServiceConstraints sets the constraints for a given service.
 func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	svc, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return err
@@ -541,11 +643,17 @@ func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
 
 // SetEnvironmentConstraints sets the constraints for the environment.
 func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	return c.api.state.SetEnvironConstraints(args.Constraints)
 }
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.AddRelationResults{}, err
+	}
 	inEps, err := c.api.state.InferEndpoints(args.Endpoints)
 	if err != nil {
 		return params.AddRelationResults{}, err
@@ -567,6 +675,9 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(args params.DestroyRelation) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	eps, err := c.api.state.InferEndpoints(args.Endpoints)
 	if err != nil {
 		return err
@@ -585,6 +696,9 @@ func (c *Client) AddMachines(args params.AddMachines) (params.AddMachinesResults
 
 // AddMachinesV2 adds new machines with the supplied parameters.
 func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.AddMachinesResults{}, err
+	}
 	results := params.AddMachinesResults{
 		Machines: make([]params.AddMachinesResult, len(args.MachineParams)),
 	}
@@ -688,6 +802,9 @@ func stateJobs(jobs []params.MachineJob) ([]state.MachineJob, error) {
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (params.ProvisioningScriptResult, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.ProvisioningScriptResult{}, err
+	}
 	var result params.ProvisioningScriptResult
 	mcfg, err := MachineConfig(c.api.state, args.MachineId, args.Nonce, args.DataDir)
 	if err != nil {
@@ -700,6 +817,9 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (param
 
 // DestroyMachines removes a given set of machines.
 func (c *Client) DestroyMachines(args params.DestroyMachines) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	var errs []string
 	for _, id := range args.MachineNames {
 		machine, err := c.api.state.Machine(id)
@@ -723,6 +843,9 @@ func (c *Client) DestroyMachines(args params.DestroyMachines) error {
 
 // CharmInfo returns information about the requested charm.
 func (c *Client) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return api.CharmInfo{}, err
+	}
 	curl, err := charm.ParseURL(args.CharmURL)
 	if err != nil {
 		return api.CharmInfo{}, err
@@ -743,6 +866,9 @@ func (c *Client) CharmInfo(args params.CharmInfo) (api.CharmInfo, error) {
 // EnvironmentInfo returns information about the current environment (default
 // series and type).
 func (c *Client) EnvironmentInfo() (api.EnvironmentInfo, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return api.EnvironmentInfo{}, err
+	}
 	state := c.api.state
 	conf, err := state.EnvironConfig()
 	if err != nil {
@@ -764,6 +890,9 @@ func (c *Client) EnvironmentInfo() (api.EnvironmentInfo, error) {
 
 // GetAnnotations returns annotations about a given entity.
 func (c *Client) GetAnnotations(args params.GetAnnotations) (params.GetAnnotationsResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.GetAnnotationsResults{}, err
+	}
 	nothing := params.GetAnnotationsResults{}
 	entity, err := c.findEntity(args.Tag)
 	if err != nil {
@@ -790,6 +919,9 @@ func (c *Client) findEntity(tag string) (state.Annotator, error) {
 
 // SetAnnotations stores annotations about a given entity.
 func (c *Client) SetAnnotations(args params.SetAnnotations) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	entity, err := c.findEntity(args.Tag)
 	if err != nil {
 		return err
@@ -832,12 +964,18 @@ func parseSettingsCompatible(ch *state.Charm, settings map[string]string) (charm
 
 // AgentVersion returns the current version that the API server is running.
 func (c *Client) AgentVersion() (params.AgentVersionResult, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.AgentVersionResult{}, err
+	}
 	return params.AgentVersionResult{Version: version.Current.Number}, nil
 }
 
 // EnvironmentGet implements the server-side part of the
 // get-environment CLI command.
 func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.EnvironmentGetResults{}, err
+	}
 	result := params.EnvironmentGetResults{}
 	// Get the existing environment config from the state.
 	config, err := c.api.state.EnvironConfig()
@@ -851,6 +989,9 @@ func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
 // EnvironmentSet implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return err
+	}
 	// Make sure we don't allow changing agent-version.
 	checkAgentVersion := func(updateAttrs map[string]interface{}, removeAttrs []string, oldConfig *config.Config) error {
 		if v, found := updateAttrs["agent-version"]; found {
@@ -870,6 +1011,9 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 // EnvironmentUnset implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return err
+	}
 	// TODO(waigani) 2014-3-11 #1167616
 	// Add a txn retry loop to ensure that the settings on disk have not
 	// changed underneath us.
@@ -878,11 +1022,17 @@ func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
 
 // SetEnvironAgentVersion sets the environment agent version.
 func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) error {
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return err
+	}
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
 // FindTools returns a List containing all tools matching the given parameters.
 func (c *Client) FindTools(args params.FindToolsParams) (params.FindToolsResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.FindToolsResults{}, err
+	}
 	result := params.FindToolsResults{}
 	// Get the existing environment config from the state.
 	envConfig, err := c.api.state.EnvironConfig()
@@ -918,6 +1068,9 @@ func destroyErr(desc string, ids, errs []string) error {
 // the environment, if it does not exist yet. Local charms are not
 // supported, only charm store URLs. See also AddLocalCharm().
 func (c *Client) AddCharm(args params.CharmURL) error {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return err
+	}
 	charmURL, err := charm.ParseURL(args.URL)
 	if err != nil {
 		return err
@@ -1006,6 +1159,9 @@ func (c *Client) AddCharm(args params.CharmURL) error {
 }
 
 func (c *Client) ResolveCharms(args params.ResolveCharms) (params.ResolveCharmResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.ResolveCharmResults{}, err
+	}
 	var results params.ResolveCharmResults
 
 	envConfig, err := c.api.state.EnvironConfig()
@@ -1049,6 +1205,9 @@ func CharmArchiveName(name string, revision int) (string, error) {
 
 // RetryProvisioning marks a provisioning error as transient on the machines.
 func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.ErrorResults{}, err
+	}
 	entityStatus := make([]params.EntityStatus, len(p.Entities))
 	for i, entity := range p.Entities {
 		entityStatus[i] = params.EntityStatus{Tag: entity.Tag, Data: params.StatusData{"transient": true}}
@@ -1060,6 +1219,9 @@ func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, erro
 
 // APIHostPorts returns the API host/port addresses stored in state.
 func (c *Client) APIHostPorts() (result params.APIHostPortsResult, err error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return result, err
+	}
 	if result.Servers, err = c.api.state.APIHostPorts(); err != nil {
 		return params.APIHostPortsResult{}, err
 	}
@@ -1068,6 +1230,9 @@ func (c *Client) APIHostPorts() (result params.APIHostPortsResult, err error) {
 
 // EnsureAvailability ensures the availability of Juju state servers.
 func (c *Client) EnsureAvailability(args params.EnsureAvailability) error {
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return err
+	}
 	series := args.Series
 	if series == "" {
 		ssi, err := c.api.state.StateServerInfo()
@@ -1088,5 +1253,62 @@ func (c *Client) EnsureAvailability(args params.EnsureAvailability) error {
 		}
 		series = templateMachine.Series()
 	}
-	return c.api.state.EnsureAvailability(args.NumStateServers, args.Constraints, series)
+	placement, err := c.stateServerPlacement(args.Placement)
+	if err != nil {
+		return err
+	}
+	return c.api.state.EnsureAvailability(args.NumStateServers, args.Constraints, series, placement)
+}
+
+// stateServerPlacement returns the placement directives for new state
+// servers as understood by state: machine ids, and directives for the
+// environment's provider with the scope removed.
+func (c *Client) stateServerPlacement(directives []string) ([]string, error) {
+	var placement []string
+	for _, directive := range directives {
+		p, err := instance.ParsePlacement(directive)
+		if err == instance.ErrPlacementScopeMissing {
+			placement = append(placement, directive)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		switch p.Scope {
+		case instance.MachineScope:
+			placement = append(placement, p.Directive)
+			continue
+		case string(instance.LXC), string(instance.KVM):
+			return nil, fmt.Errorf("cannot place a state server in a new container")
+		}
+		env, err := c.api.state.Environment()
+		if err != nil {
+			return nil, err
+		}
+		if p.Scope != env.Name() {
+			return nil, fmt.Errorf("invalid environment name %q", p.Scope)
+		}
+		placement = append(placement, p.Directive)
+	}
+	return placement, nil
+}
+
+// DemoteStateServers removes the votes of the given state server
+// machines, so that they are replaced by the next EnsureAvailability
+// call. Callers should make that call straight afterwards, as a
+// demotion may leave an even number of voters.
+func (c *Client) DemoteStateServers(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		_, id, err := names.ParseTag(entity.Tag, names.MachineTagKind)
+		if err == nil {
+			err = c.api.state.DemoteStateServer(id)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
 }
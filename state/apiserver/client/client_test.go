@@ -837,10 +837,14 @@ func (s *clientSuite) TestClientServiceDeployServiceOwner(c *gc.C) {
 	defer restore()
 	curl, _ := addCharm(c, store, "dummy")
 
-	s.AddUser(c, "foobar")
+	user := s.AddUser(c, "foobar")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
 	s.APIState = s.OpenAPIAs(c, "user-foobar", "password")
 
-	err := s.APIState.Client().ServiceDeploy(
+	err = s.APIState.Client().ServiceDeploy(
 		curl.String(), "service", 3, "", constraints.Value{}, "",
 	)
 	c.Assert(err, gc.IsNil)
@@ -2206,7 +2210,7 @@ func (s *clientSuite) TestClientEnsureAvailabilitySeries(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(machines, gc.HasLen, 1)
 	c.Assert(machines[0].Series(), gc.Equals, "quantal")
-	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err = s.State.AllMachines()
 	c.Assert(err, gc.IsNil)
@@ -2216,7 +2220,7 @@ func (s *clientSuite) TestClientEnsureAvailabilitySeries(c *gc.C) {
 	c.Assert(machines[2].Series(), gc.Equals, "quantal")
 	defer s.setAgentAlive(c, "1").Kill()
 	defer s.setAgentAlive(c, "2").Kill()
-	err = s.APIState.Client().EnsureAvailability(5, emptyCons, "non-default")
+	err = s.APIState.Client().EnsureAvailability(5, emptyCons, "non-default", nil)
 	c.Assert(err, gc.IsNil)
 	machines, err = s.State.AllMachines()
 	c.Assert(err, gc.IsNil)
@@ -2234,7 +2238,7 @@ func (s *clientSuite) TestClientEnsureAvailabilityConstraints(c *gc.C) {
 	pinger := s.setAgentAlive(c, "0")
 	defer pinger.Kill()
 	err = s.APIState.Client().EnsureAvailability(
-		3, constraints.MustParse("mem=4G"), defaultSeries)
+		3, constraints.MustParse("mem=4G"), defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err := s.State.AllMachines()
 	c.Assert(err, gc.IsNil)
@@ -2258,14 +2262,14 @@ func (s *clientSuite) TestClientEnsureAvailability0Preserves(c *gc.C) {
 	defer pinger.Kill()
 	// A value of 0 says either "if I'm not HA, make me HA" or "preserve my
 	// current HA settings".
-	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err := s.State.AllMachines()
 	c.Assert(machines, gc.HasLen, 3)
 	defer s.setAgentAlive(c, "1").Kill()
 	// Now, we keep agent 1 alive, but not agent 2, calling
 	// EnsureAvailability(0) again will cause us to start another machine
-	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err = s.State.AllMachines()
 	c.Assert(machines, gc.HasLen, 4)
@@ -2277,7 +2281,7 @@ func (s *clientSuite) TestClientEnsureAvailability0Preserves5(c *gc.C) {
 	pinger := s.setAgentAlive(c, "0")
 	defer pinger.Kill()
 	// Start off with 5 servers
-	err = s.APIState.Client().EnsureAvailability(5, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(5, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err := s.State.AllMachines()
 	c.Assert(machines, gc.HasLen, 5)
@@ -2285,7 +2289,7 @@ func (s *clientSuite) TestClientEnsureAvailability0Preserves5(c *gc.C) {
 	defer s.setAgentAlive(c, "2").Kill()
 	defer s.setAgentAlive(c, "3").Kill()
 	// Keeping all alive but one, will bring up 1 more server to preserve 5
-	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(0, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
 	machines, err = s.State.AllMachines()
 	c.Assert(machines, gc.HasLen, 6)
@@ -2296,14 +2300,64 @@ func (s *clientSuite) TestClientEnsureAvailabilityErrors(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	pinger := s.setAgentAlive(c, "0")
 	defer pinger.Kill()
-	err = s.APIState.Client().EnsureAvailability(-1, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(-1, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.ErrorMatches, "number of state servers must be odd and non-negative")
-	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.IsNil)
-	err = s.APIState.Client().EnsureAvailability(1, emptyCons, defaultSeries)
+	err = s.APIState.Client().EnsureAvailability(1, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.ErrorMatches, "cannot reduce state server count")
 }
 
+func (s *clientSuite) TestClientEnsureAvailabilityPlacement(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	pinger := s.setAgentAlive(c, "0")
+	defer pinger.Kill()
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, []string{"1", env.Name() + ":zone=a"})
+	c.Assert(err, gc.IsNil)
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "1", "2"})
+	m, err := s.State.Machine("2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m.Placement(), gc.Equals, "zone=a")
+}
+
+func (s *clientSuite) TestClientEnsureAvailabilityPlacementErrors(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	pinger := s.setAgentAlive(c, "0")
+	defer pinger.Kill()
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, []string{"lxc:0"})
+	c.Assert(err, gc.ErrorMatches, "cannot place a state server in a new container")
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, []string{"otherenv:zone=a"})
+	c.Assert(err, gc.ErrorMatches, `invalid environment name "otherenv"`)
+}
+
+func (s *clientSuite) TestClientDemoteStateServers(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	pinger := s.setAgentAlive(c, "0")
+	defer pinger.Kill()
+	err = s.APIState.Client().EnsureAvailability(3, emptyCons, defaultSeries, nil)
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.APIState.Client().DemoteStateServers("machine-1", "machine-42", "unit-wordpress-0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	c.Assert(results[2].Error, gc.ErrorMatches, `"unit-wordpress-0" is not a valid machine tag`)
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.VotingMachineIds, jc.SameContents, []string{"0", "2"})
+}
+
 func (s *clientSuite) TestAPIHostPorts(c *gc.C) {
 	apiHostPorts, err := s.APIState.Client().APIHostPorts()
 	c.Assert(err, gc.IsNil)
@@ -15,6 +15,9 @@ import (
 // DestroyEnvironment destroys all services and non-manager machine
 // instances in the environment.
 func (c *Client) DestroyEnvironment() error {
+	if err := c.requireRole(state.RoleAdmin); err != nil {
+		return err
+	}
 	// TODO(axw) 2013-08-30 bug 1218688
 	//
 	// There's a race here: a client might add a manual machine
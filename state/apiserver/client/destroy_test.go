@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
 )
 
 type destroyEnvironmentSuite struct {
@@ -119,3 +120,20 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironment(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(env.Life(), gc.Equals, state.Dying)
 }
+
+func (s *destroyEnvironmentSuite) TestDestroyEnvironmentRequiresAdmin(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	user := s.AddUser(c, "writer")
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	st := s.OpenAPIAs(c, "user-writer", "password")
+	defer st.Close()
+
+	err = st.Client().DestroyEnvironment()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+	err = env.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(env.Life(), gc.Equals, state.Alive)
+}
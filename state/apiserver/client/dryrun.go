@@ -0,0 +1,267 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charm"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// The dry-run calls check their arguments as the calls they mirror
+// would, and report the changes those calls would make, without
+// changing the environment. The first error the call would fail with
+// is returned instead.
+
+// ServiceDeployDryRun reports the changes ServiceDeploy would make.
+func (c *Client) ServiceDeployDryRun(args params.ServiceDeploy) (params.DryRunResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.DryRunResults{}, err
+	}
+	changes, err := c.checkServiceDeploy(args)
+	if err != nil {
+		return params.DryRunResults{}, err
+	}
+	return params.DryRunResults{Changes: changes}, nil
+}
+
+// AddServiceUnitsDryRun reports the changes AddServiceUnits would make.
+func (c *Client) AddServiceUnitsDryRun(args params.AddServiceUnits) (params.DryRunResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.DryRunResults{}, err
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.DryRunResults{}, err
+	}
+	if err := checkAddServiceUnits(service, args); err != nil {
+		return params.DryRunResults{}, err
+	}
+	changes, err := c.addUnitsChanges(args.ServiceName, args.NumUnits, args.ToMachineSpec)
+	if err != nil {
+		return params.DryRunResults{}, err
+	}
+	return params.DryRunResults{Changes: changes}, nil
+}
+
+// ServiceUpdateDryRun reports the changes ServiceUpdate would make.
+func (c *Client) ServiceUpdateDryRun(args params.ServiceUpdate) (params.DryRunResults, error) {
+	if err := c.requireRole(state.RoleWrite); err != nil {
+		return params.DryRunResults{}, err
+	}
+	changes, err := c.checkServiceUpdate(args)
+	if err != nil {
+		return params.DryRunResults{}, err
+	}
+	return params.DryRunResults{Changes: changes}, nil
+}
+
+func (c *Client) checkServiceDeploy(args params.ServiceDeploy) ([]string, error) {
+	if err := c.api.state.ValidateNewService(args.ServiceName); err != nil {
+		return nil, err
+	}
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return nil, err
+	}
+	ch, changes, err := c.dryRunCharm(curl)
+	if err != nil {
+		return nil, err
+	}
+	deployArgs := juju.DeployServiceParams{
+		ServiceName:   args.ServiceName,
+		Constraints:   args.Constraints,
+		NumUnits:      args.NumUnits,
+		ToMachineSpec: args.ToMachineSpec,
+	}
+	if ch != nil {
+		deployArgs.Charm = ch
+		if deployArgs.ConfigSettings, err = deploySettings(ch, args); err != nil {
+			return nil, err
+		}
+	}
+	settings, err := juju.CheckDeployService(deployArgs)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, fmt.Sprintf("add service %s using charm %s", args.ServiceName, curl))
+	if len(settings) > 0 {
+		changes = append(changes, settingsChanges(args.ServiceName, nil, settings)...)
+	} else if ch == nil && (args.ConfigYAML != "" || len(args.Config) > 0) {
+		// The charm has not been added, so the settings can only
+		// be checked once it has.
+		changes = append(changes, fmt.Sprintf("set service %s options", args.ServiceName))
+	}
+	if !constraints.IsEmpty(&args.Constraints) {
+		consChanges, err := c.checkConstraints(args.ServiceName, args.Constraints)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, consChanges...)
+	}
+	if args.NumUnits > 0 {
+		unitChanges, err := c.addUnitsChanges(args.ServiceName, args.NumUnits, args.ToMachineSpec)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, unitChanges...)
+	}
+	return changes, nil
+}
+
+func (c *Client) checkServiceUpdate(args params.ServiceUpdate) ([]string, error) {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	var changes []string
+	stateCharm, _, err := service.Charm()
+	if err != nil {
+		return nil, err
+	}
+	ch := stateCharm
+	if args.CharmUrl != "" {
+		curl, err := charm.ParseURL(args.CharmUrl)
+		if err != nil {
+			return nil, err
+		}
+		var charmChanges []string
+		ch, charmChanges, err = c.dryRunCharm(curl)
+		if err != nil {
+			return nil, err
+		}
+		if ch != nil {
+			if err := service.ValidateCharm(ch); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, charmChanges...)
+		change := fmt.Sprintf("upgrade service %s from charm %s to %s", service, stateCharm.URL(), curl)
+		if args.ForceCharmUrl {
+			change += ", including units in an error state"
+		}
+		changes = append(changes, change)
+	}
+	if args.MinUnits != nil {
+		if err := service.ValidateMinUnits(*args.MinUnits); err != nil {
+			return nil, err
+		}
+		changes = append(changes, fmt.Sprintf("set service %s minimum units to %d", service, *args.MinUnits))
+	}
+	if args.SettingsYAML != "" || len(args.SettingsStrings) > 0 {
+		if ch == nil {
+			// The new charm has not been added, so the settings
+			// can only be checked once it has.
+			changes = append(changes, fmt.Sprintf("set service %s options", service))
+		} else {
+			settings, err := updateSettings(ch, service.Name(), args)
+			if err != nil {
+				return nil, err
+			}
+			current, err := service.ConfigSettings()
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, settingsChanges(service.Name(), current, settings)...)
+		}
+	}
+	if args.Constraints != nil {
+		if !service.IsPrincipal() {
+			return nil, state.ErrSubordinateConstraints
+		}
+		consChanges, err := c.checkConstraints(service.Name(), *args.Constraints)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, consChanges...)
+	}
+	return changes, nil
+}
+
+// dryRunCharm returns the charm with the given URL, for checking the
+// calls that use it. Charms that have not been added to the environment
+// are not fetched, so that a dry run never downloads one; adding them is
+// reported as a change, and no charm is returned for them.
+func (c *Client) dryRunCharm(curl *charm.URL) (*state.Charm, []string, error) {
+	if curl.Revision < 0 {
+		return nil, nil, fmt.Errorf("charm url must include revision")
+	}
+	ch, err := c.api.state.Charm(curl)
+	if err == nil {
+		return ch, nil, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	switch curl.Schema {
+	case "cs", "local":
+		return nil, []string{fmt.Sprintf("add charm %s", curl)}, nil
+	}
+	return nil, nil, fmt.Errorf(`charm url has unsupported schema %q`, curl.Schema)
+}
+
+// checkConstraints returns the change setting the given constraints on
+// the service, as SetConstraints would make it. As there, constraints
+// the environment does not support are ignored, with a warning.
+func (c *Client) checkConstraints(serviceName string, cons constraints.Value) ([]string, error) {
+	unsupported, err := c.api.state.ValidateConstraints(cons)
+	var changes []string
+	if len(unsupported) > 0 {
+		changes = append(changes, fmt.Sprintf("ignore unsupported constraints: %s", strings.Join(unsupported, ",")))
+	} else if err != nil {
+		return nil, err
+	}
+	return append(changes, fmt.Sprintf("set service %s constraints %q", serviceName, cons)), nil
+}
+
+// addUnitsChanges returns the changes adding the given number of units
+// to the service would make, checking their placement as juju.AddUnits
+// does.
+func (c *Client) addUnitsChanges(serviceName string, n int, machineSpec string) ([]string, error) {
+	placement, err := juju.CheckAddUnits(c.api.state, serviceName, n, machineSpec)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case placement == nil:
+		return []string{fmt.Sprintf("add %d unit(s) of service %s", n, serviceName)}, nil
+	case placement.ContainerType == "":
+		return []string{fmt.Sprintf("add 1 unit(s) of service %s to machine %s", serviceName, placement.Machine.Id())}, nil
+	}
+	return []string{
+		fmt.Sprintf("add %s container on machine %s", placement.ContainerType, placement.Machine.Id()),
+		fmt.Sprintf("add 1 unit(s) of service %s to the new container", serviceName),
+	}, nil
+}
+
+// settingsChanges returns the changes setting the service's options to
+// the given settings would make, ignoring those that are unchanged.
+// Nil values unset options.
+func settingsChanges(serviceName string, current, settings charm.Settings) []string {
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var changes []string
+	for _, key := range keys {
+		value, old := settings[key], current[key]
+		switch {
+		case reflect.DeepEqual(value, old):
+		case value == nil:
+			changes = append(changes, fmt.Sprintf("unset service %s option %s", serviceName, key))
+		default:
+			changes = append(changes, fmt.Sprintf("set service %s option %s to %#v", serviceName, key, value))
+		}
+	}
+	return changes
+}
@@ -0,0 +1,270 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/charm"
+	charmtesting "github.com/juju/juju/charm/testing"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+type dryRunSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&dryRunSuite{})
+
+func (s *dryRunSuite) TestServiceDeployDryRun(c *gc.C) {
+	// The charm is not in the store, as a dry run does not fetch it.
+	_, restore := makeMockCharmStore()
+	defer restore()
+	curl := charm.MustParseURL("cs:precise/dummy-1")
+	changes, err := s.APIState.Client().ServiceDeployDryRun(
+		curl.String(), "service", 2, "service:\n  title: foo\n", constraints.MustParse("mem=4G"), "",
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add charm cs:precise/dummy-1",
+		"add service service using charm cs:precise/dummy-1",
+		"set service service options",
+		`set service service constraints "mem=4096M"`,
+		"add 2 unit(s) of service service",
+	})
+
+	// Nothing was changed.
+	_, err = s.State.Service("service")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Charm(curl)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *dryRunSuite) TestServiceDeployDryRunCharmInState(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	changes, err := s.APIState.Client().ServiceDeployDryRun(
+		ch.URL().String(), "service", 1, "service:\n  title: foo\n", constraints.Value{}, "",
+	)
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add service service using charm local:quantal/dummy-1",
+		`set service service option title to "foo"`,
+		"add 1 unit(s) of service service",
+	})
+}
+
+func (s *dryRunSuite) TestServiceDeployDryRunErrors(c *gc.C) {
+	dummy := s.AddTestingCharm(c, "dummy").URL()
+	logging := s.AddTestingCharm(c, "logging").URL()
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	for i, test := range []struct {
+		about       string
+		charm       string
+		service     string
+		numUnits    int
+		config      string
+		machineSpec string
+		err         string
+	}{{
+		about:    "invalid service name",
+		charm:    dummy.String(),
+		service:  "haha/borken",
+		numUnits: 1,
+		err:      `cannot add service "haha/borken": invalid name`,
+	}, {
+		about:    "existing service",
+		charm:    dummy.String(),
+		service:  "wordpress",
+		numUnits: 1,
+		err:      `cannot add service "wordpress": service already exists`,
+	}, {
+		about:    "charm without revision",
+		charm:    "cs:precise/dummy",
+		service:  "service",
+		numUnits: 1,
+		err:      "charm url must include revision",
+	}, {
+		about:    "subordinate with units",
+		charm:    logging.String(),
+		service:  "service",
+		numUnits: 1,
+		err:      "subordinate service must be deployed without units",
+	}, {
+		about:    "invalid config",
+		charm:    dummy.String(),
+		service:  "service",
+		numUnits: 1,
+		config:   "service:\n  skill-level: foo\n",
+		err:      `option "skill-level" expected int, got "foo"`,
+	}, {
+		about:       "unknown machine",
+		charm:       dummy.String(),
+		service:     "service",
+		numUnits:    1,
+		machineSpec: "42",
+		err:         `cannot assign unit to machine: machine 42 not found`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.APIState.Client().ServiceDeployDryRun(
+			test.charm, test.service, test.numUnits, test.config, constraints.Value{}, test.machineSpec,
+		)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *dryRunSuite) TestAddServiceUnitsDryRun(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	changes, err := s.APIState.Client().AddServiceUnitsDryRun("wordpress", 3, "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{"add 3 unit(s) of service wordpress"})
+
+	changes, err = s.APIState.Client().AddServiceUnitsDryRun("wordpress", 1, m.Id())
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{"add 1 unit(s) of service wordpress to machine " + m.Id()})
+
+	changes, err = s.APIState.Client().AddServiceUnitsDryRun("wordpress", 1, "lxc:"+m.Id())
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add lxc container on machine " + m.Id(),
+		"add 1 unit(s) of service wordpress to the new container",
+	})
+
+	// Nothing was changed.
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, gc.IsNil)
+	units, err := wordpress.AllUnits()
+	c.Assert(err, gc.IsNil)
+	c.Assert(units, gc.HasLen, 0)
+	containers, err := m.Containers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *dryRunSuite) TestAddServiceUnitsDryRunErrors(c *gc.C) {
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	_, err := s.APIState.Client().AddServiceUnitsDryRun("unknown", 1, "")
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+	_, err = s.APIState.Client().AddServiceUnitsDryRun("logging", 1, "")
+	c.Assert(err, gc.ErrorMatches, `cannot add units to subordinate service "logging"`)
+	_, err = s.APIState.Client().AddServiceUnitsDryRun("wordpress", 2, "0")
+	c.Assert(err, gc.ErrorMatches, "cannot use NumUnits with ToMachineSpec")
+	_, err = s.APIState.Client().AddServiceUnitsDryRun("wordpress", 1, "lxc:42")
+	c.Assert(err, gc.ErrorMatches, "cannot assign unit to machine: machine 42 not found")
+
+	// The real call fails in the same way.
+	_, err = s.APIState.Client().AddServiceUnits("logging", 1, "")
+	c.Assert(err, gc.ErrorMatches, `cannot add units to subordinate service "logging"`)
+	_, err = s.APIState.Client().AddServiceUnits("wordpress", 1, "lxc:42")
+	c.Assert(err, gc.ErrorMatches, "cannot assign unit to machine: machine 42 not found")
+}
+
+func (s *dryRunSuite) TestServiceUpdateDryRun(c *gc.C) {
+	// The charm is not in the store, as a dry run does not fetch it.
+	_, restore := makeMockCharmStore()
+	defer restore()
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	changes, err := s.APIState.Client().ServiceUpdateDryRun(params.ServiceUpdate{
+		ServiceName:     "wordpress",
+		CharmUrl:        "cs:quantal/wordpress-3",
+		SettingsStrings: map[string]string{"blog-title": "Bloggery"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add charm cs:quantal/wordpress-3",
+		"upgrade service wordpress from charm local:quantal/wordpress-3 to cs:quantal/wordpress-3",
+		"set service wordpress options",
+	})
+
+	// With the service's own charm, the settings are checked, and
+	// those that would not change are not reported. Empty values
+	// unset options, as they do in ServiceUpdate.
+	err = wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Bloggery"})
+	c.Assert(err, gc.IsNil)
+	changes, err = s.APIState.Client().ServiceUpdateDryRun(params.ServiceUpdate{
+		ServiceName:     "wordpress",
+		SettingsStrings: map[string]string{"blog-title": "Bloggery"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, gc.HasLen, 0)
+	changes, err = s.APIState.Client().ServiceUpdateDryRun(params.ServiceUpdate{
+		ServiceName:     "wordpress",
+		SettingsStrings: map[string]string{"blog-title": ""},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(changes, jc.DeepEquals, []string{"unset service wordpress option blog-title"})
+
+	// Nothing was changed.
+	err = wordpress.Refresh()
+	c.Assert(err, gc.IsNil)
+	current, _ := wordpress.CharmURL()
+	c.Assert(current.String(), gc.Equals, "local:quantal/wordpress-3")
+}
+
+func (s *dryRunSuite) TestServiceUpdateDryRunErrors(c *gc.C) {
+	s.setUpScenario(c)
+	mysql := charm.MustParseURL("local:quantal/mysql-1")
+	logging := charm.MustParseURL("local:quantal/logging-1")
+	precise := charm.MustParseURL("local:precise/wordpress-3")
+	bundleURL, err := url.Parse("http://bundles.testing.invalid/precise-wordpress-3")
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddCharm(charmtesting.Charms.Dir("wordpress"), precise, bundleURL, "precise-wordpress-3-sha256")
+	c.Assert(err, gc.IsNil)
+	minUnits := -1
+
+	for i, test := range []struct {
+		about string
+		args  params.ServiceUpdate
+		err   string
+	}{{
+		about: "unknown service",
+		args:  params.ServiceUpdate{ServiceName: "unknown"},
+		err:   `service "unknown" not found`,
+	}, {
+		about: "relation broken by upgrade",
+		args:  params.ServiceUpdate{ServiceName: "wordpress", CharmUrl: mysql.String()},
+		err:   `cannot upgrade service "wordpress" to charm "local:quantal/mysql-1": would break relation .*`,
+	}, {
+		about: "subordinacy changed by upgrade",
+		args:  params.ServiceUpdate{ServiceName: "wordpress", CharmUrl: logging.String()},
+		err:   "cannot change a service's subordinacy",
+	}, {
+		about: "series changed by upgrade",
+		args:  params.ServiceUpdate{ServiceName: "wordpress", CharmUrl: precise.String()},
+		err:   "cannot change a service's series",
+	}, {
+		about: "negative minimum units",
+		args:  params.ServiceUpdate{ServiceName: "wordpress", MinUnits: &minUnits},
+		err:   `cannot set minimum units for service "wordpress": cannot set a negative minimum number of units`,
+	}, {
+		about: "unknown option",
+		args: params.ServiceUpdate{
+			ServiceName:     "wordpress",
+			SettingsStrings: map[string]string{"no-such-option": "foo"},
+		},
+		err: `unknown option "no-such-option"`,
+	}, {
+		about: "constraints on subordinate",
+		args: params.ServiceUpdate{
+			ServiceName: "logging",
+			Constraints: &constraints.Value{},
+		},
+		err: "constraints do not apply to subordinate services",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.APIState.Client().ServiceUpdateDryRun(test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+)
+
+// statusFilter selects the machines, services and units reported by
+// FullStatus. Each pattern given to the filter restricts one attribute
+// of the units reported; a unit must satisfy every kind of restriction
+// given, and at least one pattern of each kind. The machines hosting
+// the units, and the units' principals and subordinates, are reported
+// along with them.
+type statusFilter struct {
+	// names holds service and unit name patterns.
+	names unitMatcher
+	// machines holds machine id patterns; a unit matches if it is on
+	// a machine matching one of them, or on a container within one.
+	machines []string
+	// states holds agent states.
+	states []params.Status
+	// exposed holds the exposed flags of the services of the units.
+	exposed []bool
+	// charms holds patterns to match against service charm URLs.
+	charms []string
+	// networks holds the names of networks the units' services or the
+	// machines must be on.
+	networks []string
+}
+
+var (
+	validMachinePart   = regexp.MustCompile("^[0-9*]+$")
+	validContainerPart = regexp.MustCompile("^[a-z*]+$")
+	validStates        = []params.Status{
+		params.StatusPending,
+		params.StatusInstalled,
+		params.StatusStarted,
+		params.StatusStopped,
+		params.StatusError,
+		params.StatusDown,
+	}
+)
+
+// NewStatusFilter returns a statusFilter built from the given patterns,
+// which select everything if there are none. A pattern may be:
+//
+//   - a service or unit name, optionally with wildcards ('*');
+//   - a machine id, optionally with wildcards, such as "1/lxc/*";
+//   - agent-state=<state>, such as agent-state=error;
+//   - exposed=<true|false>;
+//   - charm=<charm URL>, optionally with wildcards;
+//   - network=<network name>;
+//   - machine=<machine id>, equivalent to the machine id alone.
+//
+// An error is returned if any of the patterns is invalid.
+func NewStatusFilter(patterns []string) (*statusFilter, error) {
+	f := &statusFilter{}
+	var names []string
+	for _, pattern := range patterns {
+		if i := strings.Index(pattern, "="); i >= 0 {
+			if err := f.addAttribute(pattern[:i], pattern[i+1:]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if pattern != "" && strings.IndexAny(pattern[:1], "0123456789") == 0 {
+			if err := f.addMachine(pattern); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		names = append(names, pattern)
+	}
+	matcher, err := NewUnitMatcher(names)
+	if err != nil {
+		return nil, err
+	}
+	f.names = matcher
+	return f, nil
+}
+
+func (f *statusFilter) addMachine(pattern string) error {
+	parts := strings.Split(pattern, "/")
+	if len(parts)%2 == 0 {
+		return fmt.Errorf("pattern %q is not a valid machine id", pattern)
+	}
+	for i, part := range parts {
+		valid := validMachinePart
+		if i%2 == 1 {
+			valid = validContainerPart
+		}
+		if !valid.MatchString(part) {
+			return fmt.Errorf("pattern %q is not a valid machine id", pattern)
+		}
+	}
+	f.machines = append(f.machines, pattern)
+	return nil
+}
+
+func (f *statusFilter) addAttribute(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("no value specified for %q", key)
+	}
+	switch key {
+	case "machine":
+		return f.addMachine(value)
+	case "agent-state":
+		for _, status := range validStates {
+			if params.Status(value) == status {
+				f.states = append(f.states, status)
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown agent state %q", value)
+	case "exposed":
+		exposed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for exposed: must be true or false", value)
+		}
+		f.exposed = append(f.exposed, exposed)
+	case "charm":
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("pattern %q contains invalid characters", value)
+		}
+		f.charms = append(f.charms, value)
+	case "network":
+		f.networks = append(f.networks, value)
+	default:
+		return fmt.Errorf("unknown status filter %q", key)
+	}
+	return nil
+}
+
+// matchesAny returns whether the filter selects everything.
+func (f *statusFilter) matchesAny() bool {
+	return f.names.matchesAny() && !f.filtersMachines() &&
+		len(f.exposed) == 0 && len(f.charms) == 0
+}
+
+// filtersMachines returns whether the filter includes restrictions that
+// apply to machines as well as to units.
+func (f *statusFilter) filtersMachines() bool {
+	return len(f.machines) > 0 || len(f.states) > 0 || len(f.networks) > 0
+}
+
+// selectsMachines returns whether machines may be selected by the
+// filter in their own right, and not only because they host matching
+// units. This is the case when the only restrictions given apply to
+// machines as well as units.
+func (f *statusFilter) selectsMachines() bool {
+	return f.filtersMachines() && f.names.matchesAny() &&
+		len(f.exposed) == 0 && len(f.charms) == 0
+}
+
+// unitInfo holds what the filter needs to know about a unit.
+type unitInfo struct {
+	unit      *state.Unit
+	service   *state.Service
+	machineId string
+}
+
+// matchUnit returns whether the unit itself, as opposed to one of its
+// principals or subordinates, satisfies the filter.
+func (f *statusFilter) matchUnit(info unitInfo) (bool, error) {
+	if !f.names.matchesAny() && !f.names.matchString(info.unit.Name()) {
+		return false, nil
+	}
+	if len(f.machines) > 0 && !f.matchMachineId(info.machineId) {
+		return false, nil
+	}
+	if len(f.states) > 0 {
+		_, status, _ := processAgent(info.unit)
+		if !f.matchState(status) {
+			return false, nil
+		}
+	}
+	if len(f.exposed) > 0 {
+		var matched bool
+		for _, exposed := range f.exposed {
+			if exposed == info.service.IsExposed() {
+				matched = true
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if len(f.charms) > 0 {
+		curl, _ := info.service.CharmURL()
+		if !matchAny(f.charms, curl.String()) {
+			return false, nil
+		}
+	}
+	if len(f.networks) > 0 {
+		networks, err := info.service.Networks()
+		if err != nil {
+			return false, err
+		}
+		if !f.matchNetworks(networks) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchMachine returns whether the machine satisfies the restrictions
+// of the filter that apply to machines.
+func (f *statusFilter) matchMachine(m *state.Machine) (bool, error) {
+	if len(f.machines) > 0 && !f.matchMachineId(m.Id()) {
+		return false, nil
+	}
+	if len(f.states) > 0 {
+		_, status, _ := processAgent(m)
+		if !f.matchState(status) {
+			return false, nil
+		}
+	}
+	if len(f.networks) > 0 {
+		networks, err := m.RequestedNetworks()
+		if err != nil {
+			return false, err
+		}
+		if !f.matchNetworks(networks) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchMachineId returns whether the machine id, or that of one of the
+// machines it is a container within, matches one of the filter's
+// machine patterns.
+func (f *statusFilter) matchMachineId(id string) bool {
+	for ; id != ""; id = state.ParentId(id) {
+		if matchAny(f.machines, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *statusFilter) matchState(status params.Status) bool {
+	for _, s := range f.states {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *statusFilter) matchNetworks(networks []string) bool {
+	for _, name := range networks {
+		for _, wanted := range f.networks {
+			if name == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAny returns whether s matches any of the given patterns, which
+// must have been validated.
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filterUnits returns the units selected by the filter from those
+// given, keyed by unit name. A unit is selected if it matches the
+// filter itself, if it's a principal and one of its subordinates
+// matches, or if it's a subordinate and its principal matches.
+//
+// Note: a second subordinate is *not* selected if the principal is
+// only selected on account of a first subordinate matching.
+func (f *statusFilter) filterUnits(units map[string]unitInfo) (map[string]*state.Unit, error) {
+	matched := make(map[string]bool)
+	for name, info := range units {
+		ok, err := f.matchUnit(info)
+		if err != nil {
+			return nil, err
+		}
+		matched[name] = ok
+	}
+	selected := make(map[string]*state.Unit)
+	for name, info := range units {
+		u := info.unit
+		keep := matched[name]
+		if !keep && u.IsPrincipal() {
+			for _, sub := range u.SubordinateNames() {
+				if matched[sub] {
+					keep = true
+					break
+				}
+			}
+		} else if !keep {
+			principal, _ := u.PrincipalName()
+			keep = matched[principal]
+		}
+		if keep {
+			selected[name] = u
+		}
+	}
+	return selected, nil
+}
+
+// fetchMatchingMachineIds adds to machineIds the ids of the machines
+// that match the filter in their own right, and those of the machines
+// they are containers within.
+func fetchMatchingMachineIds(st *state.State, f *statusFilter, machineIds *set.Strings) error {
+	if !f.selectsMachines() {
+		return nil
+	}
+	machines, err := st.AllMachines()
+	if err != nil {
+		return err
+	}
+	for _, m := range machines {
+		ok, err := f.matchMachine(m)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for id := m.Id(); id != ""; id = state.ParentId(id) {
+			machineIds.Add(id)
+		}
+	}
+	return nil
+}
@@ -6,11 +6,15 @@ package client
 import (
 	"github.com/juju/juju/charm"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 )
 
 // ServiceGet returns the configuration for a service.
 func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.ServiceGetResults{}, err
+	}
 	service, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return params.ServiceGetResults{}, err
@@ -62,6 +66,9 @@ func describe(settings charm.Settings, config *charm.Config) map[string]interfac
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(args params.ServiceGet) (params.StringResult, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return params.StringResult{}, err
+	}
 	service, err := c.api.state.Service(args.ServiceName)
 	if err != nil {
 		return params.StringResult{}, err
@@ -39,7 +39,7 @@ var operationPermTests = []struct {
 }{{
 	about: "Client.Status",
 	op:    opClientStatus,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.ServiceSet",
 	op:    opClientServiceSet,
@@ -51,7 +51,7 @@ var operationPermTests = []struct {
 }, {
 	about: "Client.ServiceGet",
 	op:    opClientServiceGet,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.Resolved",
 	op:    opClientResolved,
@@ -83,7 +83,7 @@ var operationPermTests = []struct {
 }, {
 	about: "Client.GetAnnotations",
 	op:    opClientGetAnnotations,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.SetAnnotations",
 	op:    opClientSetAnnotations,
@@ -103,7 +103,7 @@ var operationPermTests = []struct {
 }, {
 	about: "Client.GetServiceConstraints",
 	op:    opClientGetServiceConstraints,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.SetServiceConstraints",
 	op:    opClientSetServiceConstraints,
@@ -115,23 +115,23 @@ var operationPermTests = []struct {
 }, {
 	about: "Client.EnvironmentGet",
 	op:    opClientEnvironmentGet,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.EnvironmentSet",
 	op:    opClientEnvironmentSet,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin"},
 }, {
 	about: "Client.SetEnvironAgentVersion",
 	op:    opClientSetEnvironAgentVersion,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin"},
 }, {
 	about: "Client.WatchAll",
 	op:    opClientWatchAll,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.CharmInfo",
 	op:    opClientCharmInfo,
-	allow: []string{"user-admin", "user-other"},
+	allow: []string{"user-admin", "user-other", "user-reader"},
 }, {
 	about: "Client.AddRelation",
 	op:    opClientAddRelation,
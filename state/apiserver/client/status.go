@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/replicaset"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api"
 	"github.com/juju/juju/state/api/params"
@@ -24,28 +26,34 @@ import (
 
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
+	if err := c.requireRole(state.RoleRead); err != nil {
+		return api.Status{}, err
+	}
 	conn, err := juju.NewConnFromState(c.api.state)
 	if err != nil {
 		return api.Status{}, err
 	}
 	var noStatus api.Status
 	var context statusContext
-	unitMatcher, err := NewUnitMatcher(args.Patterns)
+	filter, err := NewStatusFilter(args.Patterns)
 	if err != nil {
 		return noStatus, err
 	}
 	if context.services,
-		context.units, context.latestCharms, err = fetchAllServicesAndUnits(conn.State, unitMatcher); err != nil {
+		context.units, context.latestCharms, err = fetchAllServicesAndUnits(conn.State, filter); err != nil {
 		return noStatus, err
 	}
 
-	// Filter machines by units in scope.
+	// Filter machines by units in scope, and by the filter itself.
 	var machineIds *set.Strings
-	if !unitMatcher.matchesAny() {
+	if !filter.matchesAny() {
 		machineIds, err = fetchUnitMachineIds(context.units)
 		if err != nil {
 			return noStatus, err
 		}
+		if err := fetchMatchingMachineIds(conn.State, filter, machineIds); err != nil {
+			return noStatus, err
+		}
 	}
 	if context.machines, err = fetchMachines(conn.State, machineIds); err != nil {
 		return noStatus, err
@@ -56,6 +64,15 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	if context.networks, err = fetchNetworks(conn.State); err != nil {
 		return noStatus, err
 	}
+	backups, err := fetchBackupsStatus(conn.State)
+	if err != nil {
+		return noStatus, err
+	}
+	// The replica set status is only informative, so the status is
+	// still returned without it.
+	if context.stateServerMembers, err = fetchStateServerMembers(conn.State); err != nil {
+		logger.Warningf("cannot get state server replica set status: %v", err)
+	}
 
 	return api.Status{
 		EnvironmentName: conn.Environ.Name(),
@@ -63,9 +80,82 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 		Services:        context.processServices(),
 		Networks:        context.processNetworks(),
 		Relations:       context.processRelations(),
+		Backups:         backups,
 	}, nil
 }
 
+// fetchBackupsStatus returns the outcome of the most recent scheduled
+// backups, or nil if none has been attempted.
+func fetchBackupsStatus(st *state.State) (*api.BackupsStatus, error) {
+	status, err := st.ScheduledBackupsStatus()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &api.BackupsStatus{
+		LastSuccess:  status.LastSuccess,
+		LastBackupId: status.LastBackupId,
+		LastFailure:  status.LastFailure,
+		LastError:    status.LastError,
+	}, nil
+}
+
+// jujuMachineTag is the key of the replica set member tag holding the
+// id of the member's machine, as set by the peergrouper worker.
+const jujuMachineTag = "juju-machine-id"
+
+// fetchStateServerMembers returns the status of the members of the state
+// server replica set, keyed by machine id. It is a variable so that
+// tests, which do not run a replica set, can replace it.
+var fetchStateServerMembers = func(st *state.State) (map[string]*api.StateServerMemberStatus, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	members, err := replicaset.CurrentMembers(session)
+	if err != nil {
+		return nil, err
+	}
+	status, err := replicaset.CurrentStatus(session)
+	if err != nil {
+		return nil, err
+	}
+	return stateServerMembers(members, status), nil
+}
+
+// stateServerMembers returns the status of the given replica set members,
+// keyed by machine id.
+func stateServerMembers(members []replicaset.Member, status *replicaset.Status) map[string]*api.StateServerMemberStatus {
+	machineIds := make(map[int]string)
+	for _, member := range members {
+		if id, ok := member.Tags[jujuMachineTag]; ok {
+			machineIds[member.Id] = id
+		}
+	}
+	var primaryOpTime time.Time
+	for _, member := range status.Members {
+		if member.State == replicaset.PrimaryState {
+			primaryOpTime = member.OpTime
+		}
+	}
+	result := make(map[string]*api.StateServerMemberStatus)
+	for _, member := range status.Members {
+		id, ok := machineIds[member.Id]
+		if !ok {
+			continue
+		}
+		memberStatus := &api.StateServerMemberStatus{
+			State:   member.State.String(),
+			Healthy: member.Healthy,
+			Error:   member.ErrMsg,
+		}
+		if !primaryOpTime.IsZero() && member.OpTime.Before(primaryOpTime) {
+			memberStatus.Lag = primaryOpTime.Sub(member.OpTime)
+		}
+		result[id] = memberStatus
+	}
+	return result
+}
+
 // Status is a stub version of FullStatus that was introduced in 1.16
 func (c *Client) Status() (api.LegacyStatus, error) {
 	var legacyStatus api.LegacyStatus
@@ -90,6 +180,9 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// stateServerMembers holds the status of the state server replica
+	// set members, keyed by machine id.
+	stateServerMembers map[string]*api.StateServerMemberStatus
 }
 
 type unitMatcher struct {
@@ -102,39 +195,6 @@ func (m unitMatcher) matchesAny() bool {
 	return len(m.patterns) == 0
 }
 
-// matchUnit attempts to match a state.Unit to one of
-// a set of patterns, taking into account subordinate
-// relationships.
-func (m unitMatcher) matchUnit(u *state.Unit) bool {
-	if m.matchesAny() {
-		return true
-	}
-
-	// Keep the unit if:
-	//  (a) its name matches a pattern, or
-	//  (b) it's a principal and one of its subordinates matches, or
-	//  (c) it's a subordinate and its principal matches.
-	//
-	// Note: do *not* include a second subordinate if the principal is
-	// only matched on account of a first subordinate matching.
-	if m.matchString(u.Name()) {
-		return true
-	}
-	if u.IsPrincipal() {
-		for _, s := range u.SubordinateNames() {
-			if m.matchString(s) {
-				return true
-			}
-		}
-		return false
-	}
-	principal, valid := u.PrincipalName()
-	if !valid {
-		panic("PrincipalName failed for subordinate unit")
-	}
-	return m.matchString(principal)
-}
-
 // matchString matches a string to one of the patterns in
 // the unit matcher, returning an error if a pattern with
 // invalid syntax is encountered.
@@ -214,9 +274,10 @@ func fetchMachines(st *state.State, machineIds *set.Strings) (map[string][]*stat
 }
 
 // fetchAllServicesAndUnits returns a map from service name to service,
-// a map from service name to unit name to unit, and a map from base charm URL to latest URL.
+// a map from service name to unit name to unit, and a map from base charm URL to latest URL,
+// including only the units selected by the filter and the services they belong to.
 func fetchAllServicesAndUnits(
-	st *state.State, unitMatcher unitMatcher) (
+	st *state.State, filter *statusFilter) (
 	map[string]*state.Service, map[string]map[string]*state.Unit, map[charm.URL]string, error) {
 
 	svcMap := make(map[string]*state.Service)
@@ -226,19 +287,39 @@ func fetchAllServicesAndUnits(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	allUnits := make(map[string]unitInfo)
 	for _, s := range services {
 		units, err := s.AllUnits()
 		if err != nil {
 			return nil, nil, nil, err
 		}
-		svcUnitMap := make(map[string]*state.Unit)
 		for _, u := range units {
-			if !unitMatcher.matchUnit(u) {
-				continue
+			allUnits[u.Name()] = unitInfo{unit: u, service: s}
+		}
+	}
+	selected := make(map[string]*state.Unit)
+	if filter.matchesAny() {
+		for name, info := range allUnits {
+			selected[name] = info.unit
+		}
+	} else {
+		if len(filter.machines) > 0 {
+			if err := fillUnitMachineIds(allUnits); err != nil {
+				return nil, nil, nil, err
 			}
-			svcUnitMap[u.Name()] = u
 		}
-		if unitMatcher.matchesAny() || len(svcUnitMap) > 0 {
+		if selected, err = filter.filterUnits(allUnits); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for _, s := range services {
+		svcUnitMap := make(map[string]*state.Unit)
+		for name, u := range selected {
+			if allUnits[name].service == s {
+				svcUnitMap[name] = u
+			}
+		}
+		if filter.matchesAny() || len(svcUnitMap) > 0 {
 			unitMap[s.Name()] = svcUnitMap
 			svcMap[s.Name()] = s
 			// Record the base URL for the service's charm so that
@@ -262,6 +343,30 @@ func fetchAllServicesAndUnits(
 	return svcMap, unitMap, latestCharms, nil
 }
 
+// fillUnitMachineIds records in each unitInfo the id of the machine the
+// unit is assigned to; subordinate units are on their principal's
+// machine. Units that are not yet assigned are left without a machine.
+func fillUnitMachineIds(units map[string]unitInfo) error {
+	for name, info := range units {
+		if !info.unit.IsPrincipal() {
+			continue
+		}
+		mid, err := info.unit.AssignedMachineId()
+		if err != nil && !state.IsNotAssigned(err) {
+			return err
+		}
+		info.machineId = mid
+		units[name] = info
+	}
+	for name, info := range units {
+		if principal, ok := info.unit.PrincipalName(); ok {
+			info.machineId = units[principal].machineId
+			units[name] = info
+		}
+	}
+	return nil
+}
+
 // fetchUnitMachineIds returns a set of IDs for machines that
 // the specified units reside on, and those machines' ancestors.
 func fetchUnitMachineIds(units map[string]map[string]*state.Unit) (*set.Strings, error) {
@@ -359,6 +464,7 @@ func (context *statusContext) makeMachineStatus(machine *state.Machine) (status
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	status.StateServerMember = context.stateServerMembers[machine.Id()]
 	instid, err := machine.InstanceId()
 	if err == nil {
 		status.InstanceId = instid
@@ -549,6 +655,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		status.Charm = curl.String()
 	}
 	status.Agent, status.AgentState, status.AgentStateInfo = processAgent(unit)
+	status.Workload.Status, status.Workload.Info, status.Workload.Err = unit.WorkloadStatus()
 	status.AgentVersion = status.Agent.Version
 	status.Life = status.Agent.Life
 	status.Err = status.Agent.Err
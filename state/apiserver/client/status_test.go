@@ -4,10 +4,15 @@
 package client_test
 
 import (
+	"time"
+
 	gc "launchpad.net/gocheck"
 
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/replicaset"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api"
+	"github.com/juju/juju/state/apiserver/client"
 )
 
 type statusSuite struct {
@@ -57,3 +62,48 @@ func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	}
 	c.Check(resultMachine.InstanceId, gc.Equals, instanceId)
 }
+
+func (s *statusSuite) TestFullStatusStateServerMember(c *gc.C) {
+	machine := s.addMachine(c)
+	member := &api.StateServerMemberStatus{State: "PRIMARY", Healthy: true}
+	s.PatchValue(client.FetchStateServerMembers, func(*state.State) (map[string]*api.StateServerMemberStatus, error) {
+		return map[string]*api.StateServerMemberStatus{machine.Id(): member}, nil
+	})
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.Machines[machine.Id()].StateServerMember, gc.DeepEquals, member)
+}
+
+func (s *statusSuite) TestStateServerMembers(c *gc.C) {
+	primaryTime := time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+	members := []replicaset.Member{{
+		Id:   1,
+		Tags: map[string]string{"juju-machine-id": "0"},
+	}, {
+		Id:   2,
+		Tags: map[string]string{"juju-machine-id": "1"},
+	}, {
+		Id: 3,
+	}}
+	status := &replicaset.Status{
+		Members: []replicaset.MemberStatus{{
+			Id:      1,
+			Healthy: true,
+			State:   replicaset.PrimaryState,
+			OpTime:  primaryTime,
+		}, {
+			Id:      2,
+			Healthy: false,
+			State:   replicaset.SecondaryState,
+			OpTime:  primaryTime.Add(-5 * time.Second),
+			ErrMsg:  "still syncing",
+		}, {
+			Id:    3,
+			State: replicaset.SecondaryState,
+		}},
+	}
+	c.Assert(client.StateServerMembers(members, status), gc.DeepEquals, map[string]*api.StateServerMemberStatus{
+		"0": {State: "PRIMARY", Healthy: true},
+		"1": {State: "SECONDARY", Lag: 5 * time.Second, Error: "still syncing"},
+	})
+}
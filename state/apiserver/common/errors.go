@@ -78,6 +78,7 @@ var singletonErrorCodes = map[error]string{
 	state.ErrCannotEnterScope:    params.CodeCannotEnterScope,
 	state.ErrExcessiveContention: params.CodeExcessiveContention,
 	state.ErrUnitHasSubordinates: params.CodeUnitHasSubordinates,
+	state.ErrNotLeader:           params.CodeNotLeader,
 	ErrBadId:                     params.CodeNotFound,
 	ErrBadCreds:                  params.CodeUnauthorized,
 	ErrPerm:                      params.CodeUnauthorized,
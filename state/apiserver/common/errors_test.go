@@ -50,6 +50,10 @@ var errorTransformTests = []struct {
 	err:        state.ErrUnitHasSubordinates,
 	code:       params.CodeUnitHasSubordinates,
 	helperFunc: params.IsCodeUnitHasSubordinates,
+}, {
+	err:        state.ErrNotLeader,
+	code:       params.CodeNotLeader,
+	helperFunc: params.IsCodeNotLeader,
 }, {
 	err:        common.ErrBadId,
 	code:       params.CodeNotFound,
@@ -0,0 +1,49 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
+
+// AuthUserRole returns whether the authenticated entity is a client
+// user with at least the given role in the environment. The user is
+// read from the state every time, so that roles granted or revoked
+// while the user is connected take effect immediately.
+func AuthUserRole(st *state.State, authorizer Authorizer, role state.Role) (bool, error) {
+	if !authorizer.AuthClient() {
+		return false, nil
+	}
+	_, name, err := names.ParseTag(authorizer.GetAuthTag(), names.UserTagKind)
+	if err != nil {
+		return false, nil
+	}
+	user, err := st.User(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return user.Role(env.UUID()).Includes(role), nil
+}
+
+// RequireUserRole returns ErrPerm unless the authenticated entity is
+// a client user with at least the given role in the environment.
+func RequireUserRole(st *state.State, authorizer Authorizer, role state.Role) error {
+	ok, err := AuthUserRole(st, authorizer, role)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPerm
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/apiserver/common"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+)
+
+type rolesSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) TestAuthUserRole(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	user := s.AddUser(c, "bob")
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	s.AddUser(c, "nobody")
+
+	for i, test := range []struct {
+		authorizer apiservertesting.FakeAuthorizer
+		role       state.Role
+		ok         bool
+	}{{
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-admin", Client: true},
+		role:       state.RoleAdmin,
+		ok:         true,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-bob", Client: true},
+		role:       state.RoleRead,
+		ok:         true,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-bob", Client: true},
+		role:       state.RoleWrite,
+		ok:         true,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-bob", Client: true},
+		role:       state.RoleAdmin,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-nobody", Client: true},
+		role:       state.RoleRead,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "user-unknown", Client: true},
+		role:       state.RoleRead,
+	}, {
+		authorizer: apiservertesting.FakeAuthorizer{Tag: "machine-0", MachineAgent: true},
+		role:       state.RoleRead,
+	}} {
+		c.Logf("test %d: %s needs %q", i, test.authorizer.Tag, test.role)
+		ok, err := common.AuthUserRole(s.State, test.authorizer, test.role)
+		c.Check(err, gc.IsNil)
+		c.Check(ok, gc.Equals, test.ok)
+		err = common.RequireUserRole(s.State, test.authorizer, test.role)
+		if test.ok {
+			c.Check(err, gc.IsNil)
+		} else {
+			c.Check(err, gc.Equals, common.ErrPerm)
+		}
+	}
+}
+
+func (s *rolesSuite) TestAuthUserRoleFollowsRevoke(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	user := s.AddUser(c, "bob")
+	err = user.GrantRole(env.UUID(), state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	authorizer := apiservertesting.FakeAuthorizer{Tag: "user-bob", Client: true}
+	ok, err := common.AuthUserRole(s.State, authorizer, state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsTrue)
+
+	err = user.RevokeRole(env.UUID(), state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	ok, err = common.AuthUserRole(s.State, authorizer, state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, jc.IsFalse)
+}
@@ -4,6 +4,7 @@
 package apiserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,15 +12,19 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/tailer"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
 )
 
 // debugLogHandler takes requests to watch the debug log.
@@ -28,7 +33,10 @@ type debugLogHandler struct {
 	logDir string
 }
 
-var maxLinesReached = fmt.Errorf("max lines reached")
+var (
+	maxLinesReached = fmt.Errorf("max lines reached")
+	untilReached    = fmt.Errorf("until time reached")
+)
 
 // ServeHTTP will serve up connections as a websocket.
 // Args for the HTTP request are as follows:
@@ -46,11 +54,22 @@ var maxLinesReached = fmt.Errorf("max lines reached")
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   since -> string - an RFC3339 time, show only the lines logged at or after it
+//   until -> string - an RFC3339 time, show only the lines logged before it,
+//      and stop when they have been sent
+//   grep -> string - a regular expression, show only the lines whose message matches it
+//   format -> string - one of [text, json], if json, send each line as a
+//      JSON encoded params.LogMessage
+//
+// When agents do not send their logs through rsyslog, because the
+// environment has disable-rsyslog set, the log is read from the
+// database rather than from all-machines.log.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(socket *websocket.Conn) {
 			logger.Infof("debug log handler starting")
-			if err := h.authenticate(req); err != nil {
+			user, err := h.authenticate(req)
+			if err != nil {
 				h.sendError(socket, fmt.Errorf("auth failed: %v", err))
 				socket.Close()
 				return
@@ -60,12 +79,27 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				socket.Close()
 				return
 			}
+			if err := common.RequireUserRole(h.state, httpAuthorizer{user}, state.RoleRead); err != nil {
+				h.sendError(socket, err)
+				socket.Close()
+				return
+			}
 			stream, err := newLogStream(req.URL.Query())
 			if err != nil {
 				h.sendError(socket, err)
 				socket.Close()
 				return
 			}
+			cfg, err := h.state.EnvironConfig()
+			if err != nil {
+				h.sendError(socket, fmt.Errorf("cannot get environment config: %v", err))
+				socket.Close()
+				return
+			}
+			if cfg.DisableRsyslog() {
+				h.serveFromDatabase(socket, stream)
+				return
+			}
 			// Open log file.
 			logLocation := filepath.Join(h.logDir, "all-machines.log")
 			logFile, err := os.Open(logLocation)
@@ -97,7 +131,7 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				stream.tomb.Kill(stream.loop())
 			}()
 			if err := stream.tomb.Wait(); err != nil {
-				if err != maxLinesReached {
+				if err != maxLinesReached && err != untilReached {
 					logger.Errorf("debug-log handler error: %v", err)
 				}
 			}
@@ -105,6 +139,97 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server.ServeHTTP(w, req)
 }
 
+// serveFromDatabase sends the log records held in the database that
+// are selected by the stream to the socket, and closes it when done.
+func (h *debugLogHandler) serveFromDatabase(socket *websocket.Conn, stream *logStream) {
+	defer socket.Close()
+	tailer := h.state.NewLogTailer(stream.tailerParams())
+	defer tailer.Stop()
+	if err := h.sendError(socket, nil); err != nil {
+		logger.Errorf("could not send good log stream start")
+		return
+	}
+	for record := range tailer.Records() {
+		line, err := stream.formatRecord(record)
+		if err != nil {
+			logger.Errorf("cannot format log record: %v", err)
+			return
+		}
+		if _, err := socket.Write(line); err != nil {
+			// The client has gone away.
+			return
+		}
+		stream.lineCount++
+		if stream.maxLines > 0 && stream.lineCount >= stream.maxLines {
+			return
+		}
+	}
+	if err := tailer.Stop(); err != nil {
+		logger.Errorf("debug-log handler error: %v", err)
+	}
+}
+
+// formatRecord returns the record as a line in the stream's format.
+// Text lines are formatted as rsyslog writes them to all-machines.log.
+func (stream *logStream) formatRecord(record *state.LogRecord) ([]byte, error) {
+	if stream.jsonFormat {
+		return encodeLogMessage(params.LogMessage{
+			Entity:   record.Entity,
+			Time:     record.Time.UTC(),
+			Level:    record.Level.String(),
+			Module:   record.Module,
+			Location: record.Location,
+			Message:  record.Message,
+		})
+	}
+	line := fmt.Sprintf("%s: %s %s %s %s %s\n",
+		record.Entity,
+		record.Time.UTC().Format(logTimeFormat),
+		record.Level,
+		record.Module,
+		record.Location,
+		record.Message,
+	)
+	return []byte(line), nil
+}
+
+// encodeLogMessage returns the message encoded as a line of JSON.
+func encodeLogMessage(message params.LogMessage) ([]byte, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonLogWriter writes the log lines written to it to w as JSON
+// encoded params.LogMessage values, one per line.
+type jsonLogWriter struct {
+	w       io.Writer
+	partial []byte
+}
+
+func (w *jsonLogWriter) Write(data []byte) (int, error) {
+	buf := append(w.partial, data...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		line, err := encodeLogMessage(parseLogLine(string(buf[:i])).logMessage())
+		if err != nil {
+			return 0, err
+		}
+		if _, err := w.w.Write(line); err != nil {
+			return 0, err
+		}
+		buf = buf[i+1:]
+	}
+	// Keep any unfinished line until the rest of it is written.
+	w.partial = append([]byte(nil), buf...)
+	return len(data), nil
+}
+
 func newLogStream(queryMap url.Values) (*logStream, error) {
 	maxLines := uint(0)
 	if value := queryMap.Get("maxLines"); value != "" {
@@ -143,6 +268,38 @@ func newLogStream(queryMap url.Values) (*logStream, error) {
 		}
 	}
 
+	var grep *regexp.Regexp
+	if value := queryMap.Get("grep"); value != "" {
+		var err error
+		grep, err = regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("grep value %q is not a valid regular expression", value)
+		}
+	}
+
+	jsonFormat := false
+	switch value := queryMap.Get("format"); value {
+	case "", "text":
+	case "json":
+		jsonFormat = true
+	default:
+		return nil, fmt.Errorf("format value %q is not one of %q, %q", value, "text", "json")
+	}
+
+	var since, until time.Time
+	for _, arg := range []struct {
+		name  string
+		value *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		if value := queryMap.Get(arg.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%s value %q is not a valid RFC3339 time", arg.name, value)
+			}
+			*arg.value = t
+		}
+	}
+
 	return &logStream{
 		includeEntity: queryMap["includeEntity"],
 		includeModule: queryMap["includeModule"],
@@ -152,6 +309,10 @@ func newLogStream(queryMap url.Values) (*logStream, error) {
 		fromTheStart:  fromTheStart,
 		backlog:       backlog,
 		filterLevel:   level,
+		since:         since,
+		until:         until,
+		grep:          grep,
+		jsonFormat:    jsonFormat,
 	}, nil
 }
 
@@ -172,16 +333,28 @@ func (h *debugLogHandler) sendError(w io.Writer, err error) error {
 	return err
 }
 
+// logTimeFormat is the format of the times in the log.
+const logTimeFormat = "2006-01-02 15:04:05"
+
 type logLine struct {
-	line   string
-	agent  string
-	level  loggo.Level
-	module string
+	line     string
+	agent    string
+	time     time.Time
+	level    loggo.Level
+	module   string
+	location string
+	message  string
 }
 
+// locationPattern matches the source location logged after the module,
+// e.g. machine.go:127. Hook output is logged without one.
+var locationPattern = regexp.MustCompile(`^\S+:\d+$`)
+
 func parseLogLine(line string) *logLine {
 	const (
 		agentField  = 0
+		dateField   = 1
+		timeField   = 2
 		levelField  = 3
 		moduleField = 4
 	)
@@ -195,16 +368,56 @@ func parseLogLine(line string) *logLine {
 			result.agent = agent[:len(agent)-1]
 		}
 	}
+	if len(fields) > timeField {
+		t, err := time.Parse(logTimeFormat, fields[dateField]+" "+fields[timeField])
+		if err == nil {
+			result.time = t
+		}
+	}
 	if len(fields) > moduleField {
 		if level, valid := loggo.ParseLevel(fields[levelField]); valid {
 			result.level = level
 			result.module = fields[moduleField]
 		}
 	}
-
+	// The message is the rest of the line, which is split on single
+	// spaces to keep the spacing of the message itself.
+	switch {
+	case result.module != "":
+		parts := strings.SplitN(line, " ", moduleField+2)
+		if len(parts) > moduleField+1 {
+			result.message = parts[moduleField+1]
+		}
+		parts = strings.SplitN(result.message, " ", 2)
+		if len(parts) == 2 && locationPattern.MatchString(parts[0]) {
+			result.location = parts[0]
+			result.message = parts[1]
+		}
+	case result.agent != "":
+		if parts := strings.SplitN(line, " ", 2); len(parts) == 2 {
+			result.message = parts[1]
+		}
+	default:
+		result.message = line
+	}
 	return result
 }
 
+// logMessage returns the line as a params.LogMessage.
+func (line *logLine) logMessage() params.LogMessage {
+	message := params.LogMessage{
+		Entity:   line.agent,
+		Time:     line.time,
+		Module:   line.module,
+		Location: line.location,
+		Message:  line.message,
+	}
+	if line.level != loggo.UNSPECIFIED {
+		message.Level = line.level.String()
+	}
+	return message
+}
+
 // logStream runs the tailer to read a log file and stream
 // it via a web socket.
 type logStream struct {
@@ -219,13 +432,37 @@ type logStream struct {
 	maxLines      uint
 	lineCount     uint
 	fromTheStart  bool
+	since         time.Time
+	until         time.Time
+	grep          *regexp.Regexp
+	jsonFormat    bool
+}
+
+// tailerParams returns the parameters of a state.LogTailer that selects
+// the records matched by the stream.
+func (stream *logStream) tailerParams() state.LogTailerParams {
+	return state.LogTailerParams{
+		Filter: state.LogFilter{
+			Since:         stream.since,
+			Until:         stream.until,
+			Level:         stream.filterLevel,
+			IncludeEntity: stream.includeEntity,
+			ExcludeEntity: stream.excludeEntity,
+			IncludeModule: stream.includeModule,
+			ExcludeModule: stream.excludeModule,
+			Message:       stream.grep,
+		},
+		Replay:  stream.fromTheStart,
+		Backlog: stream.backlog,
+	}
 }
 
 // positionLogFile will update the internal read position of the logFile to be
 // at the end of the file or somewhere in the middle if backlog has been specified.
 func (stream *logStream) positionLogFile(logFile io.ReadSeeker) error {
-	// Seek to the end, or lines back from the end if we need to.
-	if !stream.fromTheStart {
+	// Seek to the end, or lines back from the end if we need to. A
+	// time range is looked for from the start.
+	if !stream.fromTheStart && stream.since.IsZero() && stream.until.IsZero() {
 		return tailer.SeekLastLines(logFile, stream.backlog, stream.filterLine)
 	}
 	return nil
@@ -234,6 +471,9 @@ func (stream *logStream) positionLogFile(logFile io.ReadSeeker) error {
 // start the tailer listening to the logFile, and sending the matching
 // lines to the writer.
 func (stream *logStream) start(logFile io.ReadSeeker, writer io.Writer) {
+	if stream.jsonFormat {
+		writer = &jsonLogWriter{w: writer}
+	}
 	stream.logTailer = tailer.NewTailer(logFile, writer, stream.countedFilterLine)
 }
 
@@ -254,13 +494,19 @@ func (stream *logStream) filterLine(line []byte) bool {
 	return stream.checkIncludeEntity(log) &&
 		stream.checkIncludeModule(log) &&
 		!stream.exclude(log) &&
-		stream.checkLevel(log)
+		stream.checkLevel(log) &&
+		stream.checkTime(log) &&
+		stream.checkGrep(log)
 }
 
 // countedFilterLine checks the received line for one of the confgured tags,
 // and also checks to make sure the stream doesn't send more than the
 // specified number of lines.
 func (stream *logStream) countedFilterLine(line []byte) bool {
+	if stream.pastUntil(line) {
+		stream.tomb.Kill(untilReached)
+		return false
+	}
 	result := stream.filterLine(line)
 	if result && stream.maxLines > 0 {
 		stream.lineCount++
@@ -323,3 +569,30 @@ func (stream *logStream) exclude(line *logLine) bool {
 func (stream *logStream) checkLevel(line *logLine) bool {
 	return line.level >= stream.filterLevel
 }
+
+func (stream *logStream) checkTime(line *logLine) bool {
+	if stream.since.IsZero() && stream.until.IsZero() {
+		return true
+	}
+	if line.time.IsZero() {
+		return false
+	}
+	if !stream.since.IsZero() && line.time.Before(stream.since) {
+		return false
+	}
+	return stream.until.IsZero() || line.time.Before(stream.until)
+}
+
+func (stream *logStream) checkGrep(line *logLine) bool {
+	return stream.grep == nil || stream.grep.MatchString(line.message)
+}
+
+// pastUntil reports whether the line was logged at or after the
+// stream's until time, so that no more lines need to be sent.
+func (stream *logStream) pastUntil(line []byte) bool {
+	if stream.until.IsZero() {
+		return false
+	}
+	t := parseLogLine(string(line)).time
+	return !t.IsZero() && !t.Before(stream.until)
+}
@@ -32,6 +32,17 @@ func (s *debugInternalSuite) TestParseLogLine(c *gc.C) {
 	c.Assert(logLine.agent, gc.Equals, "machine-0")
 	c.Assert(logLine.level, gc.Equals, loggo.INFO)
 	c.Assert(logLine.module, gc.Equals, "juju.cmd.jujud")
+	c.Assert(logLine.time, gc.Equals, time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC))
+	c.Assert(logLine.location, gc.Equals, "machine.go:127")
+	c.Assert(logLine.message, gc.Equals, "machine agent machine-0 start (1.17.7.1-trusty-amd64 [gc])")
+}
+
+func (s *debugInternalSuite) TestParseLogLineWithoutLocation(c *gc.C) {
+	line := "unit-mysql-0: 2014-03-24 22:34:25 INFO unit.mysql/0.install  two  spaces"
+	logLine := parseLogLine(line)
+	c.Assert(logLine.module, gc.Equals, "unit.mysql/0.install")
+	c.Assert(logLine.location, gc.Equals, "")
+	c.Assert(logLine.message, gc.Equals, " two  spaces")
 }
 
 func (s *debugInternalSuite) TestParseLogLineMachineMultiline(c *gc.C) {
@@ -41,6 +52,7 @@ func (s *debugInternalSuite) TestParseLogLineMachineMultiline(c *gc.C) {
 	c.Assert(logLine.agent, gc.Equals, "machine-1")
 	c.Assert(logLine.level, gc.Equals, loggo.UNSPECIFIED)
 	c.Assert(logLine.module, gc.Equals, "")
+	c.Assert(logLine.message, gc.Equals, "continuation line")
 }
 
 func (s *debugInternalSuite) TestParseLogLineInvalid(c *gc.C) {
@@ -305,6 +317,8 @@ func assertStreamParams(c *gc.C, obtained, expected *logStream) {
 	c.Check(obtained.fromTheStart, gc.Equals, expected.fromTheStart)
 	c.Check(obtained.filterLevel, gc.Equals, expected.filterLevel)
 	c.Check(obtained.backlog, gc.Equals, expected.backlog)
+	c.Check(obtained.since, gc.Equals, expected.since)
+	c.Check(obtained.until, gc.Equals, expected.until)
 }
 
 func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
@@ -348,4 +362,80 @@ func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
 
 	_, err = newLogStream(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	obtained, err = newLogStream(url.Values{
+		"since": []string{"2014-03-24T22:34:25Z"},
+		"until": []string{"2014-03-25T00:00:00Z"},
+	})
+	c.Assert(err, gc.IsNil)
+	assertStreamParams(c, obtained, &logStream{
+		since: time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		until: time.Date(2014, 3, 25, 0, 0, 0, 0, time.UTC),
+	})
+
+	_, err = newLogStream(url.Values{"until": []string{"tomorrow"}})
+	c.Assert(err, gc.ErrorMatches, `until value "tomorrow" is not a valid RFC3339 time`)
+
+	obtained, err = newLogStream(url.Values{
+		"grep":   []string{"^worker"},
+		"format": []string{"json"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(obtained.grepPattern(), gc.Equals, "^worker")
+	c.Assert(obtained.jsonFormat, jc.IsTrue)
+
+	_, err = newLogStream(url.Values{"grep": []string{"("}})
+	c.Assert(err, gc.ErrorMatches, `grep value "\(" is not a valid regular expression`)
+
+	_, err = newLogStream(url.Values{"format": []string{"yaml"}})
+	c.Assert(err, gc.ErrorMatches, `format value "yaml" is not one of "text", "json"`)
+}
+
+func (s *debugInternalSuite) TestCheckGrep(c *gc.C) {
+	line := parseLogLine("machine-0: 2014-03-24 22:34:25 INFO juju runner.go:262 worker: start \"api\"")
+	c.Check((&logStream{}).checkGrep(line), jc.IsTrue)
+	stream, err := newLogStream(url.Values{"grep": []string{`start "api"$`}})
+	c.Assert(err, gc.IsNil)
+	c.Check(stream.checkGrep(line), jc.IsTrue)
+	// Only the message is matched.
+	stream, err = newLogStream(url.Values{"grep": []string{"runner"}})
+	c.Assert(err, gc.IsNil)
+	c.Check(stream.checkGrep(line), jc.IsFalse)
+}
+
+func (s *debugInternalSuite) TestJSONLogWriter(c *gc.C) {
+	var out bytes.Buffer
+	w := &jsonLogWriter{w: &out}
+	data := "machine-0: 2014-03-24 22:34:25 INFO juju runner.go:262 worker: start \"api\"\nmachine-1: cont"
+	n, err := w.Write([]byte(data))
+	c.Assert(err, gc.IsNil)
+	c.Assert(n, gc.Equals, len(data))
+	c.Assert(out.String(), gc.Equals, `{"entity":"machine-0","time":"2014-03-24T22:34:25Z","level":"INFO","module":"juju","location":"runner.go:262","message":"worker: start \"api\""}`+"\n")
+
+	// The unfinished line is written when the rest of it arrives.
+	out.Reset()
+	_, err = w.Write([]byte("inuation\n"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(out.String(), gc.Equals, `{"entity":"machine-1","time":"0001-01-01T00:00:00Z","level":"","module":"","message":"continuation"}`+"\n")
+}
+
+func (s *debugInternalSuite) TestCheckTime(c *gc.C) {
+	logTime := time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC)
+	for i, test := range []struct {
+		since, until time.Time
+		expected     bool
+	}{
+		{expected: true},
+		{since: logTime, expected: true},
+		{since: logTime.Add(time.Second), expected: false},
+		{until: logTime, expected: false},
+		{until: logTime.Add(time.Second), expected: true},
+	} {
+		c.Logf("test %d", i)
+		stream := &logStream{since: test.since, until: test.until}
+		c.Check(stream.checkTime(&logLine{time: logTime}), gc.Equals, test.expected)
+	}
+	// Lines without a time are not in any time range.
+	stream := &logStream{since: logTime}
+	c.Check(stream.checkTime(&logLine{}), jc.IsFalse)
 }
@@ -15,12 +15,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
+	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "launchpad.net/gocheck"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/testing"
 )
@@ -56,6 +59,18 @@ func (s *debugLogSuite) TestNoAuth(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogSuite) TestRequiresRead(c *gc.C) {
+	user := s.addUserWithRole(c, "nobody", state.RoleNone)
+	header := utils.BasicAuthHeader(user.Tag(), "password")
+	conn, err := s.dialWebsocketInternal(c, nil, header)
+	c.Assert(err, gc.IsNil)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	s.assertErrorResponse(c, reader, "permission denied")
+	s.assertWebsocketClosed(c, reader)
+}
+
 func (s *debugLogSuite) TestNoLogfile(c *gc.C) {
 	reader := s.openWebsocket(c, nil)
 	s.assertErrorResponse(c, reader, "cannot open log file: .*: no such file or directory")
@@ -180,6 +195,157 @@ func (s *debugLogSuite) TestFilter(c *gc.C) {
 	c.Assert(linesRead, jc.DeepEquals, expected)
 }
 
+func (s *debugLogSuite) TestTimeRange(c *gc.C) {
+	s.writeLogLines(c, logLineCount)
+
+	reader := s.openWebsocket(c, url.Values{
+		"since": {"2014-03-24T22:34:28Z"},
+		"until": {"2014-03-24T22:36:28Z"},
+	})
+	s.assertLogFollowing(c, reader)
+
+	linesRead := s.readLogLines(c, reader, 6)
+	c.Assert(linesRead, jc.DeepEquals, logLines[21:27])
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestBadTime(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"since": {"yesterday"}})
+	s.assertErrorResponse(c, reader, `since value "yesterday" is not a valid RFC3339 time`)
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestGrep(c *gc.C) {
+	s.writeLogLines(c, logLineCount)
+
+	reader := s.openWebsocket(c, url.Values{
+		"replay": {"true"},
+		"grep":   {`^worker: start "(api|state)"$`},
+	})
+	s.assertLogFollowing(c, reader)
+
+	expected := []string{logLines[4], logLines[6], logLines[21], logLines[30], logLines[43]}
+	linesRead := s.readLogLines(c, reader, len(expected))
+	c.Assert(linesRead, jc.DeepEquals, expected)
+}
+
+func (s *debugLogSuite) TestJSONFormat(c *gc.C) {
+	s.writeLogLines(c, logLineCount)
+
+	reader := s.openWebsocket(c, url.Values{
+		"replay":   {"true"},
+		"maxLines": {"1"},
+		"format":   {"json"},
+	})
+	s.assertLogFollowing(c, reader)
+
+	linesRead := s.readLogLines(c, reader, 1)
+	var message params.LogMessage
+	err := json.Unmarshal([]byte(linesRead[0]), &message)
+	c.Assert(err, gc.IsNil)
+	c.Assert(message, jc.DeepEquals, params.LogMessage{
+		Entity:   "machine-0",
+		Time:     time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		Level:    "INFO",
+		Module:   "juju.cmd",
+		Location: "supercommand.go:297",
+		Message:  "running juju-1.17.7.1-trusty-amd64 [gc]",
+	})
+	s.assertWebsocketClosed(c, reader)
+}
+
+// disableRsyslog makes the handler read the log from the database.
+func (s *debugLogSuite) disableRsyslog(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"disable-rsyslog": true}, nil, nil)
+	c.Assert(err, gc.IsNil)
+}
+
+var logRecordTime = time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC)
+
+func (s *debugLogSuite) addLogRecords(c *gc.C, entities ...string) {
+	var records []state.LogRecord
+	for i, entity := range entities {
+		records = append(records, state.LogRecord{
+			Time:     logRecordTime.Add(time.Duration(i) * time.Minute),
+			Entity:   entity,
+			Module:   "juju.cmd",
+			Location: "supercommand.go:297",
+			Level:    loggo.INFO,
+			Message:  fmt.Sprintf("message %d", i),
+		})
+	}
+	err := s.State.AddLogRecords(records)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *debugLogSuite) TestServesLogFromDatabase(c *gc.C) {
+	s.disableRsyslog(c)
+	s.addLogRecords(c, "machine-0", "machine-1", "unit-ubuntu-0")
+
+	reader := s.openWebsocket(c, url.Values{
+		"replay":        {"true"},
+		"excludeEntity": {"machine-1"},
+	})
+	s.assertLogFollowing(c, reader)
+	linesRead := s.readLogLines(c, reader, 2)
+	c.Assert(linesRead, jc.DeepEquals, []string{
+		"machine-0: 2014-03-24 22:34:25 INFO juju.cmd supercommand.go:297 message 0",
+		"unit-ubuntu-0: 2014-03-24 22:36:25 INFO juju.cmd supercommand.go:297 message 2",
+	})
+}
+
+func (s *debugLogSuite) TestTimeRangeFromDatabase(c *gc.C) {
+	s.disableRsyslog(c)
+	s.addLogRecords(c, "machine-0", "machine-1", "machine-2", "machine-3")
+
+	reader := s.openWebsocket(c, url.Values{
+		"since": {"2014-03-24T22:35:25Z"},
+		"until": {"2014-03-24T22:37:25Z"},
+	})
+	s.assertLogFollowing(c, reader)
+	linesRead := s.readLogLines(c, reader, 2)
+	c.Assert(linesRead, jc.DeepEquals, []string{
+		"machine-1: 2014-03-24 22:35:25 INFO juju.cmd supercommand.go:297 message 1",
+		"machine-2: 2014-03-24 22:36:25 INFO juju.cmd supercommand.go:297 message 2",
+	})
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestGrepAndJSONFormatFromDatabase(c *gc.C) {
+	s.disableRsyslog(c)
+	s.addLogRecords(c, "machine-0", "machine-1", "machine-2")
+
+	reader := s.openWebsocket(c, url.Values{
+		"replay": {"true"},
+		"grep":   {"^message [12]$"},
+		"format": {"json"},
+	})
+	s.assertLogFollowing(c, reader)
+	linesRead := s.readLogLines(c, reader, 2)
+	var messages []params.LogMessage
+	for _, line := range linesRead {
+		var message params.LogMessage
+		err := json.Unmarshal([]byte(line), &message)
+		c.Assert(err, gc.IsNil)
+		messages = append(messages, message)
+	}
+	c.Assert(messages, jc.DeepEquals, []params.LogMessage{{
+		Entity:   "machine-1",
+		Time:     logRecordTime.Add(time.Minute),
+		Level:    "INFO",
+		Module:   "juju.cmd",
+		Location: "supercommand.go:297",
+		Message:  "message 1",
+	}, {
+		Entity:   "machine-2",
+		Time:     logRecordTime.Add(2 * time.Minute),
+		Level:    "INFO",
+		Module:   "juju.cmd",
+		Location: "supercommand.go:297",
+		Message:  "message 2",
+	}})
+}
+
 func (s *debugLogSuite) readLogLines(c *gc.C, reader *bufio.Reader, count int) (linesRead []string) {
 	for len(linesRead) < count {
 		line, err := reader.ReadString('\n')
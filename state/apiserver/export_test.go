@@ -15,6 +15,8 @@ var (
 	NewPingTimeout        = newPingTimeout
 	MaxClientPingInterval = &maxClientPingInterval
 	MongoPingInterval     = &mongoPingInterval
+	NewBackups            = &newBackups
+	AuditArgs             = auditArgs
 )
 
 const LoginRateLimit = loginRateLimit
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/juju/names"
 
@@ -28,33 +29,112 @@ type httpHandler struct {
 
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
-func (h *httpHandler) authenticate(r *http.Request) error {
+// It returns the authenticated user.
+func (h *httpHandler) authenticate(r *http.Request) (state.Entity, error) {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
-		return fmt.Errorf("invalid request format")
+		return nil, fmt.Errorf("invalid request format")
 	}
 	// Challenge is a base64-encoded "tag:pass" string.
 	// See RFC 2617, Section 2.
 	challenge, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return fmt.Errorf("invalid request format")
+		return nil, fmt.Errorf("invalid request format")
 	}
 	tagPass := strings.SplitN(string(challenge), ":", 2)
 	if len(tagPass) != 2 {
-		return fmt.Errorf("invalid request format")
+		return nil, fmt.Errorf("invalid request format")
 	}
 	// Only allow users, not agents.
 	_, _, err = names.ParseTag(tagPass[0], names.UserTagKind)
 	if err != nil {
-		return common.ErrBadCreds
+		return nil, common.ErrBadCreds
 	}
 	// Ensure the credentials are correct.
-	_, err = checkCreds(h.state, params.Creds{
+	entity, err := checkCreds(h.state, params.Creds{
 		AuthTag:  tagPass[0],
 		Password: tagPass[1],
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// requireUserRole sends an error and returns false unless the user
+// authenticated by the request has at least the given role in the
+// environment.
+func (h *httpHandler) requireUserRole(w http.ResponseWriter, sender errorSender, user state.Entity, role state.Role) bool {
+	err := common.RequireUserRole(h.state, httpAuthorizer{user}, role)
+	if err == common.ErrPerm {
+		sender.sendError(w, http.StatusForbidden, err.Error())
+		return false
+	} else if err != nil {
+		sender.sendError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	return true
+}
+
+// httpAuthorizer implements common.Authorizer for a user authenticated
+// by an HTTP request. Agents may not make HTTP requests.
+type httpAuthorizer struct {
+	entity state.Entity
+}
+
+func (a httpAuthorizer) AuthMachineAgent() bool      { return false }
+func (a httpAuthorizer) AuthUnitAgent() bool         { return false }
+func (a httpAuthorizer) AuthOwner(tag string) bool   { return a.entity.Tag() == tag }
+func (a httpAuthorizer) AuthEnvironManager() bool    { return false }
+func (a httpAuthorizer) AuthClient() bool            { return true }
+func (a httpAuthorizer) GetAuthTag() string          { return a.entity.Tag() }
+func (a httpAuthorizer) GetAuthEntity() state.Entity { return a.entity }
+
+// auditWriter records the status of the response written through it.
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// serveAudited serves the request made by user with serve, and records
+// it in the audit log as a call of the given facade method, with the
+// request's query as its arguments. A request that fails is recorded
+// with the status of the response. Failing to record the request does
+// not fail it.
+func (h *httpHandler) serveAudited(w http.ResponseWriter, r *http.Request, user state.Entity, facade, method string, serve func(http.ResponseWriter, *http.Request)) {
+	aw := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+	serve(aw, r)
+	args := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		// Keys starting with ":" are taken from the URL path.
+		if !strings.HasPrefix(key, ":") && len(values) > 0 {
+			args[key] = values[0]
+		}
+	}
+	encoded, entities, err := auditArgs(args)
+	if err != nil {
+		logger.Errorf("cannot encode arguments of %s.%s for the audit log: %v", facade, method, err)
+	}
+	entry := state.AuditEntry{
+		Time:     time.Now(),
+		User:     user.Tag(),
+		Facade:   facade,
+		Method:   method,
+		Args:     encoded,
+		Entities: entities,
+	}
+	if aw.status >= http.StatusBadRequest {
+		entry.Error = fmt.Sprintf("%d %s", aw.status, http.StatusText(aw.status))
+	}
+	if err := h.state.AddAuditEntry(entry); err != nil {
+		logger.Errorf("cannot record %s.%s request in the audit log: %v", facade, method, err)
+	}
 }
 
 func (h *httpHandler) getEnvironUUID(r *http.Request) string {
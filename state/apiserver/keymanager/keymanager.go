@@ -48,19 +48,20 @@ func NewKeyManagerAPI(
 	resources *common.Resources,
 	authorizer common.Authorizer,
 ) (*KeyManagerAPI, error) {
-	// Only clients and environment managers can access the key manager service.
-	if !authorizer.AuthClient() && !authorizer.AuthEnvironManager() {
-		return nil, common.ErrPerm
+	// Only environment managers and administrators of the environment
+	// can access the key manager service.
+	if !authorizer.AuthEnvironManager() {
+		if err := common.RequireUserRole(st, authorizer, state.RoleAdmin); err != nil {
+			return nil, err
+		}
 	}
-	// TODO(wallyworld) - replace stub with real canRead function
-	// For now, only admins can read authorised ssh keys.
+	// Administrators can read the authorised ssh keys.
 	getCanRead := func() (common.AuthFunc, error) {
 		return func(tag string) bool {
-			return authorizer.GetAuthTag() == "user-admin"
+			return authorizer.AuthClient()
 		}, nil
 	}
-	// TODO(wallyworld) - replace stub with real canWrite function
-	// For now, only admins can write authorised ssh keys for users.
+	// Administrators can write the authorised ssh keys for users.
 	// Machine agents can write the juju-system-key.
 	getCanWrite := func() (common.AuthFunc, error) {
 		return func(tag string) bool {
@@ -73,7 +74,7 @@ func NewKeyManagerAPI(
 			if _, err := st.User(tag); err != nil {
 				return false
 			}
-			return authorizer.GetAuthTag() == "user-admin"
+			return authorizer.AuthClient()
 		}, nil
 	}
 	return &KeyManagerAPI{
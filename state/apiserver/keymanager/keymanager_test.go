@@ -76,6 +76,26 @@ func (s *keyManagerSuite) TestNewKeyManagerAPIRefusesNonEnvironManager(c *gc.C)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *keyManagerSuite) TestNewKeyManagerAPIRequiresAdmin(c *gc.C) {
+	user := s.AddUser(c, "bob")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	anAuthoriser := s.authoriser
+	anAuthoriser.Tag = user.Tag()
+	for _, role := range []state.Role{state.RoleRead, state.RoleWrite} {
+		err = user.GrantRole(env.UUID(), role)
+		c.Assert(err, gc.IsNil)
+		endPoint, err := keymanager.NewKeyManagerAPI(s.State, s.resources, anAuthoriser)
+		c.Assert(endPoint, gc.IsNil)
+		c.Assert(err, gc.Equals, common.ErrPerm)
+	}
+
+	err = user.GrantRole(env.UUID(), state.RoleAdmin)
+	c.Assert(err, gc.IsNil)
+	_, err = keymanager.NewKeyManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(err, gc.IsNil)
+}
+
 func (s *keyManagerSuite) setAuthorisedKeys(c *gc.C, keys string) {
 	err := s.State.UpdateEnvironConfig(map[string]interface{}{"authorized-keys": keys}, nil, nil)
 	c.Assert(err, gc.IsNil)
@@ -148,6 +148,25 @@ func (s *loginSuite) TestLoginAsDeactivatedUser(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unknown object type "Client"`)
 }
 
+func (s *loginSuite) TestLoginRecordsLastConnection(c *gc.C) {
+	info, cleanup := s.setupServer(c)
+	defer cleanup()
+
+	u := s.AddUser(c, "bob")
+	c.Assert(u.LastConnection().IsZero(), jc.IsTrue)
+
+	info.Tag = "user-bob"
+	info.Password = "password"
+	before := time.Now().Add(-time.Second)
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, gc.IsNil)
+	defer st.Close()
+
+	err = u.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.LastConnection().After(before), jc.IsTrue)
+}
+
 func (s *loginSuite) TestLoginSetsLogIdentifier(c *gc.C) {
 	info, cleanup := s.setupServer(c)
 	defer cleanup()
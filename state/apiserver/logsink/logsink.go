@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink
+
+import (
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/common"
+)
+
+// LogSink defines the methods on the logsink API end point.
+type LogSink interface {
+	WriteLogs(args params.LogRecords) error
+}
+
+// LogSinkAPI implements the LogSink interface and is the concrete
+// implementation of the api end point.
+type LogSinkAPI struct {
+	state  *state.State
+	entity string
+}
+
+var _ LogSink = (*LogSinkAPI)(nil)
+
+// NewLogSinkAPI creates a new server-side logsink API end point.
+func NewLogSinkAPI(st *state.State, authorizer common.Authorizer) (*LogSinkAPI, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &LogSinkAPI{
+		state:  st,
+		entity: authorizer.GetAuthTag(),
+	}, nil
+}
+
+// WriteLogs adds the given records, logged by the authenticated agent,
+// to the environment's log.
+func (api *LogSinkAPI) WriteLogs(args params.LogRecords) error {
+	records := make([]state.LogRecord, len(args.Records))
+	for i, record := range args.Records {
+		records[i] = state.LogRecord{
+			Time:     record.Time,
+			Entity:   api.entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    record.Level,
+			Message:  record.Message,
+		}
+	}
+	return api.state.AddLogRecords(records)
+}
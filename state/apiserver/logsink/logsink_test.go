@@ -0,0 +1,77 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/state/apiserver/logsink"
+	apiservertesting "github.com/juju/juju/state/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type logSinkSuite struct {
+	jujutesting.JujuConnSuite
+
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&logSinkSuite{})
+
+func (s *logSinkSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag:          "machine-1",
+		LoggedIn:     true,
+		MachineAgent: true,
+	}
+}
+
+func (s *logSinkSuite) TestNewLogSinkAPIRefusesClient(c *gc.C) {
+	anAuthorizer := apiservertesting.FakeAuthorizer{
+		Tag:      "user-admin",
+		LoggedIn: true,
+		Client:   true,
+	}
+	endPoint, err := logsink.NewLogSinkAPI(s.State, anAuthorizer)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *logSinkSuite) TestWriteLogs(c *gc.C) {
+	api, err := logsink.NewLogSinkAPI(s.State, s.authorizer)
+	c.Assert(err, gc.IsNil)
+	logTime := time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+	err = api.WriteLogs(params.LogRecords{
+		Records: []params.LogRecord{{
+			Time:     logTime,
+			Module:   "juju.worker.machiner",
+			Location: "machiner.go:42",
+			Level:    loggo.WARNING,
+			Message:  "something happened",
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+
+	tailer := s.State.NewLogTailer(state.LogTailerParams{Replay: true})
+	defer tailer.Stop()
+	select {
+	case record := <-tailer.Records():
+		c.Assert(record.Time.Equal(logTime), jc.IsTrue)
+		c.Assert(record.Entity, gc.Equals, "machine-1")
+		c.Assert(record.Module, gc.Equals, "juju.worker.machiner")
+		c.Assert(record.Location, gc.Equals, "machiner.go:42")
+		c.Assert(record.Level, gc.Equals, loggo.WARNING)
+		c.Assert(record.Message, gc.Equals, "something happened")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
@@ -12,7 +12,10 @@ import (
 
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/apiserver/actions"
 	"github.com/juju/juju/state/apiserver/agent"
+	"github.com/juju/juju/state/apiserver/auditlog"
+	"github.com/juju/juju/state/apiserver/backups"
 	"github.com/juju/juju/state/apiserver/charmrevisionupdater"
 	"github.com/juju/juju/state/apiserver/client"
 	"github.com/juju/juju/state/apiserver/common"
@@ -22,6 +25,7 @@ import (
 	"github.com/juju/juju/state/apiserver/keymanager"
 	"github.com/juju/juju/state/apiserver/keyupdater"
 	loggerapi "github.com/juju/juju/state/apiserver/logger"
+	"github.com/juju/juju/state/apiserver/logsink"
 	"github.com/juju/juju/state/apiserver/machine"
 	"github.com/juju/juju/state/apiserver/networker"
 	"github.com/juju/juju/state/apiserver/provisioner"
@@ -29,6 +33,7 @@ import (
 	"github.com/juju/juju/state/apiserver/uniter"
 	"github.com/juju/juju/state/apiserver/upgrader"
 	"github.com/juju/juju/state/apiserver/usermanager"
+	statebackups "github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 )
 
@@ -125,6 +130,40 @@ func (r *srvRoot) UserManager(id string) (*usermanager.UserManagerAPI, error) {
 	return usermanager.NewUserManagerAPI(r.srv.state, r)
 }
 
+// Actions returns an object that provides access to the Actions API
+// facade. The id argument is reserved for future use and currently
+// needs to be empty.
+func (r *srvRoot) Actions(id string) (*actions.ActionsAPI, error) {
+	if id != "" {
+		return nil, common.ErrBadId
+	}
+	return actions.NewActionsAPI(r.srv.state, r.resources, r)
+}
+
+// Backups returns an object that provides access to the Backups API
+// facade. The id argument is reserved for future use and currently
+// needs to be empty.
+func (r *srvRoot) Backups(id string) (*backups.BackupsAPI, error) {
+	if id != "" {
+		return nil, common.ErrBadId
+	}
+	paths := statebackups.Paths{
+		DataDir: r.srv.dataDir,
+		LogDir:  r.srv.logDir,
+	}
+	return backups.NewBackupsAPI(r.srv.state, r, paths, r.srv.restored)
+}
+
+// AuditLog returns an object that provides access to the AuditLog API
+// facade. The id argument is reserved for future use and currently
+// needs to be empty.
+func (r *srvRoot) AuditLog(id string) (*auditlog.AuditLogAPI, error) {
+	if id != "" {
+		return nil, common.ErrBadId
+	}
+	return auditlog.NewAuditLogAPI(r.srv.state, r)
+}
+
 // Machiner returns an object that provides access to the Machiner API
 // facade. The id argument is reserved for future use and currently
 // needs to be empty.
@@ -232,6 +271,16 @@ func (r *srvRoot) Logger(id string) (*loggerapi.LoggerAPI, error) {
 	return loggerapi.NewLoggerAPI(r.srv.state, r.resources, r)
 }
 
+// LogSink returns an object that provides access to the LogSink API
+// facade. The id argument is reserved for future use and currently
+// needs to be empty.
+func (r *srvRoot) LogSink(id string) (*logsink.LogSinkAPI, error) {
+	if id != "" {
+		return nil, common.ErrBadId
+	}
+	return logsink.NewLogSinkAPI(r.srv.state, r)
+}
+
 // Upgrader returns an object that provides access to the Upgrader API facade.
 // The id argument is reserved for future use and must be empty.
 func (r *srvRoot) Upgrader(id string) (upgrader.Upgrader, error) {
@@ -30,5 +30,6 @@ func newRsyslogConfig(envCfg *config.Config, api *RsyslogAPI) (*apirsyslog.Rsysl
 		CACert:    envCfg.RsyslogCACert(),
 		Port:      port,
 		HostPorts: network.AddressesWithPort(apiAddresses, port),
+		Disabled:  envCfg.DisableRsyslog(),
 	}, nil
 }
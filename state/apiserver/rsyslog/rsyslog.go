@@ -75,6 +75,7 @@ func (api *RsyslogAPI) GetRsyslogConfig(args params.Entities) (params.RsyslogCon
 				CACert:    rsyslogCfg.CACert,
 				Port:      rsyslogCfg.Port,
 				HostPorts: rsyslogCfg.HostPorts,
+				Disabled:  rsyslogCfg.Disabled,
 			}
 		} else {
 			result.Results[i].Error = common.ServerError(err)
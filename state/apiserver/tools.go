@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/sync"
 	envtools "github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/apiserver/common"
 	"github.com/juju/juju/tools"
@@ -30,7 +31,8 @@ type toolsHandler struct {
 }
 
 func (h *toolsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := h.authenticate(r); err != nil {
+	user, err := h.authenticate(r)
+	if err != nil {
 		h.authError(w, h)
 		return
 	}
@@ -38,25 +40,35 @@ func (h *toolsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, http.StatusNotFound, err.Error())
 		return
 	}
+	// The uploaded tools are run by every agent of the environment,
+	// so only environment administrators may upload them.
+	if !h.requireUserRole(w, h, user, state.RoleAdmin) {
+		return
+	}
 
 	switch r.Method {
 	case "POST":
 		// Add a local charm to the store provider.
 		// Requires a "series" query specifying the series to use for the charm.
-		agentTools, disableSSLHostnameVerification, err := h.processPost(r)
-		if err != nil {
-			h.sendError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		h.sendJSON(w, http.StatusOK, &params.ToolsResult{
-			Tools: agentTools,
-			DisableSSLHostnameVerification: disableSSLHostnameVerification,
-		})
+		h.serveAudited(w, r, user, "Tools", "Upload", h.servePost)
 	default:
 		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
 	}
 }
 
+// servePost adds the tools uploaded in the request.
+func (h *toolsHandler) servePost(w http.ResponseWriter, r *http.Request) {
+	agentTools, disableSSLHostnameVerification, err := h.processPost(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendJSON(w, http.StatusOK, &params.ToolsResult{
+		Tools: agentTools,
+		DisableSSLHostnameVerification: disableSSLHostnameVerification,
+	})
+}
+
 // sendJSON sends a JSON-encoded response to the client.
 func (h *toolsHandler) sendJSON(w http.ResponseWriter, statusCode int, response *params.ToolsResult) error {
 	w.Header().Set("Content-Type", "application/json")
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 
 	"github.com/juju/utils"
 	gc "launchpad.net/gocheck"
@@ -74,6 +75,21 @@ func (s *toolsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected binaryVersion argument")
 }
 
+func (s *toolsSuite) TestUploadRequiresAdmin(c *gc.C) {
+	for _, test := range []struct {
+		name string
+		role state.Role
+	}{
+		{"reader", state.RoleRead},
+		{"writer", state.RoleWrite},
+	} {
+		user := s.addUserWithRole(c, test.name, test.role)
+		resp, err := s.sendRequest(c, user.Tag(), "password", "POST", s.toolsURI(c, "?binaryVersion=1.18.0-quantal-amd64"), "application/x-tar-gz", strings.NewReader("tools"))
+		c.Assert(err, gc.IsNil)
+		s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+	}
+}
+
 func (s *toolsSuite) TestUploadRequiresVersion(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.toolsURI(c, ""), "", nil)
 	c.Assert(err, gc.IsNil)
@@ -134,6 +150,7 @@ func (s *toolsSuite) TestUpload(c *gc.C) {
 	expectedData, err := ioutil.ReadFile(toolPath)
 	c.Assert(err, gc.IsNil)
 	c.Assert(uploadedData, gc.DeepEquals, expectedData)
+	s.assertLastAudited(c, "Tools", "Upload", `{"binaryVersion":"`+vers.String()+`"}`, "")
 }
 
 func (s *toolsSuite) TestUploadAllowsTopLevelPath(c *gc.C) {
@@ -7,6 +7,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -362,6 +363,54 @@ func (u *UniterAPI) SetCharmURL(args params.EntitiesCharmURL) (params.ErrorResul
 	return result, nil
 }
 
+// SetWorkloadStatus sets the status of the workload of each given unit,
+// as reported by its charm.
+func (u *UniterAPI) SetWorkloadStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(entity.Tag)
+			if err == nil {
+				err = unit.SetWorkloadStatus(entity.Status, entity.Info)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WorkloadStatus returns the status of the workload of each given unit.
+func (u *UniterAPI) WorkloadStatus(args params.Entities) (params.StatusResults, error) {
+	result := params.StatusResults{
+		Results: make([]params.StatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(entity.Tag)
+			if err == nil {
+				result.Results[i].Id = unit.Name()
+				result.Results[i].Status, result.Results[i].Info, err = unit.WorkloadStatus()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // OpenPort sets the policy of the port with protocol an number to be
 // opened, for all given units.
 func (u *UniterAPI) OpenPort(args params.EntitiesPorts) (params.ErrorResults, error) {
@@ -480,6 +529,142 @@ func (u *UniterAPI) ConfigSettings(args params.Entities) (params.ConfigSettingsR
 	return result, nil
 }
 
+// leaseDuration is the time for which a unit's claim to the leadership
+// of its service is granted. A unit must renew its claim before then to
+// remain the leader. The duration is returned with each successful
+// claim, so that a leader whose claim cannot be renewed knows how long
+// it remains the leader.
+const leaseDuration = time.Minute
+
+// getUnitService returns the given unit and its service.
+func (u *UniterAPI) getUnitService(tag string) (*state.Unit, *state.Service, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return nil, nil, err
+	}
+	return unit, service, nil
+}
+
+// ClaimLeadership claims the leadership of each given unit's service on
+// the unit's behalf, and reports whether the unit is the leader and for
+// how long its lease is granted.
+func (u *UniterAPI) ClaimLeadership(args params.Entities) (params.ClaimLeadershipResults, error) {
+	result := params.ClaimLeadershipResults{
+		Results: make([]params.ClaimLeadershipResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ClaimLeadershipResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			var service *state.Service
+			unit, service, err = u.getUnitService(entity.Tag)
+			if err == nil {
+				var isLeader bool
+				isLeader, err = service.ClaimLeadership(unit.Name(), leaseDuration)
+				if isLeader {
+					result.Results[i].IsLeader = true
+					result.Results[i].Duration = leaseDuration
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// LeaderSettings returns the leader settings of each given unit's
+// service.
+func (u *UniterAPI) LeaderSettings(args params.Entities) (params.LeaderSettingsResults, error) {
+	result := params.LeaderSettingsResults{
+		Results: make([]params.LeaderSettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.LeaderSettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var service *state.Service
+			_, service, err = u.getUnitService(entity.Tag)
+			if err == nil {
+				result.Results[i].Settings, err = service.LeaderSettings()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// MergeLeaderSettings merges the given settings into the leader
+// settings of each unit's service. Each unit must be the leader of
+// its service.
+func (u *UniterAPI) MergeLeaderSettings(args params.EntitiesLeaderSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			var unit *state.Unit
+			var service *state.Service
+			unit, service, err = u.getUnitService(entity.Tag)
+			if err == nil {
+				err = service.MergeLeaderSettings(unit.Name(), entity.Settings)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) watchOneUnitLeaderSettings(tag string) (string, error) {
+	_, service, err := u.getUnitService(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := service.WatchLeaderSettings()
+	// Consume the initial event, as in watchOneUnitConfigSettings.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.MustErr(watch)
+}
+
+// WatchLeaderSettings returns a NotifyWatcher for observing changes
+// to the leader settings of each unit's service.
+func (u *UniterAPI) WatchLeaderSettings(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		watcherId := ""
+		if canAccess(entity.Tag) {
+			watcherId, err = u.watchOneUnitLeaderSettings(entity.Tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 func (u *UniterAPI) watchOneServiceRelations(tag string) (params.StringsWatchResult, error) {
 	nothing := params.StringsWatchResult{}
 	service, err := u.getService(tag)
@@ -985,3 +1170,137 @@ func (u *UniterAPI) GetOwnerTag(args params.Entities) (params.StringResult, erro
 		Result: service.GetOwnerTag(),
 	}, nil
 }
+
+func (u *UniterAPI) watchOneUnitActions(tag string) (params.StringsWatchResult, error) {
+	nothing := params.StringsWatchResult{}
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nothing, err
+	}
+	watch := unit.WatchActions()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: u.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return nothing, watcher.MustErr(watch)
+}
+
+// WatchActions returns a StringsWatcher, for each given unit, that
+// notifies of actions being queued for that unit.
+func (u *UniterAPI) WatchActions(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		err := common.ErrPerm
+		if canAccess(entity.Tag) {
+			result.Results[i], err = u.watchOneUnitActions(entity.Tag)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// getAction returns the pending action with the given id, provided it
+// was queued for the authenticated unit.
+func (u *UniterAPI) getAction(id string) (*state.Action, error) {
+	unit, ok := u.auth.GetAuthEntity().(*state.Unit)
+	if !ok {
+		panic("authenticated entity is not a unit")
+	}
+	if !state.IsAction(id) {
+		return nil, common.ErrPerm
+	}
+	action, err := unit.Action(id)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	}
+	return action, err
+}
+
+// BeginActions records that the unit has started running each of the
+// given actions.
+func (u *UniterAPI) BeginActions(args params.ActionIds) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		action, err := u.getAction(id)
+		if err == nil {
+			err = action.Begin()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Actions returns the name and parameters of each given action.
+func (u *UniterAPI) Actions(args params.ActionIds) (params.ActionQueryResults, error) {
+	result := params.ActionQueryResults{
+		Results: make([]params.ActionQueryResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		action, err := u.getAction(id)
+		if err == nil {
+			result.Results[i].Action = &params.Action{
+				Id:         action.Id(),
+				Receiver:   names.UnitTag(action.UnitName()),
+				Name:       action.Name(),
+				Params:     action.Payload(),
+				Timeout:    action.Timeout(),
+				Cancelling: action.Cancelling(),
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchAction returns a NotifyWatcher for observing changes to each
+// given action, such as a request to cancel it. See also
+// state/watcher.go:Action.Watch().
+func (u *UniterAPI) WatchAction(args params.ActionIds) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		action, err := u.getAction(id)
+		if err == nil {
+			result.Results[i].NotifyWatcherId, err = u.watchOneAction(action)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) watchOneAction(action *state.Action) (string, error) {
+	watch := action.Watch()
+	// Consume the initial event.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.MustErr(watch)
+}
+
+// FinishActions records the outcome of each given action, removing it
+// from the unit's queue.
+func (u *UniterAPI) FinishActions(args params.ActionExecutionResults) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Results)),
+	}
+	for i, arg := range args.Results {
+		action, err := u.getAction(arg.ActionId)
+		if err == nil {
+			err = action.Finish(state.ActionStatus(arg.Status), arg.Results, arg.Message)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -669,6 +670,56 @@ func (s *uniterSuite) TestOpenPort(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestSetWorkloadStatus(c *gc.C) {
+	args := params.SetStatus{Entities: []params.EntityStatus{
+		{Tag: "unit-mysql-0", Status: params.StatusActive},
+		{Tag: "unit-wordpress-0", Status: params.StatusBlocked, Info: "missing config"},
+		{Tag: "unit-foo-42", Status: params.StatusActive},
+	}}
+	result, err := s.uniter.SetWorkloadStatus(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, info, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusBlocked)
+	c.Assert(info, gc.Equals, "missing config")
+
+	// Only workload statuses can be set.
+	args = params.SetStatus{Entities: []params.EntityStatus{
+		{Tag: "unit-wordpress-0", Status: params.StatusStarted},
+	}}
+	result, err = s.uniter.SetWorkloadStatus(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `cannot set invalid workload status "started"`)
+}
+
+func (s *uniterSuite) TestWorkloadStatus(c *gc.C) {
+	err := s.wordpressUnit.SetWorkloadStatus(params.StatusWaiting, "waiting for database")
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WorkloadStatus(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Id: "wordpress/0", Status: params.StatusWaiting, Info: "waiting for database"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterSuite) TestClosePort(c *gc.C) {
 	// Open port udp:4321 in advance on wordpressUnit.
 	err := s.wordpressUnit.OpenPort("udp", 4321)
@@ -757,6 +808,106 @@ func (s *uniterSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestClaimLeadership(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.ClaimLeadership(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ClaimLeadershipResults{
+		Results: []params.ClaimLeadershipResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{IsLeader: true, Duration: time.Minute},
+			{IsLeader: true, Duration: time.Minute},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	leader, err := s.wordpress.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+}
+
+func (s *uniterSuite) TestLeaderSettings(c *gc.C) {
+	settingsArgs := params.EntitiesLeaderSettings{Entities: []params.EntityLeaderSettings{
+		{Tag: "unit-mysql-0", Settings: map[string]string{"foo": "bar"}},
+		{Tag: "unit-wordpress-0", Settings: map[string]string{"foo": "bar"}},
+		{Tag: "unit-foo-42", Settings: map[string]string{"foo": "bar"}},
+	}}
+	result, err := s.uniter.MergeLeaderSettings(settingsArgs)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{&params.Error{Message: "unit is not the service leader", Code: params.CodeNotLeader}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	_, err = s.wordpress.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	result, err = s.uniter.MergeLeaderSettings(settingsArgs)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	settings, err := s.uniter.LeaderSettings(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, params.LeaderSettingsResults{
+		Results: []params.LeaderSettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: map[string]string{"foo": "bar"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestWatchLeaderSettings(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchLeaderSettings(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	_, err = s.wordpress.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	err = s.wordpress.MergeLeaderSettings("wordpress/0", map[string]string{"foo": "bar"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
+
 func (s *uniterSuite) TestWatchServiceRelations(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 
@@ -986,7 +1137,7 @@ func (s *uniterSuite) TestEnterScope(c *gc.C) {
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
-			{apiservertesting.ErrUnauthorized},
+			{&params.Error{Message: `cannot finish action "` + id3 + `" with status "running"`}},
 		},
 	})
 
@@ -1037,7 +1188,7 @@ func (s *uniterSuite) TestLeaveScope(c *gc.C) {
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
-			{apiservertesting.ErrUnauthorized},
+			{&params.Error{Message: `cannot finish action "` + id3 + `" with status "running"`}},
 		},
 	})
 
@@ -1313,7 +1464,7 @@ func (s *uniterSuite) TestUpdateSettings(c *gc.C) {
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
 			{apiservertesting.ErrUnauthorized},
-			{apiservertesting.ErrUnauthorized},
+			{&params.Error{Message: `cannot finish action "` + id3 + `" with status "running"`}},
 		},
 	})
 
@@ -1428,3 +1579,168 @@ func (s *uniterSuite) TestGetOwnerTag(c *gc.C) {
 		Result: "user-admin",
 	})
 }
+
+func (s *uniterSuite) TestWatchActions(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchActions(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{StringsWatcherId: "1", Changes: []string{id}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event.
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+
+	id2, err := s.wordpressUnit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	wc.AssertChange(id2)
+	wc.AssertNoChange()
+}
+
+func (s *uniterSuite) TestActions(c *gc.C) {
+	payload := map[string]interface{}{"outfile": "foo.tgz"}
+	id, err := s.wordpressUnit.AddAction("snapshot", payload)
+	c.Assert(err, gc.IsNil)
+	otherId, err := s.mysqlUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	args := params.ActionIds{Ids: []string{id, otherId, "foo"}}
+	result, err := s.uniter.Actions(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, jc.DeepEquals, params.ActionQueryResults{
+		Results: []params.ActionQueryResult{
+			{Action: &params.Action{
+				Id:       id,
+				Receiver: "unit-wordpress-0",
+				Name:     "snapshot",
+				Params:   payload,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestBeginActions(c *gc.C) {
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	otherId, err := s.mysqlUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.uniter.BeginActions(params.ActionIds{Ids: []string{id, otherId, "foo"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Started().IsZero(), gc.Equals, false)
+	action, err = s.State.Action(otherId)
+	c.Assert(err, gc.IsNil)
+	c.Assert(action.Started().IsZero(), gc.Equals, true)
+}
+
+func (s *uniterSuite) TestWatchAction(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+	id, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	otherId, err := s.mysqlUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	result, err := s.uniter.WatchAction(params.ActionIds{Ids: []string{id, otherId, "foo"}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	action, err := s.State.Action(id)
+	c.Assert(err, gc.IsNil)
+	err = action.Begin()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+	err = action.Cancel()
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+}
+
+func (s *uniterSuite) TestFinishActions(c *gc.C) {
+	id1, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	id2, err := s.wordpressUnit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	id3, err := s.wordpressUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	otherId, err := s.mysqlUnit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	args := params.ActionExecutionResults{Results: []params.ActionExecutionResult{
+		{ActionId: id1, Status: string(state.ActionCompleted), Message: "all good"},
+		{ActionId: id2, Status: string(state.ActionFailed), Results: map[string]interface{}{"left": "behind"}, Message: "oops"},
+		{ActionId: otherId, Status: string(state.ActionCompleted)},
+		{ActionId: id3, Status: "running"},
+	}}
+	result, err := s.uniter.FinishActions(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{&params.Error{Message: `cannot finish action "` + id3 + `" with status "running"`}},
+		},
+	})
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Id(), gc.Equals, id3)
+
+	results, err := s.State.ActionResultsForAction(id1)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionCompleted)
+	c.Assert(results[0].Message(), gc.Equals, "all good")
+
+	results, err = s.State.ActionResultsForAction(id2)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Status(), gc.Equals, state.ActionFailed)
+	c.Assert(results[0].Message(), gc.Equals, "oops")
+	c.Assert(results[0].Results(), gc.DeepEquals, map[string]interface{}{"left": "behind"})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
@@ -20,6 +21,13 @@ var logger = loggo.GetLogger("juju.state.apiserver.usermanager")
 type UserManager interface {
 	AddUser(arg params.ModifyUsers) (params.ErrorResults, error)
 	RemoveUser(arg params.Entities) (params.ErrorResults, error)
+	GrantRole(args params.ModifyUserRoles) (params.ErrorResults, error)
+	RevokeRole(args params.ModifyUserRoles) (params.ErrorResults, error)
+	UserInfo(args params.Entities) (params.UserInfoResults, error)
+	ListUsers() (params.UserInfoResults, error)
+	EnableUser(args params.Entities) (params.ErrorResults, error)
+	DisableUser(args params.Entities) (params.ErrorResults, error)
+	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -28,6 +36,7 @@ type UserManagerAPI struct {
 	state       *state.State
 	authorizer  common.Authorizer
 	getCanWrite common.GetAuthFunc
+	getCanRead  common.GetAuthFunc
 }
 
 var _ UserManager = (*UserManagerAPI)(nil)
@@ -40,12 +49,26 @@ func NewUserManagerAPI(
 		return nil, common.ErrPerm
 	}
 
-	// TODO(mattyw) - replace stub with real canWrite function
-	getCanWrite := common.AuthAlways(true)
+	// Only environment administrators may manage users.
+	getCanWrite := func() (common.AuthFunc, error) {
+		isAdmin, err := common.AuthUserRole(st, authorizer, state.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		return func(tag string) bool {
+			return isAdmin
+		}, nil
+	}
+	// Users may also read their own details and change their
+	// own password.
+	getCanRead := common.AuthEither(getCanWrite, func() (common.AuthFunc, error) {
+		return authorizer.AuthOwner, nil
+	})
 	return &UserManagerAPI{
 			state:       st,
 			authorizer:  authorizer,
-			getCanWrite: getCanWrite},
+			getCanWrite: getCanWrite,
+			getCanRead:  getCanRead},
 		nil
 }
 
@@ -63,14 +86,14 @@ func (api *UserManagerAPI) AddUser(args params.ModifyUsers) (params.ErrorResults
 	}
 	for i, arg := range args.Changes {
 		if !canWrite(arg.Tag) {
-			result.Results[0].Error = common.ServerError(common.ErrPerm)
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
 		username := arg.Username
 		if username == "" {
 			username = arg.Tag
 		}
-		_, err := api.state.AddUser(username, arg.DisplayName, arg.Password)
+		_, err := api.state.AddUser(username, arg.DisplayName, arg.Password, api.creator())
 		if err != nil {
 			err = errors.Annotate(err, "failed to create user")
 			result.Results[i].Error = common.ServerError(err)
@@ -109,3 +132,196 @@ func (api *UserManagerAPI) RemoveUser(args params.Entities) (params.ErrorResults
 	}
 	return result, nil
 }
+
+// creator returns the name of the authenticated user, recorded as the
+// creator of the users it adds.
+func (api *UserManagerAPI) creator() string {
+	_, name, err := names.ParseTag(api.authorizer.GetAuthTag(), names.UserTagKind)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// GrantRole gives each of the given users at least the given role
+// in the environment.
+func (api *UserManagerAPI) GrantRole(args params.ModifyUserRoles) (params.ErrorResults, error) {
+	return api.modifyRoles(args, (*state.User).GrantRole)
+}
+
+// RevokeRole takes the given role away from each of the given users
+// in the environment, leaving them with the role below it.
+func (api *UserManagerAPI) RevokeRole(args params.ModifyUserRoles) (params.ErrorResults, error) {
+	return api.modifyRoles(args, (*state.User).RevokeRole)
+}
+
+func (api *UserManagerAPI) modifyRoles(
+	args params.ModifyUserRoles,
+	modify func(u *state.User, envUUID string, role state.Role) error,
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	canWrite, err := api.getCanWrite()
+	if err != nil {
+		return result, err
+	}
+	env, err := api.state.Environment()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Changes {
+		if !canWrite(names.UserTag(arg.Username)) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		role, err := state.ParseRole(arg.Role)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		user, err := api.state.User(arg.Username)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := modify(user, env.UUID(), role); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// UserInfo returns information about the users with the given tags.
+func (api *UserManagerAPI) UserInfo(args params.Entities) (params.UserInfoResults, error) {
+	result := params.UserInfoResults{
+		Results: make([]params.UserInfoResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		user, err := api.user(arg.Tag, canRead)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = userInfo(user)
+	}
+	return result, nil
+}
+
+// ListUsers returns information about all the users, including
+// the disabled ones.
+func (api *UserManagerAPI) ListUsers() (params.UserInfoResults, error) {
+	var result params.UserInfoResults
+	canWrite, err := api.getCanWrite()
+	if err != nil {
+		return result, err
+	}
+	if !canWrite(api.authorizer.GetAuthTag()) {
+		return result, common.ErrPerm
+	}
+	users, err := api.state.AllUsers()
+	if err != nil {
+		return result, err
+	}
+	result.Results = make([]params.UserInfoResult, len(users))
+	for i, user := range users {
+		result.Results[i].Result = userInfo(user)
+	}
+	return result, nil
+}
+
+// EnableUser allows the users with the given tags to log in again
+// after they have been disabled.
+func (api *UserManagerAPI) EnableUser(args params.Entities) (params.ErrorResults, error) {
+	return api.setActive(args, (*state.User).Activate)
+}
+
+// DisableUser stops the users with the given tags from logging in,
+// keeping their details so that they can be enabled again.
+func (api *UserManagerAPI) DisableUser(args params.Entities) (params.ErrorResults, error) {
+	return api.setActive(args, (*state.User).Deactivate)
+}
+
+func (api *UserManagerAPI) setActive(args params.Entities, set func(u *state.User) error) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canWrite, err := api.getCanWrite()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		user, err := api.user(arg.Tag, canWrite)
+		if err == nil {
+			err = set(user)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetPassword changes the passwords of the users with the given tags.
+func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Changes {
+		user, err := api.user(arg.Tag, canRead)
+		if err == nil {
+			if arg.Password == "" {
+				err = errors.New("cannot set an empty password")
+			} else {
+				err = user.SetPassword(arg.Password)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// user returns the user with the given tag if the
+// given authorization function allows access to it.
+func (api *UserManagerAPI) user(tag string, auth common.AuthFunc) (*state.User, error) {
+	if !auth(tag) {
+		return nil, common.ErrPerm
+	}
+	_, name, err := names.ParseTag(tag, names.UserTagKind)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	return api.state.User(name)
+}
+
+func userInfo(user *state.User) *params.UserInfo {
+	info := &params.UserInfo{
+		Username:    user.Name(),
+		DisplayName: user.DisplayName(),
+		CreatedBy:   user.CreatedBy(),
+		DateCreated: user.DateCreated(),
+		Disabled:    user.IsDeactivated(),
+	}
+	if last := user.LastConnection(); !last.IsZero() {
+		info.LastConnection = &last
+	}
+	return info
+}
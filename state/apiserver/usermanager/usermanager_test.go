@@ -4,9 +4,11 @@
 package usermanager_test
 
 import (
+	jc "github.com/juju/testing/checkers"
 	gc "launchpad.net/gocheck"
 
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api/params"
 	apiservertesting "github.com/juju/juju/state/apiserver/testing"
 	"github.com/juju/juju/state/apiserver/usermanager"
@@ -116,3 +118,182 @@ func (s *userManagerSuite) TestCannotAddRemoveAdd(c *gc.C) {
 		Results: []params.ErrorResult{
 			params.ErrorResult{expectedError}}})
 }
+
+func (s *userManagerSuite) TestGrantAndRevokeRole(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+
+	args := params.ModifyUserRoles{
+		Changes: []params.ModifyUserRole{{Username: "foobar", Role: "write"}},
+	}
+	result, err := s.usermanager.GrantRole(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{Error: nil}}})
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Role(env.UUID()), gc.Equals, state.RoleWrite)
+
+	result, err = s.usermanager.RevokeRole(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{Error: nil}}})
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Role(env.UUID()), gc.Equals, state.RoleRead)
+}
+
+func (s *userManagerSuite) TestGrantRoleErrors(c *gc.C) {
+	s.AddUser(c, "foobar")
+	args := params.ModifyUserRoles{
+		Changes: []params.ModifyUserRole{
+			{Username: "foobar", Role: "root"},
+			{Username: "nobody", Role: "read"},
+			{Username: "admin", Role: "read"},
+		},
+	}
+	result, err := s.usermanager.RevokeRole(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `invalid role "root"`)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `user "nobody" not found`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, "cannot change the role of the admin user")
+}
+
+func (s *userManagerSuite) TestNonAdminCannotManageUsers(c *gc.C) {
+	user := s.AddUser(c, "writer")
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = user.GrantRole(env.UUID(), state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	authorizer := s.authorizer
+	authorizer.Tag = "user-writer"
+	api, err := usermanager.NewUserManagerAPI(s.State, authorizer)
+	c.Assert(err, gc.IsNil)
+
+	addResult, err := api.AddUser(params.ModifyUsers{
+		Changes: []params.ModifyUser{{Username: "foobar", Password: "password"}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(addResult.Results[0].Error, gc.ErrorMatches, "permission denied")
+	_, err = s.State.User("foobar")
+	c.Assert(err, gc.ErrorMatches, `user "foobar" not found`)
+
+	grantResult, err := api.GrantRole(params.ModifyUserRoles{
+		Changes: []params.ModifyUserRole{{Username: "writer", Role: "admin"}},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(grantResult.Results[0].Error, gc.ErrorMatches, "permission denied")
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.Role(env.UUID()), gc.Equals, state.RoleWrite)
+}
+
+func (s *userManagerSuite) TestAddUserRecordsCreator(c *gc.C) {
+	args := params.ModifyUsers{
+		Changes: []params.ModifyUser{{Username: "foobar", Password: "password"}},
+	}
+	_, err := s.usermanager.AddUser(args)
+	c.Assert(err, gc.IsNil)
+	user, err := s.State.User("foobar")
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+}
+
+func (s *userManagerSuite) TestUserInfo(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	err := user.UpdateLastConnection()
+	c.Assert(err, gc.IsNil)
+	s.AddUser(c, "other")
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "user-foobar"}, {Tag: "user-nobody"}, {Tag: "machine-0"},
+	}}
+	results, err := s.usermanager.UserInfo(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	info := results.Results[0].Result
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(info.Username, gc.Equals, "foobar")
+	c.Assert(info.CreatedBy, gc.Equals, "admin")
+	c.Assert(info.DateCreated.IsZero(), jc.IsFalse)
+	c.Assert(info.LastConnection, gc.NotNil)
+	c.Assert(info.Disabled, jc.IsFalse)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `user "nobody" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "permission denied")
+
+	// Users without the admin role may only see themselves.
+	authorizer := s.authorizer
+	authorizer.Tag = "user-foobar"
+	api, err := usermanager.NewUserManagerAPI(s.State, authorizer)
+	c.Assert(err, gc.IsNil)
+	results, err = api.UserInfo(params.Entities{Entities: []params.Entity{
+		{Tag: "user-foobar"}, {Tag: "user-other"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Username, gc.Equals, "foobar")
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestListUsers(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	err := user.Deactivate()
+	c.Assert(err, gc.IsNil)
+
+	results, err := s.usermanager.ListUsers()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Result.Username, gc.Equals, "admin")
+	c.Assert(results.Results[1].Result.Username, gc.Equals, "foobar")
+	c.Assert(results.Results[1].Result.Disabled, jc.IsTrue)
+
+	authorizer := s.authorizer
+	authorizer.Tag = "user-foobar"
+	api, err := usermanager.NewUserManagerAPI(s.State, authorizer)
+	c.Assert(err, gc.IsNil)
+	_, err = api.ListUsers()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *userManagerSuite) TestDisableAndEnableUser(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	args := params.Entities{Entities: []params.Entity{{Tag: "user-foobar"}, {Tag: "user-admin"}}}
+
+	result, err := s.usermanager.DisableUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "Can't deactivate admin user")
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsTrue)
+
+	result, err = s.usermanager.EnableUser(args)
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.IsDeactivated(), jc.IsFalse)
+}
+
+func (s *userManagerSuite) TestSetPassword(c *gc.C) {
+	user := s.AddUser(c, "foobar")
+	s.AddUser(c, "other")
+	authorizer := s.authorizer
+	authorizer.Tag = "user-foobar"
+	api, err := usermanager.NewUserManagerAPI(s.State, authorizer)
+	c.Assert(err, gc.IsNil)
+
+	result, err := api.SetPassword(params.EntityPasswords{Changes: []params.EntityPassword{
+		{Tag: "user-foobar", Password: "new-password"},
+		{Tag: "user-foobar", Password: ""},
+		{Tag: "user-other", Password: "new-password"},
+	}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "cannot set an empty password")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, "permission denied")
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.PasswordValid("new-password"), jc.IsTrue)
+}
@@ -0,0 +1,173 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+)
+
+// AuditEntry records a call made through the API by a user that may
+// have changed the environment.
+type AuditEntry struct {
+	// Id identifies the entry in the audit log. It is set when the
+	// entry is read from the log, and ignored when it is added.
+	Id string
+	// Time records when the call was answered.
+	Time time.Time
+	// User holds the tag of the user that made the call.
+	User string
+	// Facade and Method name the API method called.
+	Facade string
+	Method string
+	// Args holds the arguments of the call, encoded as JSON, with any
+	// secrets removed.
+	Args string
+	// Entities holds the names of the entities the call touched, as
+	// given in its arguments: service and unit names, machine ids and
+	// tags.
+	Entities []string
+	// Error holds the error returned by the call. It is empty if the
+	// call succeeded.
+	Error string
+}
+
+// auditEntryDoc is the persistent form of AuditEntry.
+type auditEntryDoc struct {
+	Id       bson.ObjectId `bson:"_id"`
+	Time     time.Time
+	User     string
+	Facade   string
+	Method   string
+	Args     string
+	Entities []string
+	Error    string
+}
+
+func (doc *auditEntryDoc) entry() *AuditEntry {
+	return &AuditEntry{
+		Id:       doc.Id.Hex(),
+		Time:     doc.Time,
+		User:     doc.User,
+		Facade:   doc.Facade,
+		Method:   doc.Method,
+		Args:     doc.Args,
+		Entities: doc.Entities,
+		Error:    doc.Error,
+	}
+}
+
+// AddAuditEntry appends an entry to the audit log. The log is held in
+// a capped collection, so the oldest entries are discarded as new ones
+// are added. Entries are never changed once written, so they are
+// inserted directly rather than through a transaction.
+func (st *State) AddAuditEntry(entry AuditEntry) error {
+	if entry.User == "" {
+		return errors.New("audit entry has no user")
+	}
+	doc := &auditEntryDoc{
+		Id:       bson.NewObjectId(),
+		Time:     entry.Time.UTC(),
+		User:     entry.User,
+		Facade:   entry.Facade,
+		Method:   entry.Method,
+		Args:     entry.Args,
+		Entities: entry.Entities,
+		Error:    entry.Error,
+	}
+	if err := st.auditLog.Insert(doc); err != nil {
+		return errors.Annotate(err, "cannot add audit entry")
+	}
+	return nil
+}
+
+// AuditFilter selects entries from the audit log. Zero valued fields
+// select every entry.
+type AuditFilter struct {
+	// User selects the entries made by the user with the given tag.
+	User string
+	// After and Before select the entries made at or after After,
+	// and before Before.
+	After  time.Time
+	Before time.Time
+	// Entity selects the entries for the calls that touched the
+	// entity, which must be given as it was in the call: a service or
+	// unit name, a machine id, or a tag.
+	Entity string
+	// AfterId selects the entries following the identified one, so
+	// that the log can be read a page at a time. If that entry has
+	// since been discarded, so have all those before it, and the
+	// entries are selected from the oldest onwards.
+	AfterId string
+	// Limit holds the maximum number of entries to return.
+	Limit int
+}
+
+// AuditEntries returns the entries in the audit log selected by the
+// filter, oldest first.
+func (st *State) AuditEntries(filter AuditFilter) ([]*AuditEntry, error) {
+	sel := bson.M{}
+	if filter.AfterId != "" {
+		after, err := st.auditEntryAfter(filter.AfterId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if after != nil {
+			sel["$or"] = after
+		}
+	}
+	if filter.User != "" {
+		sel["user"] = filter.User
+	}
+	timeSel := bson.M{}
+	if !filter.After.IsZero() {
+		timeSel["$gte"] = filter.After.UTC()
+	}
+	if !filter.Before.IsZero() {
+		timeSel["$lt"] = filter.Before.UTC()
+	}
+	if len(timeSel) > 0 {
+		sel["time"] = timeSel
+	}
+	if filter.Entity != "" {
+		sel["entities"] = filter.Entity
+	}
+	query := st.auditLog.Find(sel).Sort("time", "_id")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditEntryDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	entries := make([]*AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = doc.entry()
+	}
+	return entries, nil
+}
+
+// auditEntryAfter returns the selector for the entries that follow the
+// identified one in the order they are returned, or nil if the entry
+// has been discarded from the log.
+func (st *State) auditEntryAfter(id string) ([]bson.M, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.Errorf("invalid audit entry id %q", id)
+	}
+	var doc auditEntryDoc
+	err := st.auditLog.FindId(bson.ObjectIdHex(id)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	return []bson.M{
+		{"time": bson.M{"$gt": doc.Time}},
+		{"time": doc.Time, "_id": bson.M{"$gt": doc.Id}},
+	}, nil
+}
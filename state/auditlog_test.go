@@ -0,0 +1,212 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+)
+
+type AuditLogSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+var auditTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *AuditLogSuite) addEntries(c *gc.C) {
+	for i, entry := range []state.AuditEntry{{
+		User:     "user-admin",
+		Facade:   "Client",
+		Method:   "ServiceDeploy",
+		Args:     `{"ServiceName":"wordpress"}`,
+		Entities: []string{"wordpress"},
+	}, {
+		User:     "user-bob",
+		Facade:   "Client",
+		Method:   "DestroyServiceUnits",
+		Args:     `{"UnitNames":["wordpress/0"]}`,
+		Entities: []string{"wordpress/0"},
+		Error:    `unit "wordpress/0" is not assigned to a machine`,
+	}, {
+		User:     "user-admin",
+		Facade:   "Client",
+		Method:   "ServiceDeploy",
+		Args:     `{"ServiceName":"wordpress2"}`,
+		Entities: []string{"wordpress2"},
+	}} {
+		entry.Time = auditTime.Add(time.Duration(i) * time.Hour)
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+func (s *AuditLogSuite) describe(c *gc.C, filter state.AuditFilter) []string {
+	entries, err := s.State.AuditEntries(filter)
+	c.Assert(err, gc.IsNil)
+	var described []string
+	for _, entry := range entries {
+		described = append(described, entry.User+" "+entry.Args)
+	}
+	return described
+}
+
+func (s *AuditLogSuite) TestAddAuditEntry(c *gc.C) {
+	s.addEntries(c)
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 3)
+	entry := entries[1]
+	c.Assert(entry.Time.Equal(auditTime.Add(time.Hour)), jc.IsTrue)
+	c.Assert(entry.User, gc.Equals, "user-bob")
+	c.Assert(entry.Facade, gc.Equals, "Client")
+	c.Assert(entry.Method, gc.Equals, "DestroyServiceUnits")
+	c.Assert(entry.Args, gc.Equals, `{"UnitNames":["wordpress/0"]}`)
+	c.Assert(entry.Entities, gc.DeepEquals, []string{"wordpress/0"})
+	c.Assert(entry.Id, gc.Not(gc.Equals), "")
+	c.Assert(entry.Error, gc.Equals, `unit "wordpress/0" is not assigned to a machine`)
+}
+
+func (s *AuditLogSuite) TestAuditLogDiscardsOldestEntries(c *gc.C) {
+	// Write rather more than the 1MB the audit log holds in tests.
+	args := `{"Data":"` + strings.Repeat("x", 10000) + `"}`
+	for i := 0; i < 300; i++ {
+		err := s.State.AddAuditEntry(state.AuditEntry{
+			Time:   auditTime.Add(time.Duration(i) * time.Second),
+			User:   "user-admin",
+			Facade: "Client",
+			Method: "ServiceSet",
+			Args:   args,
+		})
+		c.Assert(err, gc.IsNil)
+	}
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(len(entries) < 300, jc.IsTrue)
+	c.Assert(entries[0].Time.After(auditTime), jc.IsTrue)
+	c.Assert(entries[len(entries)-1].Time.Equal(auditTime.Add(299*time.Second)), jc.IsTrue)
+}
+
+func (s *AuditLogSuite) TestAddAuditEntryWithoutUser(c *gc.C) {
+	err := s.State.AddAuditEntry(state.AuditEntry{Facade: "Client", Method: "ServiceDeploy"})
+	c.Assert(err, gc.ErrorMatches, "audit entry has no user")
+}
+
+func (s *AuditLogSuite) TestAuditEntriesFilter(c *gc.C) {
+	s.addEntries(c)
+	for i, test := range []struct {
+		about  string
+		filter state.AuditFilter
+		expect []string
+	}{{
+		about:  "by user",
+		filter: state.AuditFilter{User: "user-admin"},
+		expect: []string{
+			`user-admin {"ServiceName":"wordpress"}`,
+			`user-admin {"ServiceName":"wordpress2"}`,
+		},
+	}, {
+		about:  "after",
+		filter: state.AuditFilter{After: auditTime.Add(time.Hour)},
+		expect: []string{
+			`user-bob {"UnitNames":["wordpress/0"]}`,
+			`user-admin {"ServiceName":"wordpress2"}`,
+		},
+	}, {
+		about:  "before",
+		filter: state.AuditFilter{Before: auditTime.Add(time.Hour)},
+		expect: []string{
+			`user-admin {"ServiceName":"wordpress"}`,
+		},
+	}, {
+		about:  "by entity",
+		filter: state.AuditFilter{Entity: "wordpress"},
+		expect: []string{
+			`user-admin {"ServiceName":"wordpress"}`,
+		},
+	}, {
+		about:  "by unit",
+		filter: state.AuditFilter{Entity: "wordpress/0"},
+		expect: []string{
+			`user-bob {"UnitNames":["wordpress/0"]}`,
+		},
+	}, {
+		about: "combined",
+		filter: state.AuditFilter{
+			User:   "user-admin",
+			After:  auditTime.Add(time.Minute),
+			Before: auditTime.Add(3 * time.Hour),
+		},
+		expect: []string{
+			`user-admin {"ServiceName":"wordpress2"}`,
+		},
+	}, {
+		about:  "by entity not touched",
+		filter: state.AuditFilter{Entity: "mysql"},
+	}, {
+		about:  "limit",
+		filter: state.AuditFilter{Limit: 2},
+		expect: []string{
+			`user-admin {"ServiceName":"wordpress"}`,
+			`user-bob {"UnitNames":["wordpress/0"]}`,
+		},
+	}, {
+		about:  "no match",
+		filter: state.AuditFilter{User: "user-nobody"},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		c.Check(s.describe(c, test.filter), jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *AuditLogSuite) TestAuditEntriesPaging(c *gc.C) {
+	s.addEntries(c)
+	// Entries made at the same time are paged through in the order
+	// they were added.
+	err := s.State.AddAuditEntry(state.AuditEntry{
+		Time:   auditTime.Add(2 * time.Hour),
+		User:   "user-bob",
+		Facade: "Client",
+		Method: "ServiceExpose",
+		Args:   `{"ServiceName":"wordpress2"}`,
+	})
+	c.Assert(err, gc.IsNil)
+
+	var described []string
+	filter := state.AuditFilter{Limit: 2}
+	for {
+		entries, err := s.State.AuditEntries(filter)
+		c.Assert(err, gc.IsNil)
+		if len(entries) == 0 {
+			break
+		}
+		c.Assert(len(entries) <= 2, jc.IsTrue)
+		for _, entry := range entries {
+			described = append(described, entry.User+" "+entry.Args)
+		}
+		filter.AfterId = entries[len(entries)-1].Id
+	}
+	c.Assert(described, jc.DeepEquals, []string{
+		`user-admin {"ServiceName":"wordpress"}`,
+		`user-bob {"UnitNames":["wordpress/0"]}`,
+		`user-admin {"ServiceName":"wordpress2"}`,
+		`user-bob {"ServiceName":"wordpress2"}`,
+	})
+}
+
+func (s *AuditLogSuite) TestAuditEntriesAfterDiscardedEntry(c *gc.C) {
+	s.addEntries(c)
+	entries, err := s.State.AuditEntries(state.AuditFilter{AfterId: "5432f2a9e37c3f1e6f000001"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(entries, gc.HasLen, 3)
+
+	_, err = s.State.AuditEntries(state.AuditFilter{AfterId: "foo"})
+	c.Assert(err, gc.ErrorMatches, `invalid audit entry id "foo"`)
+}
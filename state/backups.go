@@ -0,0 +1,275 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+	"labix.org/v2/mgo/txn"
+
+	"github.com/juju/juju/version"
+)
+
+// BackupMetadata describes a backup archive of the state server. The
+// archive itself is held in environment storage, under the backup's
+// id.
+type BackupMetadata struct {
+	// Id uniquely identifies the backup.
+	Id string
+	// Notes holds the free-form text supplied when the backup was
+	// requested.
+	Notes string
+	// Scheduled reports whether the backup was taken on the schedule
+	// set in the environment configuration, rather than on request.
+	Scheduled bool
+	// Started and Finished record when the backup was taken.
+	Started  time.Time
+	Finished time.Time
+	// Checksum holds the checksum of the archive, in the format
+	// described by ChecksumFormat.
+	Checksum       string
+	ChecksumFormat string
+	// Size holds the size of the archive in bytes.
+	Size int64
+	// Stored reports whether the archive has been saved to storage.
+	Stored bool
+	// Environment holds the UUID of the environment backed up.
+	Environment string
+	// Machine and Hostname identify the state server backed up.
+	Machine  string
+	Hostname string
+	// Version holds the version of juju that took the backup.
+	Version version.Number
+}
+
+// backupMetadataDoc is the persistent form of BackupMetadata.
+type backupMetadataDoc struct {
+	Id             string `bson:"_id"`
+	Notes          string
+	Scheduled      bool
+	Started        time.Time
+	Finished       time.Time
+	Checksum       string
+	ChecksumFormat string
+	Size           int64
+	Stored         bool
+	Environment    string
+	Machine        string
+	Hostname       string
+	Version        version.Number
+}
+
+func (doc *backupMetadataDoc) metadata() *BackupMetadata {
+	return &BackupMetadata{
+		Id:             doc.Id,
+		Notes:          doc.Notes,
+		Scheduled:      doc.Scheduled,
+		Started:        doc.Started,
+		Finished:       doc.Finished,
+		Checksum:       doc.Checksum,
+		ChecksumFormat: doc.ChecksumFormat,
+		Size:           doc.Size,
+		Stored:         doc.Stored,
+		Environment:    doc.Environment,
+		Machine:        doc.Machine,
+		Hostname:       doc.Hostname,
+		Version:        doc.Version,
+	}
+}
+
+// AddBackupMetadata records the metadata of a new backup. The archive
+// is not yet known to be stored, whatever the value of meta.Stored;
+// call SetBackupStored once it is.
+func (st *State) AddBackupMetadata(meta BackupMetadata) error {
+	if meta.Id == "" {
+		return errors.New("backup metadata has no id")
+	}
+	doc := &backupMetadataDoc{
+		Id:             meta.Id,
+		Notes:          meta.Notes,
+		Scheduled:      meta.Scheduled,
+		Started:        meta.Started.UTC(),
+		Finished:       meta.Finished.UTC(),
+		Checksum:       meta.Checksum,
+		ChecksumFormat: meta.ChecksumFormat,
+		Size:           meta.Size,
+		Environment:    meta.Environment,
+		Machine:        meta.Machine,
+		Hostname:       meta.Hostname,
+		Version:        meta.Version,
+	}
+	ops := []txn.Op{{
+		C:      st.backupsMetadata.Name,
+		Id:     meta.Id,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.Errorf("backup %q already exists", meta.Id)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot add metadata for backup %q", meta.Id)
+	}
+	return nil
+}
+
+// SetBackupStored records that the archive of the identified backup
+// has been saved to storage.
+func (st *State) SetBackupStored(id string) error {
+	ops := []txn.Op{{
+		C:      st.backupsMetadata.Name,
+		Id:     id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"stored", true}}}},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("backup %q", id)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot update metadata for backup %q", id)
+	}
+	return nil
+}
+
+// BackupMetadata returns the metadata of the identified backup.
+func (st *State) BackupMetadata(id string) (*BackupMetadata, error) {
+	var doc backupMetadataDoc
+	err := st.backupsMetadata.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("backup %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get metadata for backup %q", id)
+	}
+	return doc.metadata(), nil
+}
+
+// AllBackupMetadata returns the metadata of every backup, oldest first.
+func (st *State) AllBackupMetadata() ([]*BackupMetadata, error) {
+	var docs []backupMetadataDoc
+	if err := st.backupsMetadata.Find(nil).Sort("started").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get backup metadata")
+	}
+	result := make([]*BackupMetadata, len(docs))
+	for i := range docs {
+		result[i] = docs[i].metadata()
+	}
+	return result, nil
+}
+
+// RemoveBackupMetadata removes the metadata of the identified backup.
+// It is not an error if there is none.
+func (st *State) RemoveBackupMetadata(id string) error {
+	ops := []txn.Op{{
+		C:      st.backupsMetadata.Name,
+		Id:     id,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot remove metadata for backup %q", id)
+	}
+	return nil
+}
+
+// ScheduledBackupsStatus records the outcome of the most recent
+// scheduled backups of the state server.
+type ScheduledBackupsStatus struct {
+	// LastSuccess records when the most recent successful scheduled
+	// backup finished, and LastBackupId identifies it.
+	LastSuccess  time.Time
+	LastBackupId string
+	// LastFailure records when the most recent failed scheduled backup
+	// was attempted, and LastError describes why it failed.
+	LastFailure time.Time
+	LastError   string
+}
+
+// scheduledBackupsStatusDoc is the persistent form of
+// ScheduledBackupsStatus. There is a single one per environment.
+type scheduledBackupsStatusDoc struct {
+	Id           string `bson:"_id"`
+	LastSuccess  time.Time
+	LastBackupId string
+	LastFailure  time.Time
+	LastError    string
+}
+
+// ScheduledBackupsStatus returns the outcome of the most recent
+// scheduled backups. It returns a not found error if no scheduled
+// backup has been attempted.
+func (st *State) ScheduledBackupsStatus() (*ScheduledBackupsStatus, error) {
+	var doc scheduledBackupsStatusDoc
+	err := st.backupsStatus.FindId(environGlobalKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("scheduled backups status")
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get scheduled backups status")
+	}
+	return &ScheduledBackupsStatus{
+		LastSuccess:  doc.LastSuccess,
+		LastBackupId: doc.LastBackupId,
+		LastFailure:  doc.LastFailure,
+		LastError:    doc.LastError,
+	}, nil
+}
+
+// SetScheduledBackupSucceeded records that the identified scheduled
+// backup finished at the given time.
+func (st *State) SetScheduledBackupSucceeded(id string, finished time.Time) error {
+	return st.setScheduledBackupsStatus(bson.D{
+		{"lastsuccess", finished.UTC()},
+		{"lastbackupid", id},
+	})
+}
+
+// SetScheduledBackupFailed records that the scheduled backup attempted
+// at the given time failed with the given error.
+func (st *State) SetScheduledBackupFailed(attempted time.Time, backupErr error) error {
+	return st.setScheduledBackupsStatus(bson.D{
+		{"lastfailure", attempted.UTC()},
+		{"lasterror", backupErr.Error()},
+	})
+}
+
+// setScheduledBackupsStatus sets the given fields of the scheduled
+// backups status, creating it if necessary.
+func (st *State) setScheduledBackupsStatus(fields bson.D) error {
+	ops := []txn.Op{{
+		C:      st.backupsStatus.Name,
+		Id:     environGlobalKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", fields}},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		doc := bson.M{"_id": environGlobalKey}
+		for _, field := range fields {
+			doc[field.Name] = field.Value
+		}
+		ops = []txn.Op{{
+			C:      st.backupsStatus.Name,
+			Id:     environGlobalKey,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}
+		err = st.runTransaction(ops)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set scheduled backups status")
+	}
+	return nil
+}
+
+// MongoConnectionInfo returns information for connecting to the mongo
+// database holding the state. It is exposed so that the database can
+// be backed up, and should not otherwise be used.
+func (st *State) MongoConnectionInfo() *Info {
+	return st.info
+}
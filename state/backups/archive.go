@@ -0,0 +1,185 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// archiveDir is the directory at the root of the archive, which
+	// holds everything else.
+	archiveDir = "juju-backup"
+	// filesArchive is the name of the tarball within the archive that
+	// holds the state server's files. Keeping the files in a tarball of
+	// their own preserves their ownership and permissions.
+	filesArchive = "root.tar"
+	// dumpDir is the directory within the archive that holds the
+	// database dump.
+	dumpDir = "dump"
+
+	checksumFormat = "SHA-1, base64 encoded"
+)
+
+// archiveFile is a backup archive held in a temporary directory, which
+// is removed when the file is closed.
+type archiveFile struct {
+	*os.File
+	tempDir  string
+	size     int64
+	checksum string
+}
+
+// Close closes the archive and removes it.
+func (a *archiveFile) Close() error {
+	err := a.File.Close()
+	if err := os.RemoveAll(a.tempDir); err != nil {
+		logger.Warningf("cannot remove temporary backup directory: %v", err)
+	}
+	return err
+}
+
+// createArchive dumps the database described by dbInfo and collects the
+// state server's files found at the given paths, and returns a gzipped
+// tarball holding them both. The archive has the layout the restore
+// process expects: a juju-backup directory holding root.tar, with the
+// files, and dump, with the database dump.
+func createArchive(paths Paths, dbInfo *DBInfo) (_ *archiveFile, err error) {
+	tempDir, err := ioutil.TempDir("", "jujuBackup")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create temporary directory")
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tempDir)
+		}
+	}()
+	contentDir := filepath.Join(tempDir, archiveDir)
+	if err := os.Mkdir(contentDir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	files, err := backupFiles(paths)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list files to back up")
+	}
+	if err := writeFilesArchive(filepath.Join(contentDir, filesArchive), files); err != nil {
+		return nil, errors.Annotate(err, "cannot archive files")
+	}
+	if err := dumpDatabase(dbInfo, filepath.Join(contentDir, dumpDir)); err != nil {
+		return nil, errors.Annotate(err, "cannot dump database")
+	}
+
+	f, err := os.Create(filepath.Join(tempDir, archiveDir+".tgz"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hash := sha1.New()
+	if err := writeArchive(io.MultiWriter(f, hash), tempDir, archiveDir); err != nil {
+		f.Close()
+		return nil, errors.Trace(err)
+	}
+	size, err := f.Seek(0, os.SEEK_CUR)
+	if err == nil {
+		_, err = f.Seek(0, os.SEEK_SET)
+	}
+	if err != nil {
+		f.Close()
+		return nil, errors.Annotate(err, "cannot rewind archive")
+	}
+	return &archiveFile{
+		File:     f,
+		tempDir:  tempDir,
+		size:     size,
+		checksum: base64.StdEncoding.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// writeFilesArchive writes an uncompressed tarball to the given path,
+// holding the named files and directories under their absolute paths.
+func writeFilesArchive(path string, files []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	for _, file := range files {
+		if err := addToTar(tw, file, strings.TrimPrefix(file, "/")); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return f.Close()
+}
+
+// writeArchive writes a gzipped tarball of the directory name within
+// dir to w.
+func writeArchive(w io.Writer, dir, name string) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	if err := addToTar(tw, filepath.Join(dir, name), name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return gzw.Close()
+}
+
+// addToTar adds the file or directory at path to the tarball under the
+// given name, along with everything within it. Symbolic links are
+// stored as links, and not followed.
+func addToTar(tw *tar.Writer, path, name string) error {
+	return filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return errors.Annotatef(err, "cannot archive %q", file)
+		}
+		rel, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(name, rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.Annotatef(err, "cannot write header for %q", file)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(tw, f); err != nil {
+			return errors.Annotatef(err, "cannot archive %q", file)
+		}
+		return nil
+	})
+}
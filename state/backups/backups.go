@@ -0,0 +1,251 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backups creates archives of a state server, keeps them in
+// environment storage, and restores state servers from them.
+//
+// An archive holds a dump of the state database, taken without
+// stopping it, along with the agent configuration, certificates and
+// logs of the state server.
+package backups
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.state.backups")
+
+// storageNamespace is the name of the GridFS in which backup archives
+// are stored.
+const storageNamespace = "backups"
+
+// Paths holds the locations on the state server of the files to be
+// included in a backup.
+type Paths struct {
+	// DataDir is the agents' data directory, such as /var/lib/juju.
+	DataDir string
+	// LogDir is the agents' log directory, such as /var/log/juju.
+	LogDir string
+}
+
+// Backups creates, retrieves and removes the backups of a state
+// server.
+type Backups interface {
+	// Create takes a new backup of the state server, using the files
+	// found at the given paths, and stores it. The notes are recorded
+	// with the backup's metadata.
+	Create(paths Paths, notes string) (*state.BackupMetadata, error)
+
+	// CreateScheduled is like Create, but records that the backup was
+	// taken on schedule, so that it can be told apart from backups
+	// taken on request.
+	CreateScheduled(paths Paths, notes string) (*state.BackupMetadata, error)
+
+	// Get returns the metadata of the identified backup and a reader
+	// for its archive.
+	Get(id string) (*state.BackupMetadata, io.ReadCloser, error)
+
+	// List returns the metadata of every backup, oldest first.
+	List() ([]*state.BackupMetadata, error)
+
+	// Remove removes the identified backup and its archive.
+	Remove(id string) error
+
+	// Add stores the backup archive read from r, which was taken
+	// elsewhere, and returns its metadata. The notes are recorded with
+	// the backup's metadata.
+	Add(archive io.Reader, notes string) (*state.BackupMetadata, error)
+
+	// Restore replaces the state of the state server with that held
+	// in the identified backup. The state server must be the only one
+	// in the environment; it takes the place of the state server the
+	// backup was taken from. The machine agent needs to be restarted
+	// afterwards.
+	Restore(paths Paths, id string) error
+}
+
+type backups struct {
+	st      *state.State
+	storage storage.ResourceStorage
+}
+
+// NewBackups returns a Backups that stores archives in the GridFS of
+// the given state's database.
+func NewBackups(st *state.State) Backups {
+	return &backups{
+		st:      st,
+		storage: storage.NewGridFS(storageNamespace, st.MongoSession()),
+	}
+}
+
+// newBackupId returns the id of a backup of the given environment
+// started at the given time.
+func newBackupId(started time.Time, envUUID string) string {
+	return started.UTC().Format("20060102-150405") + "." + envUUID
+}
+
+// Create is defined on Backups.
+func (b *backups) Create(paths Paths, notes string) (*state.BackupMetadata, error) {
+	return b.create(paths, notes, false)
+}
+
+// CreateScheduled is defined on Backups.
+func (b *backups) CreateScheduled(paths Paths, notes string) (*state.BackupMetadata, error) {
+	return b.create(paths, notes, true)
+}
+
+// create takes and stores a new backup, recording whether it was
+// scheduled.
+func (b *backups) create(paths Paths, notes string, scheduled bool) (*state.BackupMetadata, error) {
+	env, err := b.st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	started := time.Now().UTC()
+	meta := state.BackupMetadata{
+		Id:          newBackupId(started, env.UUID()),
+		Notes:       notes,
+		Scheduled:   scheduled,
+		Started:     started,
+		Environment: env.UUID(),
+		Machine:     machineId(b.st),
+		Version:     version.Current.Number,
+	}
+	if meta.Hostname, err = os.Hostname(); err != nil {
+		logger.Warningf("cannot get hostname: %v", err)
+	}
+
+	archive, err := createArchive(paths, newDBInfo(b.st))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create backup archive")
+	}
+	defer archive.Close()
+	meta.Finished = time.Now().UTC()
+	meta.Size = archive.size
+	meta.Checksum = archive.checksum
+	meta.ChecksumFormat = checksumFormat
+
+	if err := b.store(&meta, archive); err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("created backup %q (%d bytes)", meta.Id, meta.Size)
+	return &meta, nil
+}
+
+// store records the given metadata and stores the archive, which holds
+// meta.Size bytes, with it.
+func (b *backups) store(meta *state.BackupMetadata, archive io.Reader) error {
+	if err := b.st.AddBackupMetadata(*meta); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := b.storage.Put(meta.Id, archive, meta.Size); err != nil {
+		if err := b.st.RemoveBackupMetadata(meta.Id); err != nil {
+			logger.Errorf("cannot clean up after failed backup: %v", err)
+		}
+		return errors.Annotate(err, "cannot store backup archive")
+	}
+	if err := b.st.SetBackupStored(meta.Id); err != nil {
+		return errors.Trace(err)
+	}
+	meta.Stored = true
+	return nil
+}
+
+// Add is defined on Backups.
+func (b *backups) Add(archive io.Reader, notes string) (*state.BackupMetadata, error) {
+	env, err := b.st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The archive is copied to a temporary file first, so that its size
+	// and checksum are known before it is stored.
+	f, err := ioutil.TempFile("", "jujuBackup")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create temporary file")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	hash := sha1.New()
+	size, err := io.Copy(io.MultiWriter(f, hash), archive)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read backup archive")
+	}
+	if size == 0 {
+		return nil, errors.New("empty backup archive")
+	}
+	if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+		return nil, errors.Annotate(err, "cannot rewind archive")
+	}
+	now := time.Now().UTC()
+	meta := state.BackupMetadata{
+		Id:             newBackupId(now, env.UUID()),
+		Notes:          notes,
+		Started:        now,
+		Finished:       now,
+		Checksum:       base64.StdEncoding.EncodeToString(hash.Sum(nil)),
+		ChecksumFormat: checksumFormat,
+		Size:           size,
+		Environment:    env.UUID(),
+	}
+	if err := b.store(&meta, f); err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("added backup %q (%d bytes)", meta.Id, meta.Size)
+	return &meta, nil
+}
+
+// machineId returns the id of the machine whose credentials were used
+// to connect to the given state, or the empty string if it was not a
+// machine.
+func machineId(st *state.State) string {
+	_, id, err := names.ParseTag(st.MongoConnectionInfo().Tag, names.MachineTagKind)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// Get is defined on Backups.
+func (b *backups) Get(id string) (*state.BackupMetadata, io.ReadCloser, error) {
+	meta, err := b.st.BackupMetadata(id)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !meta.Stored {
+		return nil, nil, errors.NotFoundf("archive for backup %q", id)
+	}
+	archive, err := b.storage.Get(id)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "cannot get archive for backup %q", id)
+	}
+	return meta, archive, nil
+}
+
+// List is defined on Backups.
+func (b *backups) List() ([]*state.BackupMetadata, error) {
+	return b.st.AllBackupMetadata()
+}
+
+// Remove is defined on Backups.
+func (b *backups) Remove(id string) error {
+	if _, err := b.st.BackupMetadata(id); err != nil {
+		return errors.Trace(err)
+	}
+	if err := b.storage.Remove(id); err != nil {
+		return errors.Annotatef(err, "cannot remove archive for backup %q", id)
+	}
+	return b.st.RemoveBackupMetadata(id)
+}
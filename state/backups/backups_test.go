@@ -0,0 +1,458 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jujuerrors "github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/agent"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/version"
+)
+
+type backupsSuite struct {
+	jujutesting.JujuConnSuite
+	paths   backups.Paths
+	dumped  []string
+	backups backups.Backups
+}
+
+var _ = gc.Suite(&backupsSuite{})
+
+func (s *backupsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	root := c.MkDir()
+	s.paths = backups.Paths{
+		DataDir: filepath.Join(root, "var", "lib", "juju"),
+		LogDir:  filepath.Join(root, "var", "log", "juju"),
+	}
+	s.PatchValue(backups.InitDir, filepath.Join(root, "etc", "init"))
+	s.PatchValue(backups.RsyslogDir, filepath.Join(root, "etc", "rsyslog.d"))
+	s.PatchValue(backups.SSHDir, filepath.Join(root, "home", "ubuntu", ".ssh"))
+	for _, file := range []string{
+		filepath.Join(root, "etc", "init", "juju-db.conf"),
+		filepath.Join(root, "etc", "init", "jujud-machine-0.conf"),
+		filepath.Join(root, "etc", "init", "jujud-unit-mysql-0.conf"),
+		filepath.Join(s.paths.DataDir, "agents", "machine-0", "agent.conf"),
+		filepath.Join(s.paths.DataDir, "agents", "unit-mysql-0", "agent.conf"),
+		filepath.Join(s.paths.DataDir, "server.pem"),
+		filepath.Join(s.paths.DataDir, "shared-secret"),
+		filepath.Join(root, "home", "ubuntu", ".ssh", "authorized_keys"),
+		filepath.Join(root, "etc", "rsyslog.d", "25-juju.conf"),
+		filepath.Join(s.paths.LogDir, "all-machines.log"),
+		filepath.Join(s.paths.LogDir, "machine-0.log"),
+		filepath.Join(s.paths.LogDir, "unit-mysql-0.log"),
+	} {
+		err := os.MkdirAll(filepath.Dir(file), 0755)
+		c.Assert(err, gc.IsNil)
+		err = ioutil.WriteFile(file, []byte(filepath.Base(file)), 0600)
+		c.Assert(err, gc.IsNil)
+	}
+
+	s.dumped = nil
+	s.PatchValue(backups.GetMongodumpPath, func() (string, error) {
+		return "/path/to/mongodump", nil
+	})
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		c.Check(name, gc.Equals, "/path/to/mongodump")
+		var out string
+		for i, arg := range args {
+			if arg == "--out" {
+				out = args[i+1]
+			}
+		}
+		s.dumped = append(s.dumped, out)
+		file := filepath.Join(out, "juju", "machines.bson")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(file, []byte("machines"), 0644)
+	})
+	s.backups = backups.NewBackups(s.State)
+}
+
+func (s *backupsSuite) TestBackupFiles(c *gc.C) {
+	files, err := backups.BackupFiles(s.paths)
+	c.Assert(err, gc.IsNil)
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	c.Assert(names, gc.DeepEquals, []string{
+		"juju-db.conf",
+		"jujud-machine-0.conf",
+		"machine-0",
+		"server.pem",
+		"shared-secret",
+		"authorized_keys",
+		"25-juju.conf",
+		"all-machines.log",
+		"machine-0.log",
+	})
+}
+
+// readArchive returns the contents of the regular files in the
+// gzipped tarball read from r, keyed by name.
+func readArchive(c *gc.C, r io.Reader) map[string]string {
+	gzr, err := gzip.NewReader(r)
+	c.Assert(err, gc.IsNil)
+	return readTar(c, gzr)
+}
+
+func readTar(c *gc.C, r io.Reader) map[string]string {
+	contents := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, gc.IsNil)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		c.Assert(err, gc.IsNil)
+		contents[hdr.Name] = string(data)
+	}
+	return contents
+}
+
+func (s *backupsSuite) TestCreate(c *gc.C) {
+	meta, err := s.backups.Create(s.paths, "before upgrade")
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.dumped, gc.HasLen, 1)
+
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta.Id, gc.Matches, `\d{8}-\d{6}\.`+env.UUID())
+	c.Assert(meta.Notes, gc.Equals, "before upgrade")
+	c.Assert(meta.Scheduled, jc.IsFalse)
+	c.Assert(meta.Environment, gc.Equals, env.UUID())
+	c.Assert(meta.Version, gc.Equals, version.Current.Number)
+	c.Assert(meta.Stored, jc.IsTrue)
+	c.Assert(meta.Finished.Before(meta.Started), jc.IsFalse)
+	c.Assert(meta.ChecksumFormat, gc.Equals, "SHA-1, base64 encoded")
+
+	got, archive, err := s.backups.Get(meta.Id)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	c.Assert(got.Id, gc.Equals, meta.Id)
+	c.Assert(got.Stored, jc.IsTrue)
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Assert(int64(len(data)), gc.Equals, meta.Size)
+	hash := sha1.Sum(data)
+	c.Assert(base64.StdEncoding.EncodeToString(hash[:]), gc.Equals, meta.Checksum)
+
+	contents := readArchive(c, bytes.NewReader(data))
+	c.Assert(contents["juju-backup/dump/juju/machines.bson"], gc.Equals, "machines")
+	root, ok := contents["juju-backup/root.tar"]
+	c.Assert(ok, jc.IsTrue)
+	files := readTar(c, bytes.NewReader([]byte(root)))
+	agentConf := filepath.Join(s.paths.DataDir, "agents", "machine-0", "agent.conf")
+	c.Assert(files[agentConf[1:]], gc.Equals, "agent.conf")
+	machineLog := filepath.Join(s.paths.LogDir, "machine-0.log")
+	c.Assert(files[machineLog[1:]], gc.Equals, "machine-0.log")
+	for name := range files {
+		c.Check(name, gc.Not(gc.Matches), ".*unit-mysql-0.*")
+	}
+}
+
+func (s *backupsSuite) TestCreateScheduled(c *gc.C) {
+	meta, err := s.backups.CreateScheduled(s.paths, "scheduled backup")
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta.Notes, gc.Equals, "scheduled backup")
+	c.Assert(meta.Scheduled, jc.IsTrue)
+
+	got, err := s.State.BackupMetadata(meta.Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Scheduled, jc.IsTrue)
+}
+
+func (s *backupsSuite) TestCreateDumpFails(c *gc.C) {
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		return errors.New("mongodump failed: boom")
+	})
+	_, err := s.backups.Create(s.paths, "")
+	c.Assert(err, gc.ErrorMatches, "cannot create backup archive: cannot dump database: mongodump failed: boom")
+	metas, err := s.backups.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestDumpPasswordNotOnCommandLine(c *gc.C) {
+	var gotInput string
+	var gotArgs []string
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		gotInput, gotArgs = input, args
+		return nil
+	})
+	info := &backups.DBInfo{
+		Address:  "localhost:37017",
+		Username: "machine-0",
+		Password: "sekrit",
+	}
+	err := backups.DumpDatabase(info, c.MkDir())
+	c.Assert(err, gc.IsNil)
+	c.Assert(gotInput, gc.Equals, "sekrit\n")
+	c.Assert(gotArgs[len(gotArgs)-1], gc.Equals, "--password")
+	for _, arg := range gotArgs {
+		c.Assert(arg, gc.Not(jc.Contains), "sekrit")
+	}
+}
+
+func (s *backupsSuite) TestRestoreDatabaseArgs(c *gc.C) {
+	var gotInput, gotName string
+	var gotArgs []string
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "/path/to/mongorestore", nil
+	})
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		gotInput, gotName, gotArgs = input, name, args
+		return nil
+	})
+	info := &backups.DBInfo{
+		Address:  "localhost:37017",
+		Username: "machine-0",
+		Password: "sekrit",
+	}
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "oplog.bson"), nil, 0644)
+	c.Assert(err, gc.IsNil)
+	err = os.Mkdir(filepath.Join(dir, "admin"), 0755)
+	c.Assert(err, gc.IsNil)
+
+	err = backups.RestoreDatabase(info, dir)
+	c.Assert(err, gc.IsNil)
+	c.Assert(gotName, gc.Equals, "/path/to/mongorestore")
+	c.Assert(gotInput, gc.Equals, "sekrit\n")
+	c.Assert(gotArgs, gc.DeepEquals, []string{
+		"--drop",
+		"--ssl",
+		"--host", "localhost:37017",
+		"--dir", dir,
+		"--oplogReplay",
+		"--authenticationDatabase", "admin",
+		"--username", "machine-0",
+		"--password",
+	})
+	_, err = os.Stat(filepath.Join(dir, "admin"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *backupsSuite) TestListAndRemove(c *gc.C) {
+	meta, err := s.backups.Create(s.paths, "")
+	c.Assert(err, gc.IsNil)
+	metas, err := s.backups.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 1)
+	c.Assert(metas[0].Id, gc.Equals, meta.Id)
+
+	err = s.backups.Remove(meta.Id)
+	c.Assert(err, gc.IsNil)
+	metas, err = s.backups.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 0)
+	_, _, err = s.backups.Get(meta.Id)
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+
+	err = s.backups.Remove(meta.Id)
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+func (s *backupsSuite) TestAdd(c *gc.C) {
+	data := "archive data"
+	meta, err := s.backups.Add(strings.NewReader(data), "from elsewhere")
+	c.Assert(err, gc.IsNil)
+	c.Assert(meta.Notes, gc.Equals, "from elsewhere")
+	c.Assert(meta.Size, gc.Equals, int64(len(data)))
+	c.Assert(meta.Stored, jc.IsTrue)
+	hash := sha1.Sum([]byte(data))
+	c.Assert(meta.Checksum, gc.Equals, base64.StdEncoding.EncodeToString(hash[:]))
+
+	_, archive, err := s.backups.Get(meta.Id)
+	c.Assert(err, gc.IsNil)
+	defer archive.Close()
+	got, err := ioutil.ReadAll(archive)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(got), gc.Equals, data)
+}
+
+func (s *backupsSuite) TestAddEmpty(c *gc.C) {
+	_, err := s.backups.Add(strings.NewReader(""), "")
+	c.Assert(err, gc.ErrorMatches, "empty backup archive")
+}
+
+// writeAgentConfig writes the configuration of the state server's
+// machine agent, with the given password and value for the "origin"
+// key.
+func (s *backupsSuite) writeAgentConfig(c *gc.C, password, origin string) {
+	conf, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		DataDir:           s.paths.DataDir,
+		Tag:               "machine-0",
+		Password:          password,
+		UpgradedToVersion: version.Current.Number,
+		CACert:            "ca cert",
+		StateAddresses:    []string{"localhost:37017"},
+		APIAddresses:      []string{"old-host:17070"},
+		Values:            map[string]string{"origin": origin},
+	})
+	c.Assert(err, gc.IsNil)
+	conf.SetPassword(password)
+	err = conf.Write()
+	c.Assert(err, gc.IsNil)
+}
+
+// addStateServer adds the state server machine to restore onto.
+func (s *backupsSuite) addStateServer(c *gc.C) *state.Machine {
+	m, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	err = m.SetProvisioned("new-instance", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	return m
+}
+
+func (s *backupsSuite) TestRestore(c *gc.C) {
+	m := s.addStateServer(c)
+	hostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses("10.0.0.1"), 17070),
+	}
+	err := s.State.SetAPIHostPorts(hostPorts)
+	c.Assert(err, gc.IsNil)
+
+	s.writeAgentConfig(c, "backed-up-password-0", "backup")
+	meta, err := s.backups.Create(s.paths, "")
+	c.Assert(err, gc.IsNil)
+	s.writeAgentConfig(c, "new-server-password-0", "new server")
+
+	var restored []string
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "/path/to/mongorestore", nil
+	})
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		c.Check(name, gc.Equals, "/path/to/mongorestore")
+		c.Check(args[0], gc.Equals, "--drop")
+		var dir string
+		for i, arg := range args {
+			if arg == "--dir" {
+				dir = args[i+1]
+			}
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, "juju", "machines.bson"))
+		c.Check(err, gc.IsNil)
+		c.Check(string(data), gc.Equals, "machines")
+		restored = append(restored, dir)
+		return nil
+	})
+	err = s.backups.Restore(s.paths, meta.Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(restored, gc.HasLen, 1)
+
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.paths.DataDir, "machine-0"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(conf.Value("origin"), gc.Equals, "backup")
+	c.Assert(conf.APIInfo().Password, gc.Equals, "new-server-password-0")
+	addrs, err := conf.APIAddresses()
+	c.Assert(err, gc.IsNil)
+	c.Assert(addrs, gc.DeepEquals, []string{"10.0.0.1:17070"})
+
+	err = m.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(m.PasswordValid("new-server-password-0"), jc.IsTrue)
+	instId, err := m.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(instId), gc.Equals, "new-instance")
+}
+
+func (s *backupsSuite) TestRestoreWithoutAgentConfig(c *gc.C) {
+	s.addStateServer(c)
+	err := os.RemoveAll(filepath.Join(s.paths.DataDir, "agents", "machine-0"))
+	c.Assert(err, gc.IsNil)
+	meta, err := s.backups.Create(s.paths, "")
+	c.Assert(err, gc.IsNil)
+	s.writeAgentConfig(c, "new-server-password-0", "new server")
+
+	s.PatchValue(backups.RunCommand, func(input, name string, args ...string) error {
+		c.Errorf("unexpected command %s", name)
+		return nil
+	})
+	err = s.backups.Restore(s.paths, meta.Id)
+	c.Assert(err, gc.ErrorMatches, `cannot restore backup ".*": cannot read backed up agent configuration: ".*/agent.conf" in archive not found`)
+}
+
+func (s *backupsSuite) TestRestoreUnknownBackup(c *gc.C) {
+	err := s.backups.Restore(s.paths, "backup-0")
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+// gzippedTar returns a gzipped tarball holding the given entries.
+func gzippedTar(c *gc.C, hdrs ...*tar.Header) io.Reader {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, hdr := range hdrs {
+		err := tw.WriteHeader(hdr)
+		c.Assert(err, gc.IsNil)
+		if hdr.Typeflag == tar.TypeReg {
+			_, err = tw.Write([]byte(hdr.Name))
+			c.Assert(err, gc.IsNil)
+		}
+	}
+	c.Assert(tw.Close(), gc.IsNil)
+	c.Assert(gzw.Close(), gc.IsNil)
+	return &buf
+}
+
+func (s *backupsSuite) TestExtractArchiveRejectsEscapingLinks(c *gc.C) {
+	for i, linkname := range []string{
+		"/etc",
+		"..",
+		"../outside",
+		"sub/../..",
+		"self/..",
+	} {
+		c.Logf("test %d: %q", i, linkname)
+		dir := c.MkDir()
+		archive := gzippedTar(c,
+			&tar.Header{Name: "self", Typeflag: tar.TypeSymlink, Linkname: "."},
+			&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: linkname},
+			&tar.Header{Name: "link/file", Typeflag: tar.TypeReg, Mode: 0644, Size: 9},
+		)
+		err := backups.ExtractArchive(archive, dir)
+		c.Assert(err, gc.ErrorMatches, `archive entry "link" links outside the archive`)
+		_, err = os.Lstat(filepath.Join(dir, "link"))
+		c.Assert(err, jc.Satisfies, os.IsNotExist)
+	}
+}
+
+func (s *backupsSuite) TestExtractArchiveAllowsLocalLinks(c *gc.C) {
+	dir := c.MkDir()
+	archive := gzippedTar(c,
+		&tar.Header{Name: "sub/file", Typeflag: tar.TypeReg, Mode: 0644, Size: 8},
+		&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "sub/file"},
+	)
+	err := backups.ExtractArchive(archive, dir)
+	c.Assert(err, gc.IsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "link"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, "sub/file")
+}
@@ -0,0 +1,150 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// jujuMongoBinDir holds the mongo tools shipped with juju-mongodb,
+// which are used in preference to any others.
+const jujuMongoBinDir = "/usr/lib/juju/bin"
+
+// DBInfo holds the information needed to connect to the state
+// database.
+type DBInfo struct {
+	// Address is the address of the database, as host:port.
+	Address string
+	// Username and Password are the credentials to connect with. No
+	// credentials are used if Username is empty.
+	Username string
+	Password string
+}
+
+// newDBInfo returns the information needed to connect to the database
+// holding the given state, using the state's own credentials.
+func newDBInfo(st *state.State) *DBInfo {
+	info := st.MongoConnectionInfo()
+	dbInfo := &DBInfo{
+		Username: info.Tag,
+		Password: info.Password,
+	}
+	if len(info.Addrs) > 0 {
+		dbInfo.Address = info.Addrs[0]
+	}
+	if dbInfo.Username == "" && dbInfo.Password != "" {
+		dbInfo.Username = state.AdminUser
+	}
+	return dbInfo
+}
+
+// runCommand runs the named command with the given arguments, writing
+// input to its standard input. It is a variable so that tests can
+// change it.
+var runCommand = func(input, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		output := strings.TrimSpace(string(out))
+		return errors.Annotatef(err, "%s failed: %s", filepath.Base(name), output)
+	}
+	return nil
+}
+
+// getMongodumpPath returns the path of the mongodump binary to use.
+var getMongodumpPath = func() (string, error) {
+	return mongoToolPath("mongodump")
+}
+
+// getMongorestorePath returns the path of the mongorestore binary to
+// use.
+var getMongorestorePath = func() (string, error) {
+	return mongoToolPath("mongorestore")
+}
+
+// mongoToolPath returns the path of the named mongo tool.
+func mongoToolPath(name string) (string, error) {
+	path := filepath.Join(jujuMongoBinDir, name)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", errors.Errorf("%s not found", name)
+	}
+	return path, nil
+}
+
+// credentialArgs returns the arguments that make a mongo tool connect
+// with the credentials in info, along with the input that must be
+// written to the tool's standard input. The password is given on
+// standard input, where the tools read it when --password has no
+// value, so that it cannot be seen in the process list. The arguments
+// must come last: anything following the bare --password would be
+// taken as the password.
+func credentialArgs(info *DBInfo) (args []string, input string) {
+	if info.Username == "" {
+		return nil, ""
+	}
+	args = []string{
+		"--authenticationDatabase", "admin",
+		"--username", info.Username,
+		"--password",
+	}
+	return args, info.Password + "\n"
+}
+
+// dumpDatabase dumps the database described by info into dir. The
+// database is not stopped: the oplog is dumped along with the data, so
+// that the dump is consistent as of the time it completes.
+func dumpDatabase(info *DBInfo, dir string) error {
+	mongodump, err := getMongodumpPath()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	args := []string{
+		"--oplog",
+		"--ssl",
+		"--host", info.Address,
+		"--out", dir,
+	}
+	credArgs, input := credentialArgs(info)
+	args = append(args, credArgs...)
+	return runCommand(input, mongodump, args...)
+}
+
+// restoreDatabase restores the database dump in dir into the database
+// described by info, replacing the collections held in the dump. The
+// admin database is not restored: it holds the credentials of the
+// original state server's agents, and the state server being restored
+// keeps its own. The oplog dumped with the data, if any, is replayed.
+func restoreDatabase(info *DBInfo, dir string) error {
+	mongorestore, err := getMongorestorePath()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "admin")); err != nil {
+		return errors.Trace(err)
+	}
+	args := []string{
+		"--drop",
+		"--ssl",
+		"--host", info.Address,
+		"--dir", dir,
+	}
+	if _, err := os.Stat(filepath.Join(dir, "oplog.bson")); err == nil {
+		args = append(args, "--oplogReplay")
+	}
+	credArgs, input := credentialArgs(info)
+	args = append(args, credArgs...)
+	return runCommand(input, mongorestore, args...)
+}
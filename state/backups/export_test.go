@@ -0,0 +1,17 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+var (
+	RunCommand          = &runCommand
+	GetMongodumpPath    = &getMongodumpPath
+	GetMongorestorePath = &getMongorestorePath
+	InitDir             = &initDir
+	RsyslogDir          = &rsyslogDir
+	SSHDir              = &sshDir
+	BackupFiles         = backupFiles
+	DumpDatabase        = dumpDatabase
+	RestoreDatabase     = restoreDatabase
+	ExtractArchive      = extractArchive
+)
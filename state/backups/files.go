@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// These directories hold state server files outside the agents' data
+// and log directories. They are variables so that tests can change
+// them.
+var (
+	initDir    = "/etc/init"
+	rsyslogDir = "/etc/rsyslog.d"
+	sshDir     = "/home/ubuntu/.ssh"
+)
+
+// backupFiles returns the paths of the files and directories to be
+// included in a backup of a state server with the given paths: the
+// upstart configuration for mongo and the machine agent, the machine
+// agent's configuration and tools, the server certificate and key,
+// secrets, authorized SSH keys, rsyslog configuration and the machine
+// logs. Unit agents are not included. Files that do not exist are
+// omitted.
+func backupFiles(paths Paths) ([]string, error) {
+	patterns := []string{
+		filepath.Join(initDir, "juju-db.conf"),
+		filepath.Join(initDir, "jujud-machine-*.conf"),
+		filepath.Join(paths.DataDir, "agents", "machine-*"),
+		filepath.Join(paths.DataDir, "tools"),
+		filepath.Join(paths.DataDir, "server.pem"),
+		filepath.Join(paths.DataDir, "system-identity"),
+		filepath.Join(paths.DataDir, "nonce.txt"),
+		filepath.Join(paths.DataDir, "shared-secret"),
+		filepath.Join(sshDir, "authorized_keys"),
+		filepath.Join(rsyslogDir, "*juju.conf"),
+		filepath.Join(paths.LogDir, "all-machines.log"),
+		filepath.Join(paths.LogDir, "machine-*.log"),
+	}
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
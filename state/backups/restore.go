@@ -0,0 +1,221 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// Restore is defined on Backups.
+func (b *backups) Restore(paths Paths, id string) error {
+	_, archive, err := b.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	tempDir, err := ioutil.TempDir("", "jujuRestore")
+	if err != nil {
+		return errors.Annotate(err, "cannot create temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	if err := extractArchive(archive, tempDir); err != nil {
+		return errors.Annotate(err, "cannot extract backup archive")
+	}
+	if err := restore(b.st, paths, filepath.Join(tempDir, archiveDir)); err != nil {
+		return errors.Annotatef(err, "cannot restore backup %q", id)
+	}
+	logger.Infof("restored backup %q", id)
+	return nil
+}
+
+// restore restores the state server from the extracted archive
+// contents in dir. The database dump replaces the current database,
+// and the machine agent's backed up configuration replaces its current
+// one. The credentials, state serving information and API addresses of
+// the new state server are kept, both in the agent configuration and
+// in the restored state.
+func restore(st *state.State, paths Paths, dir string) error {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(info.MachineIds) != 1 {
+		return errors.Errorf("expected a single state server, found %d", len(info.MachineIds))
+	}
+	machineId := info.MachineIds[0]
+	machine, err := st.Machine(machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	instId, err := machine.InstanceId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostPorts, err := st.APIHostPorts()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	configPath := agent.ConfigPath(paths.DataDir, names.MachineTag(machineId))
+	current, err := agent.ReadConfig(configPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Find the backed up agent configuration before changing anything,
+	// so that an archive without one is rejected.
+	backedUpConfig, err := readTarFile(filepath.Join(dir, filesArchive), filepath.ToSlash(strings.TrimPrefix(configPath, "/")))
+	if err != nil {
+		return errors.Annotate(err, "cannot read backed up agent configuration")
+	}
+
+	if err := restoreDatabase(newDBInfo(st), filepath.Join(dir, dumpDir)); err != nil {
+		return errors.Annotate(err, "cannot restore database")
+	}
+	if err := st.ResetStateServer(machineId, instId); err != nil {
+		return errors.Trace(err)
+	}
+	if machine, err = st.Machine(machineId); err != nil {
+		return errors.Trace(err)
+	}
+	if err := machine.SetPassword(current.APIInfo().Password); err != nil {
+		return errors.Trace(err)
+	}
+	if err := st.SetAPIHostPorts(hostPorts); err != nil {
+		return errors.Trace(err)
+	}
+	if err := restoreAgentConfig(configPath, backedUpConfig, current, hostPorts); err != nil {
+		return errors.Annotate(err, "cannot restore agent configuration")
+	}
+	return nil
+}
+
+// restoreAgentConfig writes the backed up agent configuration data to
+// configPath, keeping the credentials and state serving information of
+// the current configuration, and setting the API addresses to
+// hostPorts.
+func restoreAgentConfig(configPath string, data []byte, current agent.Config, hostPorts [][]network.HostPort) error {
+	if err := utils.AtomicWriteFile(configPath, data, 0600); err != nil {
+		return errors.Trace(err)
+	}
+	conf, err := agent.ReadConfig(configPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	conf.SetPassword(current.APIInfo().Password)
+	conf.SetOldPassword(current.OldPassword())
+	conf.SetAPIHostPorts(hostPorts)
+	if info, ok := current.StateServingInfo(); ok {
+		conf.SetStateServingInfo(info)
+	}
+	return conf.Write()
+}
+
+// extractArchive extracts the gzipped tarball read from r into dir.
+func extractArchive(r io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Annotate(err, "cannot uncompress archive")
+	}
+	defer gzr.Close()
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("archive entry %q is outside the archive", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		mode := os.FileMode(hdr.Mode) & os.ModePerm
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, mode|0700)
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeFile(target, tr, mode)
+		case tar.TypeSymlink:
+			if !isLocalLink(hdr.Linkname) {
+				return errors.Errorf("archive entry %q links outside the archive", hdr.Name)
+			}
+			err = os.Symlink(hdr.Linkname, target)
+		default:
+			logger.Debugf("skipping archive entry %q of type %c", hdr.Name, hdr.Typeflag)
+		}
+		if err != nil {
+			return errors.Annotatef(err, "cannot extract %q", hdr.Name)
+		}
+	}
+}
+
+// isLocalLink returns whether a symlink to target stays beneath the
+// directory holding the link. Targets that are absolute or that contain
+// ".." are rejected even if they would resolve inside the archive, as
+// the directories they pass through may themselves be links.
+func isLocalLink(target string) bool {
+	if target == "" || path.IsAbs(target) {
+		return false
+	}
+	for _, part := range strings.Split(target, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// writeFile writes the contents of r to a new file at the given path,
+// creating its directory if necessary.
+func writeFile(file string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// readTarFile returns the contents of the named file in the
+// uncompressed tarball at tarPath.
+func readTarFile(tarPath, name string) ([]byte, error) {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.NotFoundf("%q in archive", name)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if path.Clean(hdr.Name) == name {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
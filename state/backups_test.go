@@ -0,0 +1,139 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type BackupsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&BackupsSuite{})
+
+func newBackupMetadata(id string, started time.Time) state.BackupMetadata {
+	return state.BackupMetadata{
+		Id:             id,
+		Notes:          "before upgrade",
+		Started:        started,
+		Finished:       started.Add(time.Minute),
+		Checksum:       "checksum",
+		ChecksumFormat: "SHA-1, base64 encoded",
+		Size:           42,
+		Environment:    "env-uuid",
+		Machine:        "0",
+		Hostname:       "juju-0",
+		Version:        version.MustParse("1.20.0"),
+	}
+}
+
+func (s *BackupsSuite) TestAddBackupMetadata(c *gc.C) {
+	started := time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC)
+	meta := newBackupMetadata("20140801-120000.env-uuid", started)
+	meta.Scheduled = true
+	meta.Stored = true
+	err := s.State.AddBackupMetadata(meta)
+	c.Assert(err, gc.IsNil)
+
+	got, err := s.State.BackupMetadata(meta.Id)
+	c.Assert(err, gc.IsNil)
+	// The archive is never recorded as stored on creation.
+	meta.Stored = false
+	c.Assert(got.Started.Equal(meta.Started), jc.IsTrue)
+	c.Assert(got.Finished.Equal(meta.Finished), jc.IsTrue)
+	got.Started, got.Finished = meta.Started, meta.Finished
+	c.Assert(*got, gc.DeepEquals, meta)
+
+	err = s.State.AddBackupMetadata(meta)
+	c.Assert(err, gc.ErrorMatches, `backup "20140801-120000.env-uuid" already exists`)
+}
+
+func (s *BackupsSuite) TestAddBackupMetadataWithoutId(c *gc.C) {
+	err := s.State.AddBackupMetadata(state.BackupMetadata{})
+	c.Assert(err, gc.ErrorMatches, "backup metadata has no id")
+}
+
+func (s *BackupsSuite) TestSetBackupStored(c *gc.C) {
+	meta := newBackupMetadata("backup-0", time.Now())
+	err := s.State.AddBackupMetadata(meta)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.SetBackupStored(meta.Id)
+	c.Assert(err, gc.IsNil)
+	got, err := s.State.BackupMetadata(meta.Id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Stored, jc.IsTrue)
+
+	err = s.State.SetBackupStored("backup-1")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `backup "backup-1" not found`)
+}
+
+func (s *BackupsSuite) TestAllBackupMetadata(c *gc.C) {
+	metas, err := s.State.AllBackupMetadata()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 0)
+
+	now := time.Now()
+	for _, meta := range []state.BackupMetadata{
+		newBackupMetadata("backup-1", now),
+		newBackupMetadata("backup-0", now.Add(-time.Hour)),
+	} {
+		err := s.State.AddBackupMetadata(meta)
+		c.Assert(err, gc.IsNil)
+	}
+	metas, err = s.State.AllBackupMetadata()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 2)
+	c.Assert(metas[0].Id, gc.Equals, "backup-0")
+	c.Assert(metas[1].Id, gc.Equals, "backup-1")
+}
+
+func (s *BackupsSuite) TestRemoveBackupMetadata(c *gc.C) {
+	meta := newBackupMetadata("backup-0", time.Now())
+	err := s.State.AddBackupMetadata(meta)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.RemoveBackupMetadata(meta.Id)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.BackupMetadata(meta.Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing it again is not an error.
+	err = s.State.RemoveBackupMetadata(meta.Id)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *BackupsSuite) TestScheduledBackupsStatus(c *gc.C) {
+	_, err := s.State.ScheduledBackupsStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	succeeded := time.Date(2014, 8, 1, 12, 0, 0, 0, time.UTC)
+	err = s.State.SetScheduledBackupSucceeded("backup-0", succeeded)
+	c.Assert(err, gc.IsNil)
+	status, err := s.State.ScheduledBackupsStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.LastSuccess.Equal(succeeded), jc.IsTrue)
+	c.Assert(status.LastBackupId, gc.Equals, "backup-0")
+	c.Assert(status.LastFailure.IsZero(), jc.IsTrue)
+	c.Assert(status.LastError, gc.Equals, "")
+
+	failed := succeeded.Add(24 * time.Hour)
+	err = s.State.SetScheduledBackupFailed(failed, errors.New("cannot dump database"))
+	c.Assert(err, gc.IsNil)
+	status, err = s.State.ScheduledBackupsStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status.LastSuccess.Equal(succeeded), jc.IsTrue)
+	c.Assert(status.LastBackupId, gc.Equals, "backup-0")
+	c.Assert(status.LastFailure.Equal(failed), jc.IsTrue)
+	c.Assert(status.LastError, gc.Equals, "cannot dump database")
+}
@@ -21,6 +21,7 @@ const (
 	cleanupRemovedUnit                 cleanupKind = "removedUnit"
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
+	cleanupServiceActions              cleanupKind = "serviceActions"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -77,6 +78,8 @@ func (st *State) Cleanup() error {
 			err = st.cleanupServicesForDyingEnvironment()
 		case cleanupForceDestroyedMachine:
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
+		case cleanupServiceActions:
+			err = st.cleanupServiceActions(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -109,3 +109,12 @@ func (s *constraintsValidationSuite) TestServiceConstraints(c *gc.C) {
 		c.Check(*ucons, jc.DeepEquals, constraints.MustParse(t.expected))
 	}
 }
+
+func (s *constraintsValidationSuite) TestValidateConstraints(c *gc.C) {
+	unsupported, err := s.State.ValidateConstraints(constraints.MustParse("mem=4G cpu-power=100"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(unsupported, jc.DeepEquals, []string{"cpu-power"})
+
+	_, err = s.State.ValidateConstraints(constraints.MustParse("instance-type=foo mem=4G"))
+	c.Assert(err, gc.ErrorMatches, `ambiguous constraints: "(instance-type|mem)" overlaps with "(instance-type|mem)"`)
+}
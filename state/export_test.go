@@ -234,6 +234,8 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	logSize = logSizeTests
+	logsSize = logsSizeTests
+	auditLogSize = auditLogSizeTests
 }
 
 // MinUnitsRevno returns the Revno of the minUnits document
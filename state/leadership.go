@@ -0,0 +1,233 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/juju/names"
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+	"labix.org/v2/mgo/txn"
+)
+
+// leadershipDoc records the unit holding the leadership lease of a
+// service, and the time at which the lease expires. Once the lease
+// has expired, any unit of the service may claim it.
+type leadershipDoc struct {
+	Service string `bson:"_id"`
+	Leader  string
+	Expiry  time.Time
+}
+
+// leaderSettingsKey returns the key of the settings that the leader of
+// the named service shares with the other units of the service.
+func leaderSettingsKey(serviceName string) string {
+	return serviceGlobalKey(serviceName) + "#leader"
+}
+
+// removeLeadershipOps returns the operations that remove the leadership
+// lease and leader settings of the named service.
+func removeLeadershipOps(st *State, serviceName string) []txn.Op {
+	return []txn.Op{{
+		C:      st.leaderships.Name,
+		Id:     serviceName,
+		Remove: true,
+	}, {
+		C:      st.settings.Name,
+		Id:     leaderSettingsKey(serviceName),
+		Remove: true,
+	}}
+}
+
+// ErrNotLeader is returned when a unit that does not hold the
+// leadership lease of its service tries to act as its leader.
+var ErrNotLeader = errors.New("unit is not the service leader")
+
+// Leader returns the name of the unit holding the leadership lease of
+// the service, or an empty string if the lease is not held.
+func (s *Service) Leader() (string, error) {
+	var doc leadershipDoc
+	err := s.st.leaderships.FindId(s.doc.Name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("cannot get leader of service %q: %v", s, err)
+	}
+	if !doc.Expiry.After(time.Now()) {
+		return "", nil
+	}
+	return doc.Leader, nil
+}
+
+// ClaimLeadership claims the leadership lease of the service for the
+// named unit, for the given duration, and reports whether the claim
+// succeeded. A unit that already holds the lease extends it; otherwise
+// the claim only succeeds once any lease held by another unit has
+// expired.
+func (s *Service) ClaimLeadership(unitName string, duration time.Duration) (bool, error) {
+	if names.UnitService(unitName) != s.doc.Name {
+		return false, fmt.Errorf("cannot claim leadership of service %q: unit %q is not a unit of the service", s, unitName)
+	}
+	for i := 0; i < 3; i++ {
+		var doc leadershipDoc
+		err := s.st.leaderships.FindId(s.doc.Name).One(&doc)
+		now := time.Now()
+		var op txn.Op
+		switch {
+		case err == mgo.ErrNotFound:
+			op = txn.Op{
+				C:      s.st.leaderships.Name,
+				Id:     s.doc.Name,
+				Assert: txn.DocMissing,
+				Insert: &leadershipDoc{
+					Service: s.doc.Name,
+					Leader:  unitName,
+					Expiry:  now.Add(duration),
+				},
+			}
+		case err != nil:
+			return false, fmt.Errorf("cannot claim leadership of service %q: %v", s, err)
+		case doc.Leader != unitName && doc.Expiry.After(now):
+			return false, nil
+		default:
+			op = txn.Op{
+				C:      s.st.leaderships.Name,
+				Id:     s.doc.Name,
+				Assert: bson.D{{"leader", doc.Leader}, {"expiry", doc.Expiry}},
+				Update: bson.D{{"$set", bson.D{
+					{"leader", unitName},
+					{"expiry", now.Add(duration)},
+				}}},
+			}
+		}
+		ops := []txn.Op{{
+			C:      s.st.units.Name,
+			Id:     unitName,
+			Assert: isAliveDoc,
+		}, op}
+		err = s.st.runTransaction(ops)
+		if err != txn.ErrAborted {
+			if err != nil {
+				return false, fmt.Errorf("cannot claim leadership of service %q: %v", s, err)
+			}
+			return true, nil
+		}
+		if alive, err := isAlive(s.st.units, unitName); err != nil {
+			return false, err
+		} else if !alive {
+			return false, fmt.Errorf("cannot claim leadership of service %q: unit %q is not alive", s, unitName)
+		}
+	}
+	return false, ErrExcessiveContention
+}
+
+// LeaderSettings returns the settings that the leader of the service
+// shares with the other units of the service.
+func (s *Service) LeaderSettings() (map[string]string, error) {
+	values, _, err := readSettingsDoc(s.st, leaderSettingsKey(s.doc.Name))
+	if err == mgo.ErrNotFound {
+		// The service was added before leader settings were recorded.
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read leader settings of service %q: %v", s, err)
+	}
+	settings := make(map[string]string)
+	for key, value := range copyMap(values, unescapeReplacer.Replace) {
+		if value, ok := value.(string); ok {
+			settings[key] = value
+		}
+	}
+	return settings, nil
+}
+
+// MergeLeaderSettings merges the given settings into the service's
+// leader settings, on behalf of the named unit, which must hold the
+// leadership lease. Settings with empty values are removed.
+func (s *Service) MergeLeaderSettings(unitName string, settings map[string]string) error {
+	key := leaderSettingsKey(s.doc.Name)
+	for i := 0; i < 3; i++ {
+		current, _, err := readSettingsDoc(s.st, key)
+		exists := err == nil
+		if err == mgo.ErrNotFound {
+			// The service was added before leader settings were recorded.
+			current = map[string]interface{}{}
+		} else if err != nil {
+			return fmt.Errorf("cannot write leader settings of service %q: %v", s, err)
+		}
+		current = copyMap(current, unescapeReplacer.Replace)
+		sets := bson.M{}
+		unsets := bson.M{}
+		for k, value := range settings {
+			old, found := current[k]
+			switch {
+			case value == "" && found:
+				unsets[escapeReplacer.Replace(k)] = 1
+			case value != "" && old != value:
+				sets[escapeReplacer.Replace(k)] = value
+			}
+		}
+		var settingsOp txn.Op
+		switch {
+		case !exists:
+			settingsOp = txn.Op{
+				C:      s.st.settings.Name,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: sets,
+			}
+		case len(sets) == 0 && len(unsets) == 0:
+			// Nothing would change, but the unit must still be
+			// the leader to have written the settings.
+			settingsOp = txn.Op{
+				C:      s.st.settings.Name,
+				Id:     key,
+				Assert: txn.DocExists,
+			}
+		default:
+			update := bson.D{}
+			if len(sets) > 0 {
+				update = append(update, bson.DocElem{"$set", sets})
+			}
+			if len(unsets) > 0 {
+				update = append(update, bson.DocElem{"$unset", unsets})
+			}
+			settingsOp = txn.Op{
+				C:      s.st.settings.Name,
+				Id:     key,
+				Assert: txn.DocExists,
+				Update: update,
+			}
+		}
+		ops := []txn.Op{{
+			C:  s.st.leaderships.Name,
+			Id: s.doc.Name,
+			Assert: bson.D{
+				{"leader", unitName},
+				{"expiry", bson.D{{"$gt", time.Now()}}},
+			},
+		}, settingsOp}
+		err = s.st.runTransaction(ops)
+		if err != txn.ErrAborted {
+			if err != nil {
+				return fmt.Errorf("cannot write leader settings of service %q: %v", s, err)
+			}
+			return nil
+		}
+		if leader, err := s.Leader(); err != nil {
+			return err
+		} else if leader != unitName {
+			return ErrNotLeader
+		}
+	}
+	return ErrExcessiveContention
+}
+
+// WatchLeaderSettings returns a watcher that notifies of changes to the
+// service's leader settings.
+func (s *Service) WatchLeaderSettings() NotifyWatcher {
+	return newEntityWatcher(s.st, s.st.settings, leaderSettingsKey(s.doc.Name))
+}
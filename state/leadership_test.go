@@ -0,0 +1,157 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type LeadershipSuite struct {
+	ConnSuite
+	service *state.Service
+	unit0   *state.Unit
+	unit1   *state.Unit
+}
+
+var _ = gc.Suite(&LeadershipSuite{})
+
+func (s *LeadershipSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit0, err = s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	s.unit1, err = s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *LeadershipSuite) TestClaimLeadership(c *gc.C) {
+	leader, err := s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "")
+
+	ok, err := s.service.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, true)
+	leader, err = s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/0")
+
+	// Another unit cannot claim an unexpired lease.
+	ok, err = s.service.ClaimLeadership("wordpress/1", time.Minute)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, false)
+
+	// The leader can extend it.
+	ok, err = s.service.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, true)
+}
+
+func (s *LeadershipSuite) TestClaimExpiredLeadership(c *gc.C) {
+	ok, err := s.service.ClaimLeadership("wordpress/0", time.Millisecond)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, true)
+	time.Sleep(10 * time.Millisecond)
+
+	leader, err := s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "")
+	ok, err = s.service.ClaimLeadership("wordpress/1", time.Minute)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, true)
+	leader, err = s.service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "wordpress/1")
+}
+
+func (s *LeadershipSuite) TestClaimLeadershipErrors(c *gc.C) {
+	_, err := s.service.ClaimLeadership("mysql/0", time.Minute)
+	c.Assert(err, gc.ErrorMatches, `cannot claim leadership of service "wordpress": unit "mysql/0" is not a unit of the service`)
+
+	err = s.unit1.Destroy()
+	c.Assert(err, gc.IsNil)
+	_, err = s.service.ClaimLeadership("wordpress/1", time.Minute)
+	c.Assert(err, gc.ErrorMatches, `cannot claim leadership of service "wordpress": unit "wordpress/1" is not alive`)
+}
+
+func (s *LeadershipSuite) TestLeaderSettings(c *gc.C) {
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{})
+
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{"master": "10.0.0.1"})
+	c.Assert(err, gc.Equals, state.ErrNotLeader)
+
+	_, err = s.service.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{
+		"master":    "10.0.0.1",
+		"pass.word": "s3cret",
+	})
+	c.Assert(err, gc.IsNil)
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{
+		"master": "",
+		"token":  "xyzzy",
+	})
+	c.Assert(err, gc.IsNil)
+	settings, err = s.service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{
+		"pass.word": "s3cret",
+		"token":     "xyzzy",
+	})
+
+	err = s.service.MergeLeaderSettings("wordpress/1", map[string]string{"token": "plugh"})
+	c.Assert(err, gc.Equals, state.ErrNotLeader)
+}
+
+func (s *LeadershipSuite) TestWatchLeaderSettings(c *gc.C) {
+	_, err := s.service.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	w := s.service.WatchLeaderSettings()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{"master": "10.0.0.1"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertOneChange()
+
+	// Non-change is not reported.
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{"master": "10.0.0.1"})
+	c.Assert(err, gc.IsNil)
+	wc.AssertNoChange()
+}
+
+func (s *LeadershipSuite) TestRemoveServiceRemovesLeadership(c *gc.C) {
+	_, err := s.service.ClaimLeadership("wordpress/0", time.Minute)
+	c.Assert(err, gc.IsNil)
+	err = s.service.MergeLeaderSettings("wordpress/0", map[string]string{"master": "10.0.0.1"})
+	c.Assert(err, gc.IsNil)
+	for _, u := range []*state.Unit{s.unit0, s.unit1} {
+		err = u.EnsureDead()
+		c.Assert(err, gc.IsNil)
+		err = u.Remove()
+		c.Assert(err, gc.IsNil)
+	}
+	err = s.service.Destroy()
+	c.Assert(err, gc.IsNil)
+
+	// A new service of the same name starts afresh.
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	leader, err := service.Leader()
+	c.Assert(err, gc.IsNil)
+	c.Assert(leader, gc.Equals, "")
+	settings, err := service.LeaderSettings()
+	c.Assert(err, gc.IsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{})
+}
@@ -0,0 +1,344 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+	"launchpad.net/tomb"
+)
+
+// LogRecord holds a log message written by an agent.
+type LogRecord struct {
+	// Time records when the message was logged by the agent.
+	Time time.Time
+	// Entity holds the tag of the agent that logged the message.
+	Entity string
+	// Module holds the name of the logger the message was logged with.
+	Module string
+	// Location holds the source file and line the message was logged
+	// from, as "file.go:123".
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// logRecordDoc is the persistent form of LogRecord. Its id is left for
+// the database to assign, so that the ids of the records increase in
+// the order they were added.
+type logRecordDoc struct {
+	Id       bson.ObjectId `bson:"_id,omitempty"`
+	Time     time.Time
+	Entity   string
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+func (doc *logRecordDoc) record() *LogRecord {
+	return &LogRecord{
+		Time:     doc.Time,
+		Entity:   doc.Entity,
+		Module:   doc.Module,
+		Location: doc.Location,
+		Level:    doc.Level,
+		Message:  doc.Message,
+	}
+}
+
+// AddLogRecords adds the given records to the log. The log is held in
+// a capped collection, so the oldest records are discarded as new ones
+// are added. Records are never changed once written, so they are
+// inserted directly rather than through a transaction.
+func (st *State) AddLogRecords(records []LogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(records))
+	for i, record := range records {
+		if record.Entity == "" {
+			return errors.New("log record has no entity")
+		}
+		docs[i] = &logRecordDoc{
+			Time:     record.Time.UTC(),
+			Entity:   record.Entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    record.Level,
+			Message:  record.Message,
+		}
+	}
+	if err := st.logs.Insert(docs...); err != nil {
+		return errors.Annotate(err, "cannot add log records")
+	}
+	return nil
+}
+
+// LogFilter selects records from the log. Zero valued fields select
+// every record.
+type LogFilter struct {
+	// Since and Until select the records logged at or after Since,
+	// and before Until.
+	Since time.Time
+	Until time.Time
+	// Level selects the records logged at this level or above.
+	Level loggo.Level
+	// IncludeEntity and ExcludeEntity hold the tags of the agents
+	// whose records are selected and rejected. Tags may finish with
+	// a '*' to match a prefix, e.g. unit-mysql-*.
+	IncludeEntity []string
+	ExcludeEntity []string
+	// IncludeModule and ExcludeModule hold the logging modules whose
+	// records, and those of their submodules, are selected and
+	// rejected.
+	IncludeModule []string
+	ExcludeModule []string
+	// Message selects the records whose messages it matches. It is
+	// matched by the tailer rather than by the database, so that it
+	// has the same RE2 syntax as every other Go regular expression.
+	Message *regexp.Regexp
+}
+
+// selector returns the query selecting the records matched by the
+// filter.
+func (f LogFilter) selector() bson.D {
+	sel := bson.D{}
+	timeSel := bson.D{}
+	if !f.Since.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$gte", f.Since.UTC()})
+	}
+	if !f.Until.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$lt", f.Until.UTC()})
+	}
+	if len(timeSel) > 0 {
+		sel = append(sel, bson.DocElem{"time", timeSel})
+	}
+	if f.Level > loggo.UNSPECIFIED {
+		sel = append(sel, bson.DocElem{"level", bson.D{{"$gte", f.Level}}})
+	}
+	if entitySel := matchAny(f.IncludeEntity, f.ExcludeEntity, entityPattern); len(entitySel) > 0 {
+		sel = append(sel, bson.DocElem{"entity", entitySel})
+	}
+	if moduleSel := matchAny(f.IncludeModule, f.ExcludeModule, modulePattern); len(moduleSel) > 0 {
+		sel = append(sel, bson.DocElem{"module", moduleSel})
+	}
+	return sel
+}
+
+// matchMessage returns whether the filter selects a record with the
+// given message.
+func (f LogFilter) matchMessage(message string) bool {
+	return f.Message == nil || f.Message.MatchString(message)
+}
+
+// matchAny returns a query matching any of the include values and
+// none of the exclude values, as translated by the pattern function.
+func matchAny(include, exclude []string, pattern func(string) interface{}) bson.D {
+	sel := bson.D{}
+	if len(include) > 0 {
+		sel = append(sel, bson.DocElem{"$in", patterns(include, pattern)})
+	}
+	if len(exclude) > 0 {
+		sel = append(sel, bson.DocElem{"$nin", patterns(exclude, pattern)})
+	}
+	return sel
+}
+
+func patterns(values []string, pattern func(string) interface{}) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = pattern(value)
+	}
+	return result
+}
+
+// entityPattern matches the given tag, or the tags it is a prefix of if
+// it finishes with a '*'.
+func entityPattern(tag string) interface{} {
+	if strings.HasSuffix(tag, "*") {
+		return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(tag[:len(tag)-1])}
+	}
+	return tag
+}
+
+// modulePattern matches the given module and its submodules.
+func modulePattern(module string) interface{} {
+	return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(module)}
+}
+
+// LogTailerParams holds the parameters of a LogTailer.
+type LogTailerParams struct {
+	// Filter selects the records sent by the tailer.
+	Filter LogFilter
+	// Replay causes the tailer to start with the oldest record
+	// selected, as it does when the filter has a Since or Until time.
+	// Otherwise it starts with the last Backlog records selected.
+	Replay  bool
+	Backlog uint
+}
+
+var (
+	// logTailTimeout is how long the tailer waits for new records
+	// before checking whether it has been stopped.
+	logTailTimeout = time.Second
+	// logTailRetryDelay is how long the tailer waits before looking
+	// again when no records have been selected yet.
+	logTailRetryDelay = time.Second
+)
+
+// LogTailer sends the log records selected by its filter, including
+// the records added after it was started, until it is stopped. If the
+// filter has an Until time, the tailer stops once it has sent the
+// records logged before then.
+type LogTailer struct {
+	tomb    tomb.Tomb
+	st      *State
+	params  LogTailerParams
+	records chan *LogRecord
+}
+
+// NewLogTailer returns a LogTailer sending the records selected by the
+// given parameters.
+func (st *State) NewLogTailer(params LogTailerParams) *LogTailer {
+	t := &LogTailer{
+		st:      st,
+		params:  params,
+		records: make(chan *LogRecord),
+	}
+	go func() {
+		defer t.tomb.Done()
+		defer close(t.records)
+		t.tomb.Kill(t.loop())
+	}()
+	return t
+}
+
+// Records returns the channel the records are sent on. It is closed
+// when the tailer stops.
+func (t *LogTailer) Records() <-chan *LogRecord {
+	return t.records
+}
+
+// Stop stops the tailer and returns any error it encountered.
+func (t *LogTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+// Err returns the error that stopped the tailer, or tomb.ErrStillAlive
+// if it is still running.
+func (t *LogTailer) Err() error {
+	return t.tomb.Err()
+}
+
+func (t *LogTailer) loop() error {
+	// Tailing holds a cursor open for as long as the tailer runs, so
+	// it is given a session of its own.
+	session := t.st.db.Session.Copy()
+	defer session.Close()
+	logs := t.st.logs.With(session)
+
+	sel := t.params.Filter.selector()
+	start, err := t.startSelector(logs, sel)
+	if err != nil {
+		return errors.Annotate(err, "cannot find first log record")
+	}
+	follow := t.params.Filter.Until.IsZero()
+	query := func(idSel bson.D) *mgo.Iter {
+		q := logs.Find(append(append(bson.D{}, sel...), idSel...))
+		if follow {
+			return q.Tail(logTailTimeout)
+		}
+		return q.Sort("$natural").Iter()
+	}
+	iter := query(start)
+	defer func() { iter.Close() }()
+	var lastId bson.ObjectId
+	for {
+		var doc logRecordDoc
+		if iter.Next(&doc) {
+			lastId = doc.Id
+			if !t.params.Filter.matchMessage(doc.Message) {
+				continue
+			}
+			select {
+			case <-t.tomb.Dying():
+				return tomb.ErrDying
+			case t.records <- doc.record():
+			}
+			continue
+		}
+		if err := iter.Err(); err != nil {
+			return errors.Annotate(err, "cannot read log records")
+		}
+		if !follow {
+			return nil
+		}
+		if iter.Timeout() {
+			select {
+			case <-t.tomb.Dying():
+				return tomb.ErrDying
+			default:
+			}
+			continue
+		}
+		// The cursor is closed when it has nothing to tail, so look
+		// again after a while, starting after the last record read.
+		iter.Close()
+		select {
+		case <-t.tomb.Dying():
+			return tomb.ErrDying
+		case <-time.After(logTailRetryDelay):
+		}
+		if lastId != "" {
+			start = bson.D{{"_id", bson.D{{"$gt", lastId}}}}
+		}
+		iter = query(start)
+	}
+}
+
+// startSelector returns the query selecting the records from the one
+// the tailer starts with.
+func (t *LogTailer) startSelector(logs *mgo.Collection, sel bson.D) (bson.D, error) {
+	filter := t.params.Filter
+	if t.params.Replay || !filter.Since.IsZero() || !filter.Until.IsZero() {
+		return nil, nil
+	}
+	if t.params.Backlog == 0 {
+		var doc logRecordDoc
+		err := logs.Find(nil).Sort("-$natural").Select(bson.D{{"_id", 1}}).One(&doc)
+		if err == mgo.ErrNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return bson.D{{"_id", bson.D{{"$gt", doc.Id}}}}, nil
+	}
+	// Walk back from the newest record until Backlog records with
+	// matching messages have been found.
+	iter := logs.Find(sel).Sort("-$natural").Select(bson.D{{"_id", 1}, {"message", 1}}).Iter()
+	var first bson.ObjectId
+	var count uint
+	var doc logRecordDoc
+	for count < t.params.Backlog && iter.Next(&doc) {
+		if filter.matchMessage(doc.Message) {
+			first = doc.Id
+			count++
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return bson.D{{"_id", bson.D{{"$gte", first}}}}, nil
+}
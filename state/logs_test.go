@@ -0,0 +1,206 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type LogsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LogsSuite{})
+
+var logTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+// addLogs adds a record to the log for each of the given entities, a
+// minute apart, and returns the records' messages.
+func (s *LogsSuite) addLogs(c *gc.C, entities ...string) []string {
+	var records []state.LogRecord
+	var messages []string
+	for i, entity := range entities {
+		message := fmt.Sprintf("message %d from %s", i, entity)
+		records = append(records, state.LogRecord{
+			Time:     logTime.Add(time.Duration(i) * time.Minute),
+			Entity:   entity,
+			Module:   "juju.worker.uniter",
+			Location: "uniter.go:42",
+			Level:    loggo.INFO,
+			Message:  message,
+		})
+		messages = append(messages, message)
+	}
+	err := s.State.AddLogRecords(records)
+	c.Assert(err, gc.IsNil)
+	return messages
+}
+
+// readMessages returns the messages of the next count records sent by
+// the tailer.
+func readMessages(c *gc.C, tailer *state.LogTailer, count int) []string {
+	var messages []string
+	for len(messages) < count {
+		select {
+		case record, ok := <-tailer.Records():
+			c.Assert(ok, jc.IsTrue)
+			messages = append(messages, record.Message)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for log records")
+		}
+	}
+	return messages
+}
+
+func assertNoMoreRecords(c *gc.C, tailer *state.LogTailer) {
+	select {
+	case record, ok := <-tailer.Records():
+		c.Fatalf("unexpected log record %v (ok %v)", record, ok)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *LogsSuite) TestAddLogRecords(c *gc.C) {
+	s.addLogs(c, "machine-0")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{Replay: true})
+	defer tailer.Stop()
+	select {
+	case record := <-tailer.Records():
+		c.Assert(record.Time.Equal(logTime), jc.IsTrue)
+		record.Time = time.Time{}
+		c.Assert(*record, gc.DeepEquals, state.LogRecord{
+			Entity:   "machine-0",
+			Module:   "juju.worker.uniter",
+			Location: "uniter.go:42",
+			Level:    loggo.INFO,
+			Message:  "message 0 from machine-0",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for log record")
+	}
+}
+
+func (s *LogsSuite) TestAddLogRecordsWithoutEntity(c *gc.C) {
+	err := s.State.AddLogRecords([]state.LogRecord{{Message: "hello"}})
+	c.Assert(err, gc.ErrorMatches, "log record has no entity")
+}
+
+func (s *LogsSuite) TestTailerFollows(c *gc.C) {
+	s.addLogs(c, "machine-0", "machine-1")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{})
+	defer tailer.Stop()
+	assertNoMoreRecords(c, tailer)
+
+	messages := s.addLogs(c, "machine-2", "machine-3")
+	c.Assert(readMessages(c, tailer, 2), jc.DeepEquals, messages)
+	c.Assert(tailer.Stop(), gc.IsNil)
+}
+
+func (s *LogsSuite) TestTailerReplay(c *gc.C) {
+	first := s.addLogs(c, "machine-0", "machine-1")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{Replay: true})
+	defer tailer.Stop()
+	c.Assert(readMessages(c, tailer, 2), jc.DeepEquals, first)
+
+	next := s.addLogs(c, "machine-2")
+	c.Assert(readMessages(c, tailer, 1), jc.DeepEquals, next)
+}
+
+func (s *LogsSuite) TestTailerBacklog(c *gc.C) {
+	messages := s.addLogs(c, "machine-0", "machine-1", "machine-2")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{Backlog: 2})
+	defer tailer.Stop()
+	c.Assert(readMessages(c, tailer, 2), jc.DeepEquals, messages[1:])
+	assertNoMoreRecords(c, tailer)
+}
+
+func (s *LogsSuite) TestTailerBacklogCountsMatchingMessages(c *gc.C) {
+	messages := s.addLogs(c, "machine-0", "unit-mysql-0", "machine-1", "unit-mysql-1", "machine-2")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{
+		Filter:  state.LogFilter{Message: regexp.MustCompile(`from machine-\d$`)},
+		Backlog: 2,
+	})
+	defer tailer.Stop()
+	c.Assert(readMessages(c, tailer, 2), jc.DeepEquals, []string{messages[2], messages[4]})
+	assertNoMoreRecords(c, tailer)
+
+	next := s.addLogs(c, "unit-mysql-2", "machine-3")
+	c.Assert(readMessages(c, tailer, 1), jc.DeepEquals, next[1:])
+	assertNoMoreRecords(c, tailer)
+}
+
+func (s *LogsSuite) TestTailerTimeRange(c *gc.C) {
+	messages := s.addLogs(c, "machine-0", "machine-1", "machine-2", "machine-3")
+	tailer := s.State.NewLogTailer(state.LogTailerParams{
+		Filter: state.LogFilter{
+			Since: logTime.Add(time.Minute),
+			Until: logTime.Add(3 * time.Minute),
+		},
+	})
+	c.Assert(readMessages(c, tailer, 2), jc.DeepEquals, messages[1:3])
+	// A tailer with an Until time stops when it has sent the records
+	// logged before then.
+	select {
+	case _, ok := <-tailer.Records():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+	c.Assert(tailer.Err(), gc.IsNil)
+}
+
+func (s *LogsSuite) TestTailerFilter(c *gc.C) {
+	err := s.State.AddLogRecords([]state.LogRecord{
+		{Entity: "machine-0", Module: "juju.cmd", Level: loggo.INFO, Message: "0"},
+		{Entity: "machine-0", Module: "juju.cmd.jujud", Level: loggo.INFO, Message: "1"},
+		{Entity: "machine-1", Module: "juju.cmd", Level: loggo.INFO, Message: "2"},
+		{Entity: "unit-mysql-0", Module: "juju.cmd", Level: loggo.DEBUG, Message: "3"},
+		{Entity: "unit-mysql-1", Module: "juju.worker", Level: loggo.ERROR, Message: "4"},
+		{Entity: "unit-ubuntu-0", Module: "juju.cmd", Level: loggo.WARNING, Message: "5"},
+	})
+	c.Assert(err, gc.IsNil)
+	for i, test := range []struct {
+		about  string
+		filter state.LogFilter
+		expect []string
+	}{{
+		about:  "include entity",
+		filter: state.LogFilter{IncludeEntity: []string{"machine-0", "unit-mysql-*"}},
+		expect: []string{"0", "1", "3", "4"},
+	}, {
+		about:  "exclude entity",
+		filter: state.LogFilter{ExcludeEntity: []string{"machine-*"}},
+		expect: []string{"3", "4", "5"},
+	}, {
+		about: "include and exclude module",
+		filter: state.LogFilter{
+			IncludeModule: []string{"juju.cmd"},
+			ExcludeModule: []string{"juju.cmd.jujud"},
+		},
+		expect: []string{"0", "2", "3", "5"},
+	}, {
+		about:  "level",
+		filter: state.LogFilter{Level: loggo.WARNING},
+		expect: []string{"4", "5"},
+	}, {
+		about:  "message",
+		filter: state.LogFilter{Message: regexp.MustCompile(`^[0-2]$`)},
+		expect: []string{"0", "1", "2"},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		tailer := s.State.NewLogTailer(state.LogTailerParams{Filter: test.filter, Replay: true})
+		c.Check(readMessages(c, tailer, len(test.expect)), jc.DeepEquals, test.expect)
+		assertNoMoreRecords(c, tailer)
+		c.Check(tailer.Stop(), gc.IsNil)
+	}
+}
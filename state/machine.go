@@ -115,6 +115,10 @@ type machineDoc struct {
 	// machine is capable of hosting.
 	SupportedContainersKnown bool
 	SupportedContainers      []instance.ContainerType `bson:",omitempty"`
+	// Demoted records that the machine was demoted from being a state
+	// server, so that its state server job is removed once it no
+	// longer has a vote, rather than its vote being restored.
+	Demoted bool `bson:",omitempty"`
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
@@ -106,11 +106,21 @@ func (u *backingUnit) updated(st *State, store *multiwatcher.Store, id interface
 		}
 		info.Status = sdoc.Status
 		info.StatusInfo = sdoc.StatusInfo
+		wdoc, err := getStatus(st, unitWorkloadGlobalKey(u.Name))
+		if errors.IsNotFound(err) {
+			wdoc.Status = params.StatusUnknown
+		} else if err != nil {
+			return err
+		}
+		info.WorkloadStatus = wdoc.Status
+		info.WorkloadStatusInfo = wdoc.StatusInfo
 	} else {
 		// The entry already exists, so preserve the current status.
 		oldInfo := oldInfo.(*params.UnitInfo)
 		info.Status = oldInfo.Status
 		info.StatusInfo = oldInfo.StatusInfo
+		info.WorkloadStatus = oldInfo.WorkloadStatus
+		info.WorkloadStatusInfo = oldInfo.WorkloadStatusInfo
 	}
 	publicAddress, privateAddress, err := getUnitAddresses(st, u.Name)
 	if err != nil {
@@ -277,6 +287,9 @@ func (a *backingAnnotation) mongoId() interface{} {
 type backingStatus statusDoc
 
 func (s *backingStatus) updated(st *State, store *multiwatcher.Store, id interface{}) error {
+	if key := id.(string); strings.HasSuffix(key, workloadSuffix) {
+		return s.updatedWorkload(store, strings.TrimSuffix(key, workloadSuffix))
+	}
 	parentId, ok := backingEntityIdForGlobalKey(id.(string))
 	if !ok {
 		return nil
@@ -305,6 +318,25 @@ func (s *backingStatus) updated(st *State, store *multiwatcher.Store, id interfa
 	return nil
 }
 
+// updatedWorkload records the workload status of the unit with the
+// given global key.
+func (s *backingStatus) updatedWorkload(store *multiwatcher.Store, unitKey string) error {
+	parentId, ok := backingEntityIdForGlobalKey(unitKey)
+	if !ok {
+		return nil
+	}
+	info, ok := store.Get(parentId).(*params.UnitInfo)
+	if !ok {
+		// The unit info doesn't exist. Ignore the status until it does.
+		return nil
+	}
+	newInfo := *info
+	newInfo.WorkloadStatus = s.Status
+	newInfo.WorkloadStatusInfo = s.StatusInfo
+	store.Update(&newInfo)
+	return nil
+}
+
 func (s *backingStatus) removed(st *State, store *multiwatcher.Store, id interface{}) error {
 	// If the status is removed, the parent will follow not long after,
 	// so do nothing.
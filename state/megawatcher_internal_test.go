@@ -48,7 +48,7 @@ func (s *storeManagerStateSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.MgoSuite.SetUpTest(c)
 	s.State = TestingInitialize(c, nil, Policy(nil))
-	s.State.AddUser(AdminUser, "", "pass")
+	s.State.AddUser(AdminUser, "", "pass", "")
 }
 
 func (s *storeManagerStateSuite) TearDownTest(c *gc.C) {
@@ -148,12 +148,13 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(m.Tag(), gc.Equals, fmt.Sprintf("machine-%d", i+1))
 
 		add(&params.UnitInfo{
-			Name:      fmt.Sprintf("wordpress/%d", i),
-			Service:   wordpress.Name(),
-			Series:    m.Series(),
-			MachineId: m.Id(),
-			Ports:     []network.Port{},
-			Status:    params.StatusPending,
+			Name:           fmt.Sprintf("wordpress/%d", i),
+			Service:        wordpress.Name(),
+			Series:         m.Series(),
+			MachineId:      m.Id(),
+			Ports:          []network.Port{},
+			Status:         params.StatusPending,
+			WorkloadStatus: params.StatusUnknown,
 		})
 		pairs := map[string]string{"name": fmt.Sprintf("bar %d", i)}
 		err = wu.SetAnnotations(pairs)
@@ -202,11 +203,12 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(ok, gc.Equals, true)
 		c.Assert(deployer, gc.Equals, fmt.Sprintf("unit-wordpress-%d", i))
 		add(&params.UnitInfo{
-			Name:    fmt.Sprintf("logging/%d", i),
-			Service: "logging",
-			Series:  "quantal",
-			Ports:   []network.Port{},
-			Status:  params.StatusPending,
+			Name:           fmt.Sprintf("logging/%d", i),
+			Service:        "logging",
+			Series:         "quantal",
+			Ports:          []network.Port{},
+			Status:         params.StatusPending,
+			WorkloadStatus: params.StatusUnknown,
 		})
 	}
 	return
@@ -373,13 +375,14 @@ var allWatcherChangedTests = []struct {
 		},
 		expectContents: []params.EntityInfo{
 			&params.UnitInfo{
-				Name:       "wordpress/0",
-				Service:    "wordpress",
-				Series:     "quantal",
-				MachineId:  "0",
-				Ports:      []network.Port{{"tcp", 12345}},
-				Status:     params.StatusError,
-				StatusInfo: "failure",
+				Name:           "wordpress/0",
+				Service:        "wordpress",
+				Series:         "quantal",
+				MachineId:      "0",
+				Ports:          []network.Port{{"tcp", 12345}},
+				Status:         params.StatusError,
+				StatusInfo:     "failure",
+				WorkloadStatus: params.StatusUnknown,
 			},
 		},
 	}, {
@@ -444,6 +447,7 @@ var allWatcherChangedTests = []struct {
 				Ports:          []network.Port{{"tcp", 12345}},
 				Status:         params.StatusError,
 				StatusInfo:     "failure",
+				WorkloadStatus: params.StatusUnknown,
 			},
 		},
 	},
@@ -735,6 +739,32 @@ var allWatcherChangedTests = []struct {
 				},
 			},
 		},
+	}, {
+		about: "workload status is changed if the unit exists in the store",
+		add: []params.EntityInfo{&params.UnitInfo{
+			Name:           "wordpress/0",
+			Status:         params.StatusStarted,
+			WorkloadStatus: params.StatusUnknown,
+		}},
+		setUp: func(c *gc.C, st *State) {
+			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"))
+			u, err := wordpress.AddUnit()
+			c.Assert(err, gc.IsNil)
+			err = u.SetWorkloadStatus(params.StatusBlocked, "missing config")
+			c.Assert(err, gc.IsNil)
+		},
+		change: watcher.Change{
+			C:  "statuses",
+			Id: "u#wordpress/0#workload",
+		},
+		expectContents: []params.EntityInfo{
+			&params.UnitInfo{
+				Name:               "wordpress/0",
+				Status:             params.StatusStarted,
+				WorkloadStatus:     params.StatusBlocked,
+				WorkloadStatusInfo: "missing config",
+			},
+		},
 	},
 	// Machine status changes
 	{
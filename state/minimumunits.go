@@ -26,6 +26,21 @@ type minUnitsDoc struct {
 	Revno       int
 }
 
+// ValidateMinUnits returns the error SetMinUnits would fail with
+// because of the given minimum number of units. It does not change
+// the service.
+func (s *Service) ValidateMinUnits(minUnits int) (err error) {
+	defer errors.Maskf(&err, "cannot set minimum units for service %q", s)
+	return checkMinUnits(minUnits)
+}
+
+func checkMinUnits(minUnits int) error {
+	if minUnits < 0 {
+		return errors.New("cannot set a negative minimum number of units")
+	}
+	return nil
+}
+
 // SetMinUnits changes the number of minimum units required by the service.
 func (s *Service) SetMinUnits(minUnits int) (err error) {
 	defer errors.Maskf(&err, "cannot set minimum units for service %q", s)
@@ -34,8 +49,8 @@ func (s *Service) SetMinUnits(minUnits int) (err error) {
 			s.doc.MinUnits = minUnits
 		}
 	}()
-	if minUnits < 0 {
-		return errors.New("cannot set a negative minimum number of units")
+	if err := checkMinUnits(minUnits); err != nil {
+		return err
 	}
 	service := &Service{st: s.st, doc: s.doc}
 	// Removing the document never fails. Racing clients trying to create the
@@ -106,6 +106,9 @@ func (s *MinUnitsSuite) TestSetMinUnits(c *gc.C) {
 func (s *MinUnitsSuite) TestInvalidMinUnits(c *gc.C) {
 	err := s.service.SetMinUnits(-1)
 	c.Assert(err, gc.ErrorMatches, `cannot set minimum units for service "dummy-service": cannot set a negative minimum number of units`)
+	err = s.service.ValidateMinUnits(-1)
+	c.Assert(err, gc.ErrorMatches, `cannot set minimum units for service "dummy-service": cannot set a negative minimum number of units`)
+	c.Assert(s.service.ValidateMinUnits(1), gc.IsNil)
 }
 
 func (s *MinUnitsSuite) TestMinUnitsInsertRetry(c *gc.C) {
@@ -225,14 +225,23 @@ var indexes = []struct {
 	{"networkinterfaces", []string{"macaddress", "networkname"}, true},
 	{"networkinterfaces", []string{"networkname"}, false},
 	{"networkinterfaces", []string{"machineid"}, false},
+	{"auditlog", []string{"time"}, false},
+	{"auditlog", []string{"entities"}, false},
+	{"logs", []string{"time"}, false},
+	{"logs", []string{"entity"}, false},
 }
 
-// The capped collection used for transaction logs defaults to 10MB.
-// It's tweaked in export_test.go to 1MB to avoid the overhead of
-// creating and deleting the large file repeatedly in tests.
+// The capped collections used for transaction logs, agent logs and the
+// audit log default to 10MB, 100MB and 50MB. They're tweaked in
+// export_test.go to 1MB to avoid the overhead of creating and deleting
+// the large files repeatedly in tests.
 var (
-	logSize      = 10000000
-	logSizeTests = 1000000
+	logSize           = 10000000
+	logSizeTests      = 1000000
+	logsSize          = 100000000
+	logsSizeTests     = 1000000
+	auditLogSize      = 50000000
+	auditLogSizeTests = 1000000
 )
 
 func maybeUnauthorized(err error, msg string) error {
@@ -304,12 +313,18 @@ func newState(session *mgo.Session, info *Info, policy Policy) (*State, error) {
 		units:             db.C("units"),
 		actions:           db.C("actions"),
 		actionresults:     db.C("actionresults"),
+		serviceactions:    db.C("serviceactions"),
 		users:             db.C("users"),
 		presence:          pdb.C("presence"),
 		cleanups:          db.C("cleanups"),
 		annotations:       db.C("annotations"),
 		statuses:          db.C("statuses"),
 		stateServers:      db.C("stateServers"),
+		backupsMetadata:   db.C("backupsmetadata"),
+		backupsStatus:     db.C("backupsstatus"),
+		auditLog:          db.C("auditlog"),
+		logs:              db.C("logs"),
+		leaderships:       db.C("leaderships"),
 	}
 	log := db.C("txns.log")
 	logInfo := mgo.CollectionInfo{Capped: true, MaxBytes: logSize}
@@ -319,6 +334,16 @@ func newState(session *mgo.Session, info *Info, policy Policy) (*State, error) {
 	if err != nil && err.Error() != "collection already exists" {
 		return nil, maybeUnauthorized(err, "cannot create log collection")
 	}
+	logsInfo := mgo.CollectionInfo{Capped: true, MaxBytes: logsSize}
+	err = st.logs.Create(&logsInfo)
+	if err != nil && err.Error() != "collection already exists" {
+		return nil, maybeUnauthorized(err, "cannot create agent logs collection")
+	}
+	auditLogInfo := mgo.CollectionInfo{Capped: true, MaxBytes: auditLogSize}
+	err = st.auditLog.Create(&auditLogInfo)
+	if err != nil && err.Error() != "collection already exists" {
+		return nil, maybeUnauthorized(err, "cannot create audit log collection")
+	}
 	st.runner = txn.NewRunner(db.C("txns"))
 	st.runner.ChangeLog(db.C("txns.log"))
 	st.watcher = watcher.New(db.C("txns.log"))
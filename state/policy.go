@@ -150,6 +150,13 @@ func (st *State) validateConstraints(cons constraints.Value) ([]string, error) {
 	return validator.Validate(cons)
 }
 
+// ValidateConstraints returns an error if the given constraints are not
+// valid for the current environment, along with the names of any
+// constraint attributes the environment does not support.
+func (st *State) ValidateConstraints(cons constraints.Value) ([]string, error) {
+	return st.validateConstraints(cons)
+}
+
 // validate calls the state's assigned policy, if non-nil, to obtain
 // a ConfigValidator, and calls Validate if a non-nil ConfigValidator is
 // returned.
@@ -0,0 +1,79 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"labix.org/v2/mgo/bson"
+	"labix.org/v2/mgo/txn"
+
+	"github.com/juju/juju/instance"
+)
+
+// ResetStateServer prepares a state restored from a backup for the new
+// state server machine it was restored onto. The identified machine is
+// recorded as running on the new instance, and becomes the only state
+// server: any other state server machines, whose instances are assumed
+// to have been lost along with the original state server, are removed.
+func (st *State) ResetStateServer(machineId string, instId instance.Id) (err error) {
+	defer errors.Maskf(&err, "cannot reset state server to machine %s", machineId)
+	machines, err := st.AllMachines()
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      st.machines.Name,
+		Id:     machineId,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{
+			{"instanceid", instId},
+			{"novote", false},
+			{"hasvote", true},
+		}}},
+	}, {
+		C:      st.instanceData.Name,
+		Id:     machineId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"instanceid", instId}}}},
+	}, {
+		C:  st.stateServers.Name,
+		Id: environGlobalKey,
+		Update: bson.D{{"$set", bson.D{
+			{"machineids", []string{machineId}},
+			{"votingmachineids", []string{machineId}},
+		}}},
+	}}
+	var lost []*Machine
+	for _, m := range machines {
+		if m.Id() == machineId || !m.IsManager() {
+			continue
+		}
+		lost = append(lost, m)
+		ops = append(ops, txn.Op{
+			C:  st.machines.Name,
+			Id: m.Id(),
+			Update: bson.D{{"$set", bson.D{
+				{"life", Dead},
+				{"novote", true},
+				{"hasvote", false},
+			}}},
+		})
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("alive provisioned machine %s", machineId)
+	} else if err != nil {
+		return err
+	}
+	for _, m := range lost {
+		if err := m.Refresh(); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := m.Remove(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
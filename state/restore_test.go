@@ -0,0 +1,59 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type RestoreSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RestoreSuite{})
+
+func (s *RestoreSuite) TestResetStateServer(c *gc.C) {
+	m0, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, gc.IsNil)
+	err = m0.SetProvisioned("old-instance", "fake_nonce", nil)
+	c.Assert(err, gc.IsNil)
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	m3, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.ResetStateServer("0", "new-instance")
+	c.Assert(err, gc.IsNil)
+
+	err = m0.Refresh()
+	c.Assert(err, gc.IsNil)
+	instId, err := m0.InstanceId()
+	c.Assert(err, gc.IsNil)
+	c.Assert(instId, gc.Equals, instance.Id("new-instance"))
+	c.Assert(m0.HasVote(), jc.IsTrue)
+
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, gc.IsNil)
+	c.Assert(info.MachineIds, gc.DeepEquals, []string{"0"})
+	c.Assert(info.VotingMachineIds, gc.DeepEquals, []string{"0"})
+
+	for _, id := range []string{"1", "2"} {
+		_, err := s.State.Machine(id)
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+	err = m3.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(m3.Life(), gc.Equals, state.Alive)
+}
+
+func (s *RestoreSuite) TestResetStateServerUnknownMachine(c *gc.C) {
+	err := s.State.ResetStateServer("42", "new-instance")
+	c.Assert(err, gc.ErrorMatches, "cannot reset state server to machine 42: alive provisioned machine 42 not found")
+}
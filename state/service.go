@@ -43,7 +43,10 @@ type serviceDoc struct {
 	Exposed       bool
 	MinUnits      int
 	OwnerTag      string
-	TxnRevno      int64 `bson:"txn-revno"`
+	// FutureActions holds the ids of the service actions to be queued
+	// on every unit added to the service.
+	FutureActions []string `bson:",omitempty"`
+	TxnRevno      int64    `bson:"txn-revno"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -224,8 +227,10 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		Id:     s.settingsKey(),
 		Remove: true,
 	}}
+	ops = append(ops, removeLeadershipOps(s.st, s.doc.Name)...)
 	ops = append(ops, removeRequestedNetworksOp(s.st, s.globalKey()))
 	ops = append(ops, removeConstraintsOp(s.st, s.globalKey()))
+	ops = append(ops, s.st.newCleanupOp(cleanupServiceActions, actionPrefix(s.globalKey())))
 	return append(ops, annotationRemoveOp(s.st, s.globalKey()))
 }
 
@@ -351,14 +356,12 @@ func (s *Service) extraPeerRelations(newMeta *charm.Meta) map[string]charm.Relat
 }
 
 func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op, error) {
+	if err := s.checkRelationsImplemented(ch, relations); err != nil {
+		return nil, err
+	}
 	asserts := make([]txn.Op, 0, len(relations))
-	// All relations must still exist and their endpoints are implemented by the charm.
+	// All relations must still exist.
 	for _, rel := range relations {
-		if ep, err := rel.Endpoint(s.doc.Name); err != nil {
-			return nil, err
-		} else if !ep.ImplementedBy(ch) {
-			return nil, fmt.Errorf("cannot upgrade service %q to charm %q: would break relation %q", s, ch, rel)
-		}
 		asserts = append(asserts, txn.Op{
 			C:      s.st.relations.Name,
 			Id:     rel.doc.Key,
@@ -368,6 +371,19 @@ func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op,
 	return asserts, nil
 }
 
+// checkRelationsImplemented returns an error if the given charm does
+// not implement the service's endpoints of all the given relations.
+func (s *Service) checkRelationsImplemented(ch *Charm, relations []*Relation) error {
+	for _, rel := range relations {
+		if ep, err := rel.Endpoint(s.doc.Name); err != nil {
+			return err
+		} else if !ep.ImplementedBy(ch) {
+			return fmt.Errorf("cannot upgrade service %q to charm %q: would break relation %q", s, ch, rel)
+		}
+	}
+	return nil
+}
+
 // changeCharmOps returns the operations necessary to set a service's
 // charm URL to a new value.
 func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
@@ -461,11 +477,8 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 // this charm, and existing units will be upgraded to use it. If force is true,
 // units will be upgraded even if they are in an error state.
 func (s *Service) SetCharm(ch *Charm, force bool) (err error) {
-	if ch.Meta().Subordinate != s.doc.Subordinate {
-		return fmt.Errorf("cannot change a service's subordinacy")
-	}
-	if ch.URL().Series != s.doc.Series {
-		return fmt.Errorf("cannot change a service's series")
+	if err := s.checkCharmChange(ch.URL(), ch.Meta()); err != nil {
+		return err
 	}
 	for i := 0; i < 5; i++ {
 		var ops []txn.Op
@@ -509,6 +522,32 @@ func (s *Service) SetCharm(ch *Charm, force bool) (err error) {
 	return ErrExcessiveContention
 }
 
+// checkCharmChange returns an error if the service's charm cannot be
+// changed to the charm with the given URL and metadata.
+func (s *Service) checkCharmChange(curl *charm.URL, meta *charm.Meta) error {
+	if meta.Subordinate != s.doc.Subordinate {
+		return fmt.Errorf("cannot change a service's subordinacy")
+	}
+	if curl.Series != s.doc.Series {
+		return fmt.Errorf("cannot change a service's series")
+	}
+	return nil
+}
+
+// ValidateCharm returns the error SetCharm would fail with if the
+// service's charm were changed to the given charm. It does not change
+// the service.
+func (s *Service) ValidateCharm(ch *Charm) error {
+	if err := s.checkCharmChange(ch.URL(), ch.Meta()); err != nil {
+		return err
+	}
+	relations, err := s.Relations()
+	if err != nil {
+		return err
+	}
+	return s.checkRelationsImplemented(ch, relations)
+}
+
 // String returns the service name.
 func (s *Service) String() string {
 	return s.doc.Name
@@ -575,10 +614,13 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 			Insert: udoc,
 		},
 		createStatusOp(s.st, globalKey, sdoc),
+		createStatusOp(s.st, unitWorkloadGlobalKey(name), statusDoc{
+			Status: params.StatusUnknown,
+		}),
 		{
 			C:      s.st.services.Name,
 			Id:     s.doc.Name,
-			Assert: append(isAliveDoc, asserts...),
+			Assert: append(append(isAliveDoc, s.futureActionsAssert()...), asserts...),
 			Update: bson.D{{"$inc", bson.D{{"unitcount", 1}}}},
 		}}
 	if s.doc.Subordinate {
@@ -601,6 +643,11 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		}
 		ops = append(ops, createConstraintsOp(s.st, globalKey, cons))
 	}
+	actionOps, err := s.futureActionOps(globalKey)
+	if err != nil {
+		return "", nil, err
+	}
+	ops = append(ops, actionOps...)
 	return name, ops, nil
 }
 
@@ -623,21 +670,28 @@ func (s *Service) GetOwnerTag() string {
 // AddUnit adds a new principal unit to the service.
 func (s *Service) AddUnit() (unit *Unit, err error) {
 	defer errors.Maskf(&err, "cannot add unit to service %q", s)
-	name, ops, err := s.addUnitOps("", nil)
-	if err != nil {
-		return nil, err
-	}
-	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
-		if alive, err := isAlive(s.st.services, s.doc.Name); err != nil {
+	for i := 0; i < 3; i++ {
+		name, ops, err := s.addUnitOps("", nil)
+		if err != nil {
 			return nil, err
-		} else if !alive {
-			return nil, fmt.Errorf("service is not alive")
 		}
-		return nil, fmt.Errorf("inconsistent state")
-	} else if err != nil {
-		return nil, err
+		if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+			if alive, err := isAlive(s.st.services, s.doc.Name); err != nil {
+				return nil, err
+			} else if !alive {
+				return nil, fmt.Errorf("service is not alive")
+			}
+			// The actions to be queued on new units have changed.
+			if err := s.Refresh(); err != nil {
+				return nil, err
+			}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return s.Unit(name)
 	}
-	return s.Unit(name)
+	return nil, ErrExcessiveContention
 }
 
 var ErrExcessiveContention = stderrors.New("state changing too quickly; try again soon")
@@ -673,6 +727,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	},
 		removeConstraintsOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeStatusOp(s.st, unitWorkloadGlobalKey(u.doc.Name)),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
@@ -80,6 +80,32 @@ func (s *ServiceSuite) TestSetCharmErrors(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cannot change a service's series")
 }
 
+func (s *ServiceSuite) TestValidateCharm(c *gc.C) {
+	logging := s.AddTestingCharm(c, "logging")
+	err := s.mysql.ValidateCharm(logging)
+	c.Assert(err, gc.ErrorMatches, "cannot change a service's subordinacy")
+
+	othermysql := s.AddSeriesCharm(c, "mysql", "otherseries")
+	err = s.mysql.ValidateCharm(othermysql)
+	c.Assert(err, gc.ErrorMatches, "cannot change a service's series")
+
+	ms := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	svc := s.AddTestingService(c, "fakemysql", ms)
+	newCh := s.AddMetaCharm(c, "mysql", metaDifferentPeer, 3)
+	err = svc.ValidateCharm(newCh)
+	c.Assert(err, gc.ErrorMatches, `cannot upgrade service "fakemysql" to charm "local:quantal/quantal-mysql-3": would break relation "fakemysql:cluster"`)
+
+	newCh = s.AddMetaCharm(c, "mysql", metaExtraEndpoints, 4)
+	err = svc.ValidateCharm(newCh)
+	c.Assert(err, gc.IsNil)
+
+	// The service's charm is left unchanged.
+	err = svc.Refresh()
+	c.Assert(err, gc.IsNil)
+	curl, _ := svc.CharmURL()
+	c.Assert(curl, gc.DeepEquals, ms.URL())
+}
+
 var metaBase = `
 name: mysql
 summary: "Fake MySQL Database engine"
@@ -0,0 +1,346 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+	"labix.org/v2/mgo/txn"
+)
+
+type serviceActionDoc struct {
+	// Id is the key for this document. The format of the id is:
+	//   <service globalKey> + actionMarker + <generated state sequence>
+	Id string `bson:"_id"`
+
+	// Name identifies the action; it should match an action defined by
+	// the service's charm.
+	Name string
+
+	// Payload holds the action's parameters, if any.
+	Payload map[string]interface{}
+
+	// IncludeFuture records whether the action should also be queued on
+	// units added to the service after the action was enqueued.
+	IncludeFuture bool
+
+	// Timeout is the timeout requested for the unit actions; zero
+	// selects the timeout declared by the charm, if any.
+	Timeout time.Duration
+
+	// Enqueued is the time the action was added.
+	Enqueued time.Time
+}
+
+// ServiceAction represents an action queued on every unit of a service.
+// Each unit runs its own Action; the ServiceAction tracks them so that
+// their progress can be reported as a whole.
+type ServiceAction struct {
+	st  *State
+	doc serviceActionDoc
+}
+
+// newServiceAction builds a ServiceAction from the supplied state and
+// serviceActionDoc.
+func newServiceAction(st *State, doc serviceActionDoc) *ServiceAction {
+	return &ServiceAction{
+		st:  st,
+		doc: doc,
+	}
+}
+
+var validServiceAction = regexp.MustCompile("^" + regexp.QuoteMeta(serviceGlobalKey("")) + ".+" + regexp.QuoteMeta(actionMarker) + "\\d+$")
+
+// IsServiceAction returns whether id is a valid service action id.
+func IsServiceAction(id string) bool {
+	return validServiceAction.MatchString(id)
+}
+
+// Id returns the id of the ServiceAction.
+func (a *ServiceAction) Id() string {
+	return a.doc.Id
+}
+
+// ServiceName returns the name of the service the action was queued on.
+func (a *ServiceAction) ServiceName() string {
+	return strings.TrimPrefix(getActionIdPrefix(a.doc.Id), serviceGlobalKey(""))
+}
+
+// Name returns the name of the action.
+func (a *ServiceAction) Name() string {
+	return a.doc.Name
+}
+
+// Payload returns the parameters passed to the action.
+func (a *ServiceAction) Payload() map[string]interface{} {
+	return a.doc.Payload
+}
+
+// IncludeFuture returns whether the action is also queued on units
+// added to the service after it was enqueued.
+func (a *ServiceAction) IncludeFuture() bool {
+	return a.doc.IncludeFuture
+}
+
+// Enqueued returns the time the action was added.
+func (a *ServiceAction) Enqueued() time.Time {
+	return a.doc.Enqueued
+}
+
+// Timeout returns the timeout requested for the unit actions, or zero
+// if the timeout declared by the charm applies.
+func (a *ServiceAction) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// Actions returns the unit actions queued by the ServiceAction that
+// have not yet finished.
+func (a *ServiceAction) Actions() ([]*Action, error) {
+	actions := []*Action{}
+	iter := a.st.actions.Find(bson.D{{"serviceactionid", a.doc.Id}}).Iter()
+	for {
+		var doc actionDoc
+		if !iter.Next(&doc) {
+			break
+		}
+		actions = append(actions, newAction(a.st, doc))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("cannot get actions for service action %q: %v", a.doc.Id, err)
+	}
+	return actions, nil
+}
+
+// Results returns the results of the unit actions queued by the
+// ServiceAction that have finished.
+func (a *ServiceAction) Results() ([]*ActionResult, error) {
+	results, err := a.st.findActionResults(bson.D{{"serviceactionid", a.doc.Id}})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get results for service action %q: %v", a.doc.Id, err)
+	}
+	return results, nil
+}
+
+// AddAction queues the named action on every unit of the service, and
+// returns a ServiceAction that tracks the progress of the unit actions.
+// A non-zero timeout overrides the timeout declared by the charm for the
+// action. If includeFuture is true, the action is also queued on each
+// unit subsequently added to the service, until the ServiceAction is
+// cancelled. The action is queued on all the units, or on none of them.
+func (s *Service) AddAction(name string, payload map[string]interface{}, timeout time.Duration, includeFuture bool) (*ServiceAction, error) {
+	if timeout < 0 {
+		return nil, fmt.Errorf("invalid action timeout %v", timeout)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, err
+	}
+	if payload, err = validateActionPayload(ch, name, payload); err != nil {
+		return nil, err
+	}
+	id, err := newActionId(s.st, s.globalKey())
+	if err != nil {
+		return nil, fmt.Errorf("cannot add action; error generating key: %v", err)
+	}
+	doc := serviceActionDoc{
+		Id:            id,
+		Name:          name,
+		Payload:       payload,
+		IncludeFuture: includeFuture,
+		Timeout:       timeout,
+		Enqueued:      nowToTheSecond(),
+	}
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, err
+			}
+		}
+		ops, err := s.addActionOps(doc)
+		if err != nil {
+			return nil, err
+		}
+		switch err := s.st.runTransaction(ops); err {
+		case txn.ErrAborted:
+			if alive, err := isAlive(s.st.services, s.doc.Name); err != nil {
+				return nil, err
+			} else if !alive {
+				return nil, fmt.Errorf("service %q is not alive", s)
+			}
+			// Units have been added, or have died; try again.
+		case nil:
+			if includeFuture {
+				s.doc.FutureActions = append(s.doc.FutureActions, doc.Id)
+			}
+			return newServiceAction(s.st, doc), nil
+		default:
+			return nil, err
+		}
+	}
+	return nil, ErrExcessiveContention
+}
+
+// addActionOps returns the operations that insert the given service
+// action and queue it on every unit of the service that is not dead.
+// The operations assert that no unit has been added to the service
+// since it was last read.
+func (s *Service) addActionOps(doc serviceActionDoc) ([]txn.Op, error) {
+	serviceOp := txn.Op{
+		C:      s.st.services.Name,
+		Id:     s.doc.Name,
+		Assert: append(isAliveDoc, bson.DocElem{"unitcount", s.doc.UnitCount}),
+	}
+	if doc.IncludeFuture {
+		serviceOp.Update = bson.D{{"$addToSet", bson.D{{"futureactions", doc.Id}}}}
+	}
+	ops := []txn.Op{serviceOp, {
+		C:      s.st.serviceactions.Name,
+		Id:     doc.Id,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range units {
+		if unit.Life() == Dead {
+			continue
+		}
+		ch, err := unit.actionCharm()
+		if err != nil {
+			return nil, err
+		}
+		_, unitOps, err := unit.addActionOps(ch, doc.Name, doc.Payload, doc.Timeout, doc.Id)
+		if err != nil {
+			return nil, fmt.Errorf("cannot queue action on unit %q: %v", unit, err)
+		}
+		ops = append(ops, unitOps...)
+	}
+	return ops, nil
+}
+
+// futureActionsAssert returns an assertion that the service actions to
+// be queued on new units of the service are those last read.
+func (s *Service) futureActionsAssert() bson.D {
+	if len(s.doc.FutureActions) == 0 {
+		return bson.D{{"futureactions.0", bson.D{{"$exists", false}}}}
+	}
+	return bson.D{{"futureactions", s.doc.FutureActions}}
+}
+
+// futureActionOps returns the operations that queue, on the new unit
+// with the given global key, every action queued on the service with
+// includeFuture set.
+func (s *Service) futureActionOps(globalKey string) ([]txn.Op, error) {
+	if len(s.doc.FutureActions) == 0 {
+		return nil, nil
+	}
+	var docs []serviceActionDoc
+	sel := bson.D{{"_id", bson.D{{"$in", s.doc.FutureActions}}}}
+	if err := s.st.serviceactions.Find(sel).All(&docs); err != nil {
+		return nil, fmt.Errorf("cannot get actions to queue on new unit: %v", err)
+	}
+	// Queue the actions in the order in which they were queued on the
+	// service.
+	sort.Sort(serviceActionDocsBySequence(docs))
+	var ch *Charm
+	var ops []txn.Op
+	for _, doc := range docs {
+		timeout := doc.Timeout
+		if timeout == 0 {
+			if ch == nil {
+				var err error
+				if ch, err = s.st.Charm(s.doc.CharmURL); err != nil {
+					return nil, err
+				}
+			}
+			timeout = actionTimeout(ch, doc.Name)
+		}
+		_, op, err := newActionOp(s.st, globalKey, doc.Name, doc.Payload, timeout, doc.Id)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// serviceActionDocsBySequence sorts service action documents in the
+// order in which they were queued.
+type serviceActionDocsBySequence []serviceActionDoc
+
+func (d serviceActionDocsBySequence) Len() int      { return len(d) }
+func (d serviceActionDocsBySequence) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d serviceActionDocsBySequence) Less(i, j int) bool {
+	return actionIdSequence(d[i].Id) < actionIdSequence(d[j].Id)
+}
+
+// Cancel stops the ServiceAction from being queued on units added to
+// the service in future, and cancels each of its unit actions that has
+// not yet finished.
+func (a *ServiceAction) Cancel() error {
+	ops := []txn.Op{{
+		C:      a.st.serviceactions.Name,
+		Id:     a.doc.Id,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"includefuture", false}}}},
+	}}
+	if a.doc.IncludeFuture {
+		ops = append(ops, txn.Op{
+			C:      a.st.services.Name,
+			Id:     a.ServiceName(),
+			Update: bson.D{{"$pull", bson.D{{"futureactions", a.doc.Id}}}},
+		})
+	}
+	switch err := a.st.runTransaction(ops); err {
+	case txn.ErrAborted:
+		return errors.NotFoundf("service action %q", a.doc.Id)
+	case nil:
+		a.doc.IncludeFuture = false
+	default:
+		return err
+	}
+	actions, err := a.Actions()
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		if err := action.Cancel(); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("cannot cancel action %q: %v", action.Id(), err)
+		}
+	}
+	return nil
+}
+
+// ServiceAction returns a ServiceAction by id.
+func (st *State) ServiceAction(id string) (*ServiceAction, error) {
+	doc := serviceActionDoc{}
+	err := st.serviceactions.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("service action %q", id)
+	}
+	if err != nil {
+		return nil, errors.Errorf("cannot get service action %q: %v", id, err)
+	}
+	return newServiceAction(st, doc), nil
+}
+
+// cleanupServiceActions removes the service actions with ids starting
+// with the given prefix, so that they are not queued on the units of a
+// later service with the same name.
+func (st *State) cleanupServiceActions(prefix string) error {
+	sel := bson.D{{"_id", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix)}}}
+	if _, err := st.serviceactions.RemoveAll(sel); err != nil {
+		return fmt.Errorf("cannot remove service actions marked for cleanup: %v", err)
+	}
+	return nil
+}
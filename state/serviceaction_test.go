@@ -0,0 +1,248 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state"
+)
+
+type ServiceActionSuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+	units   []*state.Unit
+}
+
+var _ = gc.Suite(&ServiceActionSuite{})
+
+func (s *ServiceActionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+	s.units = make([]*state.Unit, 3)
+	for i := range s.units {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, gc.IsNil)
+		s.units[i] = unit
+	}
+}
+
+func (s *ServiceActionSuite) TestAddAction(c *gc.C) {
+	serviceAction, err := s.service.AddAction("snapshot", nil, 0, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(state.IsServiceAction(serviceAction.Id()), jc.IsTrue)
+	c.Assert(state.IsServiceAction(s.units[0].Name()), jc.IsFalse)
+	c.Assert(serviceAction.ServiceName(), gc.Equals, "dummy")
+	c.Assert(serviceAction.Name(), gc.Equals, "snapshot")
+	c.Assert(serviceAction.Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+	c.Assert(serviceAction.IncludeFuture(), jc.IsFalse)
+	c.Assert(serviceAction.Enqueued().IsZero(), jc.IsFalse)
+
+	// Every unit has had the action queued.
+	for _, unit := range s.units {
+		actions, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(actions, gc.HasLen, 1)
+		c.Assert(actions[0].Name(), gc.Equals, "snapshot")
+		c.Assert(actions[0].ServiceActionId(), gc.Equals, serviceAction.Id())
+	}
+
+	// The service action can be retrieved by id.
+	fetched, err := s.State.ServiceAction(serviceAction.Id())
+	c.Assert(err, gc.IsNil)
+	c.Assert(fetched.Id(), gc.Equals, serviceAction.Id())
+	c.Assert(fetched.Payload(), jc.DeepEquals, serviceAction.Payload())
+
+	_, err = s.State.ServiceAction("s#dummy#a#99")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `service action "s#dummy#a#99" not found`)
+}
+
+func (s *ServiceActionSuite) TestAddActionValidatesPayload(c *gc.C) {
+	_, err := s.service.AddAction("snapshot", map[string]interface{}{"outfile": 5}, 0, false)
+	c.Assert(err, gc.ErrorMatches, "invalid action parameters: outfile: .*")
+	_, err = s.service.AddAction("nonsense", nil, 0, false)
+	c.Assert(err, gc.ErrorMatches, `action "nonsense" not defined by charm ".*"`)
+
+	for _, unit := range s.units {
+		actions, err := unit.Actions()
+		c.Assert(err, gc.IsNil)
+		c.Assert(actions, gc.HasLen, 0)
+	}
+}
+
+func (s *ServiceActionSuite) TestAddActionDyingService(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, gc.IsNil)
+	_, err = s.service.AddAction("snapshot", nil, 0, false)
+	c.Assert(err, gc.ErrorMatches, `service "dummy" is not alive`)
+}
+
+func (s *ServiceActionSuite) TestIncludeFuture(c *gc.C) {
+	now, err := s.service.AddAction("snapshot", nil, 0, false)
+	c.Assert(err, gc.IsNil)
+	future, err := s.service.AddAction("snapshot", map[string]interface{}{"outfile": "x.bz2"}, 0, true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(future.IncludeFuture(), jc.IsTrue)
+
+	// A unit added later only gets the action that includes future units.
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	actions, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].ServiceActionId(), gc.Equals, future.Id())
+	c.Assert(actions[0].Payload(), jc.DeepEquals, map[string]interface{}{"outfile": "x.bz2"})
+
+	nowActions, err := now.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(nowActions, gc.HasLen, 3)
+	futureActions, err := future.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(futureActions, gc.HasLen, 4)
+}
+
+func (s *ServiceActionSuite) TestAddActionQueuesOnConcurrentlyAddedUnit(c *gc.C) {
+	var added *state.Unit
+	defer state.SetBeforeHooks(c, s.State, func() {
+		service, err := s.State.Service("dummy")
+		c.Assert(err, gc.IsNil)
+		added, err = service.AddUnit()
+		c.Assert(err, gc.IsNil)
+	}).Check()
+
+	serviceAction, err := s.service.AddAction("snapshot", nil, 0, false)
+	c.Assert(err, gc.IsNil)
+	actions, err := serviceAction.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 4)
+	actions, err = added.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+}
+
+func (s *ServiceActionSuite) TestAddUnitQueuesConcurrentlyAddedFutureAction(c *gc.C) {
+	var future *state.ServiceAction
+	defer state.SetBeforeHooks(c, s.State, func() {
+		service, err := s.State.Service("dummy")
+		c.Assert(err, gc.IsNil)
+		future, err = service.AddAction("snapshot", nil, 0, true)
+		c.Assert(err, gc.IsNil)
+	}).Check()
+
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	actions, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].ServiceActionId(), gc.Equals, future.Id())
+}
+
+func (s *ServiceActionSuite) TestCancel(c *gc.C) {
+	serviceAction, err := s.service.AddAction("snapshot", nil, 0, true)
+	c.Assert(err, gc.IsNil)
+	actions, err := serviceAction.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 3)
+	err = actions[0].Begin()
+	c.Assert(err, gc.IsNil)
+
+	err = serviceAction.Cancel()
+	c.Assert(err, gc.IsNil)
+	c.Assert(serviceAction.IncludeFuture(), jc.IsFalse)
+
+	// The running action is marked for cancellation; the others are
+	// cancelled straight away.
+	actions, err = serviceAction.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Cancelling(), jc.IsTrue)
+	results, err := serviceAction.Results()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	for _, result := range results {
+		c.Assert(result.Status(), gc.Equals, state.ActionCancelled)
+	}
+
+	// Units added later do not get the action.
+	unit, err := s.service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	actions, err = unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *ServiceActionSuite) TestProgress(c *gc.C) {
+	serviceAction, err := s.service.AddAction("snapshot", nil, 0, false)
+	c.Assert(err, gc.IsNil)
+
+	// Unrelated actions are not reported.
+	_, err = s.units[0].AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	actions, err := serviceAction.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 3)
+	byUnit := make(map[string]*state.Action)
+	for _, action := range actions {
+		c.Assert(action.Status(), gc.Equals, state.ActionPending)
+		byUnit[action.UnitName()] = action
+	}
+
+	err = byUnit["dummy/0"].Begin()
+	c.Assert(err, gc.IsNil)
+	err = byUnit["dummy/1"].Finish(state.ActionCompleted, map[string]interface{}{"size": 10}, "")
+	c.Assert(err, gc.IsNil)
+	err = byUnit["dummy/2"].Finish(state.ActionFailed, nil, "disk full")
+	c.Assert(err, gc.IsNil)
+
+	actions, err = serviceAction.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].UnitName(), gc.Equals, "dummy/0")
+	c.Assert(actions[0].Status(), gc.Equals, state.ActionRunning)
+
+	results, err := serviceAction.Results()
+	c.Assert(err, gc.IsNil)
+	c.Assert(results, gc.HasLen, 2)
+	statuses := make(map[string]state.ActionStatus)
+	for _, result := range results {
+		c.Assert(result.ServiceActionId(), gc.Equals, serviceAction.Id())
+		statuses[result.UnitName()] = result.Status()
+	}
+	c.Assert(statuses, jc.DeepEquals, map[string]state.ActionStatus{
+		"dummy/1": state.ActionCompleted,
+		"dummy/2": state.ActionFailed,
+	})
+}
+
+func (s *ServiceActionSuite) TestCleanupOnServiceRemoval(c *gc.C) {
+	serviceAction, err := s.service.AddAction("snapshot", nil, 0, true)
+	c.Assert(err, gc.IsNil)
+	err = s.service.Destroy()
+	c.Assert(err, gc.IsNil)
+	for _, unit := range s.units {
+		err = unit.EnsureDead()
+		c.Assert(err, gc.IsNil)
+		err = unit.Remove()
+		c.Assert(err, gc.IsNil)
+	}
+	err = s.State.Cleanup()
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.State.ServiceAction(serviceAction.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// A new service with the same name does not inherit the action.
+	service := s.AddTestingService(c, "dummy", s.charm)
+	unit, err := service.AddUnit()
+	c.Assert(err, gc.IsNil)
+	actions, err := unit.Actions()
+	c.Assert(err, gc.IsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
@@ -66,12 +66,18 @@ type State struct {
 	units             *mgo.Collection
 	actions           *mgo.Collection
 	actionresults     *mgo.Collection
+	serviceactions    *mgo.Collection
 	users             *mgo.Collection
 	presence          *mgo.Collection
 	cleanups          *mgo.Collection
 	annotations       *mgo.Collection
 	statuses          *mgo.Collection
 	stateServers      *mgo.Collection
+	backupsMetadata   *mgo.Collection
+	backupsStatus     *mgo.Collection
+	auditLog          *mgo.Collection
+	logs              *mgo.Collection
+	leaderships       *mgo.Collection
 	runner            *txn.Runner
 	transactionHooks  chan ([]transactionHook)
 	watcher           *watcher.Watcher
@@ -923,6 +929,32 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 	return ops, nil
 }
 
+// ValidateNewService returns the error AddService would fail with
+// because of the name of the service, which must be valid and unused,
+// or the life of the environment. It does not add the service.
+func (st *State) ValidateNewService(name string) (err error) {
+	defer errors.Maskf(&err, "cannot add service %q", name)
+	return st.checkNewService(name)
+}
+
+func (st *State) checkNewService(name string) error {
+	if !names.IsService(name) {
+		return fmt.Errorf("invalid name")
+	}
+	if exists, err := isNotDead(st.services, name); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("service already exists")
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return err
+	} else if env.Life() != Alive {
+		return fmt.Errorf("environment is no longer alive")
+	}
+	return nil
+}
+
 // AddService creates a new service, running the supplied charm, with the
 // supplied name (which must be unique). If the charm defines peer relations,
 // they will be created automatically.
@@ -933,22 +965,11 @@ func (st *State) AddService(name, ownerTag string, ch *Charm, networks []string)
 		return nil, fmt.Errorf("Invalid ownertag %s", ownerTag)
 	}
 	// Sanity checks.
-	if !names.IsService(name) {
-		return nil, fmt.Errorf("invalid name")
-	}
 	if ch == nil {
 		return nil, fmt.Errorf("charm is nil")
 	}
-	if exists, err := isNotDead(st.services, name); err != nil {
+	if err := st.checkNewService(name); err != nil {
 		return nil, err
-	} else if exists {
-		return nil, fmt.Errorf("service already exists")
-	}
-	env, err := st.Environment()
-	if err != nil {
-		return nil, err
-	} else if env.Life() != Alive {
-		return nil, fmt.Errorf("environment is no longer alive")
 	}
 	if userExists, err := st.checkUserExists(ownerId); err != nil {
 		return nil, err
@@ -976,6 +997,7 @@ func (st *State) AddService(name, ownerTag string, ch *Charm, networks []string)
 		// and known before setting them.
 		createRequestedNetworksOp(st, svc.globalKey(), networks),
 		createSettingsOp(st, svc.settingsKey(), nil),
+		createSettingsOp(st, leaderSettingsKey(name), nil),
 		{
 			C:      st.users.Name,
 			Id:     ownerId,
@@ -1458,14 +1480,30 @@ func (st *State) ActionResultsForAction(actionId string) ([]*ActionResult, error
 	return st.actionResults(actionId + actionResultMarker)
 }
 
+// ActionResultsByName returns the actionresults of every finished action
+// with the given name, across all units.
+func (st *State) ActionResultsByName(name string) ([]*ActionResult, error) {
+	return st.findActionResults(bson.D{{"actionname", name}})
+}
+
 // actionResults returns actionresults that match the given id prefix.
 // We assume the prefix has been scrubbed before calling this
 func (st *State) actionResults(prefix string) ([]*ActionResult, error) {
-	results := []*ActionResult{}
 	sel := bson.D{{"_id", bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix)}}}
+	return st.findActionResults(sel)
+}
+
+// findActionResults returns the actionresults matching the given query.
+func (st *State) findActionResults(sel interface{}) ([]*ActionResult, error) {
+	results := []*ActionResult{}
 	iter := st.actionresults.Find(sel).Iter()
-	doc := actionResultDoc{}
-	for iter.Next(&doc) {
+	for {
+		// Decode each result into a fresh doc, so that the results
+		// do not share their Payload and Results maps.
+		var doc actionResultDoc
+		if !iter.Next(&doc) {
+			break
+		}
 		results = append(results, newActionResult(st, doc))
 	}
 	if err := iter.Err(); err != nil {
@@ -84,7 +84,7 @@ func (s *StateSuite) TestAddresses(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	machines[1], err = s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, gc.IsNil)
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	machines[2], err = s.State.Machine("2")
 	c.Assert(err, gc.IsNil)
@@ -1169,6 +1169,22 @@ func (s *StateSuite) TestAddServiceEnvironmentDyingAfterInitial(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot add service "s1": environment is no longer alive`)
 }
 
+func (s *StateSuite) TestValidateNewService(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	c.Assert(s.State.ValidateNewService("mysql"), gc.IsNil)
+	err := s.State.ValidateNewService("haha/borken")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "haha/borken": invalid name`)
+	err = s.State.ValidateNewService("wordpress")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "wordpress": service already exists`)
+
+	env, err := s.State.Environment()
+	c.Assert(err, gc.IsNil)
+	err = env.Destroy()
+	c.Assert(err, gc.IsNil)
+	err = s.State.ValidateNewService("mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql": environment is no longer alive`)
+}
+
 func (s *StateSuite) TestServiceNotFound(c *gc.C) {
 	_, err := s.State.Service("bummer")
 	c.Assert(err, gc.ErrorMatches, `service "bummer" not found`)
@@ -1844,7 +1860,7 @@ func (s *StateSuite) TestWatchStateServerInfo(c *gc.C) {
 		return true, nil
 	})
 
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	wc.AssertOneChange()
@@ -2417,7 +2433,7 @@ func (s *StateSuite) TestFindEntity(c *gc.C) {
 	svc := s.AddTestingService(c, "ser-vice2", s.AddTestingCharm(c, "mysql"))
 	_, err = svc.AddUnit()
 	c.Assert(err, gc.IsNil)
-	_, err = s.State.AddUser("arble", "", "pass")
+	_, err = s.State.AddUser("arble", "", "pass", "admin")
 	c.Assert(err, gc.IsNil)
 	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
 	eps, err := s.State.InferEndpoints([]string{"wordpress", "ser-vice2"})
@@ -2509,7 +2525,7 @@ func (s *StateSuite) TestParseTag(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// Parse a user entity name.
-	user, err := s.State.AddUser("arble", "", "pass")
+	user, err := s.State.AddUser("arble", "", "pass", "admin")
 	c.Assert(err, gc.IsNil)
 	coll, id, err = state.ParseTag(s.State, user.Tag())
 	c.Assert(coll, gc.Equals, "users")
@@ -3039,10 +3055,10 @@ func (s *StateSuite) TestOpenReplacesOldStateServersDoc(c *gc.C) {
 
 func (s *StateSuite) TestEnsureAvailabilityFailsWithBadCount(c *gc.C) {
 	for _, n := range []int{-1, 2, 6} {
-		err := s.State.EnsureAvailability(n, constraints.Value{}, "")
+		err := s.State.EnsureAvailability(n, constraints.Value{}, "", nil)
 		c.Assert(err, gc.ErrorMatches, "number of state servers must be odd and non-negative")
 	}
-	err := s.State.EnsureAvailability(replicaset.MaxPeers+2, constraints.Value{}, "")
+	err := s.State.EnsureAvailability(replicaset.MaxPeers+2, constraints.Value{}, "", nil)
 	c.Assert(err, gc.ErrorMatches, `state server count is too large \(allowed \d+\)`)
 }
 
@@ -3066,7 +3082,7 @@ func (s *StateSuite) TestEnsureAvailabilityAddsNewMachines(c *gc.C) {
 	cons := constraints.Value{
 		Mem: newUint64(100),
 	}
-	err = s.State.EnsureAvailability(3, cons, "quantal")
+	err = s.State.EnsureAvailability(3, cons, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	for i := 1; i < 3; i++ {
@@ -3097,13 +3113,13 @@ func (s *StateSuite) assertStateServerInfo(c *gc.C, machineIds []string, votingM
 }
 
 func (s *StateSuite) TestEnsureAvailabilityDemotesUnavailableMachines(c *gc.C) {
-	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"})
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "0", nil
 	})
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	// New state server machine "3" is created; "0" still exists in MachineIds,
@@ -3120,13 +3136,13 @@ func (s *StateSuite) TestEnsureAvailabilityDemotesUnavailableMachines(c *gc.C) {
 }
 
 func (s *StateSuite) TestEnsureAvailabilityPromotesAvailableMachines(c *gc.C) {
-	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"})
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "0", nil
 	})
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	// New state server machine "3" is created; "0" still exists in MachineIds,
@@ -3143,7 +3159,7 @@ func (s *StateSuite) TestEnsureAvailabilityPromotesAvailableMachines(c *gc.C) {
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return true, nil
 	})
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	// No change; we've got as many voting machines as we need.
 	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"1", "2", "3"})
@@ -3153,7 +3169,7 @@ func (s *StateSuite) TestEnsureAvailabilityPromotesAvailableMachines(c *gc.C) {
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "3", nil
 	})
-	err = s.State.EnsureAvailability(5, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(5, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2", "3", "4", "5"}, []string{"0", "1", "2", "4", "5"})
 	err = m0.Refresh()
@@ -3162,18 +3178,18 @@ func (s *StateSuite) TestEnsureAvailabilityPromotesAvailableMachines(c *gc.C) {
 }
 
 func (s *StateSuite) TestEnsureAvailabilityRemovesUnavailableMachines(c *gc.C) {
-	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"})
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "0", nil
 	})
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"1", "2", "3"})
 	// machine 0 does not have a vote, so another call to EnsureAvailability
 	// will remove machine 0's JobEnvironManager job.
-	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"1", "2", "3"}, []string{"1", "2", "3"})
 	m0, err := s.State.Machine("0")
@@ -3182,7 +3198,7 @@ func (s *StateSuite) TestEnsureAvailabilityRemovesUnavailableMachines(c *gc.C) {
 }
 
 func (s *StateSuite) TestEnsureAvailabilityMaintainsVoteList(c *gc.C) {
-	err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c,
 		[]string{"0", "1", "2", "3", "4"},
@@ -3191,7 +3207,7 @@ func (s *StateSuite) TestEnsureAvailabilityMaintainsVoteList(c *gc.C) {
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "0", nil
 	})
-	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	// New state server machine "5" is created; "0" still exists in MachineIds,
@@ -3210,14 +3226,14 @@ func (s *StateSuite) TestEnsureAvailabilityMaintainsVoteList(c *gc.C) {
 }
 
 func (s *StateSuite) TestEnsureAvailabilityDefaultsTo3(c *gc.C) {
-	err := s.State.EnsureAvailability(0, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(0, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"})
 	// Mark machine-0 as dead, so we'll want to create it again
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return m.Id() != "0", nil
 	})
-	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal")
+	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 
 	// New state server machine "3" is created; "0" still exists in MachineIds,
@@ -3235,13 +3251,140 @@ func (s *StateSuite) TestEnsureAvailabilityDefaultsTo3(c *gc.C) {
 	c.Assert(m3.IsManager(), jc.IsTrue)
 }
 
+func (s *StateSuite) TestEnsureAvailabilityPlacement(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	err := s.State.EnsureAvailability(1, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	m1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.EnsureAvailability(3, constraints.Value{}, "quantal", []string{"1", "zone=a", "zone=b"})
+	c.Assert(err, gc.IsNil)
+	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"})
+	err = m1.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(m1.Jobs(), jc.SameContents, []state.MachineJob{state.JobHostUnits, state.JobManageEnviron})
+	c.Assert(m1.WantsVote(), jc.IsTrue)
+	// Only the directives needed are used.
+	m2, err := s.State.Machine("2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m2.Placement(), gc.Equals, "zone=a")
+}
+
+func (s *StateSuite) TestEnsureAvailabilityPlacementErrors(c *gc.C) {
+	err := s.State.EnsureAvailability(1, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideMachine(template, "1", instance.LXC)
+	c.Assert(err, gc.IsNil)
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+
+	for i, test := range []struct {
+		placement []string
+		err       string
+	}{{
+		placement: []string{"0"},
+		err:       "machine 0 is already a state server",
+	}, {
+		placement: []string{"1/lxc/0"},
+		err:       "container 1/lxc/0 cannot be a state server",
+	}, {
+		placement: []string{"42"},
+		err:       "machine 42 not found",
+	}} {
+		c.Logf("test %d: %v", i, test.placement)
+		err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", test.placement)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.assertStateServerInfo(c, []string{"0"}, []string{"0"})
+}
+
+func (s *StateSuite) TestDemoteStateServer(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	m1, err := s.State.Machine("1")
+	c.Assert(err, gc.IsNil)
+	err = m1.SetHasVote(true)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.DemoteStateServer("1")
+	c.Assert(err, gc.IsNil)
+	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "2"})
+	err = m1.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(m1.WantsVote(), jc.IsFalse)
+
+	// The demoted machine is replaced, rather than promoted again.
+	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertStateServerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"})
+
+	// Once its vote has been removed, its state server job is removed.
+	err = m1.SetHasVote(false)
+	c.Assert(err, gc.IsNil)
+	err = s.State.EnsureAvailability(0, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	s.assertStateServerInfo(c, []string{"0", "2", "3"}, []string{"0", "2", "3"})
+	err = m1.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(m1.IsManager(), jc.IsFalse)
+}
+
+func (s *StateSuite) TestDemoteStateServerConcurrent(c *gc.C) {
+	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
+		return true, nil
+	})
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.State.DemoteStateServer("2")
+		c.Assert(err, gc.IsNil)
+	}).Check()
+
+	// The voters changed underneath the demotion, which is abandoned
+	// rather than leaving a single voter.
+	err = s.State.DemoteStateServer("1")
+	c.Assert(err, gc.Equals, state.ErrExcessiveContention)
+	s.assertStateServerInfo(c, []string{"0", "1", "2"}, []string{"0", "1"})
+	m1, err := s.State.Machine("1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(m1.WantsVote(), jc.IsTrue)
+}
+
+func (s *StateSuite) TestDemoteStateServerErrors(c *gc.C) {
+	err := s.State.EnsureAvailability(1, constraints.Value{}, "quantal", nil)
+	c.Assert(err, gc.IsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, gc.IsNil)
+
+	err = s.State.DemoteStateServer("0")
+	c.Assert(err, gc.ErrorMatches, "cannot demote the only voting state server")
+	err = s.State.DemoteStateServer("1")
+	c.Assert(err, gc.ErrorMatches, "machine 1 is not a state server")
+	err = s.State.DemoteStateServer("42")
+	c.Assert(err, gc.ErrorMatches, "machine 42 not found")
+}
+
 func (s *StateSuite) TestEnsureAvailabilityConcurrentSame(c *gc.C) {
 	s.PatchValue(state.StateServerAvailable, func(m *state.Machine) (bool, error) {
 		return true, nil
 	})
 
 	defer state.SetBeforeHooks(c, s.State, func() {
-		err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+		err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 		c.Assert(err, gc.IsNil)
 		// The outer EnsureAvailability call will allocate IDs 0..2,
 		// and the inner one 3..5.
@@ -3249,7 +3392,7 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentSame(c *gc.C) {
 		s.assertStateServerInfo(c, expected, expected)
 	}).Check()
 
-	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	s.assertStateServerInfo(c, []string{"3", "4", "5"}, []string{"3", "4", "5"})
 
@@ -3264,7 +3407,7 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentLess(c *gc.C) {
 	})
 
 	defer state.SetBeforeHooks(c, s.State, func() {
-		err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+		err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 		c.Assert(err, gc.IsNil)
 		// The outer EnsureAvailability call will initially allocate IDs 0..4,
 		// and the inner one 5..7.
@@ -3276,7 +3419,7 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentLess(c *gc.C) {
 	// machines 0..4, and fail due to the concurrent change. It will then
 	// allocate machines 8..9 to make up the difference from the concurrent
 	// EnsureAvailability call.
-	err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.IsNil)
 	expected := []string{"5", "6", "7", "8", "9"}
 	s.assertStateServerInfo(c, expected, expected)
@@ -3292,7 +3435,7 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentMore(c *gc.C) {
 	})
 
 	defer state.SetBeforeHooks(c, s.State, func() {
-		err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal")
+		err := s.State.EnsureAvailability(5, constraints.Value{}, "quantal", nil)
 		c.Assert(err, gc.IsNil)
 		// The outer EnsureAvailability call will allocate IDs 0..2,
 		// and the inner one 3..7.
@@ -3304,7 +3447,7 @@ func (s *StateSuite) TestEnsureAvailabilityConcurrentMore(c *gc.C) {
 	// machines 0..2, and fail due to the concurrent change. It will then
 	// find that the number of voting machines in state is greater than
 	// what we're attempting to ensure, and fail.
-	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal")
+	err := s.State.EnsureAvailability(3, constraints.Value{}, "quantal", nil)
 	c.Assert(err, gc.ErrorMatches, "cannot reduce state server count")
 
 	// Machine 0 should never have been created.
@@ -3458,16 +3601,16 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 
 	// Add 3 actions to first unit, and 2 to the second unit
-	_, err = unit1.AddAction("action1.1", nil)
+	_, err = unit1.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit1.AddAction("action1.2", nil)
+	_, err = unit1.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit1.AddAction("action1.3", nil)
+	_, err = unit1.AddAction("snapshot", nil)
 	c.Assert(err, gc.IsNil)
 
-	_, err = unit2.AddAction("action2.1", nil)
+	_, err = unit2.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
-	_, err = unit2.AddAction("action2.2", nil)
+	_, err = unit2.AddAction("backup", nil)
 	c.Assert(err, gc.IsNil)
 
 	// Verify that calling UnitActions with unit1.Name() returns only
@@ -3476,7 +3619,7 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(len(actions1), gc.Equals, 3)
 	for _, action := range actions1 {
-		c.Assert(action.Name(), gc.Matches, "^action1\\..")
+		c.Assert(action.Name(), gc.Equals, "snapshot")
 	}
 
 	// Verify that calling UnitActions with unit2.Name() returns only
@@ -3485,6 +3628,6 @@ func (s *StateSuite) TestUnitActionsFindsRightActions(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(len(actions2), gc.Equals, 2)
 	for _, action := range actions2 {
-		c.Assert(action.Name(), gc.Matches, "^action2\\..")
+		c.Assert(action.Name(), gc.Equals, "backup")
 	}
 }
@@ -6,6 +6,7 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -156,6 +157,22 @@ func unitGlobalKey(name string) string {
 	return "u#" + name
 }
 
+// unitWorkloadGlobalKey returns the global database key for the
+// workload status of the named unit.
+func unitWorkloadGlobalKey(name string) string {
+	return unitGlobalKey(name) + workloadSuffix
+}
+
+// workloadSuffix distinguishes the global key of a unit's workload
+// status from that of the unit itself.
+const workloadSuffix = "#workload"
+
+// unitNameFromGlobalKey returns the name of the unit with the given
+// global database key.
+func unitNameFromGlobalKey(key string) string {
+	return strings.TrimPrefix(key, "u#")
+}
+
 // globalKey returns the global database key for the unit.
 func (u *Unit) globalKey() string {
 	return unitGlobalKey(u.doc.Name)
@@ -626,6 +643,65 @@ func (u *Unit) SetStatus(status params.Status, info string, data params.StatusDa
 	return nil
 }
 
+// WorkloadStatus returns the status of the unit's workload, as set by
+// its charm, and a message describing it.
+func (u *Unit) WorkloadStatus() (status params.Status, info string, err error) {
+	doc, err := getStatus(u.st, unitWorkloadGlobalKey(u.doc.Name))
+	if errors.IsNotFound(err) {
+		// The unit was added before workload statuses were recorded.
+		return params.StatusUnknown, "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	return doc.Status, doc.StatusInfo, nil
+}
+
+// SetWorkloadStatus sets the status of the unit's workload, along with
+// a message describing it. Unlike the unit's agent status, which tracks
+// the lifecycle of the unit agent, the workload status is set by the
+// unit's charm.
+func (u *Unit) SetWorkloadStatus(status params.Status, info string) error {
+	if !status.ValidWorkload() {
+		return fmt.Errorf("cannot set invalid workload status %q", status)
+	}
+	if status == params.StatusBlocked && info == "" {
+		return fmt.Errorf("cannot set workload status %q without info", status)
+	}
+	key := unitWorkloadGlobalKey(u.doc.Name)
+	doc := statusDoc{
+		Status:     status,
+		StatusInfo: info,
+	}
+	for i := 0; i < 3; i++ {
+		statusOp := updateStatusOp(u.st, key, doc)
+		if _, err := getStatus(u.st, key); errors.IsNotFound(err) {
+			statusOp = createStatusOp(u.st, key, doc)
+		} else if err != nil {
+			return err
+		}
+		ops := []txn.Op{{
+			C:      u.st.units.Name,
+			Id:     u.doc.Name,
+			Assert: notDeadDoc,
+		},
+			statusOp,
+		}
+		err := u.st.runTransaction(ops)
+		if err != txn.ErrAborted {
+			if err != nil {
+				return fmt.Errorf("cannot set workload status of unit %q: %v", u, err)
+			}
+			return nil
+		}
+		if notDead, err := isNotDead(u.st.units, u.doc.Name); err != nil {
+			return err
+		} else if !notDead {
+			return fmt.Errorf("cannot set workload status of unit %q: %v", u, errDead)
+		}
+	}
+	return fmt.Errorf("cannot set workload status of unit %q: %v", u, ErrExcessiveContention)
+}
+
 // OpenPort sets the policy of the port with protocol and number to be opened.
 func (u *Unit) OpenPort(protocol string, number int) (err error) {
 	port := network.Port{Protocol: protocol, Number: number}
@@ -1373,24 +1449,34 @@ func (u *Unit) UnassignFromMachine() (err error) {
 }
 
 // AddAction adds a new Action of type name and using arguments payload to
-// this Unit, and returns its ID
+// this Unit, and returns its ID. The named action must be declared by the
+// unit's charm; the payload is validated against the parameter schema of
+// the action, and missing parameters take their declared defaults.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (string, error) {
-	actionId, err := newActionId(u.st, u.globalKey())
-	if err != nil {
-		return "", fmt.Errorf("cannot add action; error generating key: %v", err)
+	return u.addAction(name, payload, 0, "")
+}
+
+// AddActionWithTimeout is like AddAction, but the unit stops the action
+// if it runs for longer than timeout. A zero timeout selects the timeout
+// declared for the action by the unit's charm, if any.
+func (u *Unit) AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (string, error) {
+	if timeout < 0 {
+		return "", fmt.Errorf("invalid action timeout %v", timeout)
 	}
-	doc := actionDoc{Id: actionId, Name: name, Payload: payload}
-	ops := []txn.Op{{
-		C:      u.st.units.Name,
-		Id:     u.doc.Name,
-		Assert: notDeadDoc,
-	}, {
-		C:      u.st.actions.Name,
-		Id:     doc.Id,
-		Assert: txn.DocMissing,
-		Insert: doc,
-	}}
+	return u.addAction(name, payload, timeout, "")
+}
 
+// addAction adds a new Action to the unit, recording the id of the
+// ServiceAction that queued it, if any.
+func (u *Unit) addAction(name string, payload map[string]interface{}, timeout time.Duration, serviceActionId string) (string, error) {
+	ch, err := u.actionCharm()
+	if err != nil {
+		return "", err
+	}
+	actionId, ops, err := u.addActionOps(ch, name, payload, timeout, serviceActionId)
+	if err != nil {
+		return "", err
+	}
 	for i := 0; i < 3; i++ {
 		if notDead, err := isNotDead(u.st.units, u.doc.Name); err != nil {
 			return "", err
@@ -1410,6 +1496,87 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (string, e
 	return "", ErrExcessiveContention
 }
 
+// addActionOps validates the named action against the given charm, and
+// returns the id of a new Action for the unit along with the operations
+// that queue it, asserting that the unit is not dead.
+func (u *Unit) addActionOps(ch *Charm, name string, payload map[string]interface{}, timeout time.Duration, serviceActionId string) (string, []txn.Op, error) {
+	payload, err := validateActionPayload(ch, name, payload)
+	if err != nil {
+		return "", nil, err
+	}
+	if timeout == 0 {
+		timeout = actionTimeout(ch, name)
+	}
+	actionId, op, err := newActionOp(u.st, u.globalKey(), name, payload, timeout, serviceActionId)
+	if err != nil {
+		return "", nil, err
+	}
+	return actionId, []txn.Op{{
+		C:      u.st.units.Name,
+		Id:     u.doc.Name,
+		Assert: notDeadDoc,
+	}, op}, nil
+}
+
+// validateActionPayload checks that the charm declares the named
+// action, and that payload matches the schema declared for it, and
+// returns payload with any defaults filled in.
+func validateActionPayload(ch *Charm, name string, payload map[string]interface{}) (map[string]interface{}, error) {
+	var spec charm.ActionSpec
+	ok := false
+	if actions := ch.Actions(); actions != nil {
+		spec, ok = actions.ActionSpecs[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("action %q not defined by charm %q", name, ch.URL())
+	}
+	return spec.ValidateParams(payload)
+}
+
+// actionTimeout returns the timeout the charm declares for the named
+// action, or zero if it declares none.
+func actionTimeout(ch *Charm, name string) time.Duration {
+	if actions := ch.Actions(); actions != nil {
+		return actions.ActionSpecs[name].Timeout
+	}
+	return 0
+}
+
+// actionCharm returns the charm that will run actions queued for the
+// unit: the unit's own charm once it has one, or its service's charm
+// otherwise.
+func (u *Unit) actionCharm() (*Charm, error) {
+	if curl, ok := u.CharmURL(); ok {
+		return u.st.Charm(curl)
+	}
+	service, err := u.Service()
+	if err != nil {
+		return nil, err
+	}
+	ch, _, err := service.Charm()
+	return ch, err
+}
+
+// Actions returns a list of actions pending for this unit.
+func (u *Unit) Actions() ([]*Action, error) {
+	return u.st.UnitActions(u.doc.Name)
+}
+
+// ActionResults returns the results of the actions that have finished
+// on this unit.
+func (u *Unit) ActionResults() ([]*ActionResult, error) {
+	return u.st.ActionResultsForUnit(u.doc.Name)
+}
+
+// Action returns the pending action with the given id, which must have
+// been queued for this unit.
+func (u *Unit) Action(id string) (*Action, error) {
+	if getActionIdPrefix(id) != u.globalKey() {
+		return nil, errors.NotFoundf("action %q for unit %q", id, u)
+	}
+	return u.st.Action(id)
+}
+
 // Resolve marks the unit as having had any previous state transition
 // problems resolved, and informs the unit that it may attempt to
 // reestablish normal workflow. The retryHooks parameter informs
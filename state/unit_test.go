@@ -352,6 +352,46 @@ func (s *UnitSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "status not found")
 }
 
+func (s *UnitSuite) TestGetSetWorkloadStatus(c *gc.C) {
+	status, info, err := s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.unit.SetWorkloadStatus(params.StatusUnknown, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "unknown"`)
+	err = s.unit.SetWorkloadStatus(params.StatusStarted, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "started"`)
+	err = s.unit.SetWorkloadStatus(params.StatusBlocked, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status "blocked" without info`)
+
+	err = s.unit.SetWorkloadStatus(params.StatusWaiting, "waiting for database")
+	c.Assert(err, gc.IsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusWaiting)
+	c.Assert(info, gc.Equals, "waiting for database")
+
+	// The agent status is unaffected.
+	agentStatus, _, _, err := s.unit.Status()
+	c.Assert(err, gc.IsNil)
+	c.Assert(agentStatus, gc.Equals, params.StatusPending)
+
+	err = s.unit.SetWorkloadStatus(params.StatusActive, "")
+	c.Assert(err, gc.IsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, gc.IsNil)
+	c.Assert(status, gc.Equals, params.StatusActive)
+	c.Assert(info, gc.Equals, "")
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusWhenDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, gc.IsNil)
+	err = s.unit.SetWorkloadStatus(params.StatusActive, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestGetSetStatusDataStandard(c *gc.C) {
 	err := s.unit.SetStatus(params.StatusStarted, "", nil)
 	c.Assert(err, gc.IsNil)
@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -20,8 +21,9 @@ func (st *State) checkUserExists(name string) (bool, error) {
 	return count > 0, nil
 }
 
-// AddUser adds a user to the state.
-func (st *State) AddUser(username, displayName, password string) (*User, error) {
+// AddUser adds a user to the state. The creator is the name
+// of the user adding the new one, if any.
+func (st *State) AddUser(username, displayName, password, creator string) (*User, error) {
 	if !names.IsUser(username) {
 		return nil, errors.Errorf("invalid user name %q", username)
 	}
@@ -36,6 +38,8 @@ func (st *State) AddUser(username, displayName, password string) (*User, error)
 			DisplayName:  displayName,
 			PasswordHash: utils.UserPasswordHash(password, salt),
 			PasswordSalt: salt,
+			CreatedBy:    creator,
+			DateCreated:  time.Now(),
 		},
 	}
 	ops := []txn.Op{{
@@ -73,6 +77,20 @@ func (st *State) User(name string) (*User, error) {
 	return u, nil
 }
 
+// AllUsers returns all the users in the state, ordered by name,
+// including the deactivated ones.
+func (st *State) AllUsers() ([]*User, error) {
+	var docs []userDoc
+	if err := st.users.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all users")
+	}
+	users := make([]*User, len(docs))
+	for i, doc := range docs {
+		users[i] = &User{st: st, doc: doc}
+	}
+	return users, nil
+}
+
 // User represents a juju client user.
 type User struct {
 	st  *State
@@ -85,6 +103,12 @@ type userDoc struct {
 	Deactivated  bool // Removing users means they still exist, but are marked deactivated
 	PasswordHash string
 	PasswordSalt string
+	// Roles holds the role of the user in each environment,
+	// keyed by environment UUID.
+	Roles          map[string]Role `bson:",omitempty"`
+	CreatedBy      string
+	DateCreated    time.Time
+	LastConnection time.Time
 }
 
 // Name returns the user name,
@@ -97,6 +121,43 @@ func (u *User) DisplayName() string {
 	return u.doc.DisplayName
 }
 
+// CreatedBy returns the name of the user that added this one. It is
+// empty for users that were not added by another user.
+func (u *User) CreatedBy() string {
+	return u.doc.CreatedBy
+}
+
+// DateCreated returns when the user was added. It is the zero
+// time for users added before it was recorded.
+func (u *User) DateCreated() time.Time {
+	return u.doc.DateCreated
+}
+
+// LastConnection returns when the user last logged in to the API.
+// It is the zero time if the user has never logged in.
+func (u *User) LastConnection() time.Time {
+	return u.doc.LastConnection
+}
+
+// UpdateLastConnection records that the user has just logged in.
+func (u *User) UpdateLastConnection() error {
+	now := time.Now()
+	ops := []txn.Op{{
+		C:      u.st.users.Name,
+		Id:     u.Name(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"lastconnection", now}}}},
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("user no longer exists")
+		}
+		return errors.Annotatef(err, "cannot update last connection of user %q", u.Name())
+	}
+	u.doc.LastConnection = now
+	return nil
+}
+
 // Tag returns the Tag for
 // the user ("user-$username")
 func (u *User) Tag() string {
@@ -192,6 +253,133 @@ func (u *User) Deactivate() error {
 	return nil
 }
 
+// Activate reverses Deactivate, allowing the user to log in again.
+func (u *User) Activate() error {
+	ops := []txn.Op{{
+		C:      u.st.users.Name,
+		Id:     u.Name(),
+		Update: bson.D{{"$set", bson.D{{"deactivated", false}}}},
+		Assert: txn.DocExists,
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = fmt.Errorf("user no longer exists")
+		}
+		return fmt.Errorf("cannot activate user %q: %v", u.Name(), err)
+	}
+	u.doc.Deactivated = false
+	return nil
+}
+
 func (u *User) IsDeactivated() bool {
 	return u.doc.Deactivated
 }
+
+// Role describes what a user may do in an environment. Each role
+// includes the permissions of the roles below it.
+type Role string
+
+const (
+	// RoleNone gives no access to the environment.
+	RoleNone Role = ""
+
+	// RoleRead allows a user to inspect the environment
+	// without changing it.
+	RoleRead Role = "read"
+
+	// RoleWrite allows a user to change the services and
+	// machines in the environment.
+	RoleWrite Role = "write"
+
+	// RoleAdmin allows a user to change the environment itself,
+	// to destroy it, and to manage its users.
+	RoleAdmin Role = "admin"
+)
+
+// roleOrder holds the roles from least to most privileged.
+var roleOrder = []Role{RoleNone, RoleRead, RoleWrite, RoleAdmin}
+
+func (r Role) level() int {
+	for i, role := range roleOrder {
+		if role == r {
+			return i
+		}
+	}
+	return 0
+}
+
+// Includes returns whether the role grants all the
+// permissions of the other role.
+func (r Role) Includes(other Role) bool {
+	return r.level() >= other.level()
+}
+
+// ParseRole returns the role with the given name.
+func ParseRole(name string) (Role, error) {
+	for _, role := range roleOrder {
+		if role != RoleNone && string(role) == name {
+			return role, nil
+		}
+	}
+	return RoleNone, errors.Errorf("invalid role %q", name)
+}
+
+// Role returns the role of the user in the environment with the
+// given UUID. The admin user is an administrator of every environment.
+func (u *User) Role(envUUID string) Role {
+	if u.doc.Name == AdminUser {
+		return RoleAdmin
+	}
+	return u.doc.Roles[envUUID]
+}
+
+// GrantRole gives the user at least the given role in the
+// environment with the given UUID.
+func (u *User) GrantRole(envUUID string, role Role) error {
+	if u.Role(envUUID).Includes(role) {
+		return nil
+	}
+	return u.setRole(envUUID, role)
+}
+
+// RevokeRole takes the given role away from the user in the
+// environment with the given UUID, leaving the user with the
+// role below it. Revoking the read role removes all access.
+func (u *User) RevokeRole(envUUID string, role Role) error {
+	if role == RoleNone || !u.Role(envUUID).Includes(role) {
+		return nil
+	}
+	return u.setRole(envUUID, roleOrder[role.level()-1])
+}
+
+func (u *User) setRole(envUUID string, role Role) error {
+	if u.doc.Name == AdminUser {
+		return errors.Unauthorizedf("cannot change the role of the admin user")
+	}
+	key := "roles." + envUUID
+	update := bson.D{{"$set", bson.D{{key, role}}}}
+	if role == RoleNone {
+		update = bson.D{{"$unset", bson.D{{key, nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      u.st.users.Name,
+		Id:     u.Name(),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.New("user no longer exists")
+		}
+		return errors.Annotatef(err, "cannot set role of user %q", u.Name())
+	}
+	if u.doc.Roles == nil {
+		u.doc.Roles = make(map[string]Role)
+	}
+	if role == RoleNone {
+		delete(u.doc.Roles, envUUID)
+	} else {
+		u.doc.Roles[envUUID] = role
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"regexp"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -24,14 +25,14 @@ func (s *UserSuite) TestAddUserInvalidNames(c *gc.C) {
 		"",
 		"b^b",
 	} {
-		u, err := s.State.AddUser(name, "ignored", "ignored")
+		u, err := s.State.AddUser(name, "ignored", "ignored", "admin")
 		c.Assert(err, gc.ErrorMatches, `invalid user name "`+regexp.QuoteMeta(name)+`"`)
 		c.Assert(u, gc.IsNil)
 	}
 }
 
 func (s *UserSuite) addUser(c *gc.C, name, displayName, password string) *state.User {
-	user, err := s.State.AddUser(name, displayName, password)
+	user, err := s.State.AddUser(name, displayName, password, "admin")
 	c.Assert(err, gc.IsNil)
 	c.Assert(user, gc.NotNil)
 	c.Assert(user.Name(), gc.Equals, name)
@@ -59,6 +60,48 @@ func (s *UserSuite) TestAddUser(c *gc.C) {
 	c.Assert(user.PasswordValid(password), jc.IsTrue)
 }
 
+func (s *UserSuite) TestAddUserRecordsCreation(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	user := s.makeUser(c)
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+	c.Assert(user.DateCreated().After(before), jc.IsTrue)
+	c.Assert(user.LastConnection().IsZero(), jc.IsTrue)
+
+	err := user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+	c.Assert(user.DateCreated().After(before), jc.IsTrue)
+}
+
+func (s *UserSuite) TestUpdateLastConnection(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	user := s.makeUser(c)
+	err := user.UpdateLastConnection()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.LastConnection().After(before), jc.IsTrue)
+
+	err = user.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(user.LastConnection().After(before), jc.IsTrue)
+}
+
+func (s *UserSuite) TestAllUsers(c *gc.C) {
+	s.addUser(c, "zoe", "", "password")
+	bob := s.addUser(c, "bob", "Bob", "password")
+	err := bob.Deactivate()
+	c.Assert(err, gc.IsNil)
+
+	users, err := s.State.AllUsers()
+	c.Assert(err, gc.IsNil)
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Name())
+	}
+	c.Assert(names, gc.DeepEquals, []string{"admin", "bob", "zoe"})
+	c.Assert(users[1].DisplayName(), gc.Equals, "Bob")
+	c.Assert(users[1].IsDeactivated(), jc.IsTrue)
+}
+
 func (s *UserSuite) TestCheckUserExists(c *gc.C) {
 	user := s.makeUser(c)
 	exists, err := state.CheckUserExists(s.State, user.Name())
@@ -167,9 +210,87 @@ func (s *UserSuite) TestDeactivate(c *gc.C) {
 
 }
 
+func (s *UserSuite) TestActivate(c *gc.C) {
+	u := s.addUser(c, "someuser", "", "a-password")
+	err := u.Deactivate()
+	c.Assert(err, gc.IsNil)
+
+	err = u.Activate()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.IsDeactivated(), jc.IsFalse)
+	c.Assert(u.PasswordValid("a-password"), jc.IsTrue)
+
+	err = u.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.IsDeactivated(), jc.IsFalse)
+}
+
 func (s *UserSuite) TestCantDeactivateAdminUser(c *gc.C) {
 	u, err := s.State.User(state.AdminUser)
 	c.Assert(err, gc.IsNil)
 	err = u.Deactivate()
 	c.Assert(err, gc.ErrorMatches, "Can't deactivate admin user")
 }
+
+func (s *UserSuite) TestParseRole(c *gc.C) {
+	for _, role := range []state.Role{state.RoleRead, state.RoleWrite, state.RoleAdmin} {
+		parsed, err := state.ParseRole(string(role))
+		c.Assert(err, gc.IsNil)
+		c.Assert(parsed, gc.Equals, role)
+	}
+	for _, name := range []string{"", "root"} {
+		_, err := state.ParseRole(name)
+		c.Assert(err, gc.ErrorMatches, `invalid role "`+name+`"`)
+	}
+}
+
+func (s *UserSuite) TestRoleIncludes(c *gc.C) {
+	c.Assert(state.RoleAdmin.Includes(state.RoleWrite), jc.IsTrue)
+	c.Assert(state.RoleWrite.Includes(state.RoleWrite), jc.IsTrue)
+	c.Assert(state.RoleWrite.Includes(state.RoleRead), jc.IsTrue)
+	c.Assert(state.RoleRead.Includes(state.RoleWrite), jc.IsFalse)
+	c.Assert(state.RoleNone.Includes(state.RoleRead), jc.IsFalse)
+	c.Assert(state.RoleNone.Includes(state.RoleNone), jc.IsTrue)
+}
+
+func (s *UserSuite) TestGrantAndRevokeRole(c *gc.C) {
+	u := s.makeUser(c)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleNone)
+
+	err := u.GrantRole("env-uuid", state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleWrite)
+	c.Assert(u.Role("other-uuid"), gc.Equals, state.RoleNone)
+
+	// Granting a lesser role keeps the current one.
+	err = u.GrantRole("env-uuid", state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleWrite)
+
+	err = u.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleWrite)
+
+	// Revoking a role leaves the one below it.
+	err = u.RevokeRole("env-uuid", state.RoleAdmin)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleWrite)
+	err = u.RevokeRole("env-uuid", state.RoleWrite)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleRead)
+	err = u.RevokeRole("env-uuid", state.RoleRead)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleNone)
+
+	err = u.Refresh()
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleNone)
+}
+
+func (s *UserSuite) TestAdminUserRole(c *gc.C) {
+	u, err := s.State.User(state.AdminUser)
+	c.Assert(err, gc.IsNil)
+	c.Assert(u.Role("env-uuid"), gc.Equals, state.RoleAdmin)
+	err = u.RevokeRole("env-uuid", state.RoleAdmin)
+	c.Assert(err, gc.ErrorMatches, "cannot change the role of the admin user")
+}
@@ -1452,3 +1452,116 @@ func (w *cleanupWatcher) loop() (err error) {
 		}
 	}
 }
+
+// actionWatcher notifies of actions being added to the queue of a
+// single unit. Updates to queued actions, and their removal from the
+// queue, are not reported.
+type actionWatcher struct {
+	commonWatcher
+	prefix string
+	known  *set.Strings
+	out    chan []string
+}
+
+var _ StringsWatcher = (*actionWatcher)(nil)
+
+// WatchActions starts and returns a StringsWatcher that notifies on
+// actions being queued for the unit. The first event emitted contains
+// the ids of all actions currently pending for the unit.
+func (u *Unit) WatchActions() StringsWatcher {
+	return newActionWatcher(u.st, actionPrefix(u.globalKey()))
+}
+
+// Watch returns a watcher for observing changes to the action, such as
+// a request to cancel it or its removal from the queue.
+func (a *Action) Watch() NotifyWatcher {
+	return newEntityWatcher(a.st, a.st.actions, a.doc.Id)
+}
+
+func newActionWatcher(st *State, prefix string) StringsWatcher {
+	w := &actionWatcher{
+		commonWatcher: commonWatcher{st: st},
+		prefix:        prefix,
+		known:         new(set.Strings),
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for w.
+func (w *actionWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+func (w *actionWatcher) filter(key interface{}) bool {
+	if id, ok := key.(string); ok {
+		return strings.HasPrefix(id, w.prefix)
+	}
+	return false
+}
+
+func (w *actionWatcher) initial() (*set.Strings, error) {
+	ids := new(set.Strings)
+	var doc actionDoc
+	sel := bson.D{{"_id", bson.D{{"$regex", "^" + regexp.QuoteMeta(w.prefix)}}}}
+	iter := w.st.actions.Find(sel).Select(bson.D{{"_id", 1}}).Iter()
+	for iter.Next(&doc) {
+		ids.Add(doc.Id)
+		w.known.Add(doc.Id)
+	}
+	return ids, iter.Err()
+}
+
+// merge adds to ids the actions that have been inserted since they
+// were last seen, ignoring updates to actions already known.
+func (w *actionWatcher) merge(ids *set.Strings, updates map[interface{}]bool) {
+	for key, exists := range updates {
+		id := key.(string)
+		if exists {
+			if !w.known.Contains(id) {
+				w.known.Add(id)
+				ids.Add(id)
+			}
+		} else {
+			w.known.Remove(id)
+			ids.Remove(id)
+		}
+	}
+}
+
+func (w *actionWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(w.st.actions.Name, in, w.filter)
+	defer w.st.watcher.UnwatchCollection(w.st.actions.Name, in)
+
+	ids, err := w.initial()
+	if err != nil {
+		return err
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			w.merge(ids, updates)
+			if !ids.IsEmpty() {
+				out = w.out
+			}
+		case out <- sortedActionIds(ids.Values()):
+			out = nil
+			ids = new(set.Strings)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker that takes backups of the
+// state server on the schedule set in the environment configuration.
+package backupscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// ScheduledNotes are the notes recorded with every scheduled backup.
+const ScheduledNotes = "scheduled backup"
+
+// newBackups returns the backups implementation used by the worker. It
+// is a variable so that tests can change it.
+var newBackups = backups.NewBackups
+
+var _ worker.Worker = (*BackupScheduler)(nil)
+
+// BackupScheduler takes backups of the state server at the interval
+// set by backup-schedule in the environment configuration, and removes
+// the scheduled backups falling outside the retention policy set by
+// backup-retention-count and backup-retention-age. The outcome of
+// every scheduled backup is recorded in the state.
+type BackupScheduler struct {
+	st      *state.State
+	paths   backups.Paths
+	backups backups.Backups
+	tomb    tomb.Tomb
+}
+
+// NewBackupScheduler returns a worker that takes scheduled backups of
+// the state server, using the files found at the given paths.
+func NewBackupScheduler(st *state.State, paths backups.Paths) *BackupScheduler {
+	bs := &BackupScheduler{
+		st:      st,
+		paths:   paths,
+		backups: newBackups(st),
+	}
+	go func() {
+		defer bs.tomb.Done()
+		bs.tomb.Kill(bs.loop())
+	}()
+	return bs
+}
+
+func (bs *BackupScheduler) String() string {
+	return "backup scheduler"
+}
+
+// Stop stops the worker.
+func (bs *BackupScheduler) Stop() error {
+	bs.tomb.Kill(nil)
+	return bs.tomb.Wait()
+}
+
+// Kill is defined on the worker.Worker interface.
+func (bs *BackupScheduler) Kill() {
+	bs.tomb.Kill(nil)
+}
+
+// Wait is defined on the worker.Worker interface.
+func (bs *BackupScheduler) Wait() error {
+	return bs.tomb.Wait()
+}
+
+func (bs *BackupScheduler) loop() error {
+	configWatcher := bs.st.WatchEnvironConfig()
+	defer watcher.Stop(configWatcher, &bs.tomb)
+	var cfg *config.Config
+	var due <-chan time.Time
+	for {
+		select {
+		case <-bs.tomb.Dying():
+			return tomb.ErrDying
+		case newConfig, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.MustErr(configWatcher)
+			}
+			cfg = newConfig
+		case <-due:
+			if err := bs.backup(cfg); err != nil {
+				return err
+			}
+		}
+		due = nil
+		if schedule := cfg.BackupSchedule(); schedule > 0 {
+			delay, err := bs.nextBackupDelay(schedule)
+			if err != nil {
+				return err
+			}
+			due = time.After(delay)
+		}
+	}
+}
+
+// nextBackupDelay returns how long to wait before the next scheduled
+// backup, given the interval between them. The first backup is taken
+// as soon as backups are scheduled.
+func (bs *BackupScheduler) nextBackupDelay(schedule time.Duration) (time.Duration, error) {
+	status, err := bs.st.ScheduledBackupsStatus()
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	last := status.LastSuccess
+	if status.LastFailure.After(last) {
+		last = status.LastFailure
+	}
+	if delay := last.Add(schedule).Sub(time.Now()); delay > 0 {
+		return delay, nil
+	}
+	return 0, nil
+}
+
+// backup takes a scheduled backup, records its outcome, and prunes the
+// scheduled backups according to the retention policy in cfg. A failed
+// backup is not an error; only failing to record it is.
+func (bs *BackupScheduler) backup(cfg *config.Config) error {
+	attempted := time.Now()
+	meta, err := bs.backups.CreateScheduled(bs.paths, ScheduledNotes)
+	if err != nil {
+		logger.Errorf("scheduled backup failed: %v", err)
+		return errors.Trace(bs.st.SetScheduledBackupFailed(attempted, err))
+	}
+	logger.Infof("took scheduled backup %q", meta.Id)
+	if err := bs.st.SetScheduledBackupSucceeded(meta.Id, meta.Finished); err != nil {
+		return errors.Trace(err)
+	}
+	if err := bs.prune(cfg); err != nil {
+		logger.Errorf("cannot prune scheduled backups: %v", err)
+	}
+	return nil
+}
+
+// prune removes the scheduled backups older than the retention age,
+// and the oldest ones beyond the retention count. Only backups recorded
+// as scheduled are removed, so backups taken on request are kept until
+// they are removed by hand, whatever their notes.
+func (bs *BackupScheduler) prune(cfg *config.Config) error {
+	count, age := cfg.BackupRetentionCount(), cfg.BackupRetentionAge()
+	if count == 0 && age == 0 {
+		return nil
+	}
+	metas, err := bs.backups.List()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var scheduled []*state.BackupMetadata
+	for _, meta := range metas {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	// The backups are listed oldest first.
+	now := time.Now()
+	for i, meta := range scheduled {
+		expired := age > 0 && now.Sub(meta.Started) > age
+		excess := count > 0 && i < len(scheduled)-count
+		if !expired && !excess {
+			continue
+		}
+		if err := bs.backups.Remove(meta.Id); err != nil {
+			return errors.Annotatef(err, "cannot remove backup %q", meta.Id)
+		}
+		logger.Infof("removed scheduled backup %q", meta.Id)
+	}
+	return nil
+}
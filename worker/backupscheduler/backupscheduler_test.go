@@ -0,0 +1,218 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	jujuerrors "github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type backupSchedulerSuite struct {
+	jujutesting.JujuConnSuite
+	fake  *fakeBackups
+	paths backups.Paths
+}
+
+var _ = gc.Suite(&backupSchedulerSuite{})
+
+// fakeBackups holds the metadata of the backups it creates in memory.
+type fakeBackups struct {
+	backups.Backups
+	mu      sync.Mutex
+	metas   []*state.BackupMetadata
+	created int
+	err     error
+}
+
+func (f *fakeBackups) CreateScheduled(paths backups.Paths, notes string) (*state.BackupMetadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	if f.err != nil {
+		return nil, f.err
+	}
+	now := time.Now()
+	meta := &state.BackupMetadata{
+		Id:        fmt.Sprintf("backup-%d", len(f.metas)),
+		Notes:     notes,
+		Scheduled: true,
+		Started:   now,
+		Finished:  now,
+		Stored:    true,
+	}
+	f.metas = append(f.metas, meta)
+	return meta, nil
+}
+
+func (f *fakeBackups) List() ([]*state.BackupMetadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*state.BackupMetadata(nil), f.metas...), nil
+}
+
+func (f *fakeBackups) Remove(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, meta := range f.metas {
+		if meta.Id == id {
+			f.metas = append(f.metas[:i], f.metas[i+1:]...)
+			return nil
+		}
+	}
+	return jujuerrors.NotFoundf("backup %q", id)
+}
+
+func (f *fakeBackups) Get(id string) (*state.BackupMetadata, io.ReadCloser, error) {
+	panic("not expected")
+}
+
+func (f *fakeBackups) ids() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for _, meta := range f.metas {
+		ids = append(ids, meta.Id)
+	}
+	return ids
+}
+
+func (f *fakeBackups) createdCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.created
+}
+
+func (s *backupSchedulerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.fake = &fakeBackups{}
+	s.PatchValue(backupscheduler.NewBackups, func(*state.State) backups.Backups {
+		return s.fake
+	})
+	s.paths = backups.Paths{DataDir: "/var/lib/juju", LogDir: "/var/log/juju"}
+}
+
+func (s *backupSchedulerSuite) startScheduler(c *gc.C) {
+	bs := backupscheduler.NewBackupScheduler(s.State, s.paths)
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(bs.Stop(), gc.IsNil)
+	})
+}
+
+func (s *backupSchedulerSuite) setConfig(c *gc.C, attrs map[string]interface{}) {
+	err := s.State.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, gc.IsNil)
+}
+
+// waitForStatus waits until the scheduled backups status satisfies
+// the given check, and returns it.
+func (s *backupSchedulerSuite) waitForStatus(c *gc.C, check func(*state.ScheduledBackupsStatus) bool) *state.ScheduledBackupsStatus {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for scheduled backups status")
+		case <-time.After(coretesting.ShortWait):
+			status, err := s.State.ScheduledBackupsStatus()
+			if jujuerrors.IsNotFound(err) {
+				continue
+			}
+			c.Assert(err, gc.IsNil)
+			if check(status) {
+				return status
+			}
+		}
+	}
+}
+
+func (s *backupSchedulerSuite) TestNoSchedule(c *gc.C) {
+	s.startScheduler(c)
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(s.fake.createdCount(), gc.Equals, 0)
+	_, err := s.State.ScheduledBackupsStatus()
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotFound)
+}
+
+func (s *backupSchedulerSuite) TestScheduledBackup(c *gc.C) {
+	s.startScheduler(c)
+	s.setConfig(c, map[string]interface{}{"backup-schedule": "1h"})
+	status := s.waitForStatus(c, func(status *state.ScheduledBackupsStatus) bool {
+		return status.LastBackupId != ""
+	})
+	c.Assert(status.LastBackupId, gc.Equals, "backup-0")
+	c.Assert(status.LastError, gc.Equals, "")
+	metas, err := s.fake.List()
+	c.Assert(err, gc.IsNil)
+	c.Assert(metas, gc.HasLen, 1)
+	c.Assert(metas[0].Notes, gc.Equals, backupscheduler.ScheduledNotes)
+	c.Assert(metas[0].Scheduled, jc.IsTrue)
+
+	// The next backup is not due for an hour.
+	time.Sleep(coretesting.ShortWait)
+	c.Assert(s.fake.createdCount(), gc.Equals, 1)
+}
+
+func (s *backupSchedulerSuite) TestScheduledBackupFails(c *gc.C) {
+	s.fake.err = errors.New("cannot dump database")
+	s.startScheduler(c)
+	s.setConfig(c, map[string]interface{}{"backup-schedule": "1h"})
+	status := s.waitForStatus(c, func(status *state.ScheduledBackupsStatus) bool {
+		return status.LastError != ""
+	})
+	c.Assert(status.LastError, gc.Equals, "cannot dump database")
+	c.Assert(status.LastFailure.IsZero(), jc.IsFalse)
+	c.Assert(status.LastBackupId, gc.Equals, "")
+}
+
+func (s *backupSchedulerSuite) TestRetention(c *gc.C) {
+	old := time.Now().Add(-48 * time.Hour)
+	s.fake.metas = []*state.BackupMetadata{
+		{Id: "manual", Notes: "before upgrade", Started: old},
+		// Backups taken on request are kept even if their notes
+		// match those of scheduled backups.
+		{Id: "lookalike", Notes: backupscheduler.ScheduledNotes, Started: old},
+		{Id: "expired", Scheduled: true, Started: old},
+		{Id: "excess", Scheduled: true, Started: time.Now().Add(-2 * time.Hour)},
+		{Id: "kept", Scheduled: true, Started: time.Now().Add(-time.Hour)},
+	}
+	s.startScheduler(c)
+	s.setConfig(c, map[string]interface{}{
+		"backup-schedule":        "1h",
+		"backup-retention-count": 2,
+		"backup-retention-age":   "24h",
+	})
+	s.waitForStatus(c, func(status *state.ScheduledBackupsStatus) bool {
+		return status.LastBackupId != ""
+	})
+	timeout := time.After(coretesting.LongWait)
+	for {
+		ids := s.fake.ids()
+		if len(ids) == 4 {
+			c.Assert(ids, gc.DeepEquals, []string{"manual", "lookalike", "kept", "backup-5"})
+			return
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("timed out waiting for backups to be pruned; have %v", ids)
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+}
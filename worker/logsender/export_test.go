@@ -0,0 +1,14 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender
+
+import (
+	"github.com/juju/juju/state/api/params"
+)
+
+var SendInterval = &sendInterval
+
+func Take(b *LogBuffer) []params.LogRecord {
+	return b.take()
+}
@@ -0,0 +1,116 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package logsender provides a worker that sends the messages logged
+// by an agent to the API server, to be kept in the environment's log.
+package logsender
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/worker"
+)
+
+// LogBuffer is a loggo.Writer that holds the messages logged by the
+// agent until they are sent. It holds at most the number of messages
+// it was created with, dropping the oldest ones when full.
+type LogBuffer struct {
+	mu      sync.Mutex
+	size    int
+	records []params.LogRecord
+	dropped int
+}
+
+var _ loggo.Writer = (*LogBuffer)(nil)
+
+// NewLogBuffer returns a LogBuffer holding at most size messages.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{size: size}
+}
+
+// Write is defined on the loggo.Writer interface.
+func (b *LogBuffer) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.records) >= b.size {
+		b.records = b.records[1:]
+		b.dropped++
+	}
+	b.records = append(b.records, params.LogRecord{
+		Time:     timestamp,
+		Module:   module,
+		Location: fmt.Sprintf("%s:%d", filepath.Base(filename), line),
+		Level:    level,
+		Message:  message,
+	})
+}
+
+// take removes the messages held by the buffer and returns them. If
+// any messages were dropped since the last call, a warning saying so
+// is returned before them.
+func (b *LogBuffer) take() []params.LogRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records := b.records
+	b.records = nil
+	if b.dropped > 0 {
+		records = append([]params.LogRecord{{
+			Time:    time.Now(),
+			Module:  "juju.worker.logsender",
+			Level:   loggo.WARNING,
+			Message: fmt.Sprintf("%d log messages dropped", b.dropped),
+		}}, records...)
+		b.dropped = 0
+	}
+	return records
+}
+
+// restore returns messages that could not be sent to the buffer, ahead
+// of any logged since, keeping the buffer to its size.
+func (b *LogBuffer) restore(records []params.LogRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records = append(records, b.records...)
+	if excess := len(records) - b.size; excess > 0 {
+		records = records[excess:]
+		b.dropped += excess
+	}
+	b.records = records
+}
+
+// LogWriter is the API used by the worker to send messages.
+type LogWriter interface {
+	WriteLogs(records []params.LogRecord) error
+}
+
+// sendInterval is how often the worker sends the messages logged.
+var sendInterval = time.Second
+
+// New returns a worker that sends the messages held by the buffer
+// through the given API.
+func New(buffer *LogBuffer, api LogWriter) worker.Worker {
+	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+		for {
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(sendInterval):
+			}
+			records := buffer.take()
+			if len(records) == 0 {
+				continue
+			}
+			if err := api.WriteLogs(records); err != nil {
+				buffer.restore(records)
+				return errors.Annotate(err, "cannot send log messages")
+			}
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender_test
+
+import (
+	"errors"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/state/api/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logsender"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type logSenderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&logSenderSuite{})
+
+func (s *logSenderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(logsender.SendInterval, time.Millisecond)
+}
+
+var logTime = time.Date(2014, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func writeMessages(buffer *logsender.LogBuffer, messages ...string) {
+	for _, message := range messages {
+		buffer.Write(loggo.INFO, "juju.worker.uniter", "/src/juju/worker/uniter/uniter.go", 42, logTime, message)
+	}
+}
+
+func messages(records []params.LogRecord) []string {
+	var result []string
+	for _, record := range records {
+		result = append(result, record.Message)
+	}
+	return result
+}
+
+// fakeLogWriter sends the messages written to it on a channel.
+type fakeLogWriter struct {
+	written chan []params.LogRecord
+	err     error
+}
+
+func (w *fakeLogWriter) WriteLogs(records []params.LogRecord) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written <- records
+	return nil
+}
+
+func (s *logSenderSuite) TestLogBuffer(c *gc.C) {
+	buffer := logsender.NewLogBuffer(10)
+	writeMessages(buffer, "hello")
+	c.Assert(logsender.Take(buffer), jc.DeepEquals, []params.LogRecord{{
+		Time:     logTime,
+		Module:   "juju.worker.uniter",
+		Location: "uniter.go:42",
+		Level:    loggo.INFO,
+		Message:  "hello",
+	}})
+	c.Assert(logsender.Take(buffer), gc.HasLen, 0)
+}
+
+func (s *logSenderSuite) TestLogBufferDropsOldest(c *gc.C) {
+	buffer := logsender.NewLogBuffer(2)
+	writeMessages(buffer, "one", "two", "three")
+	records := logsender.Take(buffer)
+	c.Assert(messages(records), jc.DeepEquals, []string{"1 log messages dropped", "two", "three"})
+	c.Assert(records[0].Level, gc.Equals, loggo.WARNING)
+}
+
+func (s *logSenderSuite) TestSendsMessages(c *gc.C) {
+	buffer := logsender.NewLogBuffer(10)
+	api := &fakeLogWriter{written: make(chan []params.LogRecord, 10)}
+	w := logsender.New(buffer, api)
+	defer func() { c.Assert(worker.Stop(w), gc.IsNil) }()
+
+	writeMessages(buffer, "one", "two")
+	select {
+	case records := <-api.written:
+		c.Assert(messages(records), jc.DeepEquals, []string{"one", "two"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for messages to be sent")
+	}
+}
+
+func (s *logSenderSuite) TestKeepsMessagesOnError(c *gc.C) {
+	buffer := logsender.NewLogBuffer(10)
+	api := &fakeLogWriter{err: errors.New("connection lost")}
+	writeMessages(buffer, "one", "two")
+	w := logsender.New(buffer, api)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot send log messages: connection lost")
+	c.Assert(messages(logsender.Take(buffer)), jc.DeepEquals, []string{"one", "two"})
+}
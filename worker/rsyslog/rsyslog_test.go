@@ -138,6 +138,35 @@ func (s *RsyslogSuite) TestModeForwarding(c *gc.C) {
 	c.Assert(string(rsyslogConf), gc.DeepEquals, string(rendered))
 }
 
+func (s *RsyslogSuite) TestDisabled(c *gc.C) {
+	err := s.APIState.Client().EnvironmentSet(map[string]interface{}{
+		"rsyslog-ca-cert": coretesting.CACert,
+		"disable-rsyslog": true,
+	})
+	c.Assert(err, gc.IsNil)
+	// Configuration written before rsyslog was disabled is removed.
+	confFile := filepath.Join(*rsyslog.RsyslogConfDir, "25-juju.conf")
+	err = ioutil.WriteFile(confFile, []byte("old configuration"), 0644)
+	c.Assert(err, gc.IsNil)
+	restarted := make(chan struct{}, 1)
+	s.PatchValue(rsyslog.RestartRsyslog, func() error {
+		restarted <- struct{}{}
+		return nil
+	})
+
+	st, m := s.OpenAPIAsNewMachine(c, state.JobHostUnits)
+	worker, err := rsyslog.NewRsyslogConfigWorker(st.Rsyslog(), rsyslog.RsyslogModeForwarding, m.Tag(), "", []string{"0.1.2.3"})
+	c.Assert(err, gc.IsNil)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	waitForRestart(c, restarted)
+	_, err = os.Stat(confFile)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	_, err = os.Stat(filepath.Join(*rsyslog.LogDir, "ca-cert.pem"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *RsyslogSuite) TestModeAccumulate(c *gc.C) {
 	st, m := s.st, s.machine
 	worker, err := rsyslog.NewRsyslogConfigWorker(st.Rsyslog(), rsyslog.RsyslogModeAccumulate, m.Tag(), "", nil)
@@ -143,6 +143,13 @@ func (h *RsyslogConfigHandler) Handle() error {
 	if err != nil {
 		return errors.Annotate(err, "cannot get environ config")
 	}
+	if cfg.Disabled {
+		// Agents send their logs over the API instead, so
+		// remove any configuration written before.
+		h.syslogPort = 0
+		h.rsyslogCACert = ""
+		return h.TearDown()
+	}
 	rsyslogCACert := cfg.CACert
 	if rsyslogCACert == "" {
 		return nil
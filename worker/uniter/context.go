@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/juju/loggo"
@@ -85,6 +86,54 @@ type HookContext struct {
 
 	// proxySettings are the current proxy settings that the uniter knows about
 	proxySettings proxy.Settings
+
+	// actionData holds the state of the action being run, and is nil if
+	// the context is not running an action.
+	actionData *actionData
+}
+
+// actionData contains the parameters of a running action, and the
+// outcome it reports through the action-set and action-fail tools.
+// If timeout is non-zero, the action is stopped once it has run for
+// that long; it is also stopped when cancel is closed.
+type actionData struct {
+	params  map[string]interface{}
+	results map[string]interface{}
+	failed  bool
+	message string
+	timeout time.Duration
+	cancel  <-chan struct{}
+}
+
+// newActionData returns a new actionData for an action run with the
+// given parameters.
+func newActionData(params map[string]interface{}) *actionData {
+	return &actionData{
+		params:  params,
+		results: map[string]interface{}{},
+	}
+}
+
+// actionFailedError is returned when an action ran to completion but
+// reported its own failure with action-fail.
+type actionFailedError struct {
+	message string
+}
+
+func (e *actionFailedError) Error() string {
+	return e.message
+}
+
+// actionStoppedError is returned when an action was killed before it
+// finished, because it timed out or was cancelled. Status holds the
+// status to record for the action.
+type actionStoppedError struct {
+	status  string
+	message string
+}
+
+func (e *actionStoppedError) Error() string {
+	return e.message
 }
 
 func NewHookContext(unit *uniter.Unit, id, uuid, envName string,
@@ -135,10 +184,70 @@ func (ctx *HookContext) ClosePort(protocol string, port int) error {
 	return ctx.unit.ClosePort(protocol, port)
 }
 
+func (ctx *HookContext) WorkloadStatus() (params.Status, string, error) {
+	return ctx.unit.WorkloadStatus()
+}
+
+func (ctx *HookContext) SetWorkloadStatus(status params.Status, info string) error {
+	return ctx.unit.SetWorkloadStatus(status, info)
+}
+
+func (ctx *HookContext) IsLeader() (bool, error) {
+	isLeader, _, err := ctx.unit.ClaimLeadership()
+	return isLeader, err
+}
+
+func (ctx *HookContext) LeaderSettings() (map[string]string, error) {
+	return ctx.unit.LeaderSettings()
+}
+
+func (ctx *HookContext) WriteLeaderSettings(settings map[string]string) error {
+	return ctx.unit.MergeLeaderSettings(settings)
+}
+
 func (ctx *HookContext) OwnerTag() string {
 	return ctx.serviceOwner
 }
 
+func (ctx *HookContext) ActionParams() (map[string]interface{}, error) {
+	if ctx.actionData == nil {
+		return nil, fmt.Errorf("not running an action")
+	}
+	return ctx.actionData.params, nil
+}
+
+func (ctx *HookContext) UpdateActionResults(keys []string, value string) error {
+	if ctx.actionData == nil {
+		return fmt.Errorf("not running an action")
+	}
+	addValueToMap(keys, value, ctx.actionData.results)
+	return nil
+}
+
+func (ctx *HookContext) SetActionFailed(message string) error {
+	if ctx.actionData == nil {
+		return fmt.Errorf("not running an action")
+	}
+	ctx.actionData.failed = true
+	ctx.actionData.message = message
+	return nil
+}
+
+// addValueToMap sets the value at the given key path in target, creating
+// intermediate maps as needed and replacing any non-map values found on
+// the way.
+func addValueToMap(keys []string, value string, target map[string]interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := target[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			target[key] = next
+		}
+		target = next
+	}
+	target[keys[len(keys)-1]] = value
+}
+
 func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	if ctx.configSettings == nil {
 		var err error
@@ -240,6 +349,34 @@ func (ctx *HookContext) GetLogger(hookName string) loggo.Logger {
 // RunHook executes a hook in an environment which allows it to to call back
 // into the hook context to execute jujuc tools.
 func (ctx *HookContext) RunHook(hookName, charmDir, toolsDir, socketPath string) error {
+	return ctx.runCharmHookWithLocation(hookName, "hooks", charmDir, toolsDir, socketPath)
+}
+
+// RunAction executes the named action script, found in the charm's
+// actions directory, in an environment which allows it to call back
+// into the hook context to execute jujuc tools.
+// If the script exits cleanly but reported failure with action-fail, an
+// error carrying its failure message is returned.
+func (ctx *HookContext) RunAction(actionName, charmDir, toolsDir, socketPath string) error {
+	err := ctx.runCharmHookWithLocation(actionName, "actions", charmDir, toolsDir, socketPath)
+	if _, ok := err.(*actionStoppedError); ok {
+		return err
+	}
+	if err == nil && ctx.actionData != nil && ctx.actionData.failed {
+		err = &actionFailedError{ctx.actionData.message}
+	}
+	return err
+}
+
+// actionResults returns the results set by the running action, if any.
+func (ctx *HookContext) actionResults() map[string]interface{} {
+	if ctx.actionData == nil || len(ctx.actionData.results) == 0 {
+		return nil
+	}
+	return ctx.actionData.results
+}
+
+func (ctx *HookContext) runCharmHookWithLocation(hookName, charmLocation, charmDir, toolsDir, socketPath string) error {
 	var err error
 	env := ctx.hookVars(charmDir, toolsDir, socketPath)
 	debugctx := unitdebug.NewHooksContext(ctx.unit.Name())
@@ -247,13 +384,13 @@ func (ctx *HookContext) RunHook(hookName, charmDir, toolsDir, socketPath string)
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, charmDir, env)
 	} else {
-		err = ctx.runCharmHook(hookName, charmDir, env)
+		err = ctx.runCharmHook(hookName, charmLocation, charmDir, env)
 	}
 	return ctx.finalizeContext(hookName, err)
 }
 
-func (ctx *HookContext) runCharmHook(hookName, charmDir string, env []string) error {
-	hook, err := exec.LookPath(filepath.Join(charmDir, "hooks", hookName))
+func (ctx *HookContext) runCharmHook(hookName, charmLocation, charmDir string, env []string) error {
+	hook, err := exec.LookPath(filepath.Join(charmDir, charmLocation, hookName))
 	if err != nil {
 		if ee, ok := err.(*exec.Error); ok && os.IsNotExist(ee.Err) {
 			// Missing hook is perfectly valid, but worth mentioning.
@@ -265,6 +402,11 @@ func (ctx *HookContext) runCharmHook(hookName, charmDir string, env []string) er
 	ps := exec.Command(hook)
 	ps.Env = env
 	ps.Dir = charmDir
+	if ctx.actionData != nil {
+		// Run actions in their own process group, so that everything
+		// they start can be killed if they need to be stopped.
+		ps.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return fmt.Errorf("cannot make logging pipe: %v", err)
@@ -280,12 +422,52 @@ func (ctx *HookContext) runCharmHook(hookName, charmDir string, env []string) er
 	err = ps.Start()
 	outWriter.Close()
 	if err == nil {
-		err = ps.Wait()
+		err = ctx.wait(ps)
 	}
 	hookLogger.stop()
 	return err
 }
 
+// wait waits for the started hook process to exit. An action is killed,
+// along with every process in its process group, if it runs for longer
+// than its timeout or is cancelled; an *actionStoppedError is returned
+// in that case.
+func (ctx *HookContext) wait(ps *exec.Cmd) error {
+	if ctx.actionData == nil {
+		return ps.Wait()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	var timeout <-chan time.Time
+	if ctx.actionData.timeout > 0 {
+		timer := time.NewTimer(ctx.actionData.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var stopErr *actionStoppedError
+	select {
+	case err := <-done:
+		return err
+	case <-timeout:
+		stopErr = &actionStoppedError{
+			status:  "timeout",
+			message: fmt.Sprintf("action timed out after %v", ctx.actionData.timeout),
+		}
+	case <-ctx.actionData.cancel:
+		stopErr = &actionStoppedError{
+			status:  "cancelled",
+			message: "action cancelled",
+		}
+	}
+	if err := syscall.Kill(-ps.Process.Pid, syscall.SIGKILL); err != nil {
+		logger.Errorf("cannot kill action process group: %v", err)
+	}
+	<-done
+	return stopErr
+}
+
 type hookLogger struct {
 	r       io.ReadCloser
 	done    chan struct{}
@@ -12,7 +12,7 @@ func SetUniterObserver(u *Uniter, observer UniterExecutionObserver) {
 }
 
 func (u *Uniter) GetProxyValues() proxy.Settings {
-	u.proxyMutex.Lock()
-	defer u.proxyMutex.Unlock()
+	u.environMutex.Lock()
+	defer u.environMutex.Unlock()
 	return u.proxy
 }
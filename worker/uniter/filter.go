@@ -5,21 +5,29 @@ package uniter
 
 import (
 	"sort"
+	"time"
 
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/charm"
+	"github.com/juju/juju/charm/hooks"
 	"github.com/juju/juju/state/api/params"
 	"github.com/juju/juju/state/api/uniter"
 	apiwatcher "github.com/juju/juju/state/api/watcher"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 var filterLogger = loggo.GetLogger("juju.worker.uniter.filter")
 
+// leadershipClaimInterval is how often the filter claims, or renews its
+// unit's claim to, the leadership of the unit's service. It must be
+// shorter than the lease the state server grants with each claim.
+var leadershipClaimInterval = 30 * time.Second
+
 // filter collects unit, service, and service config information from separate
 // state watchers, and presents it as events on channels designed specifically
 // for the convenience of the uniter.
@@ -42,6 +50,13 @@ type filter struct {
 	outResolvedOn  chan params.ResolvedMode
 	outRelations   chan []int
 	outRelationsOn chan []int
+	outAction      chan *hook.Info
+	outActionOn    chan *hook.Info
+
+	outLeaderElected    chan struct{}
+	outLeaderElectedOn  chan struct{}
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
 
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
@@ -86,6 +101,12 @@ type filter struct {
 	upgradeAvailable serviceCharm
 	upgrade          *charm.URL
 	relations        []int
+	actionsPending   []string
+	nextAction       *hook.Info
+	isLeader         bool
+	// leaseExpiry holds the earliest time at which the lease granted
+	// by the last successful leadership claim can expire.
+	leaseExpiry time.Time
 }
 
 // newFilter returns a filter that handles state changes pertaining to the
@@ -102,6 +123,8 @@ func newFilter(st *uniter.State, unitTag string) (*filter, error) {
 		outResolvedOn:     make(chan params.ResolvedMode),
 		outRelations:      make(chan []int),
 		outRelationsOn:    make(chan []int),
+		outAction:         make(chan *hook.Info),
+		outActionOn:       make(chan *hook.Info),
 		wantForcedUpgrade: make(chan bool),
 		wantResolved:      make(chan struct{}),
 		discardConfig:     make(chan struct{}),
@@ -109,6 +132,9 @@ func newFilter(st *uniter.State, unitTag string) (*filter, error) {
 		didSetCharm:       make(chan struct{}),
 		clearResolved:     make(chan struct{}),
 		didClearResolved:  make(chan struct{}),
+
+		outLeaderElectedOn:  make(chan struct{}),
+		outLeaderSettingsOn: make(chan struct{}),
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -164,6 +190,25 @@ func (f *filter) RelationsEvents() <-chan []int {
 	return f.outRelationsOn
 }
 
+// ActionEvents returns a channel that will receive a hook.Info for each
+// action queued for the unit, in the order in which they were queued.
+func (f *filter) ActionEvents() <-chan *hook.Info {
+	return f.outActionOn
+}
+
+// LeaderElectedEvents returns a channel that will receive a signal
+// whenever the unit becomes the leader of its service.
+func (f *filter) LeaderElectedEvents() <-chan struct{} {
+	return f.outLeaderElectedOn
+}
+
+// LeaderSettingsEvents returns a channel that will receive a signal
+// whenever the leader settings of the unit's service change, while
+// the unit is not the leader.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
 // WantUpgradeEvent controls whether the filter will generate upgrade
 // events for unforced service charm changes.
 func (f *filter) WantUpgradeEvent(mustForce bool) {
@@ -271,6 +316,19 @@ func (f *filter) loop(unitTag string) (err error) {
 		return err
 	}
 	defer f.maybeStopWatcher(servicew)
+	actionsw, err := f.unit.WatchActions()
+	if err != nil {
+		return err
+	}
+	defer f.maybeStopWatcher(actionsw)
+	leaderSettingsw, err := f.unit.WatchLeaderSettings()
+	if err != nil {
+		return err
+	}
+	defer f.maybeStopWatcher(leaderSettingsw)
+	// Leadership is claimed straight away, and the claim renewed
+	// regularly after that.
+	claimLeadership := time.After(0)
 	// configw and relationsw can get restarted, so we need to use
 	// their eventual values in the defer calls.
 	var configw apiwatcher.NotifyWatcher
@@ -356,6 +414,24 @@ func (f *filter) loop(unitTag string) (err error) {
 				}
 			}
 			f.relationsChanged(ids)
+		case ids, ok := <-actionsw.Changes():
+			filterLogger.Debugf("got %d actions", len(ids))
+			if !ok {
+				return watcher.MustErr(actionsw)
+			}
+			f.addActions(ids)
+			f.actionsChanged()
+		case _, ok = <-leaderSettingsw.Changes():
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.MustErr(leaderSettingsw)
+			}
+			if !f.isLeader {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
+		case <-claimLeadership:
+			f.leadershipChanged()
+			claimLeadership = time.After(leadershipClaimInterval)
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -371,6 +447,16 @@ func (f *filter) loop(unitTag string) (err error) {
 			filterLogger.Debugf("sent relations event")
 			f.outRelations = nil
 			f.relations = nil
+		case f.outAction <- f.nextAction:
+			filterLogger.Debugf("sent action event")
+			f.actionsPending = f.actionsPending[1:]
+			f.actionsChanged()
+		case f.outLeaderElected <- nothing:
+			filterLogger.Debugf("sent leader elected event")
+			f.outLeaderElected = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings event")
+			f.outLeaderSettings = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -540,6 +626,86 @@ outer:
 	}
 }
 
+// addActions queues the given action ids behind those already pending,
+// ignoring any that are already pending.
+func (f *filter) addActions(ids []string) {
+	pending := make(map[string]bool)
+	for _, id := range f.actionsPending {
+		pending[id] = true
+	}
+	for _, id := range ids {
+		if !pending[id] {
+			pending[id] = true
+			f.actionsPending = append(f.actionsPending, id)
+		}
+	}
+}
+
+// actionsChanged prepares an event for the next pending action, if any.
+func (f *filter) actionsChanged() {
+	if len(f.actionsPending) == 0 {
+		f.nextAction = nil
+		f.outAction = nil
+		return
+	}
+	f.nextAction = &hook.Info{
+		Kind:     hooks.ActionRequested,
+		ActionId: f.actionsPending[0],
+	}
+	f.outAction = f.outActionOn
+}
+
+// leadershipChanged claims, or renews the unit's claim to, the leadership
+// of its service, and prepares a leader elected event if the unit has
+// become the leader. A unit that is not alive does not claim leadership.
+func (f *filter) leadershipChanged() {
+	if f.life != params.Alive {
+		f.setLeader(false)
+		return
+	}
+	claimed := time.Now()
+	isLeader, lease, err := f.unit.ClaimLeadership()
+	f.leadershipClaimed(claimed, isLeader, lease, err)
+}
+
+// leadershipClaimed records the result of a leadership claim made at
+// the given time, which granted a lease of the given duration if the
+// unit is the leader. When the claim fails, the unit keeps its
+// leadership until the lease granted by its last successful claim could
+// have expired, as no other unit can have been elected before then.
+func (f *filter) leadershipClaimed(claimed time.Time, isLeader bool, lease time.Duration, err error) {
+	switch {
+	case err == nil:
+		if isLeader {
+			f.leaseExpiry = claimed.Add(lease)
+		}
+	case f.isLeader && time.Now().Before(f.leaseExpiry):
+		filterLogger.Warningf("cannot renew leadership claim, keeping leadership until %v: %v", f.leaseExpiry, err)
+		isLeader = true
+	default:
+		filterLogger.Warningf("cannot claim leadership: %v", err)
+		isLeader = false
+	}
+	f.setLeader(isLeader)
+}
+
+// setLeader records whether the unit is the leader of its service, and
+// prepares a leader elected event if it has just become the leader.
+func (f *filter) setLeader(isLeader bool) {
+	switch {
+	case isLeader && !f.isLeader:
+		filterLogger.Infof("unit is now the service leader")
+		f.outLeaderElected = f.outLeaderElectedOn
+		// The leader has no need to hear of changes to the
+		// settings it writes itself.
+		f.outLeaderSettings = nil
+	case !isLeader && f.isLeader:
+		filterLogger.Infof("unit is no longer the service leader")
+		f.outLeaderElected = nil
+	}
+	f.isLeader = isLeader
+}
+
 // serviceCharm holds information about a charm.
 type serviceCharm struct {
 	url   *charm.URL
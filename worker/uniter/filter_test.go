@@ -12,6 +12,7 @@ import (
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/charm"
+	"github.com/juju/juju/charm/hooks"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/api"
@@ -20,6 +21,7 @@ import (
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 type FilterSuite struct {
@@ -447,6 +449,109 @@ func (s *FilterSuite) TestRelationsEvents(c *gc.C) {
 	assertChange([]int{0, 2})
 }
 
+func (s *FilterSuite) TestActionEvents(c *gc.C) {
+	// Queue an action before the filter starts.
+	id0, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+
+	f, err := newFilter(s.uniter, s.unit.Tag())
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+
+	actionAsserter := coretesting.ContentAsserterC{
+		C:       c,
+		Precond: func() { s.BackingState.StartSync() },
+		Chan:    f.ActionEvents(),
+	}
+	assertChange := func(expect string) {
+		hi := actionAsserter.AssertOneReceive().(*hook.Info)
+		c.Assert(hi, gc.DeepEquals, &hook.Info{
+			Kind:     hooks.ActionRequested,
+			ActionId: expect,
+		})
+	}
+	assertChange(id0)
+	actionAsserter.AssertNoReceive()
+
+	// Queue a couple more; check they're delivered one at a time, in order.
+	id1, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.IsNil)
+	id2, err := s.unit.AddAction("backup", nil)
+	c.Assert(err, gc.IsNil)
+	s.BackingState.StartSync()
+	var got []string
+	for i := 0; i < 2; i++ {
+		hi := actionAsserter.AssertReceive().(*hook.Info)
+		c.Assert(hi.Kind, gc.Equals, hooks.ActionRequested)
+		got = append(got, hi.ActionId)
+	}
+	c.Assert(got, gc.DeepEquals, []string{id1, id2})
+	actionAsserter.AssertNoReceive()
+
+	// Starting an action does not queue it again.
+	action, err := s.State.Action(id2)
+	c.Assert(err, gc.IsNil)
+	err = action.Begin()
+	c.Assert(err, gc.IsNil)
+	actionAsserter.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestLeaderEvents(c *gc.C) {
+	f, err := newFilter(s.uniter, s.unit.Tag())
+	c.Assert(err, gc.IsNil)
+	defer statetesting.AssertStop(c, f)
+
+	// The only unit of the service is elected leader straight away.
+	electedAsserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderElectedEvents(),
+	}
+	electedAsserter.AssertOneReceive()
+
+	// The leader is not notified of its own settings changes.
+	settingsAsserter := coretesting.NotifyAsserterC{
+		Precond: func() { s.BackingState.StartSync() },
+		C:       c,
+		Chan:    f.LeaderSettingsEvents(),
+	}
+	err = s.wordpress.MergeLeaderSettings(s.unit.Name(), map[string]string{"master": "10.0.0.1"})
+	c.Assert(err, gc.IsNil)
+	settingsAsserter.AssertNoReceive()
+	electedAsserter.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestLeadershipKeptUntilLeaseExpires(c *gc.C) {
+	f := &filter{outLeaderElectedOn: make(chan struct{})}
+	claimErr := fmt.Errorf("connection is shut down")
+
+	claimed := time.Now()
+	f.leadershipClaimed(claimed, true, time.Minute, nil)
+	c.Assert(f.isLeader, gc.Equals, true)
+	c.Assert(f.outLeaderElected, gc.NotNil)
+	c.Assert(f.leaseExpiry, gc.Equals, claimed.Add(time.Minute))
+
+	// A claim that cannot be renewed keeps the leadership while the
+	// last lease may still hold.
+	f.leadershipClaimed(time.Now(), false, 0, claimErr)
+	c.Assert(f.isLeader, gc.Equals, true)
+	c.Assert(f.outLeaderElected, gc.NotNil)
+
+	// Once the granted lease could have expired, the unit is no longer
+	// the leader.
+	f.leadershipClaimed(time.Now().Add(-time.Second), true, time.Millisecond, nil)
+	c.Assert(f.isLeader, gc.Equals, true)
+	f.leadershipClaimed(time.Now(), false, 0, claimErr)
+	c.Assert(f.isLeader, gc.Equals, false)
+	c.Assert(f.outLeaderElected, gc.IsNil)
+
+	// A unit that was not the leader does not become one by failing
+	// to claim leadership.
+	f.leaseExpiry = time.Now().Add(time.Minute)
+	f.leadershipClaimed(time.Now(), false, 0, claimErr)
+	c.Assert(f.isLeader, gc.Equals, false)
+}
+
 func (s *FilterSuite) addRelation(c *gc.C) *state.Relation {
 	if s.mysqlcharm == nil {
 		s.mysqlcharm = s.AddTestingCharm(c, "mysql")
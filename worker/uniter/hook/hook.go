@@ -26,6 +26,10 @@ type Info struct {
 	// ChangeVersion identifies the most recent unit settings change
 	// associated with RemoteUnit. It is only set when RemoteUnit is set.
 	ChangeVersion int64 `yaml:"change-version,omitempty"`
+
+	// ActionId is the id of the action to run. It is only set when Kind
+	// is ActionRequested.
+	ActionId string `yaml:"action-id,omitempty"`
 }
 
 // Validate returns an error if the info is not valid.
@@ -36,7 +40,13 @@ func (hi Info) Validate() error {
 			return fmt.Errorf("%q hook requires a remote unit", hi.Kind)
 		}
 		fallthrough
-	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken:
+	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken,
+		hooks.UpdateStatus, hooks.LeaderElected, hooks.LeaderSettingsChanged:
+		return nil
+	case hooks.ActionRequested:
+		if hi.ActionId == "" {
+			return fmt.Errorf("%q hook requires an action id", hi.Kind)
+		}
 		return nil
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
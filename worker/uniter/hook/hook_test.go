@@ -33,6 +33,9 @@ var validateTests = []struct {
 	}, {
 		hook.Info{Kind: hooks.RelationDeparted},
 		`"relation-departed" hook requires a remote unit`,
+	}, {
+		hook.Info{Kind: hooks.ActionRequested},
+		`"action-requested" hook requires an action id`,
 	}, {
 		hook.Info{Kind: hooks.Kind("grok")},
 		`unknown hook kind "grok"`,
@@ -42,10 +45,14 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.ConfigChanged}, ""},
 	{hook.Info{Kind: hooks.UpgradeCharm}, ""},
 	{hook.Info{Kind: hooks.Stop}, ""},
+	{hook.Info{Kind: hooks.UpdateStatus}, ""},
+	{hook.Info{Kind: hooks.LeaderElected}, ""},
+	{hook.Info{Kind: hooks.LeaderSettingsChanged}, ""},
 	{hook.Info{Kind: hooks.RelationJoined, RemoteUnit: "x"}, ""},
 	{hook.Info{Kind: hooks.RelationChanged, RemoteUnit: "x"}, ""},
 	{hook.Info{Kind: hooks.RelationDeparted, RemoteUnit: "x"}, ""},
 	{hook.Info{Kind: hooks.RelationBroken}, ""},
+	{hook.Info{Kind: hooks.ActionRequested, ActionId: "u#mysql/0#a#1"}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
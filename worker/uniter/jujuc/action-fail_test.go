@@ -0,0 +1,64 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type ActionFailSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ActionFailSuite{})
+
+var actionFailTests = []struct {
+	summary string
+	args    []string
+	code    int
+	errMsg  string
+	message string
+}{{
+	summary: "no message",
+	message: "action failed without reason given, check action for errors",
+}, {
+	summary: "a message",
+	args:    []string{"disk full"},
+	message: "disk full",
+}, {
+	summary: "too many arguments",
+	args:    []string{"disk", "full"},
+	code:    2,
+	errMsg:  "error: unrecognized args: [\"full\"]\n",
+}}
+
+func (s *ActionFailSuite) TestActionFail(c *gc.C) {
+	for i, t := range actionFailTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.actionParams = map[string]interface{}{}
+		com, err := jujuc.NewCommand(hctx, "action-fail")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.errMsg)
+		c.Check(hctx.actionFailed, gc.Equals, t.code == 0)
+		c.Check(hctx.actionMessage, gc.Equals, t.message)
+	}
+}
+
+func (s *ActionFailSuite) TestNotInAction(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-fail")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: not running an action\n")
+}
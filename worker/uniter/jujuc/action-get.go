@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"strings"
+
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+)
+
+// ActionGetCommand implements the action-get command.
+type ActionGetCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	keys []string
+	out  cmd.Output
+}
+
+func NewActionGetCommand(ctx Context) cmd.Command {
+	return &ActionGetCommand{ctx: ctx}
+}
+
+func (c *ActionGetCommand) Info() *cmd.Info {
+	doc := `
+action-get will print the value of the parameter at the given key, serialized
+as YAML.  If multiple keys are passed, action-get will recurse into the param
+map as needed.  Nested keys are separated by dots, so that
+    action-get outfile.format
+prints the "format" value of the "outfile" parameter.  When no <key> is
+supplied, all parameters are printed.
+`
+	return &cmd.Info{
+		Name:    "action-get",
+		Args:    "[<key>[.<key>.<key>...]]",
+		Purpose: "get action parameters",
+		Doc:     doc,
+	}
+}
+
+func (c *ActionGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *ActionGetCommand) Init(args []string) error {
+	if len(args) > 0 {
+		if err := checkKeyPath(args[0]); err != nil {
+			return err
+		}
+		c.keys = strings.Split(args[0], ".")
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// recurseMapOnKeys returns the value of the map at the given key path,
+// and whether it was found.
+func recurseMapOnKeys(keys []string, params map[string]interface{}) (interface{}, bool) {
+	key, rest := keys[0], keys[1:]
+	value, ok := params[key]
+	if !ok || len(rest) == 0 {
+		return value, ok
+	}
+	next, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return recurseMapOnKeys(rest, next)
+}
+
+func (c *ActionGetCommand) Run(ctx *cmd.Context) error {
+	params, err := c.ctx.ActionParams()
+	if err != nil {
+		return err
+	}
+	var value interface{} = params
+	if len(c.keys) > 0 {
+		value, _ = recurseMapOnKeys(c.keys, params)
+	}
+	return c.out.Write(ctx, value)
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type ActionGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ActionGetSuite{})
+
+var actionGetParams = map[string]interface{}{
+	"outfile": map[string]interface{}{
+		"name":   "foo.tar",
+		"format": "bz2",
+	},
+	"quality": 5,
+}
+
+var actionGetTests = []struct {
+	args []string
+	out  string
+}{
+	{[]string{"quality"}, "5\n"},
+	{[]string{"--format", "json", "quality"}, "5\n"},
+	{[]string{"outfile.format"}, "bz2\n"},
+	{[]string{"--format", "json", "outfile"}, `{"format":"bz2","name":"foo.tar"}` + "\n"},
+	{[]string{"outfile"}, "format: bz2\nname: foo.tar\n"},
+	{[]string{"missing"}, ""},
+	{[]string{"quality.missing"}, ""},
+	{[]string{"--format", "json", "missing"}, "null\n"},
+	{nil, "outfile:\n  format: bz2\n  name: foo.tar\nquality: 5\n"},
+}
+
+func (s *ActionGetSuite) TestActionGet(c *gc.C) {
+	for i, t := range actionGetTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.actionParams = actionGetParams
+		com, err := jujuc.NewCommand(hctx, "action-get")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *ActionGetSuite) TestNotInAction(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-get")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: not running an action\n")
+}
+
+func (s *ActionGetSuite) TestInit(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-get")
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"Bad"})
+	c.Assert(err, gc.ErrorMatches, `key "Bad" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens`)
+	err = testing.InitCommand(com, []string{"outfile", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
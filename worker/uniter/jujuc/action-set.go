@@ -0,0 +1,96 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/juju/juju/cmd"
+)
+
+var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
+
+// checkKeyPath returns an error if the dot-separated key path is not
+// made up of valid keys.
+func checkKeyPath(path string) error {
+	for _, key := range strings.Split(path, ".") {
+		if !keyRule.MatchString(key) {
+			return fmt.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
+		}
+	}
+	return nil
+}
+
+// ActionSetCommand implements the action-set command.
+type ActionSetCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	args [][]string
+}
+
+func NewActionSetCommand(ctx Context) cmd.Command {
+	return &ActionSetCommand{ctx: ctx}
+}
+
+func (c *ActionSetCommand) Info() *cmd.Info {
+	doc := `
+action-set adds the given values to the results map of the Action.  This map
+is returned to the user after the completion of the Action.  Keys must start
+and end with lowercase alphanumeric, and contain only lowercase alphanumeric
+and hyphens.  Nested keys are separated by dots.
+
+Example usage:
+ action-set outfile.size=10G
+ action-set foo.bar=2
+ action-set foo.baz.val=3
+ action-set foo.bar.zab=4
+ action-set foo.baz=1
+
+ will yield:
+
+ outfile:
+   size: "10G"
+ foo:
+   bar:
+     zab: "4"
+   baz: "1"
+`
+	return &cmd.Info{
+		Name:    "action-set",
+		Args:    "<key>=<value> [<key>=<value> ...]",
+		Purpose: "set action results",
+		Doc:     doc,
+	}
+}
+
+func (c *ActionSetCommand) Init(args []string) error {
+	c.args = nil
+	if len(args) == 0 {
+		return fmt.Errorf("no key=value pairs specified")
+	}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("argument %q must be of the form key=value", arg)
+		}
+		if err := checkKeyPath(parts[0]); err != nil {
+			return err
+		}
+		keys := strings.Split(parts[0], ".")
+		c.args = append(c.args, append(keys, parts[1]))
+	}
+	return nil
+}
+
+func (c *ActionSetCommand) Run(ctx *cmd.Context) error {
+	for _, arg := range c.args {
+		keys, value := arg[:len(arg)-1], arg[len(arg)-1]
+		if err := c.ctx.UpdateActionResults(keys, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type ActionSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&ActionSetSuite{})
+
+var actionSetTests = []struct {
+	summary  string
+	args     []string
+	expected map[string]interface{}
+	code     int
+	errMsg   string
+}{{
+	summary: "no arguments",
+	code:    2,
+	errMsg:  "error: no key=value pairs specified\n",
+}, {
+	summary: "not a key=value pair",
+	args:    []string{"result"},
+	code:    2,
+	errMsg:  `error: argument "result" must be of the form key=value` + "\n",
+}, {
+	summary: "invalid key",
+	args:    []string{"Result=5"},
+	code:    2,
+	errMsg:  `error: key "Result" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens` + "\n",
+}, {
+	summary: "invalid nested key",
+	args:    []string{"outfile..size=5"},
+	code:    2,
+	errMsg:  `error: key "" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens` + "\n",
+}, {
+	summary:  "simple values",
+	args:     []string{"outfile=foo.tar", "size=10G"},
+	expected: map[string]interface{}{"outfile": "foo.tar", "size": "10G"},
+}, {
+	summary: "nested values",
+	args:    []string{"outfile.name=foo.tar", "outfile.size=10G", "a.b.c=d", "e=f=g"},
+	expected: map[string]interface{}{
+		"outfile": map[string]interface{}{"name": "foo.tar", "size": "10G"},
+		"a":       map[string]interface{}{"b": map[string]interface{}{"c": "d"}},
+		"e":       "f=g",
+	},
+}, {
+	summary: "later values replace earlier ones",
+	args:    []string{"foo.bar=2", "foo.bar.zab=4", "foo.baz=1"},
+	expected: map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": map[string]interface{}{"zab": "4"},
+			"baz": "1",
+		},
+	},
+}}
+
+func (s *ActionSetSuite) TestActionSet(c *gc.C) {
+	for i, t := range actionSetTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.actionParams = map[string]interface{}{}
+		com, err := jujuc.NewCommand(hctx, "action-set")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.errMsg)
+		c.Check(hctx.actionResults, gc.DeepEquals, t.expected)
+	}
+}
+
+func (s *ActionSetSuite) TestNotInAction(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "action-set")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"foo=bar"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: not running an action\n")
+}
@@ -34,6 +34,27 @@ type Context interface {
 	// located unit).
 	ClosePort(protocol string, port int) error
 
+	// WorkloadStatus returns the status of the executing unit's workload,
+	// and the message describing it.
+	WorkloadStatus() (params.Status, string, error)
+
+	// SetWorkloadStatus sets the status of the executing unit's workload,
+	// along with a message describing it.
+	SetWorkloadStatus(status params.Status, info string) error
+
+	// IsLeader returns whether the executing unit is the leader of its
+	// service, claiming or renewing its leadership if possible.
+	IsLeader() (bool, error)
+
+	// LeaderSettings returns the settings that the leader of the executing
+	// unit's service shares with the other units of the service.
+	LeaderSettings() (map[string]string, error)
+
+	// WriteLeaderSettings merges the given settings into the leader settings
+	// of the executing unit's service, removing those with empty values. It
+	// fails unless the executing unit is the leader.
+	WriteLeaderSettings(settings map[string]string) error
+
 	// Config returns the current service configuration of the executing unit.
 	ConfigSettings() (charm.Settings, error)
 
@@ -55,6 +76,19 @@ type Context interface {
 
 	// OwnerTag returns the owner of the service the executing units belongs to
 	OwnerTag() string
+
+	// ActionParams returns the parameters of the action being executed,
+	// or an error if the context is not running an action.
+	ActionParams() (map[string]interface{}, error)
+
+	// UpdateActionResults sets the value at the given key path in the
+	// results of the action being executed. The results are recorded
+	// when the action finishes.
+	UpdateActionResults(keys []string, value string) error
+
+	// SetActionFailed marks the action being executed as failed, with
+	// the given message, once it finishes.
+	SetActionFailed(message string) error
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd"
+)
+
+// LeaderGetCommand implements the leader-get command.
+type LeaderGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+	Key string // The key to show. If empty, show all.
+	out cmd.Output
+}
+
+func NewLeaderGetCommand(ctx Context) cmd.Command {
+	return &LeaderGetCommand{ctx: ctx}
+}
+
+func (c *LeaderGetCommand) Info() *cmd.Info {
+	doc := `
+leader-get prints the settings that the leader of the unit's service has
+shared with the other units of the service using leader-set. When no <key>
+is supplied, all settings are printed.
+`
+	return &cmd.Info{
+		Name:    "leader-get",
+		Args:    "[<key>]",
+		Purpose: "print service leader settings",
+		Doc:     doc,
+	}
+}
+
+func (c *LeaderGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *LeaderGetCommand) Init(args []string) error {
+	if args == nil {
+		return nil
+	}
+	c.Key = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *LeaderGetCommand) Run(ctx *cmd.Context) error {
+	settings, err := c.ctx.LeaderSettings()
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if c.Key == "" {
+		value = settings
+	} else if v, found := settings[c.Key]; found {
+		value = v
+	}
+	return c.out.Write(ctx, value)
+}
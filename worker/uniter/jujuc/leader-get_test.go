@@ -0,0 +1,51 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type LeaderGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderGetSuite{})
+
+var leaderGetTests = []struct {
+	args []string
+	out  string
+}{
+	{nil, "master: 10.0.0.1\n"},
+	{[]string{"master"}, "10.0.0.1\n"},
+	{[]string{"missing"}, ""},
+	{[]string{"--format", "json"}, `{"master":"10.0.0.1"}` + "\n"},
+	{[]string{"master", "--format", "json"}, `"10.0.0.1"` + "\n"},
+}
+
+func (s *LeaderGetSuite) TestLeaderGet(c *gc.C) {
+	for i, t := range leaderGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, "leader-get")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *LeaderGetSuite) TestInitError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "leader-get")
+	c.Assert(err, gc.IsNil)
+	err = testing.InitCommand(com, []string{"master", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type LeaderSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderSetSuite{})
+
+var leaderSetTests = []struct {
+	summary  string
+	isLeader bool
+	args     []string
+	code     int
+	errMsg   string
+	settings map[string]string
+}{{
+	summary:  "set and remove settings",
+	isLeader: true,
+	args:     []string{"token=xyzzy", "master="},
+	settings: map[string]string{"token": "xyzzy"},
+}, {
+	summary:  "not leader",
+	args:     []string{"token=xyzzy"},
+	code:     1,
+	errMsg:   "error: cannot write leader settings: unit is not the service leader\n",
+	settings: map[string]string{"master": "10.0.0.1"},
+}, {
+	summary:  "bad argument",
+	isLeader: true,
+	args:     []string{"token"},
+	code:     2,
+	errMsg:   "error: expected \"key=value\", got \"token\"\n",
+	settings: map[string]string{"master": "10.0.0.1"},
+}}
+
+func (s *LeaderSetSuite) TestLeaderSet(c *gc.C) {
+	for i, t := range leaderSetTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.isLeader = t.isLeader
+		com, err := jujuc.NewCommand(hctx, "leader-set")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.errMsg)
+		settings, err := hctx.LeaderSettings()
+		c.Assert(err, gc.IsNil)
+		c.Check(settings, gc.DeepEquals, t.settings)
+	}
+}
@@ -191,14 +191,22 @@ var newCommandTests = []struct {
 	name string
 	err  string
 }{
+	{"action-fail", ""},
+	{"action-get", ""},
+	{"action-set", ""},
 	{"close-port", ""},
 	{"config-get", ""},
+	{"is-leader", ""},
 	{"juju-log", ""},
+	{"leader-get", ""},
+	{"leader-set", ""},
 	{"open-port", ""},
 	{"relation-get", ""},
 	{"relation-ids", ""},
 	{"relation-list", ""},
 	{"relation-set", ""},
+	{"status-get", ""},
+	{"status-set", ""},
 	{"unit-get", ""},
 	{"random", "unknown command: random"},
 }
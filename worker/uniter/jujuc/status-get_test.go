@@ -0,0 +1,55 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type StatusGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusGetSuite{})
+
+var statusGetTests = []struct {
+	args []string
+	out  string
+}{
+	{nil, "blocked\n"},
+	{[]string{"--format", "json"}, `"blocked"` + "\n"},
+	{[]string{"--include-message"}, "message: missing config\nstatus: blocked\n"},
+	{[]string{"--include-message", "--format", "json"}, `{"message":"missing config","status":"blocked"}` + "\n"},
+}
+
+func (s *StatusGetSuite) TestStatusGet(c *gc.C) {
+	for i, t := range statusGetTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		err := hctx.SetWorkloadStatus(params.StatusBlocked, "missing config")
+		c.Assert(err, gc.IsNil)
+		com, err := jujuc.NewCommand(hctx, "status-get")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *StatusGetSuite) TestStatusGetUnknown(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, "status-get")
+	c.Assert(err, gc.IsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "unknown\n")
+}
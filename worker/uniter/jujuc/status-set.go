@@ -0,0 +1,63 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/state/api/params"
+)
+
+// StatusSetCommand implements the status-set command.
+type StatusSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	status  params.Status
+	message string
+}
+
+func NewStatusSetCommand(ctx Context) cmd.Command {
+	return &StatusSetCommand{ctx: ctx}
+}
+
+func (c *StatusSetCommand) Info() *cmd.Info {
+	doc := `
+status-set sets the status of the unit's workload, which is reported by
+juju status alongside the status of the unit agent. The status must be
+one of:
+    maintenance  the unit is preparing to provide service
+    waiting      the unit is waiting for a related service
+    blocked      the unit needs manual intervention to progress
+    active       the unit is providing service
+A message describing the status may be given; it is required when the
+status is "blocked".
+`
+	return &cmd.Info{
+		Name:    "status-set",
+		Args:    "<maintenance | waiting | blocked | active> [\"<message>\"]",
+		Purpose: "set the status of the unit's workload",
+		Doc:     doc,
+	}
+}
+
+func (c *StatusSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no status specified")
+	}
+	c.status = params.Status(args[0])
+	if !c.status.ValidWorkload() {
+		return fmt.Errorf("invalid status %q", args[0])
+	}
+	args = args[1:]
+	if len(args) > 0 {
+		c.message = args[0]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *StatusSetCommand) Run(ctx *cmd.Context) error {
+	return c.ctx.SetWorkloadStatus(c.status, c.message)
+}
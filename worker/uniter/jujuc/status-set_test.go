@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	gc "launchpad.net/gocheck"
+
+	"github.com/juju/juju/cmd"
+	"github.com/juju/juju/state/api/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/jujuc"
+)
+
+type StatusSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusSetSuite{})
+
+var statusSetTests = []struct {
+	summary string
+	args    []string
+	code    int
+	errMsg  string
+	status  params.Status
+	message string
+}{{
+	summary: "no status",
+	code:    2,
+	errMsg:  "error: no status specified\n",
+	status:  params.StatusUnknown,
+}, {
+	summary: "agent status",
+	args:    []string{"started"},
+	code:    2,
+	errMsg:  "error: invalid status \"started\"\n",
+	status:  params.StatusUnknown,
+}, {
+	summary: "status only",
+	args:    []string{"active"},
+	status:  params.StatusActive,
+}, {
+	summary: "status and message",
+	args:    []string{"waiting", "waiting for database"},
+	status:  params.StatusWaiting,
+	message: "waiting for database",
+}, {
+	summary: "too many arguments",
+	args:    []string{"blocked", "missing", "config"},
+	code:    2,
+	errMsg:  "error: unrecognized args: [\"config\"]\n",
+	status:  params.StatusUnknown,
+}}
+
+func (s *StatusSetSuite) TestStatusSet(c *gc.C) {
+	for i, t := range statusSetTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, "status-set")
+		c.Assert(err, gc.IsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.errMsg)
+		status, message, err := hctx.WorkloadStatus()
+		c.Assert(err, gc.IsNil)
+		c.Check(status, gc.Equals, t.status)
+		c.Check(message, gc.Equals, t.message)
+	}
+}
@@ -61,9 +61,11 @@ func (s *ContextSuite) GetHookContext(c *gc.C, relid int, remote string) *Contex
 		c.Assert(found, gc.Equals, true)
 	}
 	return &Context{
-		relid:  relid,
-		remote: remote,
-		rels:   s.rels,
+		relid:          relid,
+		remote:         remote,
+		rels:           s.rels,
+		workloadStatus: params.StatusUnknown,
+		leaderSettings: map[string]string{"master": "10.0.0.1"},
 	}
 }
 
@@ -83,6 +85,18 @@ type Context struct {
 	relid  int
 	remote string
 	rels   map[int]*ContextRelation
+
+	// actionParams is nil unless the context is running an action.
+	actionParams  map[string]interface{}
+	actionResults map[string]interface{}
+	actionFailed  bool
+	actionMessage string
+
+	workloadStatus params.Status
+	workloadInfo   string
+
+	isLeader       bool
+	leaderSettings map[string]string
 }
 
 func (c *Context) UnitName() string {
@@ -107,6 +121,45 @@ func (c *Context) ClosePort(protocol string, port int) error {
 	return nil
 }
 
+func (c *Context) WorkloadStatus() (params.Status, string, error) {
+	return c.workloadStatus, c.workloadInfo, nil
+}
+
+func (c *Context) SetWorkloadStatus(status params.Status, info string) error {
+	if !status.ValidWorkload() {
+		return fmt.Errorf("cannot set invalid workload status %q", status)
+	}
+	c.workloadStatus = status
+	c.workloadInfo = info
+	return nil
+}
+
+func (c *Context) IsLeader() (bool, error) {
+	return c.isLeader, nil
+}
+
+func (c *Context) LeaderSettings() (map[string]string, error) {
+	settings := make(map[string]string)
+	for k, v := range c.leaderSettings {
+		settings[k] = v
+	}
+	return settings, nil
+}
+
+func (c *Context) WriteLeaderSettings(settings map[string]string) error {
+	if !c.isLeader {
+		return fmt.Errorf("unit is not the service leader")
+	}
+	for k, v := range settings {
+		if v == "" {
+			delete(c.leaderSettings, k)
+		} else {
+			c.leaderSettings[k] = v
+		}
+	}
+	return nil
+}
+
 func (c *Context) ConfigSettings() (charm.Settings, error) {
 	return charm.Settings{
 		"empty":               nil,
@@ -142,6 +195,42 @@ func (c *Context) OwnerTag() string {
 	return "test-owner"
 }
 
+func (c *Context) ActionParams() (map[string]interface{}, error) {
+	if c.actionParams == nil {
+		return nil, fmt.Errorf("not running an action")
+	}
+	return c.actionParams, nil
+}
+
+func (c *Context) UpdateActionResults(keys []string, value string) error {
+	if c.actionParams == nil {
+		return fmt.Errorf("not running an action")
+	}
+	if c.actionResults == nil {
+		c.actionResults = map[string]interface{}{}
+	}
+	m := c.actionResults
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+	return nil
+}
+
+func (c *Context) SetActionFailed(message string) error {
+	if c.actionParams == nil {
+		return fmt.Errorf("not running an action")
+	}
+	c.actionFailed = true
+	c.actionMessage = message
+	return nil
+}
+
 type ContextRelation struct {
 	id    int
 	name  string
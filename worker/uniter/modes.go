@@ -6,6 +6,7 @@ package uniter
 import (
 	stderrors "errors"
 	"fmt"
+	"time"
 
 	"launchpad.net/tomb"
 
@@ -75,6 +76,19 @@ func ModeContinue(u *Uniter) (next Mode, err error) {
 			}
 			return ModeContinue, nil
 		}
+		if u.s.Hook.Kind == hooks.ActionRequested {
+			// An interrupted action cannot be safely resumed, so we
+			// report it as failed and carry on.
+			logger.Infof("found interrupted action %q", u.s.Hook.ActionId)
+			err = u.st.ActionFail(u.s.Hook.ActionId, nil, "action interrupted")
+			if err != nil && !params.IsCodeNotFoundOrCodeUnauthorized(err) {
+				return nil, err
+			}
+			if err = u.commitHook(*u.s.Hook); err != nil {
+				return nil, err
+			}
+			return ModeContinue, nil
+		}
 		logger.Infof("awaiting error resolution for %q hook", u.s.Hook.Kind)
 		return ModeHookError, nil
 	}
@@ -197,6 +211,9 @@ func ModeTerminating(u *Uniter) (next Mode, err error) {
 // * service configuration changes
 // * charm upgrade requests
 // * relation changes
+// * queued actions
+// * service leadership changes
+// * the update-status interval elapsing
 // * unit death
 func ModeAbide(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeAbide", &err)()
@@ -231,6 +248,7 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 // modeAbideAliveLoop handles all state changes for ModeAbide when the unit
 // is in an Alive state.
 func modeAbideAliveLoop(u *Uniter) (Mode, error) {
+	updateStatus := time.After(u.updateStatusInterval())
 	for {
 		hi := hook.Info{}
 		select {
@@ -241,6 +259,15 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 		case <-u.f.ConfigEvents():
 			hi = hook.Info{Kind: hooks.ConfigChanged}
 		case hi = <-u.relationHooks:
+		case hInfo := <-u.f.ActionEvents():
+			hi = *hInfo
+		case <-u.f.LeaderElectedEvents():
+			hi = hook.Info{Kind: hooks.LeaderElected}
+		case <-u.f.LeaderSettingsEvents():
+			hi = hook.Info{Kind: hooks.LeaderSettingsChanged}
+		case <-updateStatus:
+			hi = hook.Info{Kind: hooks.UpdateStatus}
+			updateStatus = time.After(u.updateStatusInterval())
 		case ids := <-u.f.RelationsEvents():
 			added, err := u.updateRelations(ids)
 			if err != nil {
@@ -79,8 +79,11 @@ type Uniter struct {
 	hookLock     *fslock.Lock
 	runListener  *RunListener
 
-	proxy      proxyutils.Settings
-	proxyMutex sync.Mutex
+	// environMutex guards the settings taken from the environment
+	// configuration, which is watched for changes.
+	environMutex   sync.Mutex
+	proxy          proxyutils.Settings
+	statusInterval time.Duration
 
 	ranConfigChanged bool
 	// The execution observer is only used in tests at this stage. Should this
@@ -114,12 +117,17 @@ func (u *Uniter) loop(unitTag string) (err error) {
 	defer u.runListener.Close()
 	logger.Infof("unit %q started", u.unit)
 
+	environConfig, err := u.st.EnvironConfig()
+	if err != nil {
+		return err
+	}
+	u.updateEnvironConfig(environConfig)
 	environWatcher, err := u.st.WatchForEnvironConfigChanges()
 	if err != nil {
 		return err
 	}
 	defer watcher.Stop(environWatcher, &u.tomb)
-	u.watchForProxyChanges(environWatcher)
+	u.watchForEnvironConfigChanges(environWatcher)
 
 	// Start filtering state change events for consumption by modes.
 	u.f, err = newFilter(u.st, unitTag)
@@ -341,8 +349,8 @@ func (u *Uniter) getHookContext(hctxId string, relationId int, remoteUnitName st
 		ctxRelations[id] = r.Context()
 	}
 
-	u.proxyMutex.Lock()
-	defer u.proxyMutex.Unlock()
+	u.environMutex.Lock()
+	defer u.environMutex.Unlock()
 
 	// Make a copy of the proxy settings.
 	proxySettings := u.proxy
@@ -449,11 +457,32 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 
 	hookName := string(hi.Kind)
 	relationId := -1
+	var actionParams map[string]interface{}
+	var actionTimeout time.Duration
 	if hi.Kind.IsRelation() {
 		relationId = hi.RelationId
 		if hookName, err = u.relationers[relationId].PrepareHook(hi); err != nil {
 			return err
 		}
+	} else if hi.Kind == hooks.ActionRequested {
+		action, err := u.st.Action(hi.ActionId)
+		if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+			// The action has already been completed or removed; there
+			// is nothing left to do but record that we've seen it.
+			logger.Infof("skipping action %q: no longer queued", hi.ActionId)
+			return u.commitHook(hi)
+		} else if err != nil {
+			return err
+		}
+		hookName = action.Name()
+		actionParams = action.Params()
+		actionTimeout = action.Timeout()
+		if err := u.validateAction(hookName, actionParams); err != nil {
+			if err := u.finishAction(hi.ActionId, nil, err); err != nil {
+				return err
+			}
+			return u.commitHook(hi)
+		}
 	}
 	hctxId := fmt.Sprintf("%s:%s:%d", u.unit.Name(), hookName, u.rand.Int63())
 
@@ -467,6 +496,13 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	if err != nil {
 		return err
 	}
+	if hi.Kind == hooks.ActionRequested {
+		if actionParams == nil {
+			actionParams = map[string]interface{}{}
+		}
+		hctx.actionData = newActionData(actionParams)
+		hctx.actionData.timeout = actionTimeout
+	}
 	srv, socketPath, err := u.startJujucServer(hctx)
 	if err != nil {
 		return err
@@ -477,6 +513,30 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	if err := u.writeState(RunHook, Pending, &hi, nil); err != nil {
 		return err
 	}
+	if hi.Kind == hooks.ActionRequested {
+		err := u.st.ActionBegin(hi.ActionId)
+		if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+			logger.Infof("skipping action %q: no longer queued", hi.ActionId)
+			return u.commitHook(hi)
+		} else if err != nil {
+			return err
+		}
+		cancel := make(chan struct{})
+		stop := make(chan struct{})
+		go u.watchActionCancel(hi.ActionId, cancel, stop)
+		hctx.actionData.cancel = cancel
+		logger.Infof("running %q action", hookName)
+		err = hctx.RunAction(hookName, u.charmPath, u.toolsDir, socketPath)
+		close(stop)
+		if err := u.finishAction(hi.ActionId, hctx.actionResults(), err); err != nil {
+			return err
+		}
+		if err := u.writeState(RunHook, Done, &hi, nil); err != nil {
+			return err
+		}
+		logger.Infof("ran %q action", hookName)
+		return u.commitHook(hi)
+	}
 	logger.Infof("running %q hook", hookName)
 	ranHook := true
 	err = hctx.RunHook(hookName, u.charmPath, u.toolsDir, socketPath)
@@ -499,6 +559,88 @@ func (u *Uniter) runHook(hi hook.Info) (err error) {
 	return u.commitHook(hi)
 }
 
+// validateAction returns an error if the named action is not defined
+// by the deployed charm, or if actionParams do not match its schema. The
+// schema is checked again here because the charm may have been upgraded
+// since the action was queued.
+func (u *Uniter) validateAction(name string, actionParams map[string]interface{}) error {
+	ch, err := corecharm.ReadDir(u.charmPath)
+	if err != nil {
+		return err
+	}
+	spec, ok := ch.Actions().ActionSpecs[name]
+	if !ok {
+		return fmt.Errorf("action %q not defined by charm", name)
+	}
+	_, err = spec.ValidateParams(actionParams)
+	return err
+}
+
+// finishAction records the outcome of running the action with the
+// supplied id, as indicated by runErr, along with the results the action
+// set. A failed action is reported against the action itself, and does
+// not put the unit into an error state.
+func (u *Uniter) finishAction(actionId string, results map[string]interface{}, runErr error) error {
+	var err error
+	if runErr == nil {
+		err = u.st.ActionComplete(actionId, results)
+	} else if stopErr, ok := runErr.(*actionStoppedError); ok {
+		logger.Infof("action %q stopped: %s", actionId, stopErr.message)
+		if stopErr.status == "timeout" {
+			err = u.st.ActionTimedOut(actionId, results, stopErr.message)
+		} else {
+			err = u.st.ActionCancelled(actionId, results, stopErr.message)
+		}
+	} else {
+		message := runErr.Error()
+		if IsMissingHookError(runErr) {
+			message = "action not implemented on unit"
+		}
+		logger.Errorf("action %q failed: %s", actionId, message)
+		err = u.st.ActionFail(actionId, results, message)
+	}
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The action was removed while it was running, for example
+		// because the unit is being removed.
+		logger.Infof("cannot record outcome of action %q: no longer queued", actionId)
+		return nil
+	}
+	return err
+}
+
+// watchActionCancel closes cancel once cancellation of the running
+// action with the supplied id is requested, or the action is removed.
+// It returns when stop is closed.
+func (u *Uniter) watchActionCancel(actionId string, cancel chan<- struct{}, stop <-chan struct{}) {
+	w, err := u.st.WatchAction(actionId)
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		close(cancel)
+		return
+	} else if err != nil {
+		logger.Warningf("cannot watch action %q for cancellation: %v", actionId, err)
+		return
+	}
+	defer watcher.Stop(w, &u.tomb)
+	for {
+		select {
+		case <-stop:
+			return
+		case _, ok := <-w.Changes():
+			if !ok {
+				logger.Warningf("cannot watch action %q for cancellation: %v", actionId, watcher.MustErr(w))
+				return
+			}
+		}
+		action, err := u.st.Action(actionId)
+		if params.IsCodeNotFoundOrCodeUnauthorized(err) || err == nil && action.Cancelling() {
+			close(cancel)
+			return
+		} else if err != nil {
+			logger.Warningf("cannot check action %q for cancellation: %v", actionId, err)
+		}
+	}
+}
+
 // commitHook ensures that state is consistent with the supplied hook, and
 // that the fact of the hook's completion is persisted.
 func (u *Uniter) commitHook(hi hook.Info) error {
@@ -730,11 +872,13 @@ func (u *Uniter) fixDeployer() error {
 	return nil
 }
 
-// updatePackageProxy updates the package proxy settings from the
-// environment.
-func (u *Uniter) updatePackageProxy(cfg *config.Config) {
-	u.proxyMutex.Lock()
-	defer u.proxyMutex.Unlock()
+// updateEnvironConfig updates the package proxy settings and the
+// update-status interval from the environment configuration.
+func (u *Uniter) updateEnvironConfig(cfg *config.Config) {
+	u.environMutex.Lock()
+	defer u.environMutex.Unlock()
+
+	u.statusInterval = cfg.UpdateStatusInterval()
 
 	newSettings := cfg.ProxySettings()
 	if u.proxy != newSettings {
@@ -745,9 +889,19 @@ func (u *Uniter) updatePackageProxy(cfg *config.Config) {
 	}
 }
 
-// watchForProxyChanges kicks off a go routine to listen to the watcher and
-// update the proxy settings.
-func (u *Uniter) watchForProxyChanges(environWatcher apiwatcher.NotifyWatcher) {
+// updateStatusInterval returns the interval after which the
+// update-status hook should next be run, as last configured for the
+// environment.
+func (u *Uniter) updateStatusInterval() time.Duration {
+	u.environMutex.Lock()
+	defer u.environMutex.Unlock()
+	return u.statusInterval
+}
+
+// watchForEnvironConfigChanges kicks off a go routine to listen to the
+// watcher and update the settings taken from the environment
+// configuration.
+func (u *Uniter) watchForEnvironConfigChanges(environWatcher apiwatcher.NotifyWatcher) {
 	go func() {
 		for {
 			select {
@@ -762,7 +916,7 @@ func (u *Uniter) watchForProxyChanges(environWatcher apiwatcher.NotifyWatcher) {
 				if err != nil {
 					logger.Errorf("cannot load environment configuration: %v", err)
 				} else {
-					u.updatePackageProxy(environConfig)
+					u.updateEnvironConfig(environConfig)
 				}
 			}
 		}
@@ -456,6 +456,27 @@ func (s *UniterSuite) TestUniterConfigChangedHook(c *gc.C) {
 	s.runUniterTests(c, configChangedHookTests)
 }
 
+var updateStatusHookTests = []uniterTest{
+	ut(
+		"update-status hook runs periodically",
+		setUpdateStatusInterval("10ms"),
+		quickStart{},
+		waitHooks{"update-status", "update-status"},
+	), ut(
+		"update-status hook does not run while in error",
+		setUpdateStatusInterval("10ms"),
+		startupError{"start"},
+		// Several intervals pass while waiting, and no update-status
+		// hook runs in any of them.
+		waitHooks{},
+		verifyWaiting{},
+	),
+}
+
+func (s *UniterSuite) TestUniterUpdateStatusHook(c *gc.C) {
+	s.runUniterTests(c, updateStatusHookTests)
+}
+
 var hookSynchronizationTests = []uniterTest{
 	ut(
 		"verify config change hook not run while lock held",
@@ -1248,6 +1269,176 @@ func (s *UniterSuite) TestUniterSubordinates(c *gc.C) {
 	s.runUniterTests(c, subordinatesTests)
 }
 
+var actionEventTests = []uniterTest{
+	ut(
+		"simple action event: defined in actions.yaml, no args",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeAction(c, path, "action-log", true)
+			ctx.writeActionsYaml(c, path, "action-log")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		verifyCharm{},
+		addAction{"action-log", nil},
+		waitActionResults{[]actionResult{{
+			name:   "action-log",
+			status: state.ActionCompleted,
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action fails but unit does not enter an error state",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeAction(c, path, "action-log", false)
+			ctx.writeActionsYaml(c, path, "action-log")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addAction{"action-log", nil},
+		waitActionResults{[]actionResult{{
+			name:    "action-log",
+			status:  state.ActionFailed,
+			message: "exit status 1",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action no longer defined by the deployed charm fails without running",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeAction(c, path, "action-log", true)
+			ctx.writeActionsYaml(c, path, "action-log")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		// State still knows the action, but the charm on disk does not.
+		custom{func(c *gc.C, ctx *context) {
+			ctx.writeActionsYaml(c, filepath.Join(ctx.path, "charm"))
+		}},
+		addAction{"action-log", nil},
+		waitActionResults{[]actionResult{{
+			name:    "action-log",
+			status:  state.ActionFailed,
+			message: `action "action-log" not defined by charm`,
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action defined in actions.yaml but missing from the charm",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeActionsYaml(c, path, "action-log")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addAction{"action-log", nil},
+		waitActionResults{[]actionResult{{
+			name:    "action-log",
+			status:  state.ActionFailed,
+			message: "action not implemented on unit",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"actions queued before the uniter starts are run in order",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeAction(c, path, "action-log", true)
+			ctx.writeAction(c, path, "snapshot", true)
+			ctx.writeActionsYaml(c, path, "action-log", "snapshot")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		stopUniter{},
+		addAction{"action-log", nil},
+		addAction{"snapshot", nil},
+		startUniter{},
+		waitActionResults{[]actionResult{{
+			name:   "action-log",
+			status: state.ActionCompleted,
+		}, {
+			name:   "snapshot",
+			status: state.ActionCompleted,
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action reads its params and records results with hook tools",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeReportingAction(c, path)
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addAction{"snapshot", map[string]interface{}{"outfile": "out.tar"}},
+		waitActionResults{[]actionResult{{
+			name:    "snapshot",
+			status:  state.ActionCompleted,
+			results: "result:\n  file: out.tar\n  ok: \"yes\"\n",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action reports failure with action-fail",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeReportingAction(c, path)
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addAction{"snapshot", map[string]interface{}{"fail": true}},
+		waitActionResults{[]actionResult{{
+			name:    "snapshot",
+			status:  state.ActionFailed,
+			message: "could not finish",
+			results: "result:\n  file: foo.bz2\n  ok: \"yes\"\n",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"action running past its timeout is stopped",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeSleepingAction(c, path, "action-sleep")
+			ctx.writeActionsYaml(c, path, "action-sleep")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addActionWithTimeout{"action-sleep", time.Second},
+		waitActionResults{[]actionResult{{
+			name:    "action-sleep",
+			status:  state.ActionTimedOut,
+			message: "action timed out after 1s",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	), ut(
+		"running action is stopped when cancelled",
+		createCharm{customize: func(c *gc.C, ctx *context, path string) {
+			ctx.writeSleepingAction(c, path, "action-sleep")
+			ctx.writeActionsYaml(c, path, "action-sleep")
+		}},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start"},
+		addAction{"action-sleep", nil},
+		cancelRunningAction{},
+		waitActionResults{[]actionResult{{
+			name:    "action-sleep",
+			status:  state.ActionCancelled,
+			message: "action cancelled",
+		}}},
+		waitUnit{status: params.StatusStarted},
+	),
+}
+
+func (s *UniterSuite) TestUniterActionEvents(c *gc.C) {
+	s.runUniterTests(c, actionEventTests)
+}
+
 func (s *UniterSuite) runUniterTests(c *gc.C, uniterTests []uniterTest) {
 	for i, t := range uniterTests {
 		c.Logf("\ntest %d: %s\n", i, t.summary)
@@ -1366,7 +1557,7 @@ type createCharm struct {
 }
 
 var charmHooks = []string{
-	"install", "start", "config-changed", "upgrade-charm", "stop",
+	"install", "start", "config-changed", "upgrade-charm", "stop", "update-status",
 	"db-relation-joined", "db-relation-changed", "db-relation-departed",
 	"db-relation-broken",
 }
@@ -1748,6 +1939,185 @@ func (s waitHooks) step(c *gc.C, ctx *context) {
 	}
 }
 
+var goodAction = `
+#!/bin/bash --norc
+juju-log $JUJU_ENV_UUID action %s
+`[1:]
+
+var badAction = `
+#!/bin/bash --norc
+juju-log $JUJU_ENV_UUID fail-action %s
+exit 1
+`[1:]
+
+// writeAction writes an executable script for the named action into
+// the actions directory of the charm at charmPath.
+func (ctx *context) writeAction(c *gc.C, charmPath, name string, good bool) {
+	action := badAction
+	if good {
+		action = goodAction
+	}
+	actionsDir := filepath.Join(charmPath, "actions")
+	err := os.MkdirAll(actionsDir, 0755)
+	c.Assert(err, gc.IsNil)
+	content := fmt.Sprintf(action, name)
+	err = ioutil.WriteFile(filepath.Join(actionsDir, name), []byte(content), 0755)
+	c.Assert(err, gc.IsNil)
+}
+
+// writeSleepingAction writes an action that never finishes on its own.
+func (ctx *context) writeSleepingAction(c *gc.C, charmPath, name string) {
+	actionsDir := filepath.Join(charmPath, "actions")
+	err := os.MkdirAll(actionsDir, 0755)
+	c.Assert(err, gc.IsNil)
+	content := "#!/bin/bash --norc\nsleep 3600\n"
+	err = ioutil.WriteFile(filepath.Join(actionsDir, name), []byte(content), 0755)
+	c.Assert(err, gc.IsNil)
+}
+
+// reportingAction reads its "outfile" parameter with action-get, records
+// results with action-set, and reports failure with action-fail if its
+// "fail" parameter is set.
+var reportingAction = `
+#!/bin/bash --norc
+outfile=$(action-get outfile)
+action-set result.file="$outfile" result.ok=yes
+if [ "$(action-get fail)" = "True" ]; then
+    action-fail "could not finish"
+fi
+`[1:]
+
+var reportingActionsYaml = `
+actions:
+  snapshot:
+    description: Take a snapshot.
+    params:
+      outfile:
+        type: string
+        default: foo.bz2
+      fail:
+        type: boolean
+        default: false
+`[1:]
+
+// writeReportingAction writes the reportingAction script as the
+// "snapshot" action of the charm at charmPath, along with an
+// actions.yaml declaring its parameters.
+func (ctx *context) writeReportingAction(c *gc.C, charmPath string) {
+	actionsDir := filepath.Join(charmPath, "actions")
+	err := os.MkdirAll(actionsDir, 0755)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(filepath.Join(actionsDir, "snapshot"), []byte(reportingAction), 0755)
+	c.Assert(err, gc.IsNil)
+	err = ioutil.WriteFile(filepath.Join(charmPath, "actions.yaml"), []byte(reportingActionsYaml), 0644)
+	c.Assert(err, gc.IsNil)
+}
+
+// writeActionsYaml writes an actions.yaml declaring the named actions,
+// none of which take any parameters, into the charm at charmPath.
+func (ctx *context) writeActionsYaml(c *gc.C, charmPath string, names ...string) {
+	var buf bytes.Buffer
+	buf.WriteString("actions:\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "  %s:\n    description: %s\n", name, name)
+	}
+	err := ioutil.WriteFile(filepath.Join(charmPath, "actions.yaml"), buf.Bytes(), 0644)
+	c.Assert(err, gc.IsNil)
+}
+
+type addAction struct {
+	name   string
+	params map[string]interface{}
+}
+
+func (s addAction) step(c *gc.C, ctx *context) {
+	_, err := ctx.unit.AddAction(s.name, s.params)
+	c.Assert(err, gc.IsNil)
+}
+
+type addActionWithTimeout struct {
+	name    string
+	timeout time.Duration
+}
+
+func (s addActionWithTimeout) step(c *gc.C, ctx *context) {
+	_, err := ctx.unit.AddActionWithTimeout(s.name, nil, s.timeout)
+	c.Assert(err, gc.IsNil)
+}
+
+// cancelRunningAction waits for the unit to start running its only
+// queued action, and then cancels it.
+type cancelRunningAction struct{}
+
+func (s cancelRunningAction) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		ctx.s.BackingState.StartSync()
+		select {
+		case <-time.After(coretesting.ShortWait):
+			actions, err := ctx.unit.Actions()
+			c.Assert(err, gc.IsNil)
+			c.Assert(actions, gc.HasLen, 1)
+			if actions[0].Started().IsZero() {
+				c.Logf("action not started yet; still waiting")
+				continue
+			}
+			err = actions[0].Cancel()
+			c.Assert(err, gc.IsNil)
+			return
+		case <-timeout:
+			c.Fatalf("timed out waiting for action to start")
+		}
+	}
+}
+
+type actionResult struct {
+	name    string
+	status  state.ActionStatus
+	message string
+	// results holds the action's results formatted as YAML, if it set any.
+	results string
+}
+
+type waitActionResults struct {
+	expect []actionResult
+}
+
+func (s waitActionResults) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		ctx.s.BackingState.StartSync()
+		select {
+		case <-time.After(coretesting.ShortWait):
+			results, err := ctx.st.ActionResultsForUnit(ctx.unit.Name())
+			c.Assert(err, gc.IsNil)
+			if len(results) != len(s.expect) {
+				c.Logf("want %d action results, got %d; still waiting", len(s.expect), len(results))
+				continue
+			}
+			var got []actionResult
+			for _, result := range results {
+				var resultsYaml string
+				if len(result.Results()) > 0 {
+					data, err := goyaml.Marshal(result.Results())
+					c.Assert(err, gc.IsNil)
+					resultsYaml = string(data)
+				}
+				got = append(got, actionResult{
+					name:    result.ActionName(),
+					status:  result.Status(),
+					message: result.Message(),
+					results: resultsYaml,
+				})
+			}
+			c.Assert(got, jc.SameContents, s.expect)
+			return
+		case <-timeout:
+			c.Fatalf("timed out waiting for action results")
+		}
+	}
+}
+
 type fixHook struct {
 	name string
 }
@@ -2196,6 +2566,14 @@ var verifyHookSyncLockLocked = custom{func(c *gc.C, ctx *context) {
 	c.Assert(lock.IsLocked(), jc.IsTrue)
 }}
 
+type setUpdateStatusInterval string
+
+func (s setUpdateStatusInterval) step(c *gc.C, ctx *context) {
+	attrs := map[string]interface{}{"update-status-interval": string(s)}
+	err := ctx.st.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, gc.IsNil)
+}
+
 type setProxySettings proxy.Settings
 
 func (s setProxySettings) step(c *gc.C, ctx *context) {